@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/workspaces"
+)
+
+// daemon.go defines the "daemon" command.
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Fetch every canonical repository on an interval",
+	Long: `Run in the foreground and fetch every canonical repository right away and then again
+after every daemon.fetch_interval plus a random delay of up to daemon.fetch_jitter, using
+the git.retry policy. Repositories used by a locked workspace are skipped until the next
+run. The result of the latest run is written to a status file in projects_root, shown by
+"repo status", "doctor" and the TUI. Stop the daemon with Ctrl+C.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		once, _ := cmd.Flags().GetBool("once")
+
+		app, err := getApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		daemonCfg := app.Config.GetDaemonConfig()
+		interval, jitter := daemonCfg.FetchInterval, daemonCfg.FetchJitter
+
+		if cmd.Flags().Changed("interval") {
+			interval, _ = cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				return cerrors.NewInvalidArgument("interval", "must be positive")
+			}
+		}
+
+		if cmd.Flags().Changed("jitter") {
+			jitter, _ = cmd.Flags().GetDuration("jitter")
+			if jitter < 0 {
+				return cerrors.NewInvalidArgument("jitter", "must be zero or positive")
+			}
+		}
+
+		scheduler := workspaces.NewFetchScheduler(app.Service, interval, jitter)
+
+		if once {
+			run, err := scheduler.RunOnce(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			printFetchRun(run)
+
+			if failed := run.Count(domain.FetchOutcomeFailed); failed > 0 {
+				return cerrors.NewCommandFailed("daemon --once", fmt.Errorf("%d repositories failed to fetch", failed))
+			}
+
+			return nil
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		output.Infof("Fetching canonical repositories every %s (plus up to %s); press Ctrl+C to stop", interval, jitter)
+
+		return scheduler.Run(ctx, func(run domain.FetchRun, err error) {
+			if err != nil {
+				output.Warnf("Background fetch failed: %v", err)
+				return
+			}
+
+			printFetchRun(run)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().Bool("once", false, "Fetch every canonical repository once and exit")
+	daemonCmd.Flags().Duration("interval", 0, "Time between fetches (default: daemon.fetch_interval)")
+	daemonCmd.Flags().Duration("jitter", 0, "Maximum random delay added to each interval (default: daemon.fetch_jitter)")
+}
+
+// printFetchRun prints the outcome of a background fetch run.
+func printFetchRun(run domain.FetchRun) {
+	for _, repo := range run.Repos {
+		switch repo.Outcome {
+		case domain.FetchOutcomeFailed:
+			output.Warnf("  Failed to fetch %s: %s", repo.Name, repo.Error)
+		case domain.FetchOutcomeSkipped:
+			output.Infof("  Skipped %s: %s", repo.Name, repo.Error)
+		}
+	}
+
+	line := fmt.Sprintf("[%s] %s", run.FinishedAt.Format(time.TimeOnly), run.Summary())
+	if run.NextRunAt != nil {
+		line += fmt.Sprintf("; next fetch at %s", run.NextRunAt.Format(time.TimeOnly))
+	}
+
+	output.Info(line)
+}
@@ -14,7 +14,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+	"github.com/alexisbeaulieu97/canopy/internal/storage"
 )
 
 // CheckSeverity indicates the severity level of a check result.
@@ -87,6 +90,7 @@ Checks performed:
   - Configuration file validity
   - Directory existence and permissions
   - Canonical repository health
+  - Latest background fetch ("canopy daemon")
 
 Exit codes:
   0 - All checks pass
@@ -141,6 +145,7 @@ func buildDoctorReport(ctx context.Context, fix bool) *DoctorReport {
 		report.Checks = append(report.Checks, checkDirectory("workspaces_root", cfg.GetWorkspacesRoot(), fix)...)
 		report.Checks = append(report.Checks, checkDirectory("closed_root", cfg.GetClosedRoot(), fix)...)
 		report.Checks = append(report.Checks, checkCanonicalRepos(ctx, cfg)...)
+		report.Checks = append(report.Checks, checkBackgroundFetch(storage.NewFetchStatusFile(cfg.GetProjectsRoot()), time.Now()))
 	}
 
 	return report
@@ -435,3 +440,61 @@ func checkCanonicalRepos(_ context.Context, cfg doctorConfig) []CheckResult {
 
 	return results
 }
+
+// backgroundFetchGrace is how late a scheduled background fetch may be before doctor
+// reports that the daemon stopped.
+const backgroundFetchGrace = 15 * time.Minute
+
+// checkBackgroundFetch reports the latest run recorded by "canopy daemon".
+func checkBackgroundFetch(store ports.FetchStatusStore, now time.Time) CheckResult {
+	result := CheckResult{
+		Name:     "Background fetch",
+		Status:   statusPass,
+		Severity: SeverityInfo,
+	}
+
+	run, err := store.Load()
+	if err != nil {
+		result.Status = statusFail
+		result.Severity = SeverityWarning
+		result.Message = "cannot read fetch status"
+		result.Details = err.Error()
+
+		return result
+	}
+
+	if run == nil {
+		result.Message = "no runs recorded (start with: canopy daemon)"
+		return result
+	}
+
+	if failed := run.Count(domain.FetchOutcomeFailed); failed > 0 {
+		var names []string
+
+		for _, repo := range run.Repos {
+			if repo.Outcome == domain.FetchOutcomeFailed {
+				names = append(names, repo.Name)
+			}
+		}
+
+		result.Status = statusFail
+		result.Severity = SeverityWarning
+		result.Message = fmt.Sprintf("%s at %s", run.Summary(), run.FinishedAt.Local().Format("2006-01-02 15:04"))
+		result.Details = "Failed: " + strings.Join(names, ", ")
+
+		return result
+	}
+
+	if run.NextRunAt != nil && now.After(run.NextRunAt.Add(backgroundFetchGrace)) {
+		result.Status = statusFail
+		result.Severity = SeverityWarning
+		result.Message = fmt.Sprintf("missed the fetch scheduled for %s", run.NextRunAt.Local().Format("2006-01-02 15:04"))
+		result.Details = "Check that canopy daemon is still running"
+
+		return result
+	}
+
+	result.Message = fmt.Sprintf("%s at %s", run.Summary(), run.FinishedAt.Local().Format("2006-01-02 15:04"))
+
+	return result
+}
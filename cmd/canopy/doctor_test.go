@@ -8,6 +8,9 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/mocks"
 )
 
 func TestCheckGitInstalled(t *testing.T) {
@@ -326,6 +329,54 @@ func TestCheckCanonicalRepos_NonBareRepo(t *testing.T) {
 	}
 }
 
+func TestCheckBackgroundFetch(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	next := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		run        *domain.FetchRun
+		wantStatus string
+		wantDetail string
+	}{
+		{name: "no runs", wantStatus: statusPass},
+		{
+			name:       "all fetched",
+			run:        &domain.FetchRun{FinishedAt: now, Repos: []domain.RepoFetchResult{{Name: "api", Outcome: domain.FetchOutcomeFetched}}},
+			wantStatus: statusPass,
+		},
+		{
+			name: "failed repos",
+			run: &domain.FetchRun{FinishedAt: now, Repos: []domain.RepoFetchResult{
+				{Name: "api", Outcome: domain.FetchOutcomeFailed, Error: "timeout"},
+				{Name: "web", Outcome: domain.FetchOutcomeFetched},
+			}},
+			wantStatus: statusFail,
+			wantDetail: "Failed: api",
+		},
+		{
+			name:       "missed schedule",
+			run:        &domain.FetchRun{FinishedAt: next.Add(-time.Hour), NextRunAt: &next},
+			wantStatus: statusFail,
+			wantDetail: "Check that canopy daemon is still running",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkBackgroundFetch(&mocks.MockFetchStatusStore{Run: tt.run}, now)
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s: %s", tt.wantStatus, result.Status, result.Message)
+			}
+
+			if result.Details != tt.wantDetail {
+				t.Errorf("expected details %q, got %q", tt.wantDetail, result.Details)
+			}
+		})
+	}
+}
+
 // mockDoctorConfig implements doctorConfig interface for testing.
 type mockDoctorConfig struct {
 	projectsRoot   string
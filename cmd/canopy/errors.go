@@ -36,6 +36,8 @@ const (
 	ExitTimeout          ExitCode = 21
 	ExitHookFailed       ExitCode = 22
 	ExitPathError        ExitCode = 23
+	ExitForgeError       ExitCode = 24
+	ExitIssueLookup      ExitCode = 25
 )
 
 // errorCodeToExitCode maps error codes to CLI exit codes.
@@ -67,6 +69,8 @@ var errorCodeToExitCode = map[cerrors.ErrorCode]ExitCode{
 	cerrors.ErrHookTimeout:            ExitTimeout,
 	cerrors.ErrPathInvalid:            ExitPathError,
 	cerrors.ErrPathNotDirectory:       ExitPathError,
+	cerrors.ErrForgeRequestFailed:     ExitForgeError,
+	cerrors.ErrIssueLookupFailed:      ExitIssueLookup,
 }
 
 // exitCodeForError returns the appropriate exit code for an error.
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/workspaces"
+)
+
+// gc.go defines the "gc" command.
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Close stale and merged workspaces",
+	Long: `Find workspaces that are stale, whose branch is merged into the default branch in every
+repository, or whose branch was deleted from origin in every repository, and close them
+keeping their metadata so they can be reopened.
+
+Without criteria flags, the gc.criteria config is used, or all criteria when it is not set.
+Workspaces with uncommitted changes, unpushed commits or commits that were never pushed are
+left alone unless --force is given, in which case those changes are saved in the archive.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		yes, _ := cmd.Flags().GetBool("yes")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		var criteria []domain.GCReason
+
+		for _, flag := range []struct {
+			name   string
+			reason domain.GCReason
+		}{
+			{"stale", domain.GCReasonStale},
+			{"merged", domain.GCReasonMerged},
+			{"remote-deleted", domain.GCReasonRemoteDeleted},
+		} {
+			if enabled, _ := cmd.Flags().GetBool(flag.name); enabled {
+				criteria = append(criteria, flag.reason)
+			}
+		}
+
+		app, err := getApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		plan, err := app.Service.PlanGC(cmd.Context(), criteria)
+		if err != nil {
+			return err
+		}
+
+		for _, skipped := range plan.Skipped {
+			output.Warnf("Skipping workspace %s: %v", skipped.WorkspaceID, skipped.Err)
+		}
+
+		if dryRun {
+			if jsonOutput {
+				return output.PrintJSON(map[string]interface{}{
+					"dry_run":    true,
+					"candidates": plan.Candidates,
+				})
+			}
+
+			if len(plan.Candidates) == 0 {
+				output.Info("No workspaces to collect.")
+				return nil
+			}
+
+			for _, candidate := range plan.Candidates {
+				printGCCandidate(candidate, force)
+				printWorkspaceClosePreview(candidate.Preview)
+				output.Println("")
+			}
+
+			return nil
+		}
+
+		var closable int
+
+		for _, candidate := range plan.Candidates {
+			if candidate.Blocked == "" || force {
+				closable++
+			}
+		}
+
+		if closable == 0 {
+			if jsonOutput {
+				return output.PrintJSON(map[string]interface{}{
+					"candidates": plan.Candidates,
+					"closed":     []string{},
+				})
+			}
+
+			for _, candidate := range plan.Candidates {
+				printGCCandidate(candidate, force)
+			}
+
+			output.Info("No workspaces to collect.")
+
+			return nil
+		}
+
+		if !jsonOutput {
+			output.Infof("Found %d workspaces to collect:", len(plan.Candidates))
+
+			for _, candidate := range plan.Candidates {
+				printGCCandidate(candidate, force)
+			}
+		}
+
+		if !yes {
+			if !isInteractiveTerminal() {
+				return cerrors.NewInvalidArgument("yes", "gc requires confirmation; rerun with --yes")
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			output.Printf("Close %d workspaces? [y/N]: ", closable)
+
+			answer, readErr := reader.ReadString('\n')
+			if readErr != nil {
+				return cerrors.NewOperationCancelled("gc")
+			}
+
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if answer != "y" && answer != "yes" {
+				return cerrors.NewOperationCancelled("gc")
+			}
+		}
+
+		result, err := app.Service.CollectGarbage(cmd.Context(), plan, force)
+		if err != nil {
+			return err
+		}
+
+		return printGCResult(plan, result, jsonOutput)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+
+	gcCmd.Flags().Bool("stale", false, "Collect workspaces not modified within stale_threshold_days")
+	gcCmd.Flags().Bool("merged", false, "Collect workspaces whose branch is merged into the default branch")
+	gcCmd.Flags().Bool("remote-deleted", false, "Collect workspaces whose branch was deleted from origin")
+	gcCmd.Flags().Bool("dry-run", false, "Show the plan without closing anything")
+	gcCmd.Flags().Bool("force", false, "Also close workspaces with uncommitted or unpushed work, saving it in the archive")
+	gcCmd.Flags().Bool("yes", false, "Close without asking for confirmation")
+	gcCmd.Flags().Bool("json", false, "Output in JSON format")
+}
+
+// printGCCandidate prints a candidate with its reasons, and why it is skipped if it is blocked.
+func printGCCandidate(candidate domain.GCCandidate, force bool) {
+	reasons := make([]string, len(candidate.Reasons))
+	for i, reason := range candidate.Reasons {
+		reasons[i] = string(reason)
+	}
+
+	size := ""
+	if candidate.Preview != nil && candidate.Preview.DiskUsageBytes > 0 {
+		size = ", " + output.FormatBytes(candidate.Preview.DiskUsageBytes)
+	}
+
+	output.Infof("  - %s (%s%s)", candidate.WorkspaceID, strings.Join(reasons, ", "), size)
+
+	switch {
+	case candidate.Blocked == "":
+	case force:
+		output.Warnf("    %s; will be saved in the archive", candidate.Blocked)
+	default:
+		output.Warnf("    skipped: %s (use --force to archive anyway)", candidate.Blocked)
+	}
+}
+
+// printGCResult reports the outcome of closing the candidates.
+func printGCResult(plan *workspaces.GCPlan, result *workspaces.BulkCloseResult, jsonOutput bool) error {
+	var (
+		closedIDs []string
+		failed    []map[string]string
+		firstErr  error
+	)
+
+	for _, res := range result.Results {
+		if res.Err != nil {
+			if firstErr == nil {
+				firstErr = res.Err
+			}
+
+			failed = append(failed, map[string]string{"workspace_id": res.WorkspaceID, "error": res.Err.Error()})
+
+			if !jsonOutput {
+				output.Warnf("Failed to close workspace %s: %v", res.WorkspaceID, res.Err)
+			}
+
+			continue
+		}
+
+		closedIDs = append(closedIDs, res.WorkspaceID)
+	}
+
+	if jsonOutput {
+		if err := output.PrintJSON(map[string]interface{}{
+			"candidates": plan.Candidates,
+			"closed":     closedIDs,
+			"failed":     failed,
+		}); err != nil {
+			return err
+		}
+	} else {
+		output.Success("Garbage collection completed", fmt.Sprintf("%d closed, %d failed", len(closedIDs), len(failed)))
+	}
+
+	if firstErr != nil {
+		return cerrors.NewCommandFailed("gc", firstErr)
+	}
+
+	return nil
+}
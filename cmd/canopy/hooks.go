@@ -24,6 +24,17 @@ type hookPreviewEnvelope struct {
 	ClosedAt      *time.Time                  `json:"closed_at,omitempty"`
 }
 
+type hookResultEnvelope struct {
+	Phase         string               `json:"phase"`
+	WorkspaceID   string               `json:"workspace_id"`
+	WorkspacePath string               `json:"workspace_path,omitempty"`
+	Action        string               `json:"action"`
+	ClosedAt      *time.Time           `json:"closed_at,omitempty"`
+	Archives      []domain.RepoArchive `json:"archives,omitempty"`
+	Hooks         []domain.HookResult  `json:"hooks"`
+	Error         string               `json:"error,omitempty"`
+}
+
 var (
 	hooksCmd = &cobra.Command{
 		Use:   "hooks",
@@ -39,14 +50,25 @@ var (
 				return err
 			}
 
-			hooksConfig := app.Config.GetHooks()
-			if len(hooksConfig.PostCreate) == 0 && len(hooksConfig.PreClose) == 0 {
+			phases := app.Config.GetHooks().Phases()
+
+			configured := false
+
+			for _, phase := range phases {
+				if len(phase.Hooks) > 0 {
+					configured = true
+					break
+				}
+			}
+
+			if !configured {
 				output.Info("No hooks configured.")
 				return nil
 			}
 
-			printHookList("post_create", hooksConfig.PostCreate)
-			printHookList("pre_close", hooksConfig.PreClose)
+			for _, phase := range phases {
+				printHookList(phase.Phase, phase.Hooks)
+			}
 
 			return nil
 		},
@@ -108,14 +130,17 @@ func init() {
 func parseHookPhase(event string) (workspaces.HookPhase, error) {
 	normalized := strings.ToLower(strings.ReplaceAll(event, "-", "_"))
 
-	switch normalized {
-	case string(workspaces.HookPhasePostCreate):
-		return workspaces.HookPhasePostCreate, nil
-	case string(workspaces.HookPhasePreClose):
-		return workspaces.HookPhasePreClose, nil
-	default:
-		return "", cerrors.NewInvalidArgument("hook_event", "must be post_create or pre_close")
+	names := make([]string, 0, len(workspaces.HookPhases()))
+
+	for _, phase := range workspaces.HookPhases() {
+		if normalized == string(phase) {
+			return phase, nil
+		}
+
+		names = append(names, string(phase))
 	}
+
+	return "", cerrors.NewInvalidArgument("hook_event", "must be one of "+strings.Join(names, ", "))
 }
 
 func printHookList(name string, hooks []config.Hook) {
@@ -148,7 +173,33 @@ func printHookList(name string, hooks []config.Hook) {
 		if hook.ContinueOnError {
 			output.Info("      continue_on_error: true")
 		}
+
+		if !hook.When.IsZero() {
+			output.Infof("      when: %s", formatHookCondition(hook.When))
+		}
+	}
+}
+
+func formatHookCondition(when config.HookCondition) string {
+	var parts []string
+
+	if when.FileExists != "" {
+		parts = append(parts, "file_exists="+when.FileExists)
+	}
+
+	if when.WorkspaceID != "" {
+		parts = append(parts, "workspace_id="+when.WorkspaceID)
+	}
+
+	if when.Branch != "" {
+		parts = append(parts, "branch="+when.Branch)
 	}
+
+	if when.RepoTag != "" {
+		parts = append(parts, "repo_tag="+when.RepoTag)
+	}
+
+	return strings.Join(parts, ", ")
 }
 
 func printHookPreview(phase string, previews []domain.HookCommandPreview) {
@@ -167,6 +218,15 @@ func printHookPreview(phase string, previews []domain.HookCommandPreview) {
 			output.Infof("      repo: %s", preview.RepoName)
 		}
 
+		if preview.Skipped {
+			output.Infof("      skipped: %s", preview.SkipReason)
+			continue
+		}
+
+		if preview.MatchReason != "" {
+			output.Infof("      included: %s", preview.MatchReason)
+		}
+
 		output.Infof("      working_dir: %s", preview.WorkingDir)
 	}
 }
@@ -69,11 +69,30 @@ func printWorkspaceClosePreview(preview *domain.WorkspaceClosePreview) {
 		}
 	}
 
+	for _, entry := range preview.Archives {
+		output.Infof("  Would save %s", formatRepoArchive(entry))
+	}
+
 	if preview.DiskUsageBytes > 0 {
 		output.Infof("  Total size: %s", output.FormatBytes(preview.DiskUsageBytes))
 	}
 }
 
+// formatRepoArchive describes the artifacts saved for a repository on close.
+func formatRepoArchive(entry domain.RepoArchive) string {
+	var parts []string
+
+	if entry.Patch != "" {
+		parts = append(parts, "uncommitted changes")
+	}
+
+	if entry.Bundle != "" {
+		parts = append(parts, fmt.Sprintf("%d local commit(s)", entry.Commits))
+	}
+
+	return fmt.Sprintf("%s for %s", strings.Join(parts, " and "), entry.Repo)
+}
+
 func printClosed(id string, closedAt *time.Time) {
 	if closedAt != nil {
 		output.Infof("Closed workspace %s at %s", id, closedAt.Format(time.RFC3339))
@@ -83,6 +102,16 @@ func printClosed(id string, closedAt *time.Time) {
 	output.Success("Closed workspace", id)
 }
 
+// formatAge formats how long ago t was, in hours under a day and in days after that.
+func formatAge(t time.Time) string {
+	age := time.Since(t)
+	if age < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
+
 // formatRepoStatusIndicator creates a human-readable status indicator for a repo.
 func formatRepoStatusIndicator(status domain.RepoStatus) string {
 	if status.Error != "" {
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -19,6 +20,7 @@ import (
 	"github.com/alexisbeaulieu97/canopy/internal/giturl"
 	"github.com/alexisbeaulieu97/canopy/internal/gitx"
 	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
 )
 
 var repoCmd = &cobra.Command{
@@ -83,7 +85,9 @@ var repoAddCmd = &cobra.Command{
 
 		svc := app.Service
 
-		name, err := svc.AddCanonicalRepo(cmd.Context(), url)
+		cloneOpts := cloneOptionsFromFlags(cmd)
+
+		name, err := svc.AddCanonicalRepo(cmd.Context(), url, cloneOpts)
 		if err != nil {
 			return err
 		}
@@ -100,7 +104,12 @@ var repoAddCmd = &cobra.Command{
 				alias = name
 			}
 
-			entry := config.RegistryEntry{URL: url}
+			entry := config.RegistryEntry{
+				URL:          url,
+				Filter:       cloneOpts.Filter,
+				Depth:        cloneOpts.Depth,
+				SingleBranch: cloneOpts.SingleBranch,
+			}
 			realAlias, err := registerWithPrompt(cmd, app.Config.GetRegistry(), alias, entry, app.Logger)
 			if err != nil {
 				// Use a detached context for cleanup to ensure it runs even if cmd.Context() is cancelled
@@ -121,6 +130,117 @@ var repoAddCmd = &cobra.Command{
 	},
 }
 
+var repoDeepenCmd = &cobra.Command{
+	Use:   "deepen <NAME>",
+	Short: "Fetch what a partial, shallow or single-branch repository left out",
+	Long: `Fetch what a partial, shallow or single-branch canonical repository left out.
+By default the full history of a shallow clone is fetched; --depth fetches that many
+more commits instead. --all-branches widens a single-branch clone to every branch, and
+--blobs fetches every object a partial clone filtered out and drops the filter.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		depth, _ := cmd.Flags().GetInt("depth")
+		allBranches, _ := cmd.Flags().GetBool("all-branches")
+		blobs, _ := cmd.Flags().GetBool("blobs")
+
+		app, err := getApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := ports.DeepenOptions{Depth: depth, AllBranches: allBranches, Blobs: blobs}
+		if err := app.Service.DeepenCanonicalRepo(cmd.Context(), name, opts); err != nil {
+			return err
+		}
+
+		status, err := app.Service.GetCanonicalRepoStatus(cmd.Context(), name)
+		if err != nil {
+			return err
+		}
+
+		output.Success("Deepened repository", fmt.Sprintf("%s (%s, %s)", name, status.CloneMode, output.FormatBytes(status.DiskUsageBytes)))
+		return nil
+	},
+}
+
+var repoMaintainCmd = &cobra.Command{
+	Use:   "maintain [NAME]",
+	Short: "Run git maintenance on canonical repositories",
+	Long: `Run git maintenance tasks on a canonical repository, or on every canonical repository
+with --all, in parallel. Canonical repositories collect objects from every workspace
+branch; the tasks (gc, loose-objects, incremental-repack, commit-graph, pack-refs) pack
+them and speed up later git commands. --task runs only the given tasks. The size before
+and after is reported, and "repo status" shows when each repository was last maintained.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		tasks, _ := cmd.Flags().GetStringSlice("task")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if all == (len(args) == 1) {
+			return cerrors.NewInvalidArgument("name", "provide a repository name or --all")
+		}
+
+		app, err := getApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		results, err := app.Service.MaintainCanonicalRepos(cmd.Context(), args, tasks)
+		if err != nil {
+			return err
+		}
+
+		var (
+			firstErr error
+			freed    int64
+			failed   int
+		)
+
+		for _, res := range results {
+			if res.Error != "" {
+				failed++
+
+				if firstErr == nil {
+					firstErr = errors.New(res.Error)
+				}
+
+				continue
+			}
+
+			freed += res.FreedBytes()
+		}
+
+		if jsonOutput {
+			if err := output.PrintJSON(map[string]interface{}{
+				"repos":       results,
+				"freed_bytes": freed,
+			}); err != nil {
+				return err
+			}
+		} else {
+			for _, res := range results {
+				if res.Error != "" {
+					output.Warnf("Failed to maintain %s: %s", res.Name, res.Error)
+					continue
+				}
+
+				output.Infof("  %s: %s -> %s (%s)", res.Name, output.FormatBytes(res.SizeBeforeBytes),
+					output.FormatBytes(res.SizeAfterBytes), res.Duration.Round(time.Millisecond))
+			}
+
+			output.Success("Maintained repositories", fmt.Sprintf("%d maintained, %s freed", len(results)-failed, output.FormatBytes(freed)))
+		}
+
+		if firstErr != nil {
+			return cerrors.NewCommandFailed("repo maintain", firstErr)
+		}
+
+		return nil
+	},
+}
+
 var repoRemoveCmd = &cobra.Command{
 	Use:   "remove <NAME>",
 	Short: "Remove a canonical repository",
@@ -205,12 +325,29 @@ var repoRegisterCmd = &cobra.Command{
 		description, _ := cmd.Flags().GetString("description")
 		tagsRaw, _ := cmd.Flags().GetString("tags")
 		force, _ := cmd.Flags().GetBool("force")
+		syncStrategy, _ := cmd.Flags().GetString("sync-strategy")
+		sparseRaw, _ := cmd.Flags().GetString("sparse")
+		remotesRaw, _ := cmd.Flags().GetStringArray("remote")
+		pushRemote, _ := cmd.Flags().GetString("push-remote")
+		cloneOpts := cloneOptionsFromFlags(cmd)
+
+		remotes, err := parseRemotes(remotesRaw)
+		if err != nil {
+			return err
+		}
 
 		entry := config.RegistryEntry{
 			URL:           url,
 			DefaultBranch: branch,
 			Description:   description,
 			Tags:          parseTags(tagsRaw),
+			SyncStrategy:  syncStrategy,
+			SparsePaths:   parseTags(sparseRaw),
+			Filter:        cloneOpts.Filter,
+			Depth:         cloneOpts.Depth,
+			SingleBranch:  cloneOpts.SingleBranch,
+			Remotes:       remotes,
+			PushRemote:    pushRemote,
 		}
 
 		if err := app.Config.GetRegistry().Register(alias, entry, force); err != nil {
@@ -319,6 +456,21 @@ var repoShowCmd = &cobra.Command{
 		if len(entry.Tags) > 0 {
 			output.Infof("Tags:         %s", strings.Join(entry.Tags, ", "))
 		}
+		if entry.SyncStrategy != "" {
+			output.Infof("Sync:         %s", entry.SyncStrategy)
+		}
+		if len(entry.SparsePaths) > 0 {
+			output.Infof("Sparse:       %s", strings.Join(entry.SparsePaths, ", "))
+		}
+		if opts := entry.CloneOptions(); !opts.IsFull() {
+			output.Infof("Clone:        %s", formatCloneOptions(opts))
+		}
+		for _, remote := range entry.RemoteList() {
+			output.Infof("Remote:       %s %s", remote.Name, remote.URL)
+		}
+		if entry.PushRemote != "" {
+			output.Infof("Push Remote:  %s", entry.PushRemote)
+		}
 
 		repoName := giturl.ExtractRepoName(entry.URL)
 		canonicalPath := filepath.Join(app.Config.GetProjectsRoot(), repoName)
@@ -370,6 +522,14 @@ var repoStatusCmd = &cobra.Command{
 		}
 
 		printRepoStatusesTable(statuses)
+
+		lastRun, err := svc.LastFetchRun()
+		if err != nil {
+			return err
+		}
+
+		printLastFetchRun(lastRun)
+
 		return nil
 	},
 }
@@ -404,6 +564,26 @@ var repoPathCmd = &cobra.Command{
 	},
 }
 
+// parseRemotes parses repeated NAME=URL remote flags.
+func parseRemotes(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	remotes := make(map[string]string, len(values))
+
+	for _, value := range values {
+		name, url, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(name) == "" || strings.TrimSpace(url) == "" {
+			return nil, cerrors.NewInvalidArgument("remote", fmt.Sprintf("expected NAME=URL, got %q", value))
+		}
+
+		remotes[strings.TrimSpace(name)] = strings.TrimSpace(url)
+	}
+
+	return remotes, nil
+}
+
 func parseTags(raw string) []string {
 	if raw == "" {
 		return nil
@@ -546,6 +726,7 @@ func printSingleRepoStatus(status *domain.CanonicalRepoStatus) {
 	output.Infof("Repository:    %s", status.Name)
 	output.Infof("Path:          %s", status.Path)
 	output.Infof("Size:          %s", output.FormatBytes(status.DiskUsageBytes))
+	output.Infof("Clone Mode:    %s", status.CloneMode)
 
 	if status.LastFetchTime != nil {
 		output.Infof("Last Fetch:    %s", status.LastFetchTime.Format("2006-01-02 15:04:05"))
@@ -553,6 +734,20 @@ func printSingleRepoStatus(status *domain.CanonicalRepoStatus) {
 		output.Infof("Last Fetch:    never")
 	}
 
+	if status.LastMaintenanceTime != nil {
+		output.Infof("Last Maintain: %s", status.LastMaintenanceTime.Local().Format("2006-01-02 15:04:05"))
+	} else {
+		output.Infof("Last Maintain: never")
+	}
+
+	if bg := status.BackgroundFetch; bg != nil {
+		if bg.Error != "" {
+			output.Infof("Background:    %s (%s)", bg.Outcome, bg.Error)
+		} else {
+			output.Infof("Background:    %s", bg.Outcome)
+		}
+	}
+
 	if status.UsedByCount > 0 {
 		output.Infof("Workspaces:    %d (%s)", status.UsedByCount, strings.Join(status.UsedBy, ", "))
 	} else {
@@ -566,15 +761,19 @@ func printRepoStatusesTable(statuses []domain.CanonicalRepoStatus) {
 		return
 	}
 
-	output.Printf("%s %s %s %s\n",
+	output.Printf("%s %s %s %s %s %s\n",
 		output.Column("NAME", output.RepoNameWidth, output.AccentStyle),
 		output.Column("SIZE", output.RepoSizeWidth, output.MutedStyle),
+		output.Column("MODE", output.RepoCloneModeWidth, output.MutedStyle),
 		output.Column("LAST FETCH", output.RepoLastFetchWidth, output.MutedStyle),
+		output.Column("LAST MAINTAIN", output.RepoLastFetchWidth, output.MutedStyle),
 		output.Column("WORKSPACES", output.RepoWorkspacesWidth, output.MutedStyle),
 	)
-	output.Printf("%s %s %s %s\n",
+	output.Printf("%s %s %s %s %s %s\n",
 		output.SeparatorLine(output.RepoNameWidth),
 		output.SeparatorLine(output.RepoSizeWidth),
+		output.SeparatorLine(output.RepoCloneModeWidth),
+		output.SeparatorLine(output.RepoLastFetchWidth),
 		output.SeparatorLine(output.RepoLastFetchWidth),
 		output.SeparatorLine(output.RepoWorkspacesWidth),
 	)
@@ -585,10 +784,17 @@ func printRepoStatusesTable(statuses []domain.CanonicalRepoStatus) {
 			fetchTime = s.LastFetchTime.Format("2006-01-02 15:04")
 		}
 
-		output.Printf("%s %s %s %s\n",
+		maintainTime := "never"
+		if s.LastMaintenanceTime != nil {
+			maintainTime = s.LastMaintenanceTime.Local().Format("2006-01-02 15:04")
+		}
+
+		output.Printf("%s %s %s %s %s %s\n",
 			output.Column(s.Name, output.RepoNameWidth, lipgloss.NewStyle()),
 			output.Column(output.FormatBytes(s.DiskUsageBytes), output.RepoSizeWidth, lipgloss.NewStyle()),
+			output.Column(s.CloneMode.String(), output.RepoCloneModeWidth, lipgloss.NewStyle()),
 			output.Column(fetchTime, output.RepoLastFetchWidth, lipgloss.NewStyle()),
+			output.Column(maintainTime, output.RepoLastFetchWidth, lipgloss.NewStyle()),
 			output.Column(fmt.Sprintf("%d", s.UsedByCount), output.RepoWorkspacesWidth, lipgloss.NewStyle()),
 		)
 	}
@@ -596,12 +802,29 @@ func printRepoStatusesTable(statuses []domain.CanonicalRepoStatus) {
 	output.Infof("\n%d repositories", len(statuses))
 }
 
+// printLastFetchRun prints a one-line summary of the latest "canopy daemon" fetch.
+func printLastFetchRun(run *domain.FetchRun) {
+	if run == nil {
+		output.Infof("Background fetch: never (run 'canopy daemon')")
+		return
+	}
+
+	line := fmt.Sprintf("Background fetch: %s at %s", run.Summary(), run.FinishedAt.Local().Format("2006-01-02 15:04"))
+	if run.NextRunAt != nil {
+		line += fmt.Sprintf(", next at %s", run.NextRunAt.Local().Format("15:04"))
+	}
+
+	output.Info(line)
+}
+
 func init() {
 	rootCmd.AddCommand(repoCmd)
 	repoCmd.AddCommand(repoListCmd)
 	repoCmd.AddCommand(repoAddCmd)
 	repoCmd.AddCommand(repoRemoveCmd)
 	repoCmd.AddCommand(repoSyncCmd)
+	repoCmd.AddCommand(repoDeepenCmd)
+	repoCmd.AddCommand(repoMaintainCmd)
 	repoCmd.AddCommand(repoStatusCmd)
 	repoCmd.AddCommand(repoRegisterCmd)
 	repoCmd.AddCommand(repoUnregisterCmd)
@@ -616,11 +839,58 @@ func init() {
 	repoRemoveCmd.Flags().Bool("json", false, "Output in JSON format (use with --dry-run)")
 	repoAddCmd.Flags().String("alias", "", "Override derived alias when auto-registering")
 	repoAddCmd.Flags().Bool("no-register", false, "Skip auto-registration in the registry")
+	addCloneOptionFlags(repoAddCmd)
 	repoRegisterCmd.Flags().Bool("force", false, "Overwrite existing alias if present")
 	repoRegisterCmd.Flags().String("branch", "", "Default branch for the repository")
 	repoRegisterCmd.Flags().String("description", "", "Description for the repository")
 	repoRegisterCmd.Flags().String("tags", "", "Comma-separated tags for filtering")
+	repoRegisterCmd.Flags().String("sync-strategy", "", "Sync strategy for workspace sync: ff-only, rebase, merge, or fetch-only")
+	repoRegisterCmd.Flags().String("sparse", "", "Comma-separated directories to check out in new worktrees (sparse checkout)")
+	repoRegisterCmd.Flags().StringArray("remote", nil, "Extra remote configured in worktrees next to origin, as NAME=URL; worktrees pull from a remote named upstream (repeatable)")
+	repoRegisterCmd.Flags().String("push-remote", "", "Remote that workspace push uses (default: origin)")
+	addCloneOptionFlags(repoRegisterCmd)
+	repoDeepenCmd.Flags().Int("depth", 0, "Fetch this many more commits instead of the full history")
+	repoDeepenCmd.Flags().Bool("all-branches", false, "Fetch every branch of a single-branch clone")
+	repoDeepenCmd.Flags().Bool("blobs", false, "Fetch every object a partial clone left out and drop its filter")
+	repoMaintainCmd.Flags().Bool("all", false, "Maintain every canonical repository")
+	repoMaintainCmd.Flags().StringSlice("task", nil, "Maintenance task to run (repeatable; default: all of "+strings.Join(domain.MaintenanceTasks, ", ")+")")
+	repoMaintainCmd.Flags().Bool("json", false, "Output in JSON format")
 	repoListRegistryCmd.Flags().String("tags", "", "Filter registry entries by comma-separated tags")
 
 	repoStatusCmd.Flags().Bool("json", false, "Output in JSON format")
 }
+
+// addCloneOptionFlags adds the flags describing a partial, shallow or single-branch clone.
+func addCloneOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().String("filter", "", "Partial clone filter for the canonical repository, e.g. blob:none")
+	cmd.Flags().Int("depth", 0, "Clone only this many commits of history")
+	cmd.Flags().Bool("single-branch", false, "Clone only the default branch")
+}
+
+// cloneOptionsFromFlags reads the flags added by addCloneOptionFlags.
+func cloneOptionsFromFlags(cmd *cobra.Command) domain.CloneOptions {
+	filter, _ := cmd.Flags().GetString("filter")
+	depth, _ := cmd.Flags().GetInt("depth")
+	singleBranch, _ := cmd.Flags().GetBool("single-branch")
+
+	return domain.CloneOptions{Filter: filter, Depth: depth, SingleBranch: singleBranch}
+}
+
+// formatCloneOptions describes clone options such as "filter=blob:none, depth=1".
+func formatCloneOptions(opts domain.CloneOptions) string {
+	var parts []string
+
+	if opts.Filter != "" {
+		parts = append(parts, "filter="+opts.Filter)
+	}
+
+	if opts.Depth > 0 {
+		parts = append(parts, fmt.Sprintf("depth=%d", opts.Depth))
+	}
+
+	if opts.SingleBranch {
+		parts = append(parts, "single-branch")
+	}
+
+	return strings.Join(parts, ", ")
+}
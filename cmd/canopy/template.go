@@ -94,6 +94,9 @@ var templateShowCmd = &cobra.Command{
 		if tmpl.DefaultBranch != "" {
 			output.Infof("Default branch: %s", tmpl.DefaultBranch)
 		}
+		if tmpl.BranchNaming != "" {
+			output.Infof("Branch naming: %s", tmpl.BranchNaming)
+		}
 
 		if len(tmpl.Repos) == 0 {
 			output.Info("Repos: -")
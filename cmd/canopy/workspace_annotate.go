@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/workspaces"
+)
+
+// workspace_annotate.go defines the "workspace annotate" subcommand.
+
+var workspaceAnnotateCmd = &cobra.Command{
+	Use:   "annotate <ID>",
+	Short: "Set the description, labels and notes of a workspace",
+	Long: `Set the description, labels and free-form notes of a workspace.
+Without flags, the current annotations are shown. An empty --description or --notes clears the field.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		addLabels, _ := cmd.Flags().GetStringSlice("label")
+		removeLabels, _ := cmd.Flags().GetStringSlice("remove-label")
+		appendNote, _ := cmd.Flags().GetString("append-note")
+		notesFile, _ := cmd.Flags().GetString("notes-file")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		opts := workspaces.AnnotateOptions{
+			AddLabels:    addLabels,
+			RemoveLabels: removeLabels,
+			AppendNote:   appendNote,
+		}
+
+		if cmd.Flags().Changed("description") {
+			description, _ := cmd.Flags().GetString("description")
+			opts.Description = &description
+		}
+
+		if cmd.Flags().Changed("notes") {
+			notes, _ := cmd.Flags().GetString("notes")
+			opts.Notes = &notes
+		}
+
+		if notesFile != "" {
+			if opts.Notes != nil {
+				return cerrors.NewInvalidArgument("notes-file", "cannot use --notes with --notes-file")
+			}
+
+			data, err := os.ReadFile(notesFile) //nolint:gosec // user-provided file path
+			if err != nil {
+				return cerrors.NewIOFailed("read notes file", err)
+			}
+
+			notes := string(data)
+			opts.Notes = &notes
+		}
+
+		app, err := getApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		var ws *domain.Workspace
+
+		if opts.Description == nil && opts.Notes == nil && len(addLabels) == 0 && len(removeLabels) == 0 && appendNote == "" {
+			ws, _, err = app.Service.FindWorkspace(cmd.Context(), id)
+		} else {
+			ws, err = app.Service.AnnotateWorkspace(cmd.Context(), id, opts)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return output.PrintJSON(map[string]interface{}{
+				"workspace":   ws.ID,
+				"description": ws.Description,
+				"labels":      ws.Labels,
+				"notes":       ws.Notes,
+			})
+		}
+
+		output.Infof("Workspace: %s", ws.ID)
+		printAnnotations(*ws)
+
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceAnnotateCmd)
+
+	workspaceAnnotateCmd.Flags().String("description", "", "Short description of what the workspace is for")
+	workspaceAnnotateCmd.Flags().StringSlice("label", []string{}, "Label to add (repeatable)")
+	workspaceAnnotateCmd.Flags().StringSlice("remove-label", []string{}, "Label to remove (repeatable)")
+	workspaceAnnotateCmd.Flags().String("notes", "", "Replace the notes")
+	workspaceAnnotateCmd.Flags().String("notes-file", "", "Replace the notes with the contents of a file")
+	workspaceAnnotateCmd.Flags().String("append-note", "", "Add a line to the notes")
+	workspaceAnnotateCmd.Flags().Bool("json", false, "Output in JSON format")
+}
+
+// printAnnotations prints the description, labels and notes of a workspace, skipping empty ones.
+func printAnnotations(ws domain.Workspace) {
+	if ws.Description != "" {
+		output.Infof("Description: %s", ws.Description)
+	}
+
+	if len(ws.Labels) > 0 {
+		output.Infof("Labels: %s", strings.Join(ws.Labels, ", "))
+	}
+
+	if ws.Notes != "" {
+		output.Println("Notes:")
+
+		for _, line := range strings.Split(ws.Notes, "\n") {
+			output.Infof("  %s", line)
+		}
+	}
+}
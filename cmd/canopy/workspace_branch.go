@@ -17,14 +17,19 @@ var workspaceBranchCmd = &cobra.Command{
 	Short: "Switch branch for all repositories in a workspace",
 	Args: func(cmd *cobra.Command, args []string) error {
 		pattern, _ := cmd.Flags().GetString("pattern")
+		filterExpr, _ := cmd.Flags().GetString("filter")
 		all, _ := cmd.Flags().GetBool("all")
 		if all && pattern != "" {
 			return cerrors.NewInvalidArgument("pattern", "cannot use --pattern with --all")
 		}
 
-		if pattern != "" || all {
+		if all && filterExpr != "" {
+			return cerrors.NewInvalidArgument("filter", "cannot use --filter with --all")
+		}
+
+		if pattern != "" || filterExpr != "" || all {
 			if len(args) != 1 {
-				return cerrors.NewInvalidArgument("branch", "branch name is required when using --pattern or --all")
+				return cerrors.NewInvalidArgument("branch", "branch name is required when using --pattern, --filter or --all")
 			}
 
 			return nil
@@ -39,6 +44,7 @@ var workspaceBranchCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		create, _ := cmd.Flags().GetBool("create")
 		pattern, _ := cmd.Flags().GetString("pattern")
+		filterExpr, _ := cmd.Flags().GetString("filter")
 		all, _ := cmd.Flags().GetBool("all")
 
 		app, err := getApp(cmd)
@@ -52,9 +58,9 @@ var workspaceBranchCmd = &cobra.Command{
 			pattern = ".*"
 		}
 
-		if pattern != "" {
+		if pattern != "" || filterExpr != "" {
 			branchName := args[0]
-			matched, err := service.ListWorkspacesMatching(cmd.Context(), pattern)
+			matched, err := selectWorkspaces(cmd.Context(), service, pattern, filterExpr, defaultFilterStatusTimeout)
 			if err != nil {
 				return err
 			}
@@ -120,5 +126,6 @@ func init() {
 
 	workspaceBranchCmd.Flags().Bool("create", false, "Create branch if it doesn't exist")
 	workspaceBranchCmd.Flags().String("pattern", "", "Switch branches for workspaces matching a regex pattern")
+	workspaceBranchCmd.Flags().String("filter", "", "Switch branches for workspaces matching a filter expression (e.g. \"repo:backend\")")
 	workspaceBranchCmd.Flags().Bool("all", false, "Switch branches for all workspaces (equivalent to --pattern \".*\")")
 }
@@ -23,14 +23,19 @@ var workspaceCloseCmd = &cobra.Command{
 	Short: "Close a workspace (keep metadata or delete)",
 	Args: func(cmd *cobra.Command, args []string) error {
 		pattern, _ := cmd.Flags().GetString("pattern")
+		filterExpr, _ := cmd.Flags().GetString("filter")
 		all, _ := cmd.Flags().GetBool("all")
 		if all && pattern != "" {
 			return cerrors.NewInvalidArgument("pattern", "cannot use --pattern with --all")
 		}
 
-		if pattern != "" || all {
+		if all && filterExpr != "" {
+			return cerrors.NewInvalidArgument("filter", "cannot use --filter with --all")
+		}
+
+		if pattern != "" || filterExpr != "" || all {
 			if len(args) != 0 {
-				return cerrors.NewInvalidArgument("id", "cannot provide workspace ID with --pattern or --all")
+				return cerrors.NewInvalidArgument("id", "cannot provide workspace ID with --pattern, --filter or --all")
 			}
 
 			return nil
@@ -52,6 +57,7 @@ var workspaceCloseCmd = &cobra.Command{
 		hooksOnly, _ := cmd.Flags().GetBool("hooks-only")
 		dryRunHooks, _ := cmd.Flags().GetBool("dry-run-hooks")
 		pattern, _ := cmd.Flags().GetString("pattern")
+		filterExpr, _ := cmd.Flags().GetString("filter")
 		all, _ := cmd.Flags().GetBool("all")
 
 		if keepFlag && deleteFlag {
@@ -91,7 +97,7 @@ var workspaceCloseCmd = &cobra.Command{
 			pattern = ".*"
 		}
 
-		if pattern != "" {
+		if pattern != "" || filterExpr != "" {
 			if hooksOnly || dryRunHooks {
 				return cerrors.NewInvalidArgument("flags", "--hooks-only and --dry-run-hooks require a single workspace ID")
 			}
@@ -103,7 +109,7 @@ var workspaceCloseCmd = &cobra.Command{
 				keepMetadata = false
 			}
 
-			matched, err := service.ListWorkspacesMatching(cmd.Context(), pattern)
+			matched, err := selectWorkspaces(cmd.Context(), service, pattern, filterExpr, defaultFilterStatusTimeout)
 			if err != nil {
 				return err
 			}
@@ -267,7 +273,7 @@ var workspaceCloseCmd = &cobra.Command{
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, true, closeOpts, hookPreviews)
 			}
 
-			return keepAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return keepAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		}
 
 		if deleteFlag {
@@ -275,7 +281,7 @@ var workspaceCloseCmd = &cobra.Command{
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, false, closeOpts, hookPreviews)
 			}
 
-			return closeAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return closeAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		}
 
 		if !interactive {
@@ -284,14 +290,14 @@ var workspaceCloseCmd = &cobra.Command{
 					return closeWithHookDryRunJSON(cmd.Context(), service, id, force, true, closeOpts, hookPreviews)
 				}
 
-				return keepAndPrint(cmd.Context(), service, id, force, closeOpts)
+				return keepAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 			}
 
 			if dryRunHooks && jsonOutput {
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, false, closeOpts, hookPreviews)
 			}
 
-			return closeAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return closeAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		}
 
 		reader := bufio.NewReader(os.Stdin)
@@ -309,14 +315,14 @@ var workspaceCloseCmd = &cobra.Command{
 					return closeWithHookDryRunJSON(cmd.Context(), service, id, force, true, closeOpts, hookPreviews)
 				}
 
-				return keepAndPrint(cmd.Context(), service, id, force, closeOpts)
+				return keepAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 			}
 
 			if dryRunHooks && jsonOutput {
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, false, closeOpts, hookPreviews)
 			}
 
-			return closeAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return closeAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		}
 
 		answer = strings.ToLower(strings.TrimSpace(answer))
@@ -327,64 +333,99 @@ var workspaceCloseCmd = &cobra.Command{
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, true, closeOpts, hookPreviews)
 			}
 
-			return keepAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return keepAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		case "n", "no":
 			if dryRunHooks && jsonOutput {
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, false, closeOpts, hookPreviews)
 			}
 
-			return closeAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return closeAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		case "":
 			if configDefaultArchive {
 				if dryRunHooks && jsonOutput {
 					return closeWithHookDryRunJSON(cmd.Context(), service, id, force, true, closeOpts, hookPreviews)
 				}
 
-				return keepAndPrint(cmd.Context(), service, id, force, closeOpts)
+				return keepAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 			}
 
 			if dryRunHooks && jsonOutput {
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, false, closeOpts, hookPreviews)
 			}
 
-			return closeAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return closeAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		default:
 			if configDefaultArchive {
 				if dryRunHooks && jsonOutput {
 					return closeWithHookDryRunJSON(cmd.Context(), service, id, force, true, closeOpts, hookPreviews)
 				}
 
-				return keepAndPrint(cmd.Context(), service, id, force, closeOpts)
+				return keepAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 			}
 
 			if dryRunHooks && jsonOutput {
 				return closeWithHookDryRunJSON(cmd.Context(), service, id, force, false, closeOpts, hookPreviews)
 			}
 
-			return closeAndPrint(cmd.Context(), service, id, force, closeOpts)
+			return closeAndPrint(cmd.Context(), service, id, force, closeOpts, jsonOutput)
 		}
 	},
 }
 
-func keepAndPrint(ctx context.Context, service *workspaces.Service, id string, force bool, opts workspaces.CloseOptions) error {
+func keepAndPrint(ctx context.Context, service *workspaces.Service, id string, force bool, opts workspaces.CloseOptions, jsonOutput bool) error {
+	hookResults := []domain.HookResult{}
+	opts.HookResults = &hookResults
+
 	archived, err := service.CloseWorkspaceKeepMetadataWithOptions(ctx, id, force, opts)
 	if err != nil {
-		return err
+		return printHookFailureJSON(id, "close_keep", hookResults, err, jsonOutput)
 	}
 
-	var archivedAt *time.Time
+	var (
+		archivedAt *time.Time
+		archives   []domain.RepoArchive
+	)
+
 	if archived != nil {
 		archivedAt = archived.Metadata.ClosedAt
+		archives = archived.Metadata.Archives
+	}
+
+	if jsonOutput {
+		return output.PrintJSON(hookResultEnvelope{
+			Phase:       string(workspaces.HookPhasePreClose),
+			WorkspaceID: id,
+			Action:      "close_keep",
+			ClosedAt:    archivedAt,
+			Archives:    archives,
+			Hooks:       hookResults,
+		})
 	}
 
 	printClosed(id, archivedAt)
 
+	for _, entry := range archives {
+		output.Infof("  Saved %s", formatRepoArchive(entry))
+	}
+
 	return nil
 }
 
-func closeAndPrint(ctx context.Context, service *workspaces.Service, id string, force bool, opts workspaces.CloseOptions) error {
+func closeAndPrint(ctx context.Context, service *workspaces.Service, id string, force bool, opts workspaces.CloseOptions, jsonOutput bool) error {
+	hookResults := []domain.HookResult{}
+	opts.HookResults = &hookResults
+
 	if err := service.CloseWorkspaceWithOptions(ctx, id, force, opts); err != nil {
-		return err
+		return printHookFailureJSON(id, "close_delete", hookResults, err, jsonOutput)
+	}
+
+	if jsonOutput {
+		return output.PrintJSON(hookResultEnvelope{
+			Phase:       string(workspaces.HookPhasePreClose),
+			WorkspaceID: id,
+			Action:      "close_delete",
+			Hooks:       hookResults,
+		})
 	}
 
 	output.Success("Closed workspace", id)
@@ -392,6 +433,26 @@ func closeAndPrint(ctx context.Context, service *workspaces.Service, id string,
 	return nil
 }
 
+// printHookFailureJSON prints the pre_close hook results with the error that stopped the
+// close when JSON output was requested and hooks ran, and returns err.
+func printHookFailureJSON(id, action string, hookResults []domain.HookResult, err error, jsonOutput bool) error {
+	if !jsonOutput || len(hookResults) == 0 {
+		return err
+	}
+
+	if printErr := output.PrintJSON(hookResultEnvelope{
+		Phase:       string(workspaces.HookPhasePreClose),
+		WorkspaceID: id,
+		Action:      action,
+		Hooks:       hookResults,
+		Error:       err.Error(),
+	}); printErr != nil {
+		return printErr
+	}
+
+	return err
+}
+
 func isInteractiveTerminal() bool {
 	info, err := os.Stdin.Stat()
 	if err != nil {
@@ -408,10 +469,11 @@ func init() {
 	workspaceCloseCmd.Flags().Bool("keep", false, "Keep metadata (close without deleting)")
 	workspaceCloseCmd.Flags().Bool("delete", false, "Delete without keeping metadata")
 	workspaceCloseCmd.Flags().Bool("dry-run", false, "Preview what would be deleted without actually deleting")
-	workspaceCloseCmd.Flags().Bool("json", false, "Output in JSON format (use with --dry-run)")
+	workspaceCloseCmd.Flags().Bool("json", false, "Output in JSON format, including pre_close hook results")
 	workspaceCloseCmd.Flags().Bool("no-hooks", false, "Skip pre_close hooks")
 	workspaceCloseCmd.Flags().Bool("hooks-only", false, "Run pre_close hooks without closing the workspace")
 	workspaceCloseCmd.Flags().Bool("dry-run-hooks", false, "Preview pre_close hooks without executing them")
 	workspaceCloseCmd.Flags().String("pattern", "", "Close workspaces matching a regex pattern")
+	workspaceCloseCmd.Flags().String("filter", "", "Close workspaces matching a filter expression (e.g. \"stale and not dirty\")")
 	workspaceCloseCmd.Flags().Bool("all", false, "Close all workspaces (equivalent to --pattern \".*\")")
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+)
+
+// workspace_closed.go defines the "workspace closed" subcommands.
+
+var (
+	workspaceClosedCmd = &cobra.Command{
+		Use:   "closed",
+		Short: "Manage closed workspaces",
+	}
+
+	workspaceClosedShowCmd = &cobra.Command{
+		Use:   "show <ID>",
+		Short: "List every closed entry of a workspace",
+		Long: `List every closed entry of a workspace, newest first. The index or the close timestamp
+of an entry can be passed to "workspace reopen --at".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			app, err := getApp(cmd)
+			if err != nil {
+				return err
+			}
+
+			entries, err := app.Service.ListClosedEntries(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				payload := make([]map[string]interface{}, 0, len(entries))
+
+				for i, entry := range entries {
+					payload = append(payload, map[string]interface{}{
+						"index":            i,
+						"closed_at":        entry.ClosedAt(),
+						"path":             entry.Path,
+						"disk_usage_bytes": entry.Metadata.DiskUsageBytes,
+						"workspace":        entry.Metadata,
+					})
+				}
+
+				return output.PrintJSON(map[string]interface{}{
+					"workspace_id": id,
+					"entries":      payload,
+				})
+			}
+
+			for i, entry := range entries {
+				closedAt := entry.ClosedAt()
+
+				output.Infof("[%d] %s (%s ago, %s)", i, closedAt.Format(time.RFC3339), formatAge(closedAt),
+					output.FormatBytes(entry.Metadata.DiskUsageBytes))
+				output.Infof("    Branch: %s", entry.Metadata.BranchName)
+
+				for _, r := range entry.Metadata.Repos {
+					output.Infof("    - %s (%s)", r.Name, r.URL)
+				}
+
+				for _, archive := range entry.Metadata.Archives {
+					output.Infof("    Archived %s", formatRepoArchive(archive))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	workspaceClosedPruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Delete closed workspaces beyond the closed_retention limits",
+		Long: `Delete closed entries, including their archived patches and bundles, that exceed the
+closed_retention limits: entries older than max_age_days, entries beyond the newest
+max_per_workspace for a workspace ID, and the oldest entries until the rest fit in
+max_total_size. Pruned entries can no longer be reopened.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			app, err := getApp(cmd)
+			if err != nil {
+				return err
+			}
+
+			if !app.Config.GetClosedRetention().Enabled() {
+				output.Warnf("closed_retention is not configured; nothing to prune")
+			}
+
+			candidates, err := app.Service.PlanClosedPrune(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if candidates == nil {
+				candidates = []domain.ClosedPruneCandidate{}
+			}
+
+			if dryRun {
+				if jsonOutput {
+					return output.PrintJSON(map[string]interface{}{
+						"dry_run": true,
+						"pruned":  candidates,
+					})
+				}
+
+				for _, candidate := range candidates {
+					printClosedPruneCandidate(candidate, "Would delete")
+				}
+
+				output.Infof("%d closed entries would be pruned", len(candidates))
+
+				return nil
+			}
+
+			results := app.Service.PruneClosed(cmd.Context(), candidates)
+
+			var (
+				pruned   []domain.ClosedPruneCandidate
+				failed   []map[string]string
+				firstErr error
+				freed    int64
+			)
+
+			for i, res := range results {
+				if res.Err != nil {
+					if firstErr == nil {
+						firstErr = res.Err
+					}
+
+					failed = append(failed, map[string]string{"workspace_id": res.WorkspaceID, "error": res.Err.Error()})
+
+					if !jsonOutput {
+						output.Warnf("Failed to prune %s: %v", res.WorkspaceID, res.Err)
+					}
+
+					continue
+				}
+
+				pruned = append(pruned, candidates[i])
+				freed += candidates[i].DiskUsageBytes
+
+				if !jsonOutput {
+					printClosedPruneCandidate(candidates[i], "Deleted")
+				}
+			}
+
+			if jsonOutput {
+				if err := output.PrintJSON(map[string]interface{}{
+					"pruned": pruned,
+					"failed": failed,
+				}); err != nil {
+					return err
+				}
+			} else {
+				output.Success("Pruned closed workspaces", fmt.Sprintf("%d deleted, %s freed", len(pruned), output.FormatBytes(freed)))
+			}
+
+			if firstErr != nil {
+				return cerrors.NewCommandFailed("workspace closed prune", firstErr)
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	workspaceCmd.AddCommand(workspaceClosedCmd)
+	workspaceClosedCmd.AddCommand(workspaceClosedShowCmd)
+	workspaceClosedCmd.AddCommand(workspaceClosedPruneCmd)
+
+	workspaceClosedShowCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	workspaceClosedPruneCmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting it")
+	workspaceClosedPruneCmd.Flags().Bool("json", false, "Output in JSON format")
+}
+
+// printClosedPruneCandidate prints a closed entry and the retention limit it exceeds.
+func printClosedPruneCandidate(candidate domain.ClosedPruneCandidate, action string) {
+	output.Infof("  %s %s (Closed: %s, %s ago, %s; %s)", action, candidate.WorkspaceID,
+		candidate.ClosedAt.Format(time.RFC3339), formatAge(candidate.ClosedAt),
+		output.FormatBytes(candidate.DiskUsageBytes), candidate.Reason)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -34,6 +36,8 @@ var workspaceListCmd = &cobra.Command{
 		parallelStatus, _ := cmd.Flags().GetBool("parallel-status")
 		sequentialStatus, _ := cmd.Flags().GetBool("sequential-status")
 		timeoutStr, _ := cmd.Flags().GetString("timeout")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		filterExpr, _ := cmd.Flags().GetString("filter")
 
 		// Parse timeout duration.
 		timeout := 5 * time.Second
@@ -45,12 +49,20 @@ var workspaceListCmd = &cobra.Command{
 			}
 		}
 
+		if closedOnly && filterExpr != "" {
+			return cerrors.NewInvalidArgument("flags", "cannot use --filter with --closed")
+		}
+
 		if closedOnly {
 			archives, err := service.ListClosedWorkspaces(cmd.Context())
 			if err != nil {
 				return err
 			}
 
+			archives = slices.DeleteFunc(archives, func(a domain.ClosedWorkspace) bool {
+				return !a.Metadata.HasLabels(labels...)
+			})
+
 			if jsonOutput {
 				payload := make([]domain.Workspace, 0, len(archives))
 
@@ -66,10 +78,10 @@ var workspaceListCmd = &cobra.Command{
 			for _, a := range archives {
 				closedDate := "unknown"
 				if a.Metadata.ClosedAt != nil {
-					closedDate = a.Metadata.ClosedAt.Format(time.RFC3339)
+					closedDate = fmt.Sprintf("%s, %s ago", a.Metadata.ClosedAt.Format(time.RFC3339), formatAge(*a.Metadata.ClosedAt))
 				}
 
-				output.Infof("%s (Closed: %s)", a.Metadata.ID, closedDate)
+				output.Infof("%s (Closed: %s, Size: %s)", a.Metadata.ID, closedDate, output.FormatBytes(a.Metadata.DiskUsageBytes))
 				for _, r := range a.Metadata.Repos {
 					output.Infof("  - %s (%s)", r.Name, r.URL)
 				}
@@ -83,6 +95,26 @@ var workspaceListCmd = &cobra.Command{
 			return err
 		}
 
+		list = slices.DeleteFunc(list, func(w domain.Workspace) bool {
+			return !w.HasLabels(labels...)
+		})
+
+		if filterExpr != "" {
+			matched, err := selectWorkspaces(cmd.Context(), service, "", filterExpr, timeout)
+			if err != nil {
+				return err
+			}
+
+			matchedIDs := make(map[string]bool, len(matched))
+			for _, ws := range matched {
+				matchedIDs[ws.ID] = true
+			}
+
+			list = slices.DeleteFunc(list, func(w domain.Workspace) bool {
+				return !matchedIDs[w.ID]
+			})
+		}
+
 		sort.Slice(list, func(i, j int) bool {
 			return list[i].ID < list[j].ID
 		})
@@ -209,7 +241,15 @@ var workspaceListCmd = &cobra.Command{
 				lockSuffix = " [locked]"
 			}
 
-			output.Infof("%s (Branch: %s)%s", ws.ID, ws.BranchName, lockSuffix)
+			labelText := ""
+			if len(ws.Labels) > 0 {
+				labelText = ", Labels: " + strings.Join(ws.Labels, ", ")
+			}
+
+			output.Infof("%s (Branch: %s%s)%s", ws.ID, ws.BranchName, labelText, lockSuffix)
+			if ws.Description != "" {
+				output.Infof("  %s", ws.Description)
+			}
 			for _, r := range ws.Repos {
 				if showStatus {
 					status, ok := statusByRepo[r.Name]
@@ -237,4 +277,6 @@ func init() {
 	workspaceListCmd.Flags().Bool("sequential-status", false, "Fetch workspace status sequentially")
 	workspaceListCmd.Flags().String("timeout", "5s", "Timeout for status check per workspace (e.g. 5s, 10s)")
 	workspaceListCmd.Flags().Bool("show-locks", false, "Show workspace lock status")
+	workspaceListCmd.Flags().String("filter", "", "Only list workspaces matching a filter expression (e.g. \"stale and not dirty\")")
+	workspaceListCmd.Flags().StringSlice("label", []string{}, "Only list workspaces with this label (repeatable; all must match)")
 }
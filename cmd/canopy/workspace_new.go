@@ -30,6 +30,9 @@ var workspaceNewCmd = &cobra.Command{
 		dryRunHooks, _ := cmd.Flags().GetBool("dry-run-hooks")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		templateName, _ := cmd.Flags().GetString("template")
+		noIssue, _ := cmd.Flags().GetBool("no-issue")
+		description, _ := cmd.Flags().GetString("description")
+		labels, _ := cmd.Flags().GetStringSlice("label")
 
 		if hooksOnly && noHooks {
 			return cerrors.NewInvalidArgument("flags", "cannot use --hooks-only with --no-hooks")
@@ -43,8 +46,8 @@ var workspaceNewCmd = &cobra.Command{
 			return cerrors.NewInvalidArgument("flags", "cannot use --dry-run-hooks with --hooks-only")
 		}
 
-		if jsonOutput && !dryRunHooks {
-			return cerrors.NewInvalidArgument("flags", "--json is only supported with --dry-run-hooks")
+		if jsonOutput && printPath {
+			return cerrors.NewInvalidArgument("flags", "cannot use --json with --print-path")
 		}
 
 		app, err := getApp(cmd)
@@ -68,6 +71,10 @@ var workspaceNewCmd = &cobra.Command{
 				return cerrors.NewInvalidArgument("flags", "--hooks-only cannot be combined with --print-path")
 			}
 
+			if jsonOutput {
+				return cerrors.NewInvalidArgument("flags", "--hooks-only cannot be combined with --json")
+			}
+
 			if err := service.RunHooks(cmd.Context(), id, workspaces.HookPhasePostCreate, false); err != nil {
 				return err
 			}
@@ -110,18 +117,46 @@ var workspaceNewCmd = &cobra.Command{
 			}
 		}
 
+		// A failed issue lookup is not fatal: not every workspace is for an issue
+		var issue *domain.Issue
+		if !noIssue {
+			issue, err = service.LookupIssue(cmd.Context(), id)
+			if err != nil {
+				output.Warnf("Failed to fetch issue %s: %v", id, err)
+			}
+		}
+
+		hookResults := []domain.HookResult{}
 		opts := workspaces.CreateOptions{
-			SkipHooks: noHooks || dryRunHooks,
-			Template:  templatePtr,
+			SkipHooks:   noHooks || dryRunHooks,
+			Template:    templatePtr,
+			HookResults: &hookResults,
+			Issue:       issue,
+			Description: description,
+			Labels:      labels,
 		}
 
 		dirName, err := service.CreateWorkspaceWithOptions(cmd.Context(), id, branch, resolvedRepos, opts)
+		workspacePath := filepath.Join(cfg.GetWorkspacesRoot(), dirName)
+
 		if err != nil {
+			// Report the hook that failed, with its exit code and output, before the error
+			if jsonOutput && len(hookResults) > 0 {
+				if printErr := output.PrintJSON(hookResultEnvelope{
+					Phase:         string(workspaces.HookPhasePostCreate),
+					WorkspaceID:   id,
+					WorkspacePath: workspacePath,
+					Action:        "create",
+					Hooks:         hookResults,
+					Error:         err.Error(),
+				}); printErr != nil {
+					return printErr
+				}
+			}
+
 			return err
 		}
 
-		workspacePath := filepath.Join(cfg.GetWorkspacesRoot(), dirName)
-
 		if dryRunHooks {
 			previews, err := service.PreviewHooks(cmd.Context(), id, workspaces.HookPhasePostCreate)
 			if err != nil {
@@ -142,10 +177,24 @@ var workspaceNewCmd = &cobra.Command{
 			printHookPreview(string(workspaces.HookPhasePostCreate), previews)
 		}
 
+		if jsonOutput {
+			return output.PrintJSON(hookResultEnvelope{
+				Phase:         string(workspaces.HookPhasePostCreate),
+				WorkspaceID:   id,
+				WorkspacePath: workspacePath,
+				Action:        "create",
+				Hooks:         hookResults,
+			})
+		}
+
 		if printPath {
 			output.Printf("%s", workspacePath)
 		} else {
 			output.SuccessWithPath("Created workspace", id, workspacePath)
+
+			if issue != nil {
+				output.Infof("Issue: %s %s", issue.Title, issue.URL)
+			}
 		}
 		return nil
 	},
@@ -160,8 +209,11 @@ func init() {
 	workspaceNewCmd.Flags().Bool("no-hooks", false, "Skip post_create hooks")
 	workspaceNewCmd.Flags().Bool("hooks-only", false, "Run post_create hooks without creating the workspace")
 	workspaceNewCmd.Flags().Bool("dry-run-hooks", false, "Preview post_create hooks without executing them")
-	workspaceNewCmd.Flags().Bool("json", false, "Output in JSON format (use with --dry-run-hooks)")
+	workspaceNewCmd.Flags().Bool("json", false, "Output in JSON format, including post_create hook results")
 	workspaceNewCmd.Flags().String("template", "", "Workspace template to apply")
+	workspaceNewCmd.Flags().Bool("no-issue", false, "Do not look up the workspace ID in the issue tracker")
+	workspaceNewCmd.Flags().String("description", "", "Short description of what the workspace is for")
+	workspaceNewCmd.Flags().StringSlice("label", []string{}, "Label to attach to the workspace (repeatable)")
 }
 
 func mergeTemplateRepos(templateRepos, explicitRepos []string) []string {
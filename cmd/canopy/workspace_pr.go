@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/workspaces"
+)
+
+// workspace_pr.go defines the "workspace pr" subcommands.
+
+var (
+	workspacePRCmd = &cobra.Command{
+		Use:   "pr",
+		Short: "Manage pull requests for a workspace",
+	}
+
+	workspacePRCreateCmd = &cobra.Command{
+		Use:   "create <ID>",
+		Short: "Open a pull request in every repository of a workspace",
+		Long: `Open one pull request per repository from the workspace branch, with a shared title and body.
+Each description is then extended with links to the pull requests opened in the other repositories.
+
+The forge (GitHub, GitLab or Gitea) and the owner/repository are derived from each repository's
+upstream URL. github.com and gitlab.com work out of the box; other hosts are configured under
+"forges". API tokens are read from GITHUB_TOKEN (or GH_TOKEN), GITLAB_TOKEN, GITEA_TOKEN, or the
+variable named by the forge's token_env. Push the workspace branch first.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			title, _ := cmd.Flags().GetString("title")
+			body, _ := cmd.Flags().GetString("body")
+			bodyFile, _ := cmd.Flags().GetString("body-file")
+			base, _ := cmd.Flags().GetString("base")
+			draft, _ := cmd.Flags().GetBool("draft")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			if bodyFile != "" {
+				if body != "" {
+					return cerrors.NewInvalidArgument("body-file", "cannot use --body with --body-file")
+				}
+
+				data, err := os.ReadFile(bodyFile) //nolint:gosec // user-provided file path
+				if err != nil {
+					return cerrors.NewIOFailed("read body file", err)
+				}
+
+				body = string(data)
+			}
+
+			app, err := getApp(cmd)
+			if err != nil {
+				return err
+			}
+
+			result, err := app.Service.CreatePullRequests(cmd.Context(), id, workspaces.PullRequestOptions{
+				Title: title,
+				Body:  body,
+				Draft: draft,
+				Base:  base,
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if err := output.PrintJSON(result); err != nil {
+					return err
+				}
+			} else {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+				_, _ = fmt.Fprintln(w, "REPOSITORY\tNUMBER\tURL\tERROR")
+
+				for _, pr := range result.PullRequests {
+					number := "-"
+					if pr.Number != 0 {
+						number = fmt.Sprintf("#%d", pr.Number)
+					}
+
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pr.Repo, number, pr.URL, strings.Join(strings.Fields(pr.Error), " "))
+				}
+
+				_ = w.Flush()
+			}
+
+			if result.TotalErrors > 0 {
+				return cerrors.NewCommandFailed("pr create", fmt.Errorf("%d repos failed", result.TotalErrors))
+			}
+
+			if !jsonOutput {
+				output.Success("\nOpened pull requests", fmt.Sprintf("%d repositories", result.TotalCreated))
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	workspaceCmd.AddCommand(workspacePRCmd)
+	workspacePRCmd.AddCommand(workspacePRCreateCmd)
+
+	workspacePRCreateCmd.Flags().String("title", "", "Pull request title (required)")
+	workspacePRCreateCmd.Flags().String("body", "", "Pull request description")
+	workspacePRCreateCmd.Flags().String("body-file", "", "Read the pull request description from a file")
+	workspacePRCreateCmd.Flags().String("base", "", "Branch to merge into (default: each repository's default branch)")
+	workspacePRCreateCmd.Flags().Bool("draft", false, "Open draft pull requests")
+	workspacePRCreateCmd.Flags().Bool("json", false, "Output in JSON format")
+	_ = workspacePRCreateCmd.MarkFlagRequired("title")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/workspaces"
+)
+
+// workspace_push.go defines the "workspace push" subcommand.
+
+var workspacePushCmd = &cobra.Command{
+	Use:   "push <ID>",
+	Short: "Push the workspace branch in all repositories",
+	Long: `Push the workspace branch in every repository to its push remote (origin unless the registry
+sets push_remote, e.g. to a fork), running pre_push and post_push hooks.
+Use --remote to push elsewhere, --set-upstream to push to (and track) a differently named remote
+branch, --force-with-lease to rewrite branches that have not moved since the last fetch, and
+-o to send push options to the server. A branch's upstream only changes with --set-upstream or
+when it had none, and only once the push succeeded.
+With --atomic, every repository is first checked with a dry-run push and nothing is pushed unless
+all of them would succeed. If a push still fails midway, the remote branches already pushed are
+restored to their previous commit (or deleted if they did not exist before).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		atomic, _ := cmd.Flags().GetBool("atomic")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		remote, _ := cmd.Flags().GetString("remote")
+		remoteBranch, _ := cmd.Flags().GetString("set-upstream")
+		forceWithLease, _ := cmd.Flags().GetBool("force-with-lease")
+		pushOptions, _ := cmd.Flags().GetStringArray("push-option")
+
+		app, err := getApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := workspaces.PushOptions{
+			Atomic:         atomic,
+			Remote:         remote,
+			RemoteBranch:   remoteBranch,
+			ForceWithLease: forceWithLease,
+			PushOptions:    pushOptions,
+		}
+
+		result, err := app.Service.PushWorkspaceWithOptions(cmd.Context(), id, opts)
+		if result == nil {
+			return err
+		}
+
+		if jsonOutput {
+			if printErr := output.PrintJSON(result); printErr != nil {
+				return printErr
+			}
+
+			return err
+		}
+
+		printPushResult(result)
+
+		if err != nil {
+			return err
+		}
+
+		output.Success("\nWorkspace push completed", fmt.Sprintf("%d repositories pushed", result.TotalPushed))
+
+		return nil
+	},
+}
+
+func printPushResult(result *domain.PushResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REPOSITORY\tSTATUS\tDETAILS")
+
+	for _, r := range result.Repos {
+		details := strings.Join(strings.Fields(r.Error), " ")
+		runes := []rune(details)
+
+		if len(runes) > 100 {
+			details = string(runes[:97]) + "..."
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, strings.ToUpper(string(r.Status)), details)
+	}
+
+	_ = w.Flush()
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspacePushCmd)
+
+	workspacePushCmd.Flags().Bool("atomic", false, "Pre-flight all repositories and roll back pushed branches if any push fails")
+	workspacePushCmd.Flags().Bool("json", false, "Output in JSON format")
+	workspacePushCmd.Flags().String("remote", "", "Remote to push to in every repository (default: each repository's push remote)")
+	workspacePushCmd.Flags().String("set-upstream", "", "Push to and track a differently named remote branch")
+	workspacePushCmd.Flags().Bool("force-with-lease", false, "Allow rewriting remote branches that have not moved since the last fetch")
+	workspacePushCmd.Flags().StringArrayP("push-option", "o", nil, "Push option to send to the server (repeatable)")
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/workspaces"
+)
+
+// workspace_query.go holds the workspace selection shared by --pattern and --filter.
+
+// defaultFilterStatusTimeout bounds the status lookup of each workspace for filters such as "dirty".
+const defaultFilterStatusTimeout = 5 * time.Second
+
+// selectWorkspaces returns the workspaces matching a pattern and/or filter expression,
+// warning about workspaces skipped because the filter could not be evaluated.
+func selectWorkspaces(ctx context.Context, service *workspaces.Service, pattern, filterExpr string, statusTimeout time.Duration) ([]domain.Workspace, error) {
+	result, err := service.QueryWorkspaces(ctx, workspaces.WorkspaceQuery{
+		Pattern:       pattern,
+		Filter:        filterExpr,
+		StatusTimeout: statusTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, skipped := range result.Skipped {
+		output.Warnf("Skipping workspace %s: status unavailable: %v", skipped.WorkspaceID, skipped.Err)
+	}
+
+	return result.Workspaces, nil
+}
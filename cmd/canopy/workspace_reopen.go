@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/alexisbeaulieu97/canopy/internal/output"
+	"github.com/alexisbeaulieu97/canopy/internal/workspaces"
 )
 
 // workspace_reopen.go defines the "workspace reopen" subcommand.
@@ -12,20 +13,36 @@ var workspaceReopenCmd = &cobra.Command{
 	Use:     "reopen <ID>",
 	Aliases: []string{"open"},
 	Short:   "Reopen a closed workspace",
-	Args:    cobra.ExactArgs(1),
+	Long: `Reopen a closed workspace from its newest closed entry, or from the entry selected with
+--at: an index as listed by "workspace closed show" (0 is the newest) or the close timestamp.
+Use --as to restore it under a different ID.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		force, _ := cmd.Flags().GetBool("force")
+		at, _ := cmd.Flags().GetString("at")
+		as, _ := cmd.Flags().GetString("as")
+		renameBranch, _ := cmd.Flags().GetBool("rename-branch")
 
 		app, err := getApp(cmd)
 		if err != nil {
 			return err
 		}
 
-		if err := app.Service.RestoreWorkspace(cmd.Context(), id, force); err != nil {
+		if err := app.Service.RestoreWorkspaceWithOptions(cmd.Context(), id, workspaces.RestoreOptions{
+			Force:        force,
+			At:           at,
+			As:           as,
+			RenameBranch: renameBranch,
+		}); err != nil {
 			return err
 		}
 
+		if as != "" && as != id {
+			output.Success("Restored workspace", id+" as "+as)
+			return nil
+		}
+
 		output.Success("Restored workspace", id)
 		return nil
 	},
@@ -35,4 +52,7 @@ func init() {
 	workspaceCmd.AddCommand(workspaceReopenCmd)
 
 	workspaceReopenCmd.Flags().Bool("force", false, "Close and restore the workspace, replacing an active workspace with the same ID if it exists")
+	workspaceReopenCmd.Flags().String("at", "", "Closed entry to restore: index from 'workspace closed show' (0 is the newest) or close timestamp")
+	workspaceReopenCmd.Flags().String("as", "", "Restore the workspace under a different ID")
+	workspaceReopenCmd.Flags().Bool("rename-branch", true, "With --as, rename branches that match the old workspace ID")
 }
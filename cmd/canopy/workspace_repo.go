@@ -1,8 +1,13 @@
 package main
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/spf13/cobra"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
 	"github.com/alexisbeaulieu97/canopy/internal/output"
 )
 
@@ -61,8 +66,79 @@ var workspaceRepoRemoveCmd = &cobra.Command{
 	},
 }
 
+var workspaceRepoSparseCmd = &cobra.Command{
+	Use:   "sparse <WORKSPACE-ID> <REPO-NAME> [add|remove <PATH>...]",
+	Short: "Show or change the sparse checkout directories of a repository",
+	Long: `Show or change the directories checked out in a repository of a workspace, using
+cone-mode sparse checkout. Files at the repository root are always checked out.
+Removing every directory checks out the full tree; adding a directory to a full
+checkout restricts it to that directory.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspaceID := args[0]
+		repoName := args[1]
+
+		app, err := getApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 2 {
+			ws, _, err := app.Service.FindWorkspace(cmd.Context(), workspaceID)
+			if err != nil {
+				return err
+			}
+
+			index := slices.IndexFunc(ws.Repos, func(r domain.Repo) bool { return r.Name == repoName })
+			if index == -1 {
+				return cerrors.NewRepoNotFound(repoName).WithContext("workspace_id", workspaceID)
+			}
+
+			printSparsePaths(repoName, ws.Repos[index].SparsePaths)
+
+			return nil
+		}
+
+		action, paths := args[2], args[3:]
+		if len(paths) == 0 {
+			return cerrors.NewInvalidArgument("path", "at least one path is required")
+		}
+
+		var add, remove []string
+
+		switch action {
+		case "add":
+			add = paths
+		case "remove":
+			remove = paths
+		default:
+			return cerrors.NewInvalidArgument("action", "must be add or remove, got "+action)
+		}
+
+		updated, err := app.Service.UpdateSparsePaths(cmd.Context(), workspaceID, repoName, add, remove)
+		if err != nil {
+			return err
+		}
+
+		printSparsePaths(repoName, updated)
+
+		return nil
+	},
+}
+
 func init() {
 	workspaceCmd.AddCommand(workspaceRepoCmd)
 	workspaceRepoCmd.AddCommand(workspaceRepoAddCmd)
 	workspaceRepoCmd.AddCommand(workspaceRepoRemoveCmd)
+	workspaceRepoCmd.AddCommand(workspaceRepoSparseCmd)
+}
+
+// printSparsePaths prints the sparse checkout directories of a repository.
+func printSparsePaths(repoName string, paths []string) {
+	if len(paths) == 0 {
+		output.Infof("%s: full checkout", repoName)
+		return
+	}
+
+	output.Infof("%s: sparse checkout of %s", repoName, strings.Join(paths, ", "))
 }
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/output"
+)
+
+// workspace_snapshot.go defines the "workspace snapshot" and "workspace rollback" subcommands.
+
+var (
+	workspaceSnapshotCmd = &cobra.Command{
+		Use:   "snapshot <ID> [NAME]",
+		Short: "Record a checkpoint of all repositories in a workspace",
+		Long: `Record the HEAD commit and uncommitted changes, untracked files included, of every
+repository in a workspace. The name defaults to the current UTC timestamp. Use --list to show
+existing snapshots and --delete to remove one. Snapshots are deleted with the workspace.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			list, _ := cmd.Flags().GetBool("list")
+			deleteSnapshot, _ := cmd.Flags().GetBool("delete")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			app, err := getApp(cmd)
+			if err != nil {
+				return err
+			}
+
+			if list {
+				ws, _, err := app.Service.FindWorkspace(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+
+				if jsonOutput {
+					return output.PrintJSON(map[string]interface{}{
+						"workspace": id,
+						"snapshots": ws.Snapshots,
+					})
+				}
+
+				if len(ws.Snapshots) == 0 {
+					output.Infof("No snapshots for workspace %s", id)
+					return nil
+				}
+
+				for _, snapshot := range ws.Snapshots {
+					output.Infof("%s  %s  (%d repos)", snapshot.Name, snapshot.CreatedAt.Format(time.RFC3339), len(snapshot.Repos))
+				}
+
+				return nil
+			}
+
+			var name string
+			if len(args) == 2 {
+				name = args[1]
+			}
+
+			if deleteSnapshot {
+				if name == "" {
+					return cerrors.NewInvalidArgument("name", "--delete requires a snapshot name")
+				}
+
+				if err := app.Service.DeleteSnapshot(cmd.Context(), id, name); err != nil {
+					return err
+				}
+
+				output.Success("Deleted snapshot", fmt.Sprintf("%s of workspace %s", name, id))
+
+				return nil
+			}
+
+			snapshot, err := app.Service.SnapshotWorkspace(cmd.Context(), id, name)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return output.PrintJSON(snapshot)
+			}
+
+			output.Success("Created snapshot", fmt.Sprintf("%s for workspace %s", snapshot.Name, id))
+			printSnapshotRepos(snapshot)
+
+			return nil
+		},
+	}
+
+	workspaceRollbackCmd = &cobra.Command{
+		Use:   "rollback <ID> <SNAPSHOT>",
+		Short: "Restore all repositories in a workspace to a snapshot",
+		Long: `Reset every repository to the HEAD recorded in a snapshot and restore its uncommitted changes
+and untracked files. Repositories with uncommitted changes or untracked files are refused unless
+--force is passed, which discards them.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, name := args[0], args[1]
+			force, _ := cmd.Flags().GetBool("force")
+
+			app, err := getApp(cmd)
+			if err != nil {
+				return err
+			}
+
+			snapshot, err := app.Service.RollbackWorkspace(cmd.Context(), id, name, force)
+			if err != nil {
+				return err
+			}
+
+			output.Success("Rolled back workspace", fmt.Sprintf("%s to snapshot %s", id, snapshot.Name))
+			printSnapshotRepos(snapshot)
+
+			return nil
+		},
+	}
+)
+
+func printSnapshotRepos(snapshot *domain.Snapshot) {
+	for _, repo := range snapshot.Repos {
+		state := "clean"
+		if repo.Stash != "" {
+			state = "with uncommitted changes"
+		}
+
+		output.Infof("  - %s: %s (%s)", repo.Repo, shortSHA(repo.Head), state)
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+
+	return sha
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceSnapshotCmd)
+	workspaceCmd.AddCommand(workspaceRollbackCmd)
+
+	workspaceSnapshotCmd.Flags().Bool("list", false, "List existing snapshots instead of creating one")
+	workspaceSnapshotCmd.Flags().Bool("delete", false, "Delete the named snapshot instead of creating one")
+	workspaceSnapshotCmd.Flags().Bool("json", false, "Output in JSON format")
+	workspaceRollbackCmd.Flags().Bool("force", false, "Discard uncommitted changes in repositories before rolling back")
+}
@@ -4,12 +4,12 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"sync"
 	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/alexisbeaulieu97/canopy/internal/config"
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
 	"github.com/alexisbeaulieu97/canopy/internal/output"
@@ -23,17 +23,27 @@ var workspaceSyncCmd = &cobra.Command{
 	Short: "Pull updates for all repositories in a workspace",
 	Long: `Pull updates for all repositories in a workspace and display a summary.
 Per-repository timeouts can be configured to prevent slow remotes from blocking the entire operation.
+The --strategy flag overrides the sync strategy configured per repository or template
+(ff-only, rebase, merge, or fetch-only). Dirty worktrees are autostashed while integrating.
+With --onto-default, each worktree branch is brought up to date with origin/<default branch>
+(upstream/<default branch> when the repository has an upstream remote) instead of its own
+upstream, which is useful for fresh feature branches.
 Bulk sync continues across workspaces and exits non-zero if any workspace fails.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		pattern, _ := cmd.Flags().GetString("pattern")
+		filterExpr, _ := cmd.Flags().GetString("filter")
 		all, _ := cmd.Flags().GetBool("all")
 		if all && pattern != "" {
 			return cerrors.NewInvalidArgument("pattern", "cannot use --pattern with --all")
 		}
 
-		if pattern != "" || all {
+		if all && filterExpr != "" {
+			return cerrors.NewInvalidArgument("filter", "cannot use --filter with --all")
+		}
+
+		if pattern != "" || filterExpr != "" || all {
 			if len(args) != 0 {
-				return cerrors.NewInvalidArgument("id", "cannot provide workspace ID with --pattern or --all")
+				return cerrors.NewInvalidArgument("id", "cannot provide workspace ID with --pattern, --filter or --all")
 			}
 
 			return nil
@@ -49,7 +59,14 @@ Bulk sync continues across workspaces and exits non-zero if any workspace fails.
 		timeoutStr, _ := cmd.Flags().GetString("timeout")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		pattern, _ := cmd.Flags().GetString("pattern")
+		filterExpr, _ := cmd.Flags().GetString("filter")
 		all, _ := cmd.Flags().GetBool("all")
+		strategy, _ := cmd.Flags().GetString("strategy")
+		ontoDefault, _ := cmd.Flags().GetBool("onto-default")
+
+		if strategy != "" && !domain.SyncStrategy(strategy).IsValid() {
+			return cerrors.NewInvalidArgument("strategy", fmt.Sprintf("must be one of %s", config.SyncStrategyNames()))
+		}
 
 		var timeout time.Duration
 		if timeoutStr != "" {
@@ -66,15 +83,17 @@ Bulk sync continues across workspaces and exits non-zero if any workspace fails.
 		}
 
 		opts := workspaces.SyncOptions{
-			Timeout: timeout,
+			Timeout:     timeout,
+			Strategy:    domain.SyncStrategy(strategy),
+			OntoDefault: ontoDefault,
 		}
 
 		if all {
 			pattern = ".*"
 		}
 
-		if pattern != "" {
-			matched, err := app.Service.ListWorkspacesMatching(cmd.Context(), pattern)
+		if pattern != "" || filterExpr != "" {
+			matched, err := selectWorkspaces(cmd.Context(), app.Service, pattern, filterExpr, defaultFilterStatusTimeout)
 			if err != nil {
 				return err
 			}
@@ -94,77 +113,30 @@ Bulk sync continues across workspaces and exits non-zero if any workspace fails.
 				output.Infof("  - %s", id)
 			}
 
-			type syncJob struct {
-				index int
-				id    string
-			}
-			type syncResult struct {
-				index  int
-				id     string
-				result *domain.SyncResult
-				err    error
-			}
-
-			jobs := make(chan syncJob, len(ids))
-			results := make(chan syncResult, len(ids))
-
-			for i, id := range ids {
-				jobs <- syncJob{index: i, id: id}
-			}
-			close(jobs)
-
-			numWorkers := app.Config.GetParallelWorkers()
-			if numWorkers <= 0 {
-				numWorkers = 1
-			}
-			if numWorkers > len(ids) {
-				numWorkers = len(ids)
-			}
-
-			var wg sync.WaitGroup
-			for w := 0; w < numWorkers; w++ {
-				wg.Add(1)
-				go func() {
-					defer wg.Done()
-					for job := range jobs {
-						result, syncErr := app.Service.SyncWorkspace(cmd.Context(), job.id, opts)
-						results <- syncResult{
-							index:  job.index,
-							id:     job.id,
-							result: result,
-							err:    syncErr,
-						}
-					}
-				}()
+			bulk, err := app.Service.SyncWorkspaces(cmd.Context(), matched, opts)
+			if bulk == nil {
+				return err
 			}
 
-			go func() {
-				wg.Wait()
-				close(results)
-			}()
-
-			orderedResults := make([]syncResult, len(ids))
-			done := 0
-			for res := range results {
-				done++
-				orderedResults[res.index] = res
-				if res.err != nil {
-					output.Warnf("Workspace %s sync failed (%d/%d): %v", res.id, done, len(ids), res.err)
+			orderedResults := bulk.Results
+			for i, res := range orderedResults {
+				if res.Err != nil {
+					output.Warnf("Workspace %s sync failed (%d/%d): %v", res.WorkspaceID, i+1, len(orderedResults), res.Err)
 					continue
 				}
-				output.Infof("Synced workspace %s (%d/%d)", res.id, done, len(ids))
+				output.Infof("Synced workspace %s (%d/%d)", res.WorkspaceID, i+1, len(orderedResults))
 			}
 
 			if jsonOutput {
 				payload := make([]map[string]interface{}, 0, len(orderedResults))
 				for _, res := range orderedResults {
 					errText := ""
-					if res.err != nil {
-						errText = res.err.Error()
+					if res.Err != nil {
+						errText = res.Err.Error()
 					}
 					payload = append(payload, map[string]interface{}{
-						"workspace_id": res.id,
-						"result":       res.result,
+						"workspace_id": res.WorkspaceID,
+						"result":       res.Result,
 						"error":        errText,
 					})
 				}
@@ -183,13 +155,13 @@ Bulk sync continues across workspaces and exits non-zero if any workspace fails.
 				errorsCount := 0
 				details := ""
 
-				if res.err != nil {
+				if res.Err != nil {
 					status = "ERROR"
-					details = res.err.Error()
+					details = res.Err.Error()
 					failed++
-				} else if res.result != nil {
-					updated = res.result.TotalUpdated
-					errorsCount = res.result.TotalErrors
+				} else if res.Result != nil {
+					updated = res.Result.TotalUpdated
+					errorsCount = res.Result.TotalErrors
 					totalUpdated += updated
 					if errorsCount > 0 {
 						status = "PARTIAL"
@@ -206,7 +178,7 @@ Bulk sync continues across workspaces and exits non-zero if any workspace fails.
 					details = string(runes[:97]) + "..."
 				}
 
-				_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", res.id, status, updated, errorsCount, details)
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", res.WorkspaceID, status, updated, errorsCount, details)
 			}
 			_ = w.Flush()
 
@@ -242,8 +214,15 @@ Bulk sync continues across workspaces and exits non-zero if any workspace fails.
 				updatedStr = "-"
 			}
 
-			// Sanitize error message to prevent breaking tabwriter layout.
 			errDetail := r.Error
+			if errDetail == "" && r.Onto != "" {
+				errDetail = "onto " + r.Onto
+			}
+			if len(r.Conflicts) > 0 {
+				errDetail = fmt.Sprintf("%s: %s", errDetail, strings.Join(r.Conflicts, ", "))
+			}
+
+			// Sanitize error message to prevent breaking tabwriter layout.
 			errDetail = strings.ReplaceAll(errDetail, "\n", " ")
 			errDetail = strings.ReplaceAll(errDetail, "\r", " ")
 			errDetail = strings.ReplaceAll(errDetail, "\t", " ")
@@ -272,5 +251,8 @@ func init() {
 	workspaceSyncCmd.Flags().String("timeout", "60s", "Timeout for each repository sync (e.g. 30s, 2m)")
 	workspaceSyncCmd.Flags().Bool("json", false, "Output in JSON format")
 	workspaceSyncCmd.Flags().String("pattern", "", "Sync workspaces matching a regex pattern")
+	workspaceSyncCmd.Flags().String("filter", "", "Sync workspaces matching a filter expression (e.g. \"label:urgent and not dirty\")")
 	workspaceSyncCmd.Flags().Bool("all", false, "Sync all workspaces (equivalent to --pattern \".*\")")
+	workspaceSyncCmd.Flags().Bool("onto-default", false, "Integrate origin/<default branch> into each worktree branch")
+	workspaceSyncCmd.Flags().String("strategy", "", "Sync strategy: ff-only, rebase, merge, or fetch-only (default: per-repo or template config, then ff-only)")
 }
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	"github.com/alexisbeaulieu97/canopy/internal/output"
 )
 
@@ -17,6 +19,7 @@ var workspaceViewCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		jsonOutput, _ := cmd.Flags().GetBool("json")
+		noReview, _ := cmd.Flags().GetBool("no-review")
 
 		app, err := getApp(cmd)
 		if err != nil {
@@ -30,16 +33,36 @@ var workspaceViewCmd = &cobra.Command{
 			return err
 		}
 
+		ws, _, err := service.FindWorkspace(cmd.Context(), id)
+		if err != nil {
+			return err
+		}
+
+		if !noReview {
+			reviews, err := service.GetReviewStatus(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			for i := range status.Repos {
+				status.Repos[i].ReviewStatus = reviews[status.Repos[i].Name]
+			}
+		}
+
 		if jsonOutput {
 			return output.PrintJSON(map[string]interface{}{
-				"workspace": status.ID,
-				"branch":    status.BranchName,
-				"repos":     status.Repos,
+				"workspace":   status.ID,
+				"branch":      status.BranchName,
+				"description": ws.Description,
+				"labels":      ws.Labels,
+				"notes":       ws.Notes,
+				"repos":       status.Repos,
 			})
 		}
 
 		output.Infof("Workspace: %s", status.ID)
 		output.Infof("Branch: %s", status.BranchName)
+		printAnnotations(*ws)
 
 		output.Println("Repositories:")
 		for _, r := range status.Repos {
@@ -53,6 +76,10 @@ var workspaceViewCmd = &cobra.Command{
 				statusStr = "Dirty"
 			}
 			output.Infof("  - %s: %s (Branch: %s, Unpushed: %d)", r.Name, statusStr, r.Branch, r.UnpushedCommits)
+
+			if r.ReviewStatus != nil {
+				output.Infof("      %s", formatReviewStatus(*r.ReviewStatus))
+			}
 		}
 		return nil
 	},
@@ -62,4 +89,27 @@ func init() {
 	workspaceCmd.AddCommand(workspaceViewCmd)
 
 	workspaceViewCmd.Flags().Bool("json", false, "Output in JSON format")
+	workspaceViewCmd.Flags().Bool("no-review", false, "Skip fetching pull request review and CI status from the forge")
+}
+
+// formatReviewStatus describes a repository's pull request on one line.
+func formatReviewStatus(r domain.ReviewStatus) string {
+	if r.Error != "" {
+		return "Pull request: Error: " + strings.ReplaceAll(r.Error, "\n", " ")
+	}
+
+	parts := []string{string(r.State)}
+	if r.Draft {
+		parts = append(parts, "draft")
+	}
+
+	if r.ReviewDecision != "" {
+		parts = append(parts, "review: "+strings.ReplaceAll(string(r.ReviewDecision), "_", " "))
+	}
+
+	if r.Checks != "" {
+		parts = append(parts, "checks: "+string(r.Checks))
+	}
+
+	return fmt.Sprintf("Pull request #%d: %s %s", r.Number, strings.Join(parts, ", "), r.URL)
 }
@@ -116,10 +116,12 @@ func New(debug bool, opts ...Option) (*App, error) {
 		wsEngine = storage.NewWithNaming(cfg.GetWorkspacesRoot(), cfg.GetClosedRoot(), cfg.ComputeWorkspaceDir)
 	}
 
+	fetchStatus := storage.NewFetchStatusFile(cfg.GetProjectsRoot())
+
 	return &App{
 		Config:  cfg,
 		Logger:  logger,
-		Service: workspaces.NewService(cfg, gitEngine, wsEngine, logger),
+		Service: workspaces.NewService(cfg, gitEngine, wsEngine, logger, workspaces.WithFetchStatusStore(fetchStatus)),
 	}, nil
 }
 
@@ -46,16 +46,87 @@
 //	      repos: ["frontend"]
 //	  pre_close:
 //	    - command: "git stash"
+//	  pre_push:
+//	    - command: "make test"
+//
+// Supported phases are post_create, pre_close, post_sync, pre_push, post_push,
+// post_rename, post_restore and post_repo_add.
+//
+// # Branch Naming
+//
+// Branch names default to the workspace ID. A branch naming template builds them
+// from the ID, the git user name, the date, the template name and custom variables;
+// workspace patterns and templates can override it:
+//
+//	branch_naming: "{{slug .User}}/{{.ID}}"
+//	branch_vars:
+//	  team: core
+//	templates:
+//	  hotfix:
+//	    repos: ["backend"]
+//	    branch_naming: "hotfix/{{.Date}}-{{.ID | lower}}"
+//
+// # Forges
+//
+// Forges tell Canopy which API serves a git host, for commands such as
+// "workspace pr create". github.com and gitlab.com work without configuration:
+//
+//	forges:
+//	  - host: git.example.com
+//	    type: gitea
+//	    token_env: GITEA_TOKEN
+//
+// # Issue Tracker
+//
+// An issue tracker lets "workspace new" fetch the title of the issue a workspace
+// ID refers to, store it in the workspace metadata, and use it in the branch name:
+//
+//	issue_tracker:
+//	  type: jira
+//	  url: https://example.atlassian.net
+//	  branch_naming: "{{.ID}}-{{slug .Title}}"
+//
+// # Garbage Collection
+//
+// The gc section sets which workspaces "canopy gc" closes by default, and
+// which labels protect a workspace from it:
+//
+//	gc:
+//	  criteria: [merged, remote_deleted]
+//	  exclude_labels: [keep]
+//
+// # Closed Retention
+//
+// The closed_retention section limits what "workspace closed prune" keeps under
+// closed_root. Each limit is disabled when zero or empty:
+//
+//	closed_retention:
+//	  max_age_days: 90
+//	  max_per_workspace: 3
+//	  max_total_size: 2GB
+//
+// # Background Fetch
+//
+// The daemon section sets how often "canopy daemon" fetches every canonical
+// repository, and the random delay added to each interval:
+//
+//	daemon:
+//	  fetch_interval: 15m
+//	  fetch_jitter: 1m
 //
 // See the configuration documentation for complete reference.
 package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -63,6 +134,7 @@ import (
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
 	"github.com/alexisbeaulieu97/canopy/internal/validation"
 )
@@ -86,18 +158,76 @@ var (
 
 // Hook defines a single lifecycle hook command.
 type Hook struct {
-	Command         string   `mapstructure:"command"`
-	Description     string   `mapstructure:"description,omitempty"`       // human-readable description
-	Repos           []string `mapstructure:"repos,omitempty"`             // filter to specific repos
-	Shell           string   `mapstructure:"shell,omitempty"`             // default: sh -c
-	Timeout         int      `mapstructure:"timeout,omitempty"`           // default: 30 seconds
-	ContinueOnError bool     `mapstructure:"continue_on_error,omitempty"` // don't fail workspace operation
+	Name            string            `mapstructure:"name,omitempty"` // referenced by needs
+	Command         string            `mapstructure:"command"`
+	Description     string            `mapstructure:"description,omitempty"`       // human-readable description
+	Repos           []string          `mapstructure:"repos,omitempty"`             // filter to specific repos
+	Shell           string            `mapstructure:"shell,omitempty"`             // default: sh -c
+	Timeout         int               `mapstructure:"timeout,omitempty"`           // default: 30 seconds
+	ContinueOnError bool              `mapstructure:"continue_on_error,omitempty"` // don't fail workspace operation
+	Env             map[string]string `mapstructure:"env,omitempty"`               // templated, added after CANOPY_* variables
+	WorkingDir      string            `mapstructure:"working_dir,omitempty"`       // relative to the workspace or repo directory
+	When            HookCondition     `mapstructure:"when,omitempty"`              // skip the hook unless all conditions match
+	Parallel        bool              `mapstructure:"parallel,omitempty"`          // run across repos concurrently
+	Needs           []string          `mapstructure:"needs,omitempty"`             // names of hooks that must succeed first
+}
+
+// HookCondition restricts when a hook runs. Every non-empty field must match.
+// Repo conditions apply to each repo for repo-filtered hooks; for workspace-level
+// hooks, file_exists is relative to the workspace and repo_tag needs any repo to match.
+type HookCondition struct {
+	FileExists  string `mapstructure:"file_exists,omitempty"`  // relative path that must exist
+	WorkspaceID string `mapstructure:"workspace_id,omitempty"` // regex matched against the workspace ID
+	Branch      string `mapstructure:"branch,omitempty"`       // glob matched against the branch name
+	RepoTag     string `mapstructure:"repo_tag,omitempty"`     // registry tag the repo must carry
+}
+
+// IsZero reports whether no condition is set.
+func (c HookCondition) IsZero() bool {
+	return c == HookCondition{}
 }
 
 // Hooks holds lifecycle hook configurations.
 type Hooks struct {
-	PostCreate []Hook `mapstructure:"post_create"`
-	PreClose   []Hook `mapstructure:"pre_close"`
+	PostCreate  []Hook `mapstructure:"post_create"`
+	PreClose    []Hook `mapstructure:"pre_close"`
+	PostSync    []Hook `mapstructure:"post_sync"`
+	PrePush     []Hook `mapstructure:"pre_push"`
+	PostPush    []Hook `mapstructure:"post_push"`
+	PostRename  []Hook `mapstructure:"post_rename"`
+	PostRestore []Hook `mapstructure:"post_restore"`
+	PostRepoAdd []Hook `mapstructure:"post_repo_add"`
+}
+
+// PhaseHooks pairs a lifecycle phase name with its configured hooks.
+type PhaseHooks struct {
+	Phase string
+	Hooks []Hook
+}
+
+// Phases returns the hooks of every lifecycle phase in a stable order.
+func (h Hooks) Phases() []PhaseHooks {
+	return []PhaseHooks{
+		{Phase: "post_create", Hooks: h.PostCreate},
+		{Phase: "pre_close", Hooks: h.PreClose},
+		{Phase: "post_sync", Hooks: h.PostSync},
+		{Phase: "pre_push", Hooks: h.PrePush},
+		{Phase: "post_push", Hooks: h.PostPush},
+		{Phase: "post_rename", Hooks: h.PostRename},
+		{Phase: "post_restore", Hooks: h.PostRestore},
+		{Phase: "post_repo_add", Hooks: h.PostRepoAdd},
+	}
+}
+
+// ForPhase returns the hooks configured for a phase and whether the phase is known.
+func (h Hooks) ForPhase(phase string) ([]Hook, bool) {
+	for _, ph := range h.Phases() {
+		if ph.Phase == phase {
+			return ph.Hooks, true
+		}
+	}
+
+	return nil, false
 }
 
 // Keybindings holds TUI keybinding configurations.
@@ -178,23 +308,192 @@ type GitConfig struct {
 	Retry GitRetrySettings `mapstructure:"retry"`
 }
 
+// Supported forge types.
+const (
+	ForgeTypeGitHub = "github"
+	ForgeTypeGitLab = "gitlab"
+	ForgeTypeGitea  = "gitea"
+)
+
+// ForgeConfig maps a git host to the API of the forge serving it.
+// github.com and gitlab.com are known without configuration.
+type ForgeConfig struct {
+	Host     string `mapstructure:"host"`      // Host from repository URLs, e.g. git.example.com
+	Type     string `mapstructure:"type"`      // github, gitlab or gitea
+	APIURL   string `mapstructure:"api_url"`   // Defaults to the public API for the type and host
+	TokenEnv string `mapstructure:"token_env"` // Environment variable holding the API token
+}
+
+// Supported issue tracker types.
+const (
+	IssueTrackerJira   = "jira"
+	IssueTrackerGitHub = "github"
+	IssueTrackerLinear = "linear"
+	IssueTrackerHTTP   = "http"
+)
+
+// IssueTrackerConfig selects the issue tracker that workspace IDs refer to.
+// An empty Type disables issue lookups.
+type IssueTrackerConfig struct {
+	Type         string `mapstructure:"type"`          // jira, github, linear or http
+	URL          string `mapstructure:"url"`           // Jira site, API base URL, or HTTP endpoint containing {id}
+	Repository   string `mapstructure:"repository"`    // owner/name, for GitHub Issues
+	TokenEnv     string `mapstructure:"token_env"`     // Environment variable holding the API token
+	UserEnv      string `mapstructure:"user_env"`      // Jira Cloud: environment variable holding the account email
+	BranchNaming string `mapstructure:"branch_naming"` // Branch name template, e.g. {{.ID}}-{{slug .Title}}
+}
+
+// GCConfig is the garbage collection policy applied by "canopy gc".
+type GCConfig struct {
+	Criteria      []domain.GCReason `mapstructure:"criteria"`       // stale, merged, remote_deleted; empty means all
+	ExcludeLabels []string          `mapstructure:"exclude_labels"` // Workspaces with any of these labels are never collected
+}
+
+// ClosedRetentionConfig limits the closed entries kept under closed_root.
+// Zero or empty values disable a limit.
+type ClosedRetentionConfig struct {
+	MaxAgeDays      int    `mapstructure:"max_age_days"`      // Entries closed longer ago are pruned
+	MaxPerWorkspace int    `mapstructure:"max_per_workspace"` // Newest entries kept per workspace ID
+	MaxTotalSize    string `mapstructure:"max_total_size"`    // Size of all entries, e.g. 500MB or 2GB; oldest are pruned first
+}
+
+// Enabled reports whether any limit is set.
+func (r ClosedRetentionConfig) Enabled() bool {
+	return r.MaxAgeDays > 0 || r.MaxPerWorkspace > 0 || strings.TrimSpace(r.MaxTotalSize) != ""
+}
+
+// MaxTotalBytes returns MaxTotalSize in bytes, or 0 when it is not set.
+func (r ClosedRetentionConfig) MaxTotalBytes() (int64, error) {
+	if strings.TrimSpace(r.MaxTotalSize) == "" {
+		return 0, nil
+	}
+
+	size, err := ParseByteSize(r.MaxTotalSize)
+	if err != nil {
+		return 0, cerrors.NewConfigValidation("closed_retention.max_total_size", err.Error())
+	}
+
+	return size, nil
+}
+
+// byteSizeUnits maps size suffixes to their multiplier, using powers of 1024 like output.FormatBytes.
+var byteSizeUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+}
+
+// ParseByteSize parses a size such as "512MB", "1.5GB" or "1024".
+func ParseByteSize(value string) (int64, error) {
+	trimmed := strings.ToUpper(strings.TrimSpace(value))
+	split := strings.IndexFunc(trimmed, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+
+	number, unit := trimmed, ""
+	if split >= 0 {
+		number, unit = trimmed[:split], strings.TrimSpace(trimmed[split:])
+	}
+
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q in %q (use B, KB, MB, GB or TB)", unit, value)
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+
+	return int64(n * float64(multiplier)), nil
+}
+
+// DaemonConfig configures the background fetch run by "canopy daemon".
+// This is the config-file representation; use ParsedDaemonConfig for runtime use.
+type DaemonConfig struct {
+	FetchInterval string `mapstructure:"fetch_interval"` // Time between fetches of every canonical repository, e.g. "15m"
+	FetchJitter   string `mapstructure:"fetch_jitter"`   // Up to this much random delay is added to each interval
+}
+
+// ParsedDaemonConfig holds the parsed daemon configuration.
+type ParsedDaemonConfig struct {
+	FetchInterval time.Duration
+	FetchJitter   time.Duration
+}
+
+// Parse converts the duration strings of DaemonConfig, using the defaults for empty values.
+func (d DaemonConfig) Parse() (ParsedDaemonConfig, error) {
+	parsed := ParsedDaemonConfig{FetchInterval: DefaultFetchInterval, FetchJitter: DefaultFetchJitter}
+
+	if strings.TrimSpace(d.FetchInterval) != "" {
+		interval, err := time.ParseDuration(d.FetchInterval)
+		if err != nil {
+			return ParsedDaemonConfig{}, cerrors.NewConfigValidation("daemon.fetch_interval", fmt.Sprintf("invalid duration %q: %v", d.FetchInterval, err))
+		}
+
+		if interval <= 0 {
+			return ParsedDaemonConfig{}, cerrors.NewConfigValidation("daemon.fetch_interval", fmt.Sprintf("must be positive, got %s", d.FetchInterval))
+		}
+
+		parsed.FetchInterval = interval
+	}
+
+	if strings.TrimSpace(d.FetchJitter) != "" {
+		jitter, err := time.ParseDuration(d.FetchJitter)
+		if err != nil {
+			return ParsedDaemonConfig{}, cerrors.NewConfigValidation("daemon.fetch_jitter", fmt.Sprintf("invalid duration %q: %v", d.FetchJitter, err))
+		}
+
+		if jitter < 0 {
+			return ParsedDaemonConfig{}, cerrors.NewConfigValidation("daemon.fetch_jitter", fmt.Sprintf("must be zero or positive, got %s", d.FetchJitter))
+		}
+
+		parsed.FetchJitter = jitter
+	}
+
+	return parsed, nil
+}
+
+// BranchNamingTemplateData defines the data available to branch naming templates.
+type BranchNamingTemplateData struct {
+	ID string
+	// Title is the title of the issue the workspace ID refers to, if any.
+	Title string
+	// User is user.name from the git config.
+	User string
+	// Date is the creation date as YYYY-MM-DD.
+	Date string
+	// Template is the name of the workspace template, if any.
+	Template string
+	// Vars holds branch_vars, with the template's branch_vars merged over the global ones.
+	Vars map[string]string
+}
+
 // Config holds the global configuration
 type Config struct {
-	ProjectsRoot       string              `mapstructure:"projects_root"`
-	WorkspacesRoot     string              `mapstructure:"workspaces_root"`
-	ClosedRoot         string              `mapstructure:"closed_root"`
-	CloseDefault       string              `mapstructure:"workspace_close_default"`
-	WorkspaceNaming    string              `mapstructure:"workspace_naming"`
-	StaleThresholdDays int                 `mapstructure:"stale_threshold_days"`
-	ParallelWorkers    int                 `mapstructure:"parallel_workers"`
-	LockTimeout        string              `mapstructure:"lock_timeout"`
-	LockStaleThreshold string              `mapstructure:"lock_stale_threshold"`
-	Defaults           Defaults            `mapstructure:"defaults"`
-	Templates          map[string]Template `mapstructure:"templates"`
-	Hooks              Hooks               `mapstructure:"hooks"`
-	TUI                TUIConfig           `mapstructure:"tui"`
-	Git                GitConfig           `mapstructure:"git"`
-	Registry           *RepoRegistry       `mapstructure:"-"`
+	ProjectsRoot       string                `mapstructure:"projects_root"`
+	WorkspacesRoot     string                `mapstructure:"workspaces_root"`
+	ClosedRoot         string                `mapstructure:"closed_root"`
+	CloseDefault       string                `mapstructure:"workspace_close_default"`
+	WorkspaceNaming    string                `mapstructure:"workspace_naming"`
+	BranchNaming       string                `mapstructure:"branch_naming"`
+	BranchVars         map[string]string     `mapstructure:"branch_vars"`
+	StaleThresholdDays int                   `mapstructure:"stale_threshold_days"`
+	ParallelWorkers    int                   `mapstructure:"parallel_workers"`
+	LockTimeout        string                `mapstructure:"lock_timeout"`
+	LockStaleThreshold string                `mapstructure:"lock_stale_threshold"`
+	Defaults           Defaults              `mapstructure:"defaults"`
+	Templates          map[string]Template   `mapstructure:"templates"`
+	Hooks              Hooks                 `mapstructure:"hooks"`
+	TUI                TUIConfig             `mapstructure:"tui"`
+	Git                GitConfig             `mapstructure:"git"`
+	Forges             []ForgeConfig         `mapstructure:"forges"`
+	IssueTracker       IssueTrackerConfig    `mapstructure:"issue_tracker"`
+	GC                 GCConfig              `mapstructure:"gc"`
+	ClosedRetention    ClosedRetentionConfig `mapstructure:"closed_retention"`
+	Daemon             DaemonConfig          `mapstructure:"daemon"`
+	Registry           *RepoRegistry         `mapstructure:"-"`
 }
 
 // WorkspaceNamingTemplateData defines the data available to workspace naming templates.
@@ -204,8 +503,9 @@ type WorkspaceNamingTemplateData struct {
 
 // WorkspacePattern defines a regex pattern and default repos
 type WorkspacePattern struct {
-	Pattern string   `mapstructure:"pattern"`
-	Repos   []string `mapstructure:"repos"`
+	Pattern      string   `mapstructure:"pattern"`
+	Repos        []string `mapstructure:"repos"`
+	BranchNaming string   `mapstructure:"branch_naming"` // Overrides branch_naming for matching IDs
 }
 
 // Defaults holds default configurations
@@ -220,6 +520,13 @@ type Template struct {
 	DefaultBranch string   `mapstructure:"default_branch"`
 	Description   string   `mapstructure:"description"`
 	SetupCommands []string `mapstructure:"setup_commands"`
+	SyncStrategy  string   `mapstructure:"sync_strategy"`
+	// BranchNaming overrides branch_naming for workspaces created from the template.
+	BranchNaming string `mapstructure:"branch_naming"`
+	// BranchVars are merged over the global branch_vars.
+	BranchVars map[string]string `mapstructure:"branch_vars"`
+	// SparsePaths maps a repo of the template to the directories checked out in its worktree.
+	SparsePaths map[string][]string `mapstructure:"sparse_paths"`
 }
 
 // knownConfigFields contains all valid top-level and nested config field names
@@ -230,6 +537,8 @@ var knownConfigFields = []string{
 	"closed_root",
 	"workspace_close_default",
 	"workspace_naming",
+	"branch_naming",
+	"branch_vars",
 	"stale_threshold_days",
 	"parallel_workers",
 	"lock_timeout",
@@ -241,9 +550,19 @@ var knownConfigFields = []string{
 	"templates.default_branch",
 	"templates.description",
 	"templates.setup_commands",
+	"templates.sync_strategy",
+	"templates.branch_naming",
+	"templates.branch_vars",
+	"templates.sparse_paths",
 	"hooks",
 	"hooks.post_create",
 	"hooks.pre_close",
+	"hooks.post_sync",
+	"hooks.pre_push",
+	"hooks.post_push",
+	"hooks.post_rename",
+	"hooks.post_restore",
+	"hooks.post_repo_add",
 	"tui",
 	"tui.keybindings",
 	"tui.use_emoji",
@@ -254,6 +573,28 @@ var knownConfigFields = []string{
 	"git.retry.max_delay",
 	"git.retry.multiplier",
 	"git.retry.jitter_factor",
+	"forges",
+	"forges.host",
+	"forges.type",
+	"forges.api_url",
+	"forges.token_env",
+	"issue_tracker",
+	"issue_tracker.type",
+	"issue_tracker.url",
+	"issue_tracker.repository",
+	"issue_tracker.token_env",
+	"issue_tracker.user_env",
+	"issue_tracker.branch_naming",
+	"gc",
+	"gc.criteria",
+	"gc.exclude_labels",
+	"closed_retention",
+	"closed_retention.max_age_days",
+	"closed_retention.max_per_workspace",
+	"closed_retention.max_total_size",
+	"daemon",
+	"daemon.fetch_interval",
+	"daemon.fetch_jitter",
 	// Hook fields
 	"command",
 	"description",
@@ -261,6 +602,16 @@ var knownConfigFields = []string{
 	"shell",
 	"timeout",
 	"continue_on_error",
+	"env",
+	"working_dir",
+	"when",
+	"file_exists",
+	"workspace_id",
+	"branch",
+	"repo_tag",
+	"name",
+	"parallel",
+	"needs",
 	// Keybinding fields
 	"quit",
 	"search",
@@ -444,6 +795,10 @@ func Load(configPath string) (*Config, error) {
 	viper.SetDefault("git.retry.multiplier", 2.0)
 	viper.SetDefault("git.retry.jitter_factor", 0.25)
 
+	// Background fetch defaults
+	viper.SetDefault("daemon.fetch_interval", DefaultFetchInterval.String())
+	viper.SetDefault("daemon.fetch_jitter", DefaultFetchJitter.String())
+
 	viper.SetEnvPrefix("CANOPY")
 	// Replace dots with underscores for nested keys (e.g., CANOPY_GIT_RETRY_MAX_ATTEMPTS)
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -648,7 +1003,11 @@ func (c *Config) validateWorkspaceSettings() error {
 		return err
 	}
 
-	return c.validateTemplates()
+	if err := c.validateTemplates(); err != nil {
+		return err
+	}
+
+	return c.validateBranchNaming()
 }
 
 func (c *Config) validateRuntimeSettings() error {
@@ -668,6 +1027,26 @@ func (c *Config) validateRuntimeSettings() error {
 		return err
 	}
 
+	if err := c.validateForges(); err != nil {
+		return err
+	}
+
+	if err := c.validateIssueTracker(); err != nil {
+		return err
+	}
+
+	if err := c.validateGC(); err != nil {
+		return err
+	}
+
+	if err := c.validateClosedRetention(); err != nil {
+		return err
+	}
+
+	if _, err := c.Daemon.Parse(); err != nil {
+		return err
+	}
+
 	return c.validateLockSettings()
 }
 
@@ -692,6 +1071,57 @@ func (c *Config) validatePatterns() error {
 	return nil
 }
 
+// validateBranchNaming renders every branch naming template with a sample workspace so that
+// template errors and invalid branch names are reported by config validation, not on first use.
+func (c *Config) validateBranchNaming() error {
+	if c.BranchNaming != "" {
+		if _, err := RenderBranchName("branch_naming", c.BranchNaming, c.sampleBranchNamingData(nil)); err != nil {
+			return err
+		}
+	}
+
+	for i, p := range c.Defaults.WorkspacePatterns {
+		if p.BranchNaming == "" {
+			continue
+		}
+
+		field := fmt.Sprintf("defaults.workspace_patterns[%d].branch_naming", i)
+		if _, err := RenderBranchName(field, p.BranchNaming, c.sampleBranchNamingData(nil)); err != nil {
+			return err
+		}
+	}
+
+	for name, tmpl := range c.GetTemplates() {
+		if tmpl.BranchNaming == "" {
+			continue
+		}
+
+		field := fmt.Sprintf("templates.%s.branch_naming", name)
+		if _, err := RenderBranchName(field, tmpl.BranchNaming, c.sampleBranchNamingData(&tmpl)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sampleBranchNamingData returns the data used to validate branch naming templates.
+func (c *Config) sampleBranchNamingData(tmpl *Template) BranchNamingTemplateData {
+	data := BranchNamingTemplateData{
+		ID:    "EXAMPLE-123",
+		Title: "Fix login redirect",
+		User:  "Jane Doe",
+		Date:  time.Now().Format(time.DateOnly),
+		Vars:  c.GetBranchVars(tmpl),
+	}
+
+	if tmpl != nil {
+		data.Template = tmpl.Name
+	}
+
+	return data
+}
+
 func (c *Config) validateTemplates() error {
 	for name, tmpl := range c.Templates {
 		if err := validateTemplate(name, tmpl); err != nil {
@@ -734,9 +1164,60 @@ func validateTemplate(name string, tmpl Template) error {
 		}
 	}
 
+	if tmpl.SyncStrategy != "" && !domain.SyncStrategy(tmpl.SyncStrategy).IsValid() {
+		return cerrors.NewConfigValidation(fmt.Sprintf("templates.%s.sync_strategy", name),
+			fmt.Sprintf("must be one of %s, got %q", SyncStrategyNames(), tmpl.SyncStrategy))
+	}
+
+	for repo, paths := range tmpl.SparsePaths {
+		if !slices.Contains(tmpl.Repos, repo) {
+			return cerrors.NewConfigValidation(fmt.Sprintf("templates.%s.sparse_paths", name),
+				fmt.Sprintf("repo %q is not one of the template repos", repo))
+		}
+
+		if _, err := NormalizeSparsePaths(paths); err != nil {
+			return cerrors.NewConfigValidation(fmt.Sprintf("templates.%s.sparse_paths.%s", name, repo), err.Error())
+		}
+	}
+
 	return nil
 }
 
+// NormalizeSparsePaths validates sparse checkout directories and returns them normalized,
+// sorted and without duplicates.
+func NormalizeSparsePaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		cleaned, err := validation.NormalizeSparsePath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized = append(normalized, cleaned)
+	}
+
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized), nil
+}
+
+// SyncStrategyNames returns the supported sync strategies as a comma-separated list.
+func SyncStrategyNames() string {
+	strategies := domain.SyncStrategies()
+	names := make([]string, len(strategies))
+
+	for i, strategy := range strategies {
+		names[i] = string(strategy)
+	}
+
+	return strings.Join(names, ", ")
+}
+
 // validateStaleThreshold checks that the stale threshold is non-negative.
 func (c *Config) validateStaleThreshold() error {
 	if c.StaleThresholdDays < 0 {
@@ -803,6 +1284,10 @@ const (
 	DefaultLockTimeout = 30 * time.Second
 	// DefaultLockStaleThreshold is the default age before a lock is considered stale.
 	DefaultLockStaleThreshold = 5 * time.Minute
+	// DefaultFetchInterval is the default time between background fetches.
+	DefaultFetchInterval = 15 * time.Minute
+	// DefaultFetchJitter is the default random delay added to each background fetch interval.
+	DefaultFetchJitter = time.Minute
 	// MinParallelWorkers is the minimum allowed value for parallel workers.
 	MinParallelWorkers = 1
 	// MaxParallelWorkers is the maximum allowed value for parallel workers.
@@ -859,20 +1344,202 @@ func (c *Config) validateGitRetry() error {
 	return nil
 }
 
+// validateForges checks that each forge names a host and a supported type.
+func (c *Config) validateForges() error {
+	seen := make(map[string]bool)
+
+	for i, forge := range c.Forges {
+		field := fmt.Sprintf("forges[%d]", i)
+
+		if strings.TrimSpace(forge.Host) == "" {
+			return cerrors.NewConfigValidation(field+".host", "is required")
+		}
+
+		host := strings.ToLower(forge.Host)
+		if seen[host] {
+			return cerrors.NewConfigValidation(field+".host", fmt.Sprintf("duplicate forge host %q", forge.Host))
+		}
+
+		seen[host] = true
+
+		switch forge.Type {
+		case ForgeTypeGitHub, ForgeTypeGitLab, ForgeTypeGitea:
+		default:
+			return cerrors.NewConfigValidation(field+".type",
+				fmt.Sprintf("must be one of %s, %s, %s, got %q", ForgeTypeGitHub, ForgeTypeGitLab, ForgeTypeGitea, forge.Type))
+		}
+
+		if forge.APIURL != "" {
+			if parsed, err := url.Parse(forge.APIURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+				return cerrors.NewConfigValidation(field+".api_url", fmt.Sprintf("must be an absolute URL, got %q", forge.APIURL))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateIssueTracker checks that the issue tracker has the settings its type needs and that
+// its branch naming template renders a valid branch name.
+func (c *Config) validateIssueTracker() error {
+	tracker := c.IssueTracker
+
+	switch tracker.Type {
+	case "":
+		if tracker != (IssueTrackerConfig{}) {
+			return cerrors.NewConfigValidation("issue_tracker.type", "is required when issue_tracker is configured")
+		}
+
+		return nil
+	case IssueTrackerJira, IssueTrackerHTTP:
+		if strings.TrimSpace(tracker.URL) == "" {
+			return cerrors.NewConfigValidation("issue_tracker.url", fmt.Sprintf("is required for %s issue trackers", tracker.Type))
+		}
+	case IssueTrackerGitHub:
+		if owner, name, ok := strings.Cut(tracker.Repository, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return cerrors.NewConfigValidation("issue_tracker.repository", fmt.Sprintf("must be owner/name for GitHub Issues, got %q", tracker.Repository))
+		}
+	case IssueTrackerLinear:
+	default:
+		return cerrors.NewConfigValidation("issue_tracker.type",
+			fmt.Sprintf("must be one of %s, %s, %s, %s, got %q", IssueTrackerJira, IssueTrackerGitHub, IssueTrackerLinear, IssueTrackerHTTP, tracker.Type))
+	}
+
+	if tracker.URL != "" {
+		if parsed, err := url.Parse(tracker.URL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return cerrors.NewConfigValidation("issue_tracker.url", fmt.Sprintf("must be an absolute URL, got %q", tracker.URL))
+		}
+	}
+
+	if tracker.BranchNaming == "" {
+		return nil
+	}
+
+	_, err := RenderBranchName("issue_tracker.branch_naming", tracker.BranchNaming, c.sampleBranchNamingData(nil))
+
+	return err
+}
+
+// validateGC checks the garbage collection criteria and excluded labels.
+func (c *Config) validateGC() error {
+	for i, criterion := range c.GC.Criteria {
+		if !criterion.IsValid() {
+			return cerrors.NewConfigValidation(fmt.Sprintf("gc.criteria[%d]", i),
+				fmt.Sprintf("must be one of %s, got %q", GCReasonNames(), criterion))
+		}
+	}
+
+	for i, label := range c.GC.ExcludeLabels {
+		if err := validation.ValidateLabel(label); err != nil {
+			return cerrors.NewConfigValidation(fmt.Sprintf("gc.exclude_labels[%d]", i), err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateClosedRetention checks the closed entry retention limits.
+func (c *Config) validateClosedRetention() error {
+	if c.ClosedRetention.MaxAgeDays < 0 {
+		return cerrors.NewConfigValidation("closed_retention.max_age_days",
+			fmt.Sprintf("must be zero or positive, got %d", c.ClosedRetention.MaxAgeDays))
+	}
+
+	if c.ClosedRetention.MaxPerWorkspace < 0 {
+		return cerrors.NewConfigValidation("closed_retention.max_per_workspace",
+			fmt.Sprintf("must be zero or positive, got %d", c.ClosedRetention.MaxPerWorkspace))
+	}
+
+	_, err := c.ClosedRetention.MaxTotalBytes()
+
+	return err
+}
+
+// GCReasonNames returns the supported garbage collection criteria as a comma-separated list.
+func GCReasonNames() string {
+	reasons := domain.GCReasons()
+	names := make([]string, len(reasons))
+
+	for i, reason := range reasons {
+		names[i] = string(reason)
+	}
+
+	return strings.Join(names, ", ")
+}
+
 // validateHooks validates all hook configurations.
 func (c *Config) validateHooks() error {
-	for i, h := range c.Hooks.PostCreate {
-		if err := validateHook(h, "post_create", i); err != nil {
+	for _, phase := range c.Hooks.Phases() {
+		for i, h := range phase.Hooks {
+			if err := validateHook(h, phase.Phase, i); err != nil {
+				return err
+			}
+		}
+
+		if err := validateHookNeeds(phase.Phase, phase.Hooks); err != nil {
 			return err
 		}
 	}
 
-	for i, h := range c.Hooks.PreClose {
-		if err := validateHook(h, "pre_close", i); err != nil {
-			return err
+	return nil
+}
+
+// validateHookNeeds checks hook names are unique within a phase and needs form an acyclic graph.
+func validateHookNeeds(phase string, hks []Hook) error {
+	byName := make(map[string]int, len(hks))
+
+	for i, h := range hks {
+		if h.Name == "" {
+			continue
+		}
+
+		if _, exists := byName[h.Name]; exists {
+			return cerrors.NewConfigValidation(fmt.Sprintf("%s hook[%d]", phase, i), fmt.Sprintf("duplicate hook name %q", h.Name))
+		}
+
+		byName[h.Name] = i
+	}
+
+	for i, h := range hks {
+		for _, need := range h.Needs {
+			if _, ok := byName[need]; !ok {
+				return cerrors.NewConfigValidation(fmt.Sprintf("%s hook[%d]", phase, i), fmt.Sprintf("needs unknown hook %q", need))
+			}
+		}
+	}
+
+	// Resolve named hooks whose needs are all resolved until nothing changes;
+	// anything left over is part of a cycle.
+	resolved := make(map[string]bool, len(byName))
+
+	for progress := true; progress; {
+		progress = false
+
+		for name, i := range byName {
+			if resolved[name] {
+				continue
+			}
+
+			ready := true
+
+			for _, need := range hks[i].Needs {
+				if !resolved[need] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				resolved[name] = true
+				progress = true
+			}
 		}
 	}
 
+	if len(resolved) < len(byName) {
+		return cerrors.NewConfigValidation(phase+" hooks", "needs contain a dependency cycle")
+	}
+
 	return nil
 }
 
@@ -901,6 +1568,41 @@ func validateHook(h Hook, hookType string, index int) error {
 		return cerrors.NewConfigValidation(field, "shell cannot be empty or whitespace-only when specified")
 	}
 
+	for key := range h.Env {
+		if key == "" || strings.ContainsAny(key, "= \x00") {
+			return cerrors.NewConfigValidation(field, fmt.Sprintf("invalid env variable name %q", key))
+		}
+	}
+
+	if h.Parallel && len(h.Repos) == 0 {
+		return cerrors.NewConfigValidation(field, "parallel requires repos to fan out across")
+	}
+
+	if h.WorkingDir != "" && !filepath.IsLocal(filepath.FromSlash(h.WorkingDir)) {
+		return cerrors.NewConfigValidation(field, fmt.Sprintf("working_dir must be a relative path inside the workspace or repo, got %q", h.WorkingDir))
+	}
+
+	return validateHookCondition(h.When, field)
+}
+
+// validateHookCondition checks that a hook's when clause is well-formed.
+func validateHookCondition(when HookCondition, field string) error {
+	if when.FileExists != "" && !filepath.IsLocal(filepath.FromSlash(when.FileExists)) {
+		return cerrors.NewConfigValidation(field, fmt.Sprintf("when.file_exists must be a relative path, got %q", when.FileExists))
+	}
+
+	if when.WorkspaceID != "" {
+		if _, err := regexp.Compile(when.WorkspaceID); err != nil {
+			return cerrors.NewConfigValidation(field, fmt.Sprintf("when.workspace_id is not a valid regex: %v", err))
+		}
+	}
+
+	if when.Branch != "" {
+		if _, err := path.Match(when.Branch, ""); err != nil {
+			return cerrors.NewConfigValidation(field, fmt.Sprintf("when.branch is not a valid glob: %v", err))
+		}
+	}
+
 	return nil
 }
 
@@ -1015,6 +1717,58 @@ func (c *Config) computeWorkspaceDir(id string) (string, error) {
 	return dirName, nil
 }
 
+// branchNamingFuncs are the helper functions available to branch naming templates.
+var branchNamingFuncs = template.FuncMap{
+	"slug":     Slug,
+	"lower":    strings.ToLower,
+	"truncate": truncate,
+}
+
+// truncate shortens s to at most n characters, for use in pipelines: {{.Title | slug | truncate 30}}.
+// Hyphens left at the end by the cut are removed.
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+
+	return strings.TrimRight(string(runes[:n]), "-")
+}
+
+// nonSlugChars matches runs of characters that are not allowed in a slug.
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug lower-cases s and joins its words with hyphens, for use in branch names:
+// "Fix: login redirect!" becomes "fix-login-redirect".
+func Slug(s string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// RenderBranchName renders a branch naming template and validates the result as a branch name.
+// field names the configuration setting the template came from, for error messages.
+func RenderBranchName(field, pattern string, data BranchNamingTemplateData) (string, error) {
+	tmpl, err := template.New(field).Funcs(branchNamingFuncs).Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return "", cerrors.NewConfigValidation(field, fmt.Sprintf("invalid template: %v", err))
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", cerrors.NewConfigValidation(field, fmt.Sprintf("template execution failed: %v", err))
+	}
+
+	branch := strings.TrimSpace(rendered.String())
+	if branch == "" {
+		return "", cerrors.NewConfigValidation(field, "template rendered an empty branch name")
+	}
+
+	if err := validation.ValidateBranchName(branch); err != nil {
+		return "", cerrors.NewConfigValidation(field, fmt.Sprintf("template output %q is not a valid branch name: %v", branch, err))
+	}
+
+	return branch, nil
+}
+
 // GetStaleThresholdDays returns the stale threshold in days.
 func (c *Config) GetStaleThresholdDays() int {
 	return c.StaleThresholdDays
@@ -1078,6 +1832,64 @@ func (c *Config) GetUseEmoji() bool {
 	return c.TUI.GetUseEmoji()
 }
 
+// GetForges returns the configured forges.
+func (c *Config) GetForges() []ForgeConfig {
+	return c.Forges
+}
+
+// GetIssueTracker returns the issue tracker configuration.
+func (c *Config) GetIssueTracker() IssueTrackerConfig {
+	return c.IssueTracker
+}
+
+// GetGC returns the garbage collection policy.
+func (c *Config) GetGC() GCConfig {
+	return c.GC
+}
+
+// GetClosedRetention returns the closed entry retention limits.
+func (c *Config) GetClosedRetention() ClosedRetentionConfig {
+	return c.ClosedRetention
+}
+
+// GetDaemonConfig returns the parsed background fetch configuration.
+// Since validation has already run, we can safely ignore the error.
+func (c *Config) GetDaemonConfig() ParsedDaemonConfig {
+	parsed, _ := c.Daemon.Parse()
+	return parsed
+}
+
+// GetBranchNaming returns the branch naming template for a workspace ID: the branch_naming of
+// the first matching workspace pattern, otherwise the global branch_naming. field names the
+// setting the template came from; pattern is empty when branches are named after the ID.
+func (c *Config) GetBranchNaming(workspaceID string) (field, pattern string) {
+	for i, p := range c.Defaults.WorkspacePatterns {
+		matched, err := regexp.MatchString(p.Pattern, workspaceID)
+		if err == nil && matched && p.BranchNaming != "" {
+			return fmt.Sprintf("defaults.workspace_patterns[%d].branch_naming", i), p.BranchNaming
+		}
+	}
+
+	return "branch_naming", c.BranchNaming
+}
+
+// GetBranchVars returns the custom branch naming variables, with the branch_vars of tmpl
+// (which may be nil) merged over the global ones.
+func (c *Config) GetBranchVars(tmpl *Template) map[string]string {
+	vars := make(map[string]string, len(c.BranchVars))
+	for k, v := range c.BranchVars {
+		vars[k] = v
+	}
+
+	if tmpl != nil {
+		for k, v := range tmpl.BranchVars {
+			vars[k] = v
+		}
+	}
+
+	return vars
+}
+
 // GetGitRetryConfig returns the parsed git retry configuration.
 // Since validation has already run, we can safely ignore the error.
 func (c *Config) GetGitRetryConfig() ParsedRetryConfig {
@@ -6,9 +6,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
 )
 
@@ -780,6 +782,79 @@ func TestValidateValues(t *testing.T) {
 			errSubstr: "post_create hook[0]",
 			errType:   cerrors.ConfigValidation,
 		},
+		{
+			name: "pre_push hook with newline",
+			cfg: &Config{
+				ProjectsRoot:       "/tmp/projects",
+				WorkspacesRoot:     "/tmp/workspaces",
+				ClosedRoot:         "/tmp/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+				Hooks: Hooks{
+					PostSync: []Hook{{Command: "make deps"}},
+					PrePush:  []Hook{{Command: "make test\nrm -rf /"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "pre_push hook[0]",
+			errType:   cerrors.ConfigValidation,
+		},
+		{
+			name: "hook with absolute working_dir",
+			cfg: &Config{
+				ProjectsRoot:       "/tmp/projects",
+				WorkspacesRoot:     "/tmp/workspaces",
+				ClosedRoot:         "/tmp/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+				Hooks: Hooks{
+					PostCreate: []Hook{{Command: "npm install", WorkingDir: "/etc"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "working_dir",
+			errType:   cerrors.ConfigValidation,
+		},
+		{
+			name: "hook with invalid env name",
+			cfg: &Config{
+				ProjectsRoot:       "/tmp/projects",
+				WorkspacesRoot:     "/tmp/workspaces",
+				ClosedRoot:         "/tmp/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+				Hooks: Hooks{
+					PostCreate: []Hook{{Command: "npm install", Env: map[string]string{"A=B": "c"}}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "invalid env variable name",
+			errType:   cerrors.ConfigValidation,
+		},
+		{
+			name: "hook with invalid when regex",
+			cfg: &Config{
+				ProjectsRoot:       "/tmp/projects",
+				WorkspacesRoot:     "/tmp/workspaces",
+				ClosedRoot:         "/tmp/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+				Hooks: Hooks{
+					PostSync: []Hook{{Command: "make", When: HookCondition{WorkspaceID: "(["}}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "when.workspace_id",
+			errType:   cerrors.ConfigValidation,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1476,6 +1551,375 @@ func TestValidateGitRetry(t *testing.T) {
 	}
 }
 
+func TestDaemonConfigParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		daemon       DaemonConfig
+		wantInterval time.Duration
+		wantJitter   time.Duration
+		errSubstr    string
+	}{
+		{
+			name:         "defaults when empty",
+			wantInterval: DefaultFetchInterval,
+			wantJitter:   DefaultFetchJitter,
+		},
+		{
+			name:         "custom values",
+			daemon:       DaemonConfig{FetchInterval: "1h", FetchJitter: "0s"},
+			wantInterval: time.Hour,
+			wantJitter:   0,
+		},
+		{
+			name:      "invalid interval",
+			daemon:    DaemonConfig{FetchInterval: "often"},
+			errSubstr: "daemon.fetch_interval",
+		},
+		{
+			name:      "zero interval",
+			daemon:    DaemonConfig{FetchInterval: "0s"},
+			errSubstr: "must be positive",
+		},
+		{
+			name:      "negative jitter",
+			daemon:    DaemonConfig{FetchJitter: "-1m"},
+			errSubstr: "daemon.fetch_jitter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := tt.daemon.Parse()
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Errorf("Parse() error = %v, want substring %q", err, tt.errSubstr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			if parsed.FetchInterval != tt.wantInterval || parsed.FetchJitter != tt.wantJitter {
+				t.Errorf("Parse() = %+v, want interval %s and jitter %s", parsed, tt.wantInterval, tt.wantJitter)
+			}
+		})
+	}
+}
+
+func TestValidateForges(t *testing.T) {
+	baseConfig := func() *Config {
+		return &Config{
+			ProjectsRoot:       "/projects",
+			WorkspacesRoot:     "/workspaces",
+			ClosedRoot:         "/closed",
+			CloseDefault:       "delete",
+			StaleThresholdDays: 14,
+			Git:                validGitConfig(),
+			ParallelWorkers:    DefaultParallelWorkers,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		forges    []ForgeConfig
+		errSubstr string
+	}{
+		{
+			name:   "valid forges",
+			forges: []ForgeConfig{{Host: "git.example.com", Type: ForgeTypeGitea, APIURL: "https://git.example.com/api/v1", TokenEnv: "GITEA_TOKEN"}},
+		},
+		{
+			name:      "missing host",
+			forges:    []ForgeConfig{{Type: ForgeTypeGitHub}},
+			errSubstr: "forges[0].host",
+		},
+		{
+			name:      "unknown type",
+			forges:    []ForgeConfig{{Host: "git.example.com", Type: "bitbucket"}},
+			errSubstr: "forges[0].type",
+		},
+		{
+			name:      "relative api url",
+			forges:    []ForgeConfig{{Host: "git.example.com", Type: ForgeTypeGitLab, APIURL: "api/v4"}},
+			errSubstr: "forges[0].api_url",
+		},
+		{
+			name: "duplicate host",
+			forges: []ForgeConfig{
+				{Host: "git.example.com", Type: ForgeTypeGitea},
+				{Host: "Git.Example.com", Type: ForgeTypeGitLab},
+			},
+			errSubstr: "duplicate forge host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseConfig()
+			cfg.Forges = tt.forges
+
+			err := cfg.ValidateValues()
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("ValidateValues() unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("ValidateValues() error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestValidateIssueTracker(t *testing.T) {
+	tests := []struct {
+		name      string
+		tracker   IssueTrackerConfig
+		errSubstr string
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:    "jira with branch naming",
+			tracker: IssueTrackerConfig{Type: IssueTrackerJira, URL: "https://example.atlassian.net", BranchNaming: "{{.ID}}-{{slug .Title}}"},
+		},
+		{
+			name:    "http endpoint with placeholder",
+			tracker: IssueTrackerConfig{Type: IssueTrackerHTTP, URL: "http://localhost:8080/issues/{id}"},
+		},
+		{
+			name:      "settings without type",
+			tracker:   IssueTrackerConfig{URL: "https://example.atlassian.net"},
+			errSubstr: "issue_tracker.type",
+		},
+		{
+			name:      "unknown type",
+			tracker:   IssueTrackerConfig{Type: "trello"},
+			errSubstr: "issue_tracker.type",
+		},
+		{
+			name:      "jira without url",
+			tracker:   IssueTrackerConfig{Type: IssueTrackerJira},
+			errSubstr: "issue_tracker.url",
+		},
+		{
+			name:      "github without repository",
+			tracker:   IssueTrackerConfig{Type: IssueTrackerGitHub, Repository: "app"},
+			errSubstr: "issue_tracker.repository",
+		},
+		{
+			name:      "unknown template field",
+			tracker:   IssueTrackerConfig{Type: IssueTrackerLinear, BranchNaming: "{{.Summary}}"},
+			errSubstr: "issue_tracker.branch_naming",
+		},
+		{
+			name:      "invalid branch name",
+			tracker:   IssueTrackerConfig{Type: IssueTrackerLinear, BranchNaming: "{{.ID}}..{{.Title}}"},
+			errSubstr: "not a valid branch name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ProjectsRoot:       "/projects",
+				WorkspacesRoot:     "/workspaces",
+				ClosedRoot:         "/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+				IssueTracker:       tt.tracker,
+			}
+
+			err := cfg.ValidateValues()
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("ValidateValues() unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("ValidateValues() error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestValidateGC(t *testing.T) {
+	tests := []struct {
+		name      string
+		gc        GCConfig
+		errSubstr string
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name: "criteria and labels",
+			gc:   GCConfig{Criteria: []domain.GCReason{domain.GCReasonMerged, domain.GCReasonRemoteDeleted}, ExcludeLabels: []string{"keep"}},
+		},
+		{
+			name:      "unknown criterion",
+			gc:        GCConfig{Criteria: []domain.GCReason{"abandoned"}},
+			errSubstr: "gc.criteria[0]",
+		},
+		{
+			name:      "invalid label",
+			gc:        GCConfig{ExcludeLabels: []string{"keep", "-x"}},
+			errSubstr: "gc.exclude_labels[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ProjectsRoot:       "/projects",
+				WorkspacesRoot:     "/workspaces",
+				ClosedRoot:         "/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+				GC:                 tt.gc,
+			}
+
+			err := cfg.ValidateValues()
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("ValidateValues() unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("ValidateValues() error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestRenderBranchName(t *testing.T) {
+	got, err := RenderBranchName("issue_tracker.branch_naming", "{{.ID}}-{{slug .Title}}",
+		BranchNamingTemplateData{ID: "PROJ-123", Title: "Fix: Login redirect loops (Safari)!"})
+	if err != nil {
+		t.Fatalf("RenderBranchName() error = %v", err)
+	}
+
+	if want := "PROJ-123-fix-login-redirect-loops-safari"; got != want {
+		t.Errorf("RenderBranchName() = %q, want %q", got, want)
+	}
+
+	got, err = RenderBranchName("branch_naming", "{{.Vars.team}}/{{slug .User}}/{{.ID | lower}}-{{.Title | slug | truncate 9}}",
+		BranchNamingTemplateData{ID: "PROJ-123", Title: "Fix login redirect", User: "Jane Doe", Vars: map[string]string{"team": "core"}})
+	if err != nil {
+		t.Fatalf("RenderBranchName() error = %v", err)
+	}
+
+	if want := "core/jane-doe/proj-123-fix-login"; got != want {
+		t.Errorf("RenderBranchName() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateBranchNaming(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(c *Config)
+		errSubstr string
+	}{
+		{
+			name: "global template with vars",
+			modify: func(c *Config) {
+				c.BranchNaming = "{{.Vars.team}}/{{slug .User}}/{{.ID}}"
+				c.BranchVars = map[string]string{"team": "core"}
+			},
+		},
+		{
+			name: "template var overrides",
+			modify: func(c *Config) {
+				c.Templates = map[string]Template{
+					"hotfix": {Repos: []string{"backend"}, BranchNaming: "{{.Template}}/{{.Vars.team}}-{{.Date}}", BranchVars: map[string]string{"team": "ops"}},
+				}
+			},
+		},
+		{
+			name:      "unknown variable",
+			modify:    func(c *Config) { c.BranchNaming = "{{.Vars.team}}/{{.ID}}" },
+			errSubstr: "branch_naming",
+		},
+		{
+			name: "invalid pattern override",
+			modify: func(c *Config) {
+				c.Defaults.WorkspacePatterns = []WorkspacePattern{{Pattern: "^OPS-", BranchNaming: "ops/{{.ID}}.lock"}}
+			},
+			errSubstr: "defaults.workspace_patterns[0].branch_naming",
+		},
+		{
+			name: "invalid template override",
+			modify: func(c *Config) {
+				c.Templates = map[string]Template{"web": {Repos: []string{"frontend"}, BranchNaming: "{{.ID}} {{.Title}}"}}
+			},
+			errSubstr: "templates.web.branch_naming",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ProjectsRoot:       "/projects",
+				WorkspacesRoot:     "/workspaces",
+				ClosedRoot:         "/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+			}
+			tt.modify(cfg)
+
+			err := cfg.ValidateValues()
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("ValidateValues() unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("ValidateValues() error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestGetBranchNaming(t *testing.T) {
+	cfg := &Config{
+		BranchNaming: "{{.ID}}",
+		Defaults: Defaults{WorkspacePatterns: []WorkspacePattern{
+			{Pattern: "^BUG-", Repos: []string{"backend"}},
+			{Pattern: "^OPS-", BranchNaming: "ops/{{.ID}}"},
+		}},
+	}
+
+	if field, pattern := cfg.GetBranchNaming("OPS-1"); field != "defaults.workspace_patterns[1].branch_naming" || pattern != "ops/{{.ID}}" {
+		t.Errorf("GetBranchNaming(OPS-1) = %q, %q", field, pattern)
+	}
+
+	if field, pattern := cfg.GetBranchNaming("BUG-1"); field != "branch_naming" || pattern != "{{.ID}}" {
+		t.Errorf("GetBranchNaming(BUG-1) = %q, %q", field, pattern)
+	}
+}
+
 func TestTUIConfig_GetUseEmoji(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1761,6 +2205,19 @@ func TestHookValidation(t *testing.T) {
 			hookType: "post_create",
 			wantErr:  false,
 		},
+		{
+			name:     "parallel hook with repos",
+			hook:     Hook{Command: "npm install", Repos: []string{"web", "admin"}, Parallel: true},
+			hookType: "post_create",
+			wantErr:  false,
+		},
+		{
+			name:      "parallel hook without repos rejected",
+			hook:      Hook{Command: "npm install", Parallel: true},
+			hookType:  "post_create",
+			wantErr:   true,
+			errSubstr: "parallel requires repos",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1782,3 +2239,156 @@ func TestHookValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestHookNeedsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		hooks     []Hook
+		errSubstr string
+	}{
+		{
+			name: "valid dependency graph",
+			hooks: []Hook{
+				{Name: "deps", Command: "npm install"},
+				{Name: "assets", Command: "make assets"},
+				{Name: "build", Command: "make", Needs: []string{"deps", "assets"}},
+				{Command: "echo done", Needs: []string{"build"}},
+			},
+		},
+		{
+			name: "duplicate names rejected",
+			hooks: []Hook{
+				{Name: "deps", Command: "npm install"},
+				{Name: "deps", Command: "make deps"},
+			},
+			errSubstr: "duplicate hook name",
+		},
+		{
+			name: "unknown need rejected",
+			hooks: []Hook{
+				{Name: "build", Command: "make", Needs: []string{"deps"}},
+			},
+			errSubstr: "needs unknown hook",
+		},
+		{
+			name: "cycle rejected",
+			hooks: []Hook{
+				{Name: "a", Command: "true", Needs: []string{"c"}},
+				{Name: "b", Command: "true", Needs: []string{"a"}},
+				{Name: "c", Command: "true", Needs: []string{"b"}},
+			},
+			errSubstr: "dependency cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHookNeeds("post_create", tt.hooks)
+
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("validateHookNeeds() unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("validateHookNeeds() error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestValidateClosedRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention ClosedRetentionConfig
+		errSubstr string
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:      "all limits",
+			retention: ClosedRetentionConfig{MaxAgeDays: 90, MaxPerWorkspace: 3, MaxTotalSize: "2GB"},
+		},
+		{
+			name:      "negative age",
+			retention: ClosedRetentionConfig{MaxAgeDays: -1},
+			errSubstr: "closed_retention.max_age_days",
+		},
+		{
+			name:      "negative count",
+			retention: ClosedRetentionConfig{MaxPerWorkspace: -2},
+			errSubstr: "closed_retention.max_per_workspace",
+		},
+		{
+			name:      "invalid size",
+			retention: ClosedRetentionConfig{MaxTotalSize: "2 parsecs"},
+			errSubstr: "closed_retention.max_total_size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ProjectsRoot:       "/projects",
+				WorkspacesRoot:     "/workspaces",
+				ClosedRoot:         "/closed",
+				CloseDefault:       "delete",
+				StaleThresholdDays: 14,
+				Git:                validGitConfig(),
+				ParallelWorkers:    DefaultParallelWorkers,
+				ClosedRetention:    tt.retention,
+			}
+
+			err := cfg.ValidateValues()
+			if tt.errSubstr == "" {
+				if err != nil {
+					t.Errorf("ValidateValues() unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("ValidateValues() error = %v, want substring %q", err, tt.errSubstr)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "1024", want: 1024},
+		{input: "512B", want: 512},
+		{input: "2kb", want: 2048},
+		{input: "1.5 GB", want: 3 << 29},
+		{input: "1TB", want: 1 << 40},
+		{input: "", wantErr: true},
+		{input: "GB", wantErr: true},
+		{input: "-1GB", wantErr: true},
+		{input: "0MB", wantErr: true},
+		{input: "10XB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseByteSize(%q) = %d, want error", tt.input, got)
+			}
+
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %d, %v, want %d", tt.input, got, err, tt.want)
+		}
+	}
+}
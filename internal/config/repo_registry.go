@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
 	"github.com/alexisbeaulieu97/canopy/internal/giturl"
 )
@@ -21,9 +23,45 @@ type RegistryEntry struct {
 	DefaultBranch string   `yaml:"default_branch,omitempty"`
 	Description   string   `yaml:"description,omitempty"`
 	Tags          []string `yaml:"tags,omitempty"`
+	SyncStrategy  string   `yaml:"sync_strategy,omitempty"`
+	// SparsePaths are the directories checked out in new worktrees (cone mode).
+	SparsePaths []string `yaml:"sparse_paths,omitempty"`
+	// Filter, Depth and SingleBranch make the canonical clone partial, shallow or
+	// limited to the default branch.
+	Filter       string `yaml:"filter,omitempty"`
+	Depth        int    `yaml:"depth,omitempty"`
+	SingleBranch bool   `yaml:"single_branch,omitempty"`
+	// Remotes maps remote names to URLs configured in worktrees next to origin, which
+	// always points at URL. PushRemote names the remote pushes go to (default origin).
+	Remotes    map[string]string `yaml:"remotes,omitempty"`
+	PushRemote string            `yaml:"push_remote,omitempty"`
 }
 
-// RepoRegistry stores repository aliases and metadata.
+// CloneOptions returns the options used to clone the entry's canonical repository.
+func (e RegistryEntry) CloneOptions() domain.CloneOptions {
+	return domain.CloneOptions{Filter: e.Filter, Depth: e.Depth, SingleBranch: e.SingleBranch}
+}
+
+// RemoteList returns the entry's extra remotes sorted by name.
+func (e RegistryEntry) RemoteList() []domain.Remote {
+	if len(e.Remotes) == 0 {
+		return nil
+	}
+
+	remotes := make([]domain.Remote, 0, len(e.Remotes))
+	for name, url := range e.Remotes {
+		remotes = append(remotes, domain.Remote{Name: name, URL: url})
+	}
+
+	sort.Slice(remotes, func(i, j int) bool {
+		return remotes[i].Name < remotes[j].Name
+	})
+
+	return remotes
+}
+
+// RepoRegistry stores repository aliases and metadata. Lookups do not modify it, so they
+// are safe for concurrent use.
 type RepoRegistry struct {
 	path  string                   `yaml:"-"`
 	Repos map[string]RegistryEntry `yaml:"repos"`
@@ -102,8 +140,6 @@ func (r *RepoRegistry) Save() error {
 
 // Resolve returns a registry entry by alias if present.
 func (r *RepoRegistry) Resolve(alias string) (RegistryEntry, bool) {
-	r.ensureMap()
-
 	entry, ok := r.Repos[alias]
 	if !ok {
 		return RegistryEntry{}, false
@@ -116,8 +152,6 @@ func (r *RepoRegistry) Resolve(alias string) (RegistryEntry, bool) {
 
 // ResolveByURL returns a registry entry whose URL matches exactly.
 func (r *RepoRegistry) ResolveByURL(url string) (RegistryEntry, bool) {
-	r.ensureMap()
-
 	for alias, entry := range r.Repos {
 		if entry.URL == strings.TrimSpace(url) {
 			entry.Alias = alias
@@ -144,6 +178,25 @@ func (r *RepoRegistry) Register(alias string, entry RegistryEntry, force bool) e
 		return cerrors.NewInvalidArgument("url", fmt.Sprintf("invalid repository URL: %s", sanitizedURL))
 	}
 
+	if err := validateRegistrySyncStrategy(entry.SyncStrategy); err != nil {
+		return err
+	}
+
+	sparsePaths, err := NormalizeSparsePaths(entry.SparsePaths)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateCloneOptions(entry.CloneOptions()); err != nil {
+		return err
+	}
+
+	if err := ValidateRemotes(entry.RemoteList(), entry.PushRemote); err != nil {
+		return err
+	}
+
+	entry.SparsePaths = sparsePaths
+
 	if _, exists := r.Repos[alias]; exists && !force {
 		existing := r.Repos[alias]
 		return cerrors.NewRegistryError("register", fmt.Sprintf("alias '%s' already exists for %s", alias, giturl.Sanitize(existing.URL)), nil)
@@ -171,6 +224,10 @@ func (r *RepoRegistry) RegisterWithSuffix(alias string, entry RegistryEntry) (st
 		return "", cerrors.NewInvalidArgument("url", fmt.Sprintf("invalid repository URL: %s", sanitizedURL))
 	}
 
+	if err := validateRegistrySyncStrategy(entry.SyncStrategy); err != nil {
+		return "", err
+	}
+
 	target := alias
 	for idx := 2; ; idx++ {
 		if _, exists := r.Repos[target]; !exists {
@@ -236,6 +293,60 @@ func defaultRegistryPath() (string, error) {
 	return filepath.Join(home, ".canopy", "repos.yaml"), nil
 }
 
+func validateRegistrySyncStrategy(strategy string) error {
+	if strategy == "" || domain.SyncStrategy(strategy).IsValid() {
+		return nil
+	}
+
+	return cerrors.NewInvalidArgument("sync_strategy", fmt.Sprintf("unsupported sync strategy %q (valid: %s)", strategy, SyncStrategyNames()))
+}
+
+// cloneFilterPattern matches the partial clone filters git supports for canonical repositories.
+var cloneFilterPattern = regexp.MustCompile(`^(blob:none|blob:limit=[0-9]+[kmg]?|tree:[0-9]+)$`)
+
+// ValidateCloneOptions checks a partial clone filter and a shallow clone depth.
+func ValidateCloneOptions(opts domain.CloneOptions) error {
+	if opts.Filter != "" && !cloneFilterPattern.MatchString(opts.Filter) {
+		return cerrors.NewInvalidArgument("filter", fmt.Sprintf("unsupported clone filter %q (valid: blob:none, blob:limit=<size>, tree:<depth>)", opts.Filter))
+	}
+
+	if opts.Depth < 0 {
+		return cerrors.NewInvalidArgument("depth", fmt.Sprintf("must be zero or positive, got %d", opts.Depth))
+	}
+
+	return nil
+}
+
+// remoteNamePattern matches the remote names accepted for extra remotes.
+var remoteNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateRemotes checks extra remotes and the push remote of a repository. origin is
+// reserved for the repository URL. The push remote must be origin or one of remotes.
+func ValidateRemotes(remotes []domain.Remote, pushRemote string) error {
+	seen := make(map[string]bool, len(remotes))
+
+	for _, remote := range remotes {
+		switch {
+		case !remoteNamePattern.MatchString(remote.Name) || strings.HasSuffix(remote.Name, ".lock"):
+			return cerrors.NewInvalidArgument("remotes", fmt.Sprintf("invalid remote name %q", remote.Name))
+		case remote.Name == domain.OriginRemote:
+			return cerrors.NewInvalidArgument("remotes", "origin is reserved for the repository URL")
+		case seen[remote.Name]:
+			return cerrors.NewInvalidArgument("remotes", fmt.Sprintf("duplicate remote %q", remote.Name))
+		case !giturl.IsURL(strings.TrimSpace(remote.URL)):
+			return cerrors.NewInvalidArgument("remotes", fmt.Sprintf("invalid URL for remote %s: %s", remote.Name, giturl.Sanitize(remote.URL)))
+		}
+
+		seen[remote.Name] = true
+	}
+
+	if pushRemote != "" && pushRemote != domain.OriginRemote && !seen[pushRemote] {
+		return cerrors.NewInvalidArgument("push_remote", fmt.Sprintf("unknown remote %q", pushRemote))
+	}
+
+	return nil
+}
+
 func stripAlias(entry RegistryEntry) RegistryEntry {
 	entry.Alias = ""
 	return entry
@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 )
 
 func TestLoadRegistryMissingFile(t *testing.T) {
@@ -73,6 +76,118 @@ func TestRegisterCollisionAndForce(t *testing.T) {
 	}
 }
 
+func TestRegisterValidatesSyncStrategy(t *testing.T) {
+	registry := &RepoRegistry{path: filepath.Join(t.TempDir(), "repos.yaml"), Repos: map[string]RegistryEntry{}}
+
+	entry := RegistryEntry{URL: "https://github.com/example/api.git", SyncStrategy: "rebase"}
+	if err := registry.Register("api", entry, false); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	entry.SyncStrategy = "squash"
+	if err := registry.Register("web", entry, false); err == nil {
+		t.Fatalf("expected error for unknown sync strategy")
+	}
+}
+
+func TestRegisterNormalizesSparsePaths(t *testing.T) {
+	registry := &RepoRegistry{path: filepath.Join(t.TempDir(), "repos.yaml"), Repos: map[string]RegistryEntry{}}
+
+	entry := RegistryEntry{URL: "https://github.com/example/mono.git", SparsePaths: []string{"services/api/", "docs", "docs"}}
+	if err := registry.Register("mono", entry, false); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	if got := registry.Repos["mono"].SparsePaths; !reflect.DeepEqual(got, []string{"docs", "services/api"}) {
+		t.Errorf("sparse paths = %v, want [docs services/api]", got)
+	}
+
+	entry.SparsePaths = []string{"../outside"}
+	if err := registry.Register("other", entry, false); err == nil {
+		t.Fatalf("expected error for invalid sparse path")
+	}
+}
+
+func TestValidateCloneOptions(t *testing.T) {
+	valid := []domain.CloneOptions{
+		{},
+		{Filter: "blob:none"},
+		{Filter: "blob:limit=1m", Depth: 1},
+		{Filter: "tree:0", SingleBranch: true},
+	}
+	for _, opts := range valid {
+		if err := ValidateCloneOptions(opts); err != nil {
+			t.Errorf("ValidateCloneOptions(%+v) = %v, want nil", opts, err)
+		}
+	}
+
+	invalid := []domain.CloneOptions{
+		{Filter: "blob"},
+		{Filter: "sparse:oid=abc"},
+		{Depth: -1},
+	}
+	for _, opts := range invalid {
+		if err := ValidateCloneOptions(opts); err == nil {
+			t.Errorf("ValidateCloneOptions(%+v) = nil, want error", opts)
+		}
+	}
+}
+
+func TestRegisterWithRemotes(t *testing.T) {
+	registry := &RepoRegistry{path: filepath.Join(t.TempDir(), "repos.yaml"), Repos: map[string]RegistryEntry{}}
+
+	entry := RegistryEntry{
+		URL:        "https://github.com/upstream/api.git",
+		Remotes:    map[string]string{"fork": "git@github.com:me/api.git", "backup": "https://example.com/api.git"},
+		PushRemote: "fork",
+	}
+	if err := registry.Register("api", entry, false); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	want := []domain.Remote{{Name: "backup", URL: "https://example.com/api.git"}, {Name: "fork", URL: "git@github.com:me/api.git"}}
+	if got := registry.Repos["api"].RemoteList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("remotes = %v, want %v", got, want)
+	}
+}
+
+func TestValidateRemotes(t *testing.T) {
+	fork := domain.Remote{Name: "fork", URL: "git@github.com:me/api.git"}
+
+	valid := []struct {
+		remotes    []domain.Remote
+		pushRemote string
+	}{
+		{nil, ""},
+		{nil, "origin"},
+		{[]domain.Remote{fork}, ""},
+		{[]domain.Remote{fork}, "fork"},
+		{[]domain.Remote{{Name: "upstream", URL: "git@github.com:upstream/api.git"}}, ""},
+	}
+	for _, tc := range valid {
+		if err := ValidateRemotes(tc.remotes, tc.pushRemote); err != nil {
+			t.Errorf("ValidateRemotes(%v, %q) = %v, want nil", tc.remotes, tc.pushRemote, err)
+		}
+	}
+
+	invalid := []struct {
+		remotes    []domain.Remote
+		pushRemote string
+	}{
+		{[]domain.Remote{{Name: "origin", URL: fork.URL}}, ""},
+		{[]domain.Remote{{Name: "-fork", URL: fork.URL}}, ""},
+		{[]domain.Remote{{Name: "fork.lock", URL: fork.URL}}, ""},
+		{[]domain.Remote{fork, fork}, ""},
+		{[]domain.Remote{{Name: "fork", URL: "not a url"}}, ""},
+		{[]domain.Remote{fork}, "upstream"},
+	}
+	for _, tc := range invalid {
+		if err := ValidateRemotes(tc.remotes, tc.pushRemote); err == nil {
+			t.Errorf("ValidateRemotes(%v, %q) = nil, want error", tc.remotes, tc.pushRemote)
+		}
+	}
+}
+
 func TestRegisterWithSuffix(t *testing.T) {
 	registry := &RepoRegistry{path: filepath.Join(t.TempDir(), "repos.yaml"), Repos: map[string]RegistryEntry{}}
 
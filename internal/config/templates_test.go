@@ -26,6 +26,8 @@ templates:
     default_branch: "main"
     setup_commands:
       - "echo setup"
+    sparse_paths:
+      backend: ["services/api", "docs"]
 `
 
 	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
@@ -49,6 +51,10 @@ templates:
 	if len(tmpl.Repos) != 2 {
 		t.Fatalf("expected 2 repos, got %d", len(tmpl.Repos))
 	}
+
+	if got := tmpl.SparsePaths["backend"]; len(got) != 2 || got[0] != "services/api" {
+		t.Errorf("SparsePaths[backend] = %v, want [services/api docs]", got)
+	}
 }
 
 func TestResolveTemplateUnknown(t *testing.T) {
@@ -76,3 +82,41 @@ func TestValidateTemplatesMissingRepos(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestValidateTemplatesSyncStrategy(t *testing.T) {
+	cfg := &Config{Templates: map[string]Template{"backend": {Repos: []string{"repo"}, SyncStrategy: "rebase"}}}
+	if err := cfg.ValidateTemplates(); err != nil {
+		t.Fatalf("unexpected error for valid sync strategy: %v", err)
+	}
+
+	cfg.Templates["backend"] = Template{Repos: []string{"repo"}, SyncStrategy: "squash"}
+
+	err := cfg.ValidateTemplates()
+	if err == nil {
+		t.Fatal("expected validation error for unknown sync strategy")
+	}
+
+	if !strings.Contains(err.Error(), "templates.backend.sync_strategy") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateTemplatesSparsePaths(t *testing.T) {
+	cfg := &Config{Templates: map[string]Template{"backend": {
+		Repos:       []string{"monorepo"},
+		SparsePaths: map[string][]string{"monorepo": {"services/api", "docs/"}},
+	}}}
+	if err := cfg.ValidateTemplates(); err != nil {
+		t.Fatalf("unexpected error for valid sparse paths: %v", err)
+	}
+
+	cfg.Templates["backend"] = Template{Repos: []string{"monorepo"}, SparsePaths: map[string][]string{"other": {"docs"}}}
+	if err := cfg.ValidateTemplates(); err == nil || !strings.Contains(err.Error(), "templates.backend.sparse_paths") {
+		t.Fatalf("expected validation error for a repo outside the template, got %v", err)
+	}
+
+	cfg.Templates["backend"] = Template{Repos: []string{"monorepo"}, SparsePaths: map[string][]string{"monorepo": {"services/*"}}}
+	if err := cfg.ValidateTemplates(); err == nil || !strings.Contains(err.Error(), "templates.backend.sparse_paths.monorepo") {
+		t.Fatalf("expected validation error for a glob sparse path, got %v", err)
+	}
+}
@@ -9,8 +9,11 @@
 // Workspace-related types:
 //   - Workspace: Represents an active workspace with its repositories
 //   - ClosedWorkspace: Represents an archived workspace
+//   - Snapshot: Named checkpoint of a workspace's repositories
 //   - WorkspaceStatus: Aggregate git status for a workspace
 //   - WorkspaceClosePreview: Preview of what closing a workspace would do
+//   - GCCandidate: Workspace that garbage collection would close, and why
+//   - ClosedPruneCandidate: Closed entry that the retention policy would delete, and why
 //   - WorkspaceExport: Portable format for workspace import/export
 //
 // Repository-related types:
@@ -20,6 +23,7 @@
 //
 // Hook-related types:
 //   - HookContext: Context provided to lifecycle hooks
+//   - HookResult: Outcome of a single hook command
 //
 // Orphan detection:
 //   - OrphanedWorktree: A worktree with missing or invalid references
@@ -27,34 +31,155 @@
 //
 // Canonical repository status:
 //   - CanonicalRepoStatus: Detailed status of a bare repository
+//   - CloneOptions: How a canonical repository is cloned (partial, shallow, single branch)
+//   - CloneMode: How an existing canonical repository was cloned
+//   - RepoMaintenanceResult: Outcome of maintaining a canonical repository
+//   - FetchRun: Latest background fetch of the canonical repositories
 package domain
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
 
 // CurrentWorkspaceVersion is the current workspace metadata schema version.
 // Version history:
 //   - 0: Legacy workspaces without version field (implicit)
 //   - 1: First versioned schema (adds version field)
-const CurrentWorkspaceVersion = 1
+//   - 2: Adds description, labels and notes
+const CurrentWorkspaceVersion = 2
 
 // Repo represents a git repository
 type Repo struct {
 	Name string `yaml:"name"`
 	URL  string `yaml:"url"`
+	// SparsePaths lists the directories checked out with cone-mode sparse checkout.
+	// Empty means the full tree.
+	SparsePaths []string `yaml:"sparse_paths,omitempty"`
+	// Remotes are configured in worktrees next to origin, which always points at URL.
+	Remotes []Remote `yaml:"remotes,omitempty"`
+	// PushRemote is the remote pushes go to. Empty means origin.
+	PushRemote string `yaml:"push_remote,omitempty"`
+}
+
+// OriginRemote is the remote that points at a repository's URL. Canonical repositories are
+// cloned from it and worktree branches pull from it, unless the repo has an upstream remote.
+const OriginRemote = "origin"
+
+// UpstreamRemote names the remote of the repository a fork was made from. When a repo whose
+// URL is a fork has one, worktree branches pull from it and pull requests target it.
+const UpstreamRemote = "upstream"
+
+// Remote returns the extra remote with the given name.
+func (r Repo) Remote(name string) (Remote, bool) {
+	for _, remote := range r.Remotes {
+		if remote.Name == name {
+			return remote, true
+		}
+	}
+
+	return Remote{}, false
+}
+
+// SyncRemote returns the remote worktree branches pull from: upstream when the repo has
+// one, otherwise origin.
+func (r Repo) SyncRemote() string {
+	if _, ok := r.Remote(UpstreamRemote); ok {
+		return UpstreamRemote
+	}
+
+	return OriginRemote
+}
+
+// PushRemoteName returns the remote pushes go to, origin when PushRemote is empty.
+func (r Repo) PushRemoteName() string {
+	if r.PushRemote == "" {
+		return OriginRemote
+	}
+
+	return r.PushRemote
+}
+
+// Remote is a named git remote, such as a fork.
+type Remote struct {
+	Name string `yaml:"name" json:"name"`
+	URL  string `yaml:"url" json:"url"`
 }
 
 // Workspace represents a work item
 type Workspace struct {
-	Version         int        `yaml:"version"`
-	ID              string     `yaml:"id"`
-	BranchName      string     `yaml:"branch_name,omitempty"`
-	Repos           []Repo     `yaml:"repos"`
-	ClosedAt        *time.Time `yaml:"closed_at,omitempty"`
-	SetupIncomplete bool       `yaml:"setup_incomplete,omitempty"`
-	Locked          bool       `yaml:"-" json:"locked,omitempty"`
-	DirName         string     `yaml:"-" json:"-"`
-	LastModified    time.Time  `yaml:"-"`
-	DiskUsageBytes  int64      `yaml:"-"`
+	Version         int           `yaml:"version"`
+	ID              string        `yaml:"id"`
+	BranchName      string        `yaml:"branch_name,omitempty"`
+	Repos           []Repo        `yaml:"repos"`
+	ClosedAt        *time.Time    `yaml:"closed_at,omitempty"`
+	Archives        []RepoArchive `yaml:"archives,omitempty"`
+	Snapshots       []Snapshot    `yaml:"snapshots,omitempty"`
+	SetupIncomplete bool          `yaml:"setup_incomplete,omitempty"`
+	Template        string        `yaml:"template,omitempty"`
+	Issue           *Issue        `yaml:"issue,omitempty"`
+	Description     string        `yaml:"description,omitempty"`
+	Labels          []string      `yaml:"labels,omitempty"`
+	Notes           string        `yaml:"notes,omitempty"`
+	Locked          bool          `yaml:"-" json:"locked,omitempty"`
+	DirName         string        `yaml:"-" json:"-"`
+	LastModified    time.Time     `yaml:"-"`
+	DiskUsageBytes  int64         `yaml:"-"`
+}
+
+// Issue is the issue-tracker entry a workspace was created for.
+type Issue struct {
+	ID    string `yaml:"id" json:"id"`
+	Title string `yaml:"title" json:"title"`
+	URL   string `yaml:"url,omitempty" json:"url,omitempty"`
+}
+
+// Snapshot is a named checkpoint of every repository in a workspace.
+type Snapshot struct {
+	Name      string         `yaml:"name" json:"name"`
+	CreatedAt time.Time      `yaml:"created_at" json:"created_at"`
+	Repos     []RepoSnapshot `yaml:"repos" json:"repos"`
+}
+
+// RepoSnapshot records a repository's HEAD and, when it was dirty, a stash-like commit of its changes.
+// Ref is the ref in the canonical repository that keeps the commits reachable.
+type RepoSnapshot struct {
+	Repo  string `yaml:"repo" json:"repo"`
+	Head  string `yaml:"head" json:"head"`
+	Stash string `yaml:"stash,omitempty" json:"stash,omitempty"`
+	Ref   string `yaml:"ref" json:"ref"`
+}
+
+// Target returns the commit the snapshot ref points at. The stash commit has HEAD as a
+// parent, so one ref keeps both reachable.
+func (r RepoSnapshot) Target() string {
+	if r.Stash != "" {
+		return r.Stash
+	}
+
+	return r.Head
+}
+
+// FindSnapshot returns the snapshot with the given name, or nil.
+func (w Workspace) FindSnapshot(name string) *Snapshot {
+	for i := range w.Snapshots {
+		if w.Snapshots[i].Name == name {
+			return &w.Snapshots[i]
+		}
+	}
+
+	return nil
+}
+
+// RepoArchive lists the artifacts saved for a repository when its workspace was closed.
+// File names are relative to the closed entry directory.
+type RepoArchive struct {
+	Repo    string `yaml:"repo" json:"repo"`
+	Patch   string `yaml:"patch,omitempty" json:"patch,omitempty"`
+	Bundle  string `yaml:"bundle,omitempty" json:"bundle,omitempty"`
+	Commits int    `yaml:"commits,omitempty" json:"commits,omitempty"`
 }
 
 // ClosedWorkspace describes a stored closed workspace entry.
@@ -81,6 +206,8 @@ type RepoStatus struct {
 	BehindRemote    int
 	Branch          string
 	Error           StatusError
+	// ReviewStatus is the pull request opened from the workspace branch, when known.
+	ReviewStatus *ReviewStatus `json:",omitempty"`
 }
 
 // StatusError represents an error state for repository status checks.
@@ -98,6 +225,17 @@ type WorkspaceStatus struct {
 	Repos      []RepoStatus
 }
 
+// HasLabels reports whether the workspace carries every one of the given labels.
+func (w Workspace) HasLabels(labels ...string) bool {
+	for _, label := range labels {
+		if !slices.Contains(w.Labels, label) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // IsStale reports whether the workspace is older than the provided threshold.
 func (w Workspace) IsStale(thresholdDays int) bool {
 	if thresholdDays <= 0 || w.LastModified.IsZero() {
@@ -157,6 +295,60 @@ type WorkspaceClosePreview struct {
 	RepoStatuses   []RepoCloseStatus `json:"repo_statuses,omitempty"`
 	DiskUsageBytes int64             `json:"disk_usage_bytes"`
 	KeepMetadata   bool              `json:"keep_metadata"`
+	// Archives lists the patches and bundles that closing with keep_metadata would save.
+	Archives []RepoArchive `json:"archives,omitempty"`
+}
+
+// GCReason explains why a workspace is a garbage collection candidate.
+type GCReason string
+
+const (
+	// GCReasonStale means the workspace was not modified within the stale threshold.
+	GCReasonStale GCReason = "stale"
+	// GCReasonMerged means every repository's branch was pushed and is merged into its default branch.
+	GCReasonMerged GCReason = "merged"
+	// GCReasonRemoteDeleted means every repository's branch was pushed and later deleted from origin.
+	GCReasonRemoteDeleted GCReason = "remote_deleted"
+)
+
+// GCReasons returns all garbage collection reasons.
+func GCReasons() []GCReason {
+	return []GCReason{GCReasonStale, GCReasonMerged, GCReasonRemoteDeleted}
+}
+
+// IsValid reports whether the reason is a supported value.
+func (r GCReason) IsValid() bool {
+	return slices.Contains(GCReasons(), r)
+}
+
+// GCCandidate is a workspace that garbage collection would close.
+type GCCandidate struct {
+	WorkspaceID string     `json:"workspace_id"`
+	Reasons     []GCReason `json:"reasons"`
+	// Blocked explains why the workspace is only closed with force, e.g. uncommitted changes.
+	Blocked string                 `json:"blocked,omitempty"`
+	Preview *WorkspaceClosePreview `json:"preview,omitempty"`
+}
+
+// RetentionReason explains which closed_retention limit a closed entry exceeds.
+type RetentionReason string
+
+const (
+	// RetentionReasonMaxAge means the entry was closed longer ago than the maximum age.
+	RetentionReasonMaxAge RetentionReason = "max_age"
+	// RetentionReasonMaxPerWorkspace means newer entries for the same workspace ID fill the per-ID limit.
+	RetentionReasonMaxPerWorkspace RetentionReason = "max_per_workspace"
+	// RetentionReasonMaxTotalSize means newer entries fill the total size limit.
+	RetentionReasonMaxTotalSize RetentionReason = "max_total_size"
+)
+
+// ClosedPruneCandidate is a closed entry that the retention policy would delete.
+type ClosedPruneCandidate struct {
+	WorkspaceID    string          `json:"workspace_id"`
+	ClosedAt       time.Time       `json:"closed_at"`
+	Path           string          `json:"path"`
+	DiskUsageBytes int64           `json:"disk_usage_bytes"`
+	Reason         RetentionReason `json:"reason"`
 }
 
 // RepoRemovePreview describes what would happen when removing a canonical repo.
@@ -169,12 +361,141 @@ type RepoRemovePreview struct {
 
 // CanonicalRepoStatus describes the health and usage of a canonical repository.
 type CanonicalRepoStatus struct {
-	Name           string     `json:"name"`
-	Path           string     `json:"path"`
-	DiskUsageBytes int64      `json:"disk_usage_bytes"`
-	LastFetchTime  *time.Time `json:"last_fetch_time"`
-	UsedByCount    int        `json:"used_by_count"`
-	UsedBy         []string   `json:"used_by"`
+	Name                string     `json:"name"`
+	Path                string     `json:"path"`
+	DiskUsageBytes      int64      `json:"disk_usage_bytes"`
+	CloneMode           CloneMode  `json:"clone_mode"`
+	LastFetchTime       *time.Time `json:"last_fetch_time"`
+	LastMaintenanceTime *time.Time `json:"last_maintenance_time"`
+	UsedByCount         int        `json:"used_by_count"`
+	UsedBy              []string   `json:"used_by"`
+	// BackgroundFetch is the repository's result in the latest "canopy daemon" run
+	BackgroundFetch *RepoFetchResult `json:"background_fetch,omitempty"`
+}
+
+// FetchOutcome identifies the result of fetching a canonical repository in the background.
+type FetchOutcome string
+
+const (
+	// FetchOutcomeFetched means the repository was fetched.
+	FetchOutcomeFetched FetchOutcome = "fetched"
+	// FetchOutcomeFailed means the fetch failed after its retries.
+	FetchOutcomeFailed FetchOutcome = "failed"
+	// FetchOutcomeSkipped means a workspace using the repository was locked, so it was left alone.
+	FetchOutcomeSkipped FetchOutcome = "skipped"
+)
+
+// RepoFetchResult describes the background fetch of a single canonical repository.
+type RepoFetchResult struct {
+	Name    string       `json:"name"`
+	Outcome FetchOutcome `json:"outcome"`
+	Error   string       `json:"error,omitempty"` // Why the fetch failed or was skipped
+}
+
+// FetchRun records one background fetch of every canonical repository. "canopy daemon"
+// keeps the latest run in a status file read by "repo status", "doctor" and the TUI.
+type FetchRun struct {
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+	NextRunAt  *time.Time        `json:"next_run_at,omitempty"` // nil when the daemon stopped or ran once
+	Repos      []RepoFetchResult `json:"repos"`
+}
+
+// Count returns how many repositories had the given outcome.
+func (r FetchRun) Count(outcome FetchOutcome) int {
+	count := 0
+
+	for _, repo := range r.Repos {
+		if repo.Outcome == outcome {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Summary describes the run in a few words, e.g. "3 fetched, 1 failed".
+func (r FetchRun) Summary() string {
+	parts := []string{fmt.Sprintf("%d fetched", r.Count(FetchOutcomeFetched))}
+
+	for _, outcome := range []FetchOutcome{FetchOutcomeFailed, FetchOutcomeSkipped} {
+		if n := r.Count(outcome); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, outcome))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// MaintenanceTasks lists the git maintenance tasks run on canonical repositories, in the
+// order they run.
+var MaintenanceTasks = []string{"gc", "loose-objects", "incremental-repack", "commit-graph", "pack-refs"}
+
+// RepoMaintenanceResult describes the maintenance of a single canonical repository.
+type RepoMaintenanceResult struct {
+	Name            string        `json:"name"`
+	Tasks           []string      `json:"tasks"`
+	SizeBeforeBytes int64         `json:"size_before_bytes"`
+	SizeAfterBytes  int64         `json:"size_after_bytes"`
+	Duration        time.Duration `json:"duration"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// FreedBytes returns how much disk space the maintenance reclaimed, negative if the
+// repository grew (e.g. by writing a commit-graph).
+func (r RepoMaintenanceResult) FreedBytes() int64 {
+	return r.SizeBeforeBytes - r.SizeAfterBytes
+}
+
+// CloneOptions describes how a canonical repository is cloned.
+// The zero value is a full clone of every branch.
+type CloneOptions struct {
+	// Filter is a partial clone filter such as "blob:none".
+	Filter string
+	// Depth truncates history to the given number of commits.
+	Depth int
+	// SingleBranch clones only the remote's default branch.
+	SingleBranch bool
+}
+
+// IsFull reports whether the options describe a full clone.
+func (o CloneOptions) IsFull() bool {
+	return o == CloneOptions{}
+}
+
+// CloneMode describes how an existing canonical repository was cloned.
+type CloneMode struct {
+	Filter       string `json:"filter,omitempty"`
+	Shallow      bool   `json:"shallow,omitempty"`
+	SingleBranch bool   `json:"single_branch,omitempty"`
+}
+
+// IsFull reports whether the repository has every object of every branch.
+func (m CloneMode) IsFull() bool {
+	return m == CloneMode{}
+}
+
+// String returns a short description such as "full" or "blob:none, shallow".
+func (m CloneMode) String() string {
+	if m.IsFull() {
+		return "full"
+	}
+
+	var parts []string
+
+	if m.Filter != "" {
+		parts = append(parts, m.Filter)
+	}
+
+	if m.Shallow {
+		parts = append(parts, "shallow")
+	}
+
+	if m.SingleBranch {
+		parts = append(parts, "single-branch")
+	}
+
+	return strings.Join(parts, ", ")
 }
 
 // SyncStatus identifies the outcome of a repository sync operation.
@@ -191,14 +512,49 @@ const (
 	SyncStatusTimeout SyncStatus = "timeout"
 	// SyncStatusError means an unexpected error occurred during sync.
 	SyncStatusError SyncStatus = "error"
+	// SyncStatusFetched means upstream changes were fetched but the worktree was left untouched.
+	SyncStatusFetched SyncStatus = "fetched"
 )
 
+// SyncStrategy identifies how a worktree integrates upstream changes during sync.
+type SyncStrategy string
+
+const (
+	// SyncStrategyFFOnly only fast-forwards the worktree branch.
+	SyncStrategyFFOnly SyncStrategy = "ff-only"
+	// SyncStrategyRebase rebases local commits onto the upstream branch.
+	SyncStrategyRebase SyncStrategy = "rebase"
+	// SyncStrategyMerge merges the upstream branch into the local branch.
+	SyncStrategyMerge SyncStrategy = "merge"
+	// SyncStrategyFetchOnly fetches upstream changes without touching the worktree.
+	SyncStrategyFetchOnly SyncStrategy = "fetch-only"
+)
+
+// SyncStrategies returns all supported sync strategies.
+func SyncStrategies() []SyncStrategy {
+	return []SyncStrategy{SyncStrategyFFOnly, SyncStrategyRebase, SyncStrategyMerge, SyncStrategyFetchOnly}
+}
+
+// IsValid reports whether the strategy is a supported value.
+func (s SyncStrategy) IsValid() bool {
+	for _, strategy := range SyncStrategies() {
+		if s == strategy {
+			return true
+		}
+	}
+
+	return false
+}
+
 // RepoSyncStatus describes the sync result for a single repository.
 type RepoSyncStatus struct {
-	Name    string     `json:"name"`
-	Status  SyncStatus `json:"status"`
-	Updated int        `json:"updated"` // Number of commits pulled
-	Error   string     `json:"error,omitempty"`
+	Name      string       `json:"name"`
+	Status    SyncStatus   `json:"status"`
+	Strategy  SyncStrategy `json:"strategy,omitempty"`
+	Onto      string       `json:"onto,omitempty"` // Upstream ref integrated with --onto-default
+	Updated   int          `json:"updated"`        // Number of commits pulled
+	Conflicts []string     `json:"conflicts,omitempty"`
+	Error     string       `json:"error,omitempty"`
 }
 
 // SyncResult aggregates sync results for an entire workspace.
@@ -209,6 +565,110 @@ type SyncResult struct {
 	TotalErrors  int              `json:"total_errors"`
 }
 
+// PushStatus identifies the outcome of pushing a repository.
+type PushStatus string
+
+const (
+	// PushStatusPushed means the branch was pushed.
+	PushStatusPushed PushStatus = "pushed"
+	// PushStatusRejected means the pre-flight dry-run push failed, so nothing was pushed.
+	PushStatusRejected PushStatus = "rejected"
+	// PushStatusFailed means the push itself failed.
+	PushStatusFailed PushStatus = "failed"
+	// PushStatusSkipped means the repository was not pushed because another repository failed.
+	PushStatusSkipped PushStatus = "skipped"
+	// PushStatusRolledBack means the pushed branch was restored after another repository failed.
+	PushStatusRolledBack PushStatus = "rolled-back"
+	// PushStatusRollbackFailed means restoring the pushed branch failed and the remote needs attention.
+	PushStatusRollbackFailed PushStatus = "rollback-failed"
+)
+
+// RepoPushStatus describes the push result for a single repository.
+type RepoPushStatus struct {
+	Name       string     `json:"name"`
+	Status     PushStatus `json:"status"`
+	LocalHead  string     `json:"local_head,omitempty"`
+	RemoteHead string     `json:"remote_head,omitempty"` // Remote commit before the push, empty for new branches
+	Error      string     `json:"error,omitempty"`
+}
+
+// PushResult aggregates push results for an entire workspace.
+type PushResult struct {
+	WorkspaceID string           `json:"workspace_id"`
+	Branch      string           `json:"branch"`
+	Atomic      bool             `json:"atomic"`
+	Repos       []RepoPushStatus `json:"repos"`
+	TotalPushed int              `json:"total_pushed"`
+	TotalErrors int              `json:"total_errors"`
+}
+
+// PullRequest is a pull request (or GitLab merge request) opened for a repository.
+type PullRequest struct {
+	Repo   string `json:"repo"`
+	Number int    `json:"number,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Base   string `json:"base,omitempty"`
+	Head   string `json:"head,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PullRequestResult aggregates the pull requests opened for an entire workspace.
+type PullRequestResult struct {
+	WorkspaceID  string        `json:"workspace_id"`
+	Title        string        `json:"title"`
+	PullRequests []PullRequest `json:"pull_requests"`
+	TotalCreated int           `json:"total_created"`
+	TotalErrors  int           `json:"total_errors"`
+}
+
+// PullRequestState is the lifecycle state of a pull request.
+type PullRequestState string
+
+// Known pull request states.
+const (
+	PullRequestOpen   PullRequestState = "open"
+	PullRequestClosed PullRequestState = "closed"
+	PullRequestMerged PullRequestState = "merged"
+)
+
+// ReviewDecision summarizes the reviews of a pull request.
+type ReviewDecision string
+
+// Known review decisions.
+const (
+	ReviewApproved         ReviewDecision = "approved"
+	ReviewChangesRequested ReviewDecision = "changes_requested"
+	ReviewRequired         ReviewDecision = "review_required"
+)
+
+// CheckConclusion summarizes the CI checks of a pull request's head commit.
+// An empty conclusion means no checks reported.
+type CheckConclusion string
+
+// Known check conclusions.
+const (
+	ChecksSuccess CheckConclusion = "success"
+	ChecksFailure CheckConclusion = "failure"
+	ChecksPending CheckConclusion = "pending"
+)
+
+// ReviewStatus is the state of the pull request opened from a workspace branch in a repository.
+type ReviewStatus struct {
+	Number         int              `json:"number,omitempty"`
+	URL            string           `json:"url,omitempty"`
+	State          PullRequestState `json:"state,omitempty"`
+	Draft          bool             `json:"draft,omitempty"`
+	ReviewDecision ReviewDecision   `json:"review_decision,omitempty"`
+	Checks         CheckConclusion  `json:"checks,omitempty"`
+	// Error is set when the status could not be fetched from the forge.
+	Error string `json:"error,omitempty"`
+}
+
+// AwaitingReview reports whether the pull request is open and not yet approved.
+func (r ReviewStatus) AwaitingReview() bool {
+	return r.Error == "" && r.State == PullRequestOpen && r.ReviewDecision != ReviewApproved
+}
+
 // WorkspaceExport is the portable format for exporting/importing workspaces.
 type WorkspaceExport struct {
 	Version          string       `yaml:"version" json:"version"`
@@ -221,9 +681,12 @@ type WorkspaceExport struct {
 
 // RepoExport is the portable format for a repository in an export.
 type RepoExport struct {
-	Name  string `yaml:"name" json:"name"`
-	URL   string `yaml:"url" json:"url"`
-	Alias string `yaml:"alias,omitempty" json:"alias,omitempty"`
+	Name        string   `yaml:"name" json:"name"`
+	URL         string   `yaml:"url" json:"url"`
+	Alias       string   `yaml:"alias,omitempty" json:"alias,omitempty"`
+	SparsePaths []string `yaml:"sparse_paths,omitempty" json:"sparse_paths,omitempty"`
+	Remotes     []Remote `yaml:"remotes,omitempty" json:"remotes,omitempty"`
+	PushRemote  string   `yaml:"push_remote,omitempty" json:"push_remote,omitempty"`
 }
 
 // HookContext provides context for hook execution.
@@ -232,6 +695,8 @@ type HookContext struct {
 	WorkspacePath string
 	BranchName    string
 	Repos         []Repo
+	// RepoTags holds registry tags keyed by repo name, used by hook conditions.
+	RepoTags map[string][]string
 }
 
 // HookCommandPreview describes a resolved hook command in dry-run mode.
@@ -245,4 +710,30 @@ type HookCommandPreview struct {
 	BranchName    string `json:"branch_name"`
 	RepoName      string `json:"repo_name,omitempty"`
 	RepoPath      string `json:"repo_path,omitempty"`
+	// MatchReason explains which when conditions matched, if any were set.
+	MatchReason string `json:"match_reason,omitempty"`
+	Skipped     bool   `json:"skipped,omitempty"`
+	SkipReason  string `json:"skip_reason,omitempty"`
+}
+
+// HookResult describes the outcome of a single hook command.
+// In dry-run mode only the embedded preview is populated.
+type HookResult struct {
+	HookCommandPreview
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	Stdout     string `json:"stdout,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// HookPreviews extracts the command previews from hook results, including skipped commands.
+func HookPreviews(results []HookResult) []HookCommandPreview {
+	previews := make([]HookCommandPreview, 0, len(results))
+
+	for _, r := range results {
+		previews = append(previews, r.HookCommandPreview)
+	}
+
+	return previews
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -41,6 +42,8 @@ const (
 	ErrHookTimeout            ErrorCode = "HOOK_TIMEOUT"
 	ErrPathInvalid            ErrorCode = "PATH_INVALID"
 	ErrPathNotDirectory       ErrorCode = "PATH_NOT_DIRECTORY"
+	ErrForgeRequestFailed     ErrorCode = "FORGE_REQUEST_FAILED"
+	ErrIssueLookupFailed      ErrorCode = "ISSUE_LOOKUP_FAILED"
 )
 
 // CanopyError is a typed error with code, message, cause, and context.
@@ -431,6 +434,28 @@ func NewPathNotDirectory(path string) *CanopyError {
 	}
 }
 
+// NewForgeRequestFailed creates an error for a failed forge API request.
+// Status is the HTTP status code, or 0 when no response was received.
+func NewForgeRequestFailed(forge, operation string, status int, cause error) *CanopyError {
+	return &CanopyError{
+		Code:    ErrForgeRequestFailed,
+		Message: fmt.Sprintf("%s %s failed", forge, operation),
+		Cause:   cause,
+		Context: map[string]string{"forge": forge, "operation": operation, "status": strconv.Itoa(status)},
+	}
+}
+
+// NewIssueLookupFailed creates an error for an issue that could not be fetched from an issue tracker.
+// Status is the HTTP status code, or 0 when no response was received.
+func NewIssueLookupFailed(tracker, issueID string, status int, cause error) *CanopyError {
+	return &CanopyError{
+		Code:    ErrIssueLookupFailed,
+		Message: fmt.Sprintf("failed to fetch issue %s from %s", issueID, tracker),
+		Cause:   cause,
+		Context: map[string]string{"tracker": tracker, "issue": issueID, "status": strconv.Itoa(status)},
+	}
+}
+
 // Sentinel errors for use with errors.Is().
 var (
 	WorkspaceNotFound      = &CanopyError{Code: ErrWorkspaceNotFound}
@@ -460,4 +485,6 @@ var (
 	HookTimeout            = &CanopyError{Code: ErrHookTimeout}
 	PathInvalid            = &CanopyError{Code: ErrPathInvalid}
 	PathNotDirectory       = &CanopyError{Code: ErrPathNotDirectory}
+	ForgeRequestFailed     = &CanopyError{Code: ErrForgeRequestFailed}
+	IssueLookupFailed      = &CanopyError{Code: ErrIssueLookupFailed}
 )
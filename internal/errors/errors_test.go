@@ -576,3 +576,40 @@ func TestNewPathNotDirectory(t *testing.T) {
 		t.Error("PathNotDirectory sentinel should match")
 	}
 }
+
+func TestNewForgeRequestFailed(t *testing.T) {
+	cause := errors.New("Bad credentials")
+	err := cerrors.NewForgeRequestFailed("github", "create pull request", 401, cause)
+
+	if err.Code != cerrors.ErrForgeRequestFailed {
+		t.Errorf("Code = %q, want %q", err.Code, cerrors.ErrForgeRequestFailed)
+	}
+
+	if err.Context["status"] != "401" {
+		t.Errorf("Context[status] = %q, want %q", err.Context["status"], "401")
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("cause should be unwrapped")
+	}
+
+	if !errors.Is(err, cerrors.ForgeRequestFailed) {
+		t.Error("ForgeRequestFailed sentinel should match")
+	}
+}
+
+func TestNewIssueLookupFailed(t *testing.T) {
+	err := cerrors.NewIssueLookupFailed("jira", "PROJ-123", 404, errors.New("Issue does not exist"))
+
+	if err.Code != cerrors.ErrIssueLookupFailed {
+		t.Errorf("Code = %q, want %q", err.Code, cerrors.ErrIssueLookupFailed)
+	}
+
+	if err.Context["issue"] != "PROJ-123" || err.Context["status"] != "404" {
+		t.Errorf("unexpected context: %v", err.Context)
+	}
+
+	if !errors.Is(err, cerrors.IssueLookupFailed) {
+		t.Error("IssueLookupFailed sentinel should match")
+	}
+}
@@ -0,0 +1,328 @@
+// Package filter parses and evaluates workspace filter expressions such as
+// "stale and not dirty" or "repo:backend or label:urgent".
+//
+// An expression combines predicates with "and", "or", "not" and parentheses; "not" binds
+// tighter than "and", which binds tighter than "or". Keywords are case-insensitive.
+//
+// # Predicates
+//
+// Metadata predicates are evaluated from the stored workspace metadata:
+//   - id:GLOB, repo:GLOB, branch:GLOB, label:GLOB, template:GLOB
+//
+// Globs use path.Match syntax, so "branch:feat/*" matches "feat/login" but not "feat/a/b".
+//
+// Activity predicates read the workspace's last modification time:
+//   - stale: older than the configured stale threshold
+//   - age>14d, age<=2w, ...: compares the time since the last modification; units are h, d and w
+//
+// Status predicates need the git status of every repository:
+//   - dirty: a repository has uncommitted changes
+//   - unpushed: a repository has commits not pushed to its upstream
+//   - behind: a repository is behind its upstream
+//
+// Lock predicates:
+//   - locked: another canopy process holds the workspace lock
+package filter
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// Env holds the facts an expression is evaluated against.
+type Env struct {
+	Workspace domain.Workspace
+	// Status is the workspace's git status. Status predicates are false when it is nil.
+	Status *domain.WorkspaceStatus
+	Locked bool
+	// StaleThresholdDays is the threshold used by the "stale" predicate.
+	StaleThresholdDays int
+	// Now is the reference time for "age" comparisons; the zero value means time.Now.
+	Now time.Time
+}
+
+// Requirements lists the facts beyond stored metadata that an expression reads,
+// so callers only compute what is needed.
+type Requirements struct {
+	Status   bool
+	Locks    bool
+	Activity bool
+}
+
+// Expr is a parsed filter expression.
+type Expr interface {
+	// Match reports whether the workspace described by env satisfies the expression.
+	Match(env Env) bool
+	String() string
+}
+
+// Requires returns the facts the expression reads.
+func Requires(e Expr) Requirements {
+	var req Requirements
+
+	walk(e, func(p predicate) {
+		switch p.kind {
+		case kindDirty, kindUnpushed, kindBehind:
+			req.Status = true
+		case kindLocked:
+			req.Locks = true
+		case kindStale, kindAge:
+			req.Activity = true
+		}
+	})
+
+	return req
+}
+
+func walk(e Expr, visit func(predicate)) {
+	switch n := e.(type) {
+	case andExpr:
+		walk(n.left, visit)
+		walk(n.right, visit)
+	case orExpr:
+		walk(n.left, visit)
+		walk(n.right, visit)
+	case notExpr:
+		walk(n.expr, visit)
+	case predicate:
+		visit(n)
+	}
+}
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Match(env Env) bool { return e.left.Match(env) && e.right.Match(env) }
+func (e andExpr) String() string     { return "(" + e.left.String() + " and " + e.right.String() + ")" }
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Match(env Env) bool { return e.left.Match(env) || e.right.Match(env) }
+func (e orExpr) String() string     { return "(" + e.left.String() + " or " + e.right.String() + ")" }
+
+type notExpr struct{ expr Expr }
+
+func (e notExpr) Match(env Env) bool { return !e.expr.Match(env) }
+func (e notExpr) String() string     { return "not " + e.expr.String() }
+
+type predicateKind int
+
+const (
+	kindID predicateKind = iota
+	kindRepo
+	kindBranch
+	kindLabel
+	kindTemplate
+	kindStale
+	kindAge
+	kindDirty
+	kindUnpushed
+	kindBehind
+	kindLocked
+)
+
+// globFields maps the "field:" prefix of glob predicates to their kind.
+var globFields = map[string]predicateKind{
+	"id":       kindID,
+	"repo":     kindRepo,
+	"branch":   kindBranch,
+	"label":    kindLabel,
+	"template": kindTemplate,
+}
+
+// flags maps bare-word predicates to their kind.
+var flags = map[string]predicateKind{
+	"stale":    kindStale,
+	"dirty":    kindDirty,
+	"unpushed": kindUnpushed,
+	"behind":   kindBehind,
+	"locked":   kindLocked,
+}
+
+// predicate is a single test on a workspace.
+type predicate struct {
+	kind predicateKind
+	text string
+	// glob is the pattern of field predicates.
+	glob string
+	// op and age describe "age" comparisons.
+	op  string
+	age time.Duration
+}
+
+func (p predicate) String() string { return p.text }
+
+func (p predicate) Match(env Env) bool {
+	ws := env.Workspace
+
+	switch p.kind {
+	case kindID:
+		return globMatch(p.glob, ws.ID)
+	case kindRepo:
+		for _, repo := range ws.Repos {
+			if globMatch(p.glob, repo.Name) {
+				return true
+			}
+		}
+
+		return false
+	case kindBranch:
+		return globMatch(p.glob, ws.BranchName)
+	case kindLabel:
+		for _, label := range ws.Labels {
+			if globMatch(p.glob, label) {
+				return true
+			}
+		}
+
+		return false
+	case kindTemplate:
+		return ws.Template != "" && globMatch(p.glob, ws.Template)
+	case kindStale:
+		return ws.IsStale(env.StaleThresholdDays)
+	case kindAge:
+		return p.matchAge(env)
+	case kindDirty:
+		return anyRepo(env.Status, func(r domain.RepoStatus) bool { return r.IsDirty })
+	case kindUnpushed:
+		return anyRepo(env.Status, func(r domain.RepoStatus) bool { return r.UnpushedCommits > 0 })
+	case kindBehind:
+		return anyRepo(env.Status, func(r domain.RepoStatus) bool { return r.BehindRemote > 0 })
+	case kindLocked:
+		return env.Locked
+	default:
+		return false
+	}
+}
+
+func (p predicate) matchAge(env Env) bool {
+	if env.Workspace.LastModified.IsZero() {
+		return false
+	}
+
+	now := env.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	age := now.Sub(env.Workspace.LastModified)
+
+	switch p.op {
+	case ">":
+		return age > p.age
+	case ">=":
+		return age >= p.age
+	case "<":
+		return age < p.age
+	case "<=":
+		return age <= p.age
+	default:
+		return false
+	}
+}
+
+func globMatch(pattern, value string) bool {
+	ok, _ := path.Match(pattern, value)
+
+	return ok
+}
+
+func anyRepo(status *domain.WorkspaceStatus, test func(domain.RepoStatus) bool) bool {
+	if status == nil {
+		return false
+	}
+
+	for _, repo := range status.Repos {
+		if test(repo) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parsePredicate parses a single term such as "repo:backend", "dirty" or "age>14d".
+func parsePredicate(tok token) (predicate, error) {
+	text := tok.text
+	lower := strings.ToLower(text)
+
+	if kind, ok := flags[lower]; ok {
+		return predicate{kind: kind, text: lower}, nil
+	}
+
+	if field, pattern, ok := strings.Cut(text, ":"); ok {
+		kind, known := globFields[strings.ToLower(field)]
+		if !known {
+			return predicate{}, syntaxError(tok.pos, fmt.Sprintf("unknown field %q", field))
+		}
+
+		if pattern == "" {
+			return predicate{}, syntaxError(tok.pos, fmt.Sprintf("%s: requires a value", field))
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return predicate{}, syntaxError(tok.pos, fmt.Sprintf("invalid pattern %q", pattern))
+		}
+
+		return predicate{kind: kind, text: strings.ToLower(field) + ":" + pattern, glob: pattern}, nil
+	}
+
+	if strings.HasPrefix(lower, "age") {
+		return parseAge(tok, lower)
+	}
+
+	return predicate{}, syntaxError(tok.pos, fmt.Sprintf("unknown predicate %q", text))
+}
+
+// parseAge parses comparisons such as "age>14d" or "age<=12h".
+func parseAge(tok token, text string) (predicate, error) {
+	rest := strings.TrimPrefix(text, "age")
+
+	var op string
+
+	for _, candidate := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	if op == "" {
+		return predicate{}, syntaxError(tok.pos, fmt.Sprintf("%q: expected age followed by >, >=, < or <= and a duration such as 14d", tok.text))
+	}
+
+	age, err := parseDuration(strings.TrimPrefix(rest, op))
+	if err != nil {
+		return predicate{}, syntaxError(tok.pos, fmt.Sprintf("%q: %s", tok.text, err))
+	}
+
+	return predicate{kind: kindAge, text: text, op: op, age: age}, nil
+}
+
+// parseDuration parses a whole number followed by h (hours), d (days) or w (weeks).
+func parseDuration(value string) (time.Duration, error) {
+	if len(value) < 2 {
+		return 0, fmt.Errorf("expected a duration such as 12h, 14d or 2w")
+	}
+
+	unit := map[byte]time.Duration{'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[value[len(value)-1]]
+	if unit == 0 {
+		return 0, fmt.Errorf("unknown duration unit %q (use h, d or w)", value[len(value)-1:])
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return time.Duration(n) * unit, nil
+}
+
+func syntaxError(pos int, detail string) error {
+	return cerrors.NewInvalidArgument("filter", fmt.Sprintf("%s at position %d", detail, pos+1))
+}
@@ -0,0 +1,162 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+func TestParse_Match(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	env := Env{
+		Workspace: domain.Workspace{
+			ID:           "PROJ-1",
+			BranchName:   "feat/login",
+			Repos:        []domain.Repo{{Name: "backend"}, {Name: "frontend"}},
+			Labels:       []string{"urgent", "team:core"},
+			Template:     "fullstack",
+			LastModified: now.Add(-20 * 24 * time.Hour),
+		},
+		Status: &domain.WorkspaceStatus{Repos: []domain.RepoStatus{
+			{Name: "backend", IsDirty: true},
+			{Name: "frontend", UnpushedCommits: 2},
+		}},
+		StaleThresholdDays: 14,
+		Now:                now,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "repo:backend", want: true},
+		{expr: "repo:back*", want: true},
+		{expr: "repo:api", want: false},
+		{expr: "branch:feat/*", want: true},
+		{expr: "branch:fix/*", want: false},
+		{expr: "label:urgent", want: true},
+		{expr: "label:team:*", want: true},
+		{expr: "id:PROJ-*", want: true},
+		{expr: "template:fullstack", want: true},
+		{expr: "dirty", want: true},
+		{expr: "unpushed", want: true},
+		{expr: "behind", want: false},
+		{expr: "locked", want: false},
+		{expr: "age>14d", want: true},
+		{expr: "age>=3w", want: false},
+		{expr: "age<2w", want: false},
+		{expr: "age<=480h", want: true},
+		{expr: "stale and not dirty", want: false},
+		{expr: "stale and dirty", want: true},
+		{expr: "NOT dirty OR label:urgent", want: true},
+		{expr: "repo:api or repo:backend and dirty", want: true},
+		{expr: "(repo:api or repo:backend) and behind", want: false},
+		{expr: "not (locked or behind)", want: true},
+		{expr: "not not dirty", want: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.expr, err)
+			}
+
+			if got := e.Match(env); got != tt.want {
+				t.Errorf("Parse(%q) = %s, Match = %v, want %v", tt.expr, e, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "", wantErr: "expression is empty"},
+		{expr: "dirty and", wantErr: "unexpected end of expression"},
+		{expr: "(dirty", wantErr: `expected ")"`},
+		{expr: "dirty stale", wantErr: `expected "and" or "or" before "stale" at position 7`},
+		{expr: "owner:me", wantErr: `unknown field "owner"`},
+		{expr: "repo:", wantErr: "requires a value"},
+		{expr: "repo:[", wantErr: "invalid pattern"},
+		{expr: "clean", wantErr: `unknown predicate "clean"`},
+		{expr: "age=3d", wantErr: "expected age followed by"},
+		{expr: "age>3m", wantErr: "unknown duration unit"},
+		{expr: "or dirty", wantErr: `unexpected "or"`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.expr)
+			if err == nil {
+				t.Fatalf("Parse(%q) succeeded, want error", tt.expr)
+			}
+
+			var cerr *cerrors.CanopyError
+			if !errors.As(err, &cerr) || cerr.Code != cerrors.ErrInvalidArgument {
+				t.Errorf("expected invalid argument error, got %v", err)
+			}
+
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequires(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr string
+		want Requirements
+	}{
+		{expr: "repo:backend and label:urgent", want: Requirements{}},
+		{expr: "stale and not dirty", want: Requirements{Status: true, Activity: true}},
+		{expr: "locked or age>1w", want: Requirements{Locks: true, Activity: true}},
+		{expr: "not (unpushed or behind)", want: Requirements{Status: true}},
+	}
+
+	for _, tt := range tests {
+		e, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.expr, err)
+		}
+
+		if got := Requires(e); got != tt.want {
+			t.Errorf("Requires(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestMatch_MissingStatus(t *testing.T) {
+	t.Parallel()
+
+	e, err := Parse("dirty or not unpushed")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// Without status, status predicates are false
+	if !e.Match(Env{Workspace: domain.Workspace{ID: "ws"}}) {
+		t.Error("expected match when status is unknown")
+	}
+}
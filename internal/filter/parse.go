@@ -0,0 +1,182 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+	tokenEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// Parse parses a filter expression.
+func Parse(expr string) (Expr, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, syntaxError(0, "expression is empty")
+	}
+
+	p := &parser{tokens: tokenize(expr)}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, syntaxError(tok.pos, fmt.Sprintf("expected \"and\" or \"or\" before %q", tok.text))
+	}
+
+	return e, nil
+}
+
+// tokenize splits an expression into parentheses and whitespace-separated words.
+func tokenize(expr string) []token {
+	var tokens []token
+
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: i})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				i++
+			}
+
+			text := string(runes[start:i])
+			tokens = append(tokens, token{kind: wordKind(text), text: text, pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)})
+}
+
+func wordKind(text string) tokenKind {
+	switch strings.ToLower(text) {
+	case "and":
+		return tokenAnd
+	case "or":
+		return tokenOr
+	case "not":
+		return tokenNot
+	default:
+		return tokenTerm
+	}
+}
+
+// parser is a recursive descent parser over the grammar:
+//
+//	or    = and { "or" and }
+//	and   = unary { "and" unary }
+//	unary = "not" unary | "(" or ")" | term
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNot:
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{expr: e}, nil
+	case tokenOpen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenClose {
+			return nil, syntaxError(closing.pos, "expected \")\"")
+		}
+
+		return e, nil
+	case tokenTerm:
+		return parsePredicate(tok)
+	case tokenEOF:
+		return nil, syntaxError(tok.pos, "unexpected end of expression")
+	default:
+		return nil, syntaxError(tok.pos, fmt.Sprintf("unexpected %q", tok.text))
+	}
+}
@@ -0,0 +1,38 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/httpjson"
+)
+
+// apiClient sends JSON requests to a forge REST API.
+type apiClient struct {
+	forge   string
+	baseURL string
+	client  *http.Client
+	// authorize sets the authentication headers on a request.
+	authorize func(*http.Request)
+}
+
+// do sends in as the JSON body of a request to path and decodes the response into out.
+// Either may be nil. Non-2xx responses become forge request errors.
+func (c *apiClient) do(ctx context.Context, operation, method, path string, in, out interface{}) error {
+	err := httpjson.Do(ctx, c.client, method, c.baseURL+path, c.authorize, in, out)
+
+	var reqErr *httpjson.Error
+
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return cerrors.NewContextError(ctx, c.forge+" "+operation, c.baseURL)
+	case errors.As(err, &reqErr):
+		return cerrors.NewForgeRequestFailed(c.forge, operation, reqErr.Status, reqErr.Err)
+	default:
+		return cerrors.NewInternalError("encode "+c.forge+" request", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package forge implements ports.Forge for the GitHub, GitLab and Gitea REST APIs.
+//
+// Forges are looked up by the host of a repository's upstream URL. github.com and
+// gitlab.com are known out of the box; other hosts (GitHub Enterprise, self-hosted
+// GitLab, Gitea) are configured with the "forges" config section. API tokens are read
+// from the environment: GITHUB_TOKEN or GH_TOKEN, GITLAB_TOKEN, GITEA_TOKEN, or the
+// variable named by token_env.
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/httpjson"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// DefaultTimeout bounds each forge API request.
+const DefaultTimeout = 30 * time.Second
+
+// Compile-time check that Resolver implements ports.ForgeResolver.
+var _ ports.ForgeResolver = (*Resolver)(nil)
+
+// knownForges are the public forges that work without configuration.
+var knownForges = []config.ForgeConfig{
+	{Host: "github.com", Type: config.ForgeTypeGitHub},
+	{Host: "gitlab.com", Type: config.ForgeTypeGitLab},
+}
+
+// defaultTokenEnvs lists the environment variables checked for each forge type's token.
+var defaultTokenEnvs = map[string][]string{
+	config.ForgeTypeGitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	config.ForgeTypeGitLab: {"GITLAB_TOKEN"},
+	config.ForgeTypeGitea:  {"GITEA_TOKEN"},
+}
+
+// Resolver finds the forge for a git host from the configured forges.
+type Resolver struct {
+	forges map[string]config.ForgeConfig
+	getenv func(string) string
+	client *http.Client
+}
+
+// NewResolver creates a Resolver for the configured forges, which take precedence
+// over the known public forges.
+func NewResolver(forges []config.ForgeConfig, opts ...httpjson.Option) *Resolver {
+	o := httpjson.NewOptions(DefaultTimeout, opts...)
+
+	r := &Resolver{
+		forges: make(map[string]config.ForgeConfig),
+		getenv: o.Getenv,
+		client: o.Client,
+	}
+
+	for _, f := range append(append([]config.ForgeConfig{}, knownForges...), forges...) {
+		r.forges[strings.ToLower(f.Host)] = f
+	}
+
+	return r
+}
+
+// ForgeFor returns the forge for a host such as github.com.
+func (r *Resolver) ForgeFor(host string) (ports.Forge, error) {
+	cfg, ok := r.forges[strings.ToLower(host)]
+	if !ok {
+		return nil, cerrors.NewConfigValidation("forges", fmt.Sprintf("no forge configured for host %q", host))
+	}
+
+	envs := defaultTokenEnvs[cfg.Type]
+	if cfg.TokenEnv != "" {
+		envs = []string{cfg.TokenEnv}
+	}
+
+	var token string
+
+	for _, env := range envs {
+		if token = r.getenv(env); token != "" {
+			break
+		}
+	}
+
+	if token == "" {
+		return nil, cerrors.NewForgeRequestFailed(cfg.Type, "authenticate", 0,
+			fmt.Errorf("no API token for %s: set %s", host, strings.Join(envs, " or ")))
+	}
+
+	return New(cfg, token, r.client)
+}
+
+// New returns the forge client for cfg, authenticating with token.
+func New(cfg config.ForgeConfig, token string, client *http.Client) (ports.Forge, error) {
+	baseURL := strings.TrimSuffix(cfg.APIURL, "/")
+
+	switch cfg.Type {
+	case config.ForgeTypeGitHub:
+		if baseURL == "" {
+			baseURL = "https://" + cfg.Host + "/api/v3"
+			if strings.EqualFold(cfg.Host, "github.com") {
+				baseURL = "https://api.github.com"
+			}
+		}
+
+		return newGitHub(baseURL, token, client), nil
+	case config.ForgeTypeGitLab:
+		if baseURL == "" {
+			baseURL = "https://" + cfg.Host + "/api/v4"
+		}
+
+		return newGitLab(baseURL, token, client), nil
+	case config.ForgeTypeGitea:
+		if baseURL == "" {
+			baseURL = "https://" + cfg.Host + "/api/v1"
+		}
+
+		return newGitea(baseURL, token, client), nil
+	default:
+		return nil, cerrors.NewConfigValidation("forges", fmt.Sprintf("unsupported forge type %q for host %q", cfg.Type, cfg.Host))
+	}
+}
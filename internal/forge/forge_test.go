@@ -0,0 +1,472 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/httpjson"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// stubRequest is a request received by the stub server.
+type stubRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// newStubServer starts a server that records requests and answers them with status and response.
+func newStubServer(t *testing.T, status int, response string) (*httptest.Server, func() []stubRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []stubRequest
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := stubRequest{Method: r.Method, Path: r.URL.EscapedPath(), Header: r.Header.Clone()}
+		_ = json.NewDecoder(r.Body).Decode(&req.Body)
+
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []stubRequest {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]stubRequest(nil), requests...)
+	}
+}
+
+func TestForges_CreateAndUpdatePullRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		forgeType   string
+		owner       string
+		response    string
+		createPath  string
+		updatePath  string
+		updateVerb  string
+		authHeader  string
+		authValue   string
+		wantTitle   string
+		headField   string
+		bodyField   string
+		wantURL     string
+		wantNumber  int
+		extraChecks func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			forgeType:  config.ForgeTypeGitHub,
+			owner:      "org",
+			response:   `{"number": 12, "html_url": "https://github.com/org/repo/pull/12"}`,
+			createPath: "/repos/org/repo/pulls",
+			updatePath: "/repos/org/repo/pulls/12",
+			updateVerb: http.MethodPatch,
+			authHeader: "Authorization",
+			authValue:  "Bearer secret",
+			wantTitle:  "Add feature",
+			headField:  "head",
+			bodyField:  "body",
+			wantURL:    "https://github.com/org/repo/pull/12",
+			wantNumber: 12,
+			extraChecks: func(t *testing.T, body map[string]interface{}) {
+				t.Helper()
+
+				if body["draft"] != true {
+					t.Errorf("expected draft flag, got %v", body["draft"])
+				}
+			},
+		},
+		{
+			forgeType:  config.ForgeTypeGitLab,
+			owner:      "group/subgroup",
+			response:   `{"iid": 7, "web_url": "https://gitlab.com/group/subgroup/repo/-/merge_requests/7"}`,
+			createPath: "/projects/group%2Fsubgroup%2Frepo/merge_requests",
+			updatePath: "/projects/group%2Fsubgroup%2Frepo/merge_requests/7",
+			updateVerb: http.MethodPut,
+			authHeader: "PRIVATE-TOKEN",
+			authValue:  "secret",
+			wantTitle:  "Draft: Add feature",
+			headField:  "source_branch",
+			bodyField:  "description",
+			wantURL:    "https://gitlab.com/group/subgroup/repo/-/merge_requests/7",
+			wantNumber: 7,
+		},
+		{
+			forgeType:  config.ForgeTypeGitea,
+			owner:      "org",
+			response:   `{"number": 3, "html_url": "https://git.example.com/org/repo/pulls/3"}`,
+			createPath: "/repos/org/repo/pulls",
+			updatePath: "/repos/org/repo/pulls/3",
+			updateVerb: http.MethodPatch,
+			authHeader: "Authorization",
+			authValue:  "token secret",
+			wantTitle:  "WIP: Add feature",
+			headField:  "head",
+			bodyField:  "body",
+			wantURL:    "https://git.example.com/org/repo/pulls/3",
+			wantNumber: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.forgeType, func(t *testing.T) {
+			t.Parallel()
+
+			server, requests := newStubServer(t, http.StatusCreated, tt.response)
+
+			forge, err := New(config.ForgeConfig{Host: "example.com", Type: tt.forgeType, APIURL: server.URL}, "secret", server.Client())
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			spec := ports.PullRequestSpec{Title: "Add feature", Body: "Details", Head: "PROJ-1", Base: "main", Draft: true}
+
+			pr, err := forge.CreatePullRequest(context.Background(), tt.owner, "repo", spec)
+			if err != nil {
+				t.Fatalf("CreatePullRequest failed: %v", err)
+			}
+
+			if pr.Number != tt.wantNumber || pr.URL != tt.wantURL {
+				t.Errorf("got PR %d %s, want %d %s", pr.Number, pr.URL, tt.wantNumber, tt.wantURL)
+			}
+
+			if err := forge.UpdatePullRequestBody(context.Background(), tt.owner, "repo", pr.Number, "Updated"); err != nil {
+				t.Fatalf("UpdatePullRequestBody failed: %v", err)
+			}
+
+			got := requests()
+			if len(got) != 2 {
+				t.Fatalf("expected 2 requests, got %d", len(got))
+			}
+
+			create, update := got[0], got[1]
+
+			if create.Method != http.MethodPost || create.Path != tt.createPath {
+				t.Errorf("create request = %s %s, want POST %s", create.Method, create.Path, tt.createPath)
+			}
+
+			if v := create.Header.Get(tt.authHeader); v != tt.authValue {
+				t.Errorf("%s header = %q, want %q", tt.authHeader, v, tt.authValue)
+			}
+
+			if create.Body["title"] != tt.wantTitle || create.Body[tt.headField] != "PROJ-1" || create.Body[tt.bodyField] != "Details" {
+				t.Errorf("unexpected create body: %v", create.Body)
+			}
+
+			if tt.extraChecks != nil {
+				tt.extraChecks(t, create.Body)
+			}
+
+			if update.Method != tt.updateVerb || update.Path != tt.updatePath {
+				t.Errorf("update request = %s %s, want %s %s", update.Method, update.Path, tt.updateVerb, tt.updatePath)
+			}
+
+			if update.Body[tt.bodyField] != "Updated" {
+				t.Errorf("unexpected update body: %v", update.Body)
+			}
+		})
+	}
+}
+
+func TestForges_CreatePullRequestFromFork(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		forgeType  string
+		createPath string
+		headField  string
+		wantHead   string
+		wantTarget interface{}
+	}{
+		{forgeType: config.ForgeTypeGitHub, createPath: "/repos/org/repo/pulls", headField: "head", wantHead: "me:PROJ-1"},
+		{forgeType: config.ForgeTypeGitLab, createPath: "/projects/me%2Frepo-fork/merge_requests", headField: "source_branch", wantHead: "PROJ-1", wantTarget: float64(10)},
+		{forgeType: config.ForgeTypeGitea, createPath: "/repos/org/repo/pulls", headField: "head", wantHead: "me:PROJ-1"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.forgeType, func(t *testing.T) {
+			t.Parallel()
+
+			// GitLab looks up the target project ID first; the same response serves both requests
+			server, requests := newStubServer(t, http.StatusCreated, `{"id": 10, "number": 3, "iid": 3}`)
+
+			forge, err := New(config.ForgeConfig{Host: "example.com", Type: tt.forgeType, APIURL: server.URL}, "secret", server.Client())
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			spec := ports.PullRequestSpec{
+				Title:    "Add feature",
+				Head:     "PROJ-1",
+				HeadRepo: giturl.Remote{Host: "example.com", Owner: "me", Name: "repo-fork"},
+				Base:     "main",
+			}
+
+			pr, err := forge.CreatePullRequest(context.Background(), "org", "repo", spec)
+			if err != nil {
+				t.Fatalf("CreatePullRequest failed: %v", err)
+			}
+
+			if pr.Number != 3 || pr.Head != "PROJ-1" {
+				t.Errorf("got PR %+v, want number 3 from PROJ-1", pr)
+			}
+
+			got := requests()
+			create := got[len(got)-1]
+
+			if create.Method != http.MethodPost || create.Path != tt.createPath {
+				t.Errorf("create request = %s %s, want POST %s", create.Method, create.Path, tt.createPath)
+			}
+
+			if create.Body[tt.headField] != tt.wantHead || create.Body["target_project_id"] != tt.wantTarget {
+				t.Errorf("unexpected create body: %v", create.Body)
+			}
+		})
+	}
+}
+
+func TestForge_ErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newStubServer(t, http.StatusUnprocessableEntity,
+		`{"message": "Validation Failed", "errors": [{"message": "A pull request already exists for org:PROJ-1."}]}`)
+
+	forge, err := New(config.ForgeConfig{Host: "github.com", Type: config.ForgeTypeGitHub, APIURL: server.URL}, "secret", server.Client())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = forge.CreatePullRequest(context.Background(), "org", "repo", ports.PullRequestSpec{Title: "t", Head: "PROJ-1", Base: "main"})
+
+	var cerr *cerrors.CanopyError
+	if !errors.As(err, &cerr) || cerr.Code != cerrors.ErrForgeRequestFailed {
+		t.Fatalf("expected forge request error, got %v", err)
+	}
+
+	if cerr.Context["status"] != "422" || !strings.Contains(err.Error(), "A pull request already exists") {
+		t.Errorf("unexpected error details: %v", err)
+	}
+}
+
+func TestResolver_ForgeFor(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"GH_TOKEN": "gh", "CUSTOM_TOKEN": "custom"}
+	resolver := NewResolver(
+		[]config.ForgeConfig{{Host: "Git.Example.com", Type: config.ForgeTypeGitea, TokenEnv: "CUSTOM_TOKEN"}},
+		httpjson.WithGetenv(func(key string) string { return env[key] }),
+	)
+
+	if _, err := resolver.ForgeFor("github.com"); err != nil {
+		t.Errorf("expected github.com to be known, got %v", err)
+	}
+
+	if forge, err := resolver.ForgeFor("git.example.com"); err != nil {
+		t.Errorf("expected configured host to resolve, got %v", err)
+	} else if _, ok := forge.(*gitea); !ok {
+		t.Errorf("expected gitea forge, got %T", forge)
+	}
+
+	if _, err := resolver.ForgeFor("gitlab.com"); err == nil || !strings.Contains(err.Error(), "GITLAB_TOKEN") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+
+	if _, err := resolver.ForgeFor("unknown.example.com"); err == nil {
+		t.Error("expected error for unknown host")
+	}
+}
+
+// newRouteServer starts a server answering each escaped path in routes with its JSON response,
+// and 404 for any other path. It returns the query strings received per path.
+func newRouteServer(t *testing.T, routes map[string]string) (*httptest.Server, func() map[string]string) {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		queries = make(map[string]string)
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries[r.URL.EscapedPath()] = r.URL.RawQuery
+		mu.Unlock()
+
+		response, ok := routes[r.URL.EscapedPath()]
+		if !ok {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return queries
+	}
+}
+
+func TestForges_ReviewStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		forgeType string
+		headRepo  giturl.Remote
+		routes    map[string]string
+		listPath  string
+		wantQuery string
+		want      *domain.ReviewStatus
+	}{
+		{
+			name:      "github open pull request",
+			forgeType: config.ForgeTypeGitHub,
+			routes: map[string]string{
+				"/repos/org/repo/pulls": `[{"number": 12, "html_url": "https://github.com/org/repo/pull/12", "state": "open",
+					"merged_at": null, "head": {"ref": "PROJ-1", "sha": "abc"}}]`,
+				"/repos/org/repo/pulls/12/reviews": `[{"user": {"login": "a"}, "state": "APPROVED"},
+					{"user": {"login": "b"}, "state": "CHANGES_REQUESTED"}, {"user": {"login": "b"}, "state": "APPROVED"},
+					{"user": {"login": "b"}, "state": "COMMENTED"}]`,
+				"/repos/org/repo/commits/abc/check-runs": `{"check_runs": [{"status": "completed", "conclusion": "success"},
+					{"status": "in_progress", "conclusion": null}]}`,
+				"/repos/org/repo/commits/abc/status": `{"state": "success", "total_count": 1}`,
+			},
+			listPath:  "/repos/org/repo/pulls",
+			wantQuery: "head=org%3APROJ-1",
+			want: &domain.ReviewStatus{Number: 12, URL: "https://github.com/org/repo/pull/12", State: domain.PullRequestOpen,
+				ReviewDecision: domain.ReviewApproved, Checks: domain.ChecksPending},
+		},
+		{
+			name:      "github pull request from a fork",
+			forgeType: config.ForgeTypeGitHub,
+			headRepo:  giturl.Remote{Host: "example.com", Owner: "me", Name: "repo"},
+			routes: map[string]string{
+				"/repos/org/repo/pulls": `[{"number": 13, "html_url": "https://github.com/org/repo/pull/13", "state": "closed",
+					"merged_at": null, "head": {"ref": "PROJ-1", "sha": "abc"}}]`,
+			},
+			listPath:  "/repos/org/repo/pulls",
+			wantQuery: "head=me%3APROJ-1",
+			want:      &domain.ReviewStatus{Number: 13, URL: "https://github.com/org/repo/pull/13", State: domain.PullRequestClosed},
+		},
+		{
+			name:      "github without pull request",
+			forgeType: config.ForgeTypeGitHub,
+			routes:    map[string]string{"/repos/org/repo/pulls": `[]`},
+			listPath:  "/repos/org/repo/pulls",
+			wantQuery: "state=all",
+		},
+		{
+			name:      "gitlab draft merge request",
+			forgeType: config.ForgeTypeGitLab,
+			routes: map[string]string{
+				"/projects/org%2Frepo/merge_requests": `[{"iid": 7, "web_url": "https://gitlab.com/org/repo/-/merge_requests/7",
+					"state": "opened", "draft": true}]`,
+				"/projects/org%2Frepo/merge_requests/7":           `{"iid": 7, "head_pipeline": {"status": "failed"}}`,
+				"/projects/org%2Frepo/merge_requests/7/approvals": `{"approved": true, "approvals_left": 0, "approved_by": []}`,
+			},
+			listPath:  "/projects/org%2Frepo/merge_requests",
+			wantQuery: "source_branch=PROJ-1",
+			want: &domain.ReviewStatus{Number: 7, URL: "https://gitlab.com/org/repo/-/merge_requests/7", State: domain.PullRequestOpen,
+				Draft: true, ReviewDecision: domain.ReviewRequired, Checks: domain.ChecksFailure},
+		},
+		{
+			name:      "gitlab merge request from a fork",
+			forgeType: config.ForgeTypeGitLab,
+			headRepo:  giturl.Remote{Host: "example.com", Owner: "me", Name: "repo-fork"},
+			routes: map[string]string{
+				"/projects/me%2Frepo-fork": `{"id": 42}`,
+				"/projects/org%2Frepo/merge_requests": `[
+					{"iid": 8, "state": "opened", "source_project_id": 41, "target_project_id": 10},
+					{"iid": 9, "web_url": "https://gitlab.com/org/repo/-/merge_requests/9", "state": "merged",
+					"source_project_id": 42, "target_project_id": 10}]`,
+			},
+			listPath:  "/projects/org%2Frepo/merge_requests",
+			wantQuery: "source_branch=PROJ-1",
+			want:      &domain.ReviewStatus{Number: 9, URL: "https://gitlab.com/org/repo/-/merge_requests/9", State: domain.PullRequestMerged},
+		},
+		{
+			name:      "gitea merged pull request",
+			forgeType: config.ForgeTypeGitea,
+			routes: map[string]string{
+				"/repos/org/repo/pulls": `[{"number": 2, "state": "open", "head": {"ref": "other"}},
+					{"number": 3, "html_url": "https://git.example.com/org/repo/pulls/3", "state": "closed", "merged": true,
+					"head": {"ref": "PROJ-1", "sha": "def"}}]`,
+			},
+			listPath:  "/repos/org/repo/pulls",
+			wantQuery: "state=all",
+			want:      &domain.ReviewStatus{Number: 3, URL: "https://git.example.com/org/repo/pulls/3", State: domain.PullRequestMerged},
+		},
+		{
+			name:      "gitea pull request from a fork",
+			forgeType: config.ForgeTypeGitea,
+			headRepo:  giturl.Remote{Host: "example.com", Owner: "me", Name: "repo"},
+			routes: map[string]string{
+				"/repos/org/repo/pulls": `[{"number": 2, "state": "closed", "head": {"ref": "PROJ-1", "repo": {"owner": {"login": "org"}}}},
+					{"number": 4, "html_url": "https://git.example.com/org/repo/pulls/4", "state": "closed",
+					"head": {"ref": "PROJ-1", "repo": {"owner": {"login": "me"}}}}]`,
+			},
+			listPath:  "/repos/org/repo/pulls",
+			wantQuery: "state=all",
+			want:      &domain.ReviewStatus{Number: 4, URL: "https://git.example.com/org/repo/pulls/4", State: domain.PullRequestClosed},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, queries := newRouteServer(t, tt.routes)
+
+			forge, err := New(config.ForgeConfig{Host: "example.com", Type: tt.forgeType, APIURL: server.URL}, "secret", server.Client())
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			got, err := forge.ReviewStatus(context.Background(), "org", "repo", "PROJ-1", tt.headRepo)
+			if err != nil {
+				t.Fatalf("ReviewStatus failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReviewStatus = %+v, want %+v", got, tt.want)
+			}
+
+			if query := queries()[tt.listPath]; !strings.Contains(query, tt.wantQuery) {
+				t.Errorf("list query = %q, want it to contain %q", query, tt.wantQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// gitea talks to the Gitea (and Forgejo) REST API.
+type gitea struct {
+	api apiClient
+}
+
+func newGitea(baseURL, token string, client *http.Client) *gitea {
+	return &gitea{api: apiClient{
+		forge:   config.ForgeTypeGitea,
+		baseURL: baseURL,
+		client:  client,
+		authorize: func(req *http.Request) {
+			req.Header.Set("Authorization", "token "+token)
+		},
+	}}
+}
+
+// CreatePullRequest opens a pull request. Drafts use Gitea's "WIP:" title prefix.
+func (g *gitea) CreatePullRequest(ctx context.Context, owner, name string, spec ports.PullRequestSpec) (*domain.PullRequest, error) {
+	title := spec.Title
+	if spec.Draft {
+		title = "WIP: " + title
+	}
+
+	in := map[string]string{
+		"title": title,
+		"body":  spec.Body,
+		"head":  qualifiedHead(spec.Head, spec.HeadRepo),
+		"base":  spec.Base,
+	}
+
+	var out gitHubPullRequest
+	if err := g.api.do(ctx, "create pull request", http.MethodPost, gitHubRepoPath(owner, name)+"/pulls", in, &out); err != nil {
+		return nil, err
+	}
+
+	return &domain.PullRequest{Number: out.Number, URL: out.HTMLURL, Base: spec.Base, Head: spec.Head}, nil
+}
+
+// UpdatePullRequestBody replaces the description of a pull request.
+func (g *gitea) UpdatePullRequestBody(ctx context.Context, owner, name string, number int, body string) error {
+	path := fmt.Sprintf("%s/pulls/%d", gitHubRepoPath(owner, name), number)
+
+	return g.api.do(ctx, "update pull request", http.MethodPatch, path, map[string]string{"body": body}, nil)
+}
+
+// giteaPageSize is how many recently updated pull requests are searched for a head branch,
+// since the Gitea API cannot filter pull requests by head.
+const giteaPageSize = 50
+
+// ReviewStatus finds the most recently updated pull request from head and, while it is open,
+// its review decision and combined commit status.
+func (g *gitea) ReviewStatus(ctx context.Context, owner, name, head string, headRepo giturl.Remote) (*domain.ReviewStatus, error) {
+	repoPath := gitHubRepoPath(owner, name)
+	query := url.Values{"state": {"all"}, "sort": {"recentupdate"}, "limit": {fmt.Sprint(giteaPageSize)}}
+
+	var prs []gitHubPullRequest
+	if err := g.api.do(ctx, "list pull requests", http.MethodGet, repoPath+"/pulls?"+query.Encode(), nil, &prs); err != nil {
+		return nil, err
+	}
+
+	var pr *gitHubPullRequest
+
+	for i := range prs {
+		if prs[i].fromHead(headOwner(owner, headRepo), head) {
+			pr = &prs[i]
+			break
+		}
+	}
+
+	if pr == nil {
+		return nil, nil
+	}
+
+	status := pr.reviewStatus()
+	if status.State != domain.PullRequestOpen {
+		return status, nil
+	}
+
+	var reviews []review
+	if err := g.api.do(ctx, "list reviews", http.MethodGet, fmt.Sprintf("%s/pulls/%d/reviews", repoPath, pr.Number), nil, &reviews); err != nil {
+		return nil, err
+	}
+
+	status.ReviewDecision = reviewDecision(reviews)
+
+	var combined gitHubCommitStatus
+	if err := g.api.do(ctx, "get commit status", http.MethodGet, repoPath+"/commits/"+url.PathEscape(pr.Head.SHA)+"/status", nil, &combined); err != nil {
+		return nil, err
+	}
+
+	status.Checks = commitStatusConclusion(combined.State, combined.TotalCount)
+
+	return status, nil
+}
@@ -0,0 +1,188 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// gitHub talks to the GitHub REST API (github.com or GitHub Enterprise).
+type gitHub struct {
+	api apiClient
+}
+
+func newGitHub(baseURL, token string, client *http.Client) *gitHub {
+	return &gitHub{api: apiClient{
+		forge:   config.ForgeTypeGitHub,
+		baseURL: baseURL,
+		client:  client,
+		authorize: func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Accept", "application/vnd.github+json")
+			req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		},
+	}}
+}
+
+type gitHubPullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+	Draft   bool   `json:"draft"`
+	// Merged is only reported by Gitea; GitHub sets MergedAt.
+	Merged   bool    `json:"merged"`
+	MergedAt *string `json:"merged_at"`
+	Head     struct {
+		Ref  string `json:"ref"`
+		SHA  string `json:"sha"`
+		Repo *struct {
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repo"`
+	} `json:"head"`
+}
+
+// fromHead reports whether the pull request was opened from branch in a repository of owner.
+// Pull requests whose head repository was deleted match on the branch alone.
+func (pr gitHubPullRequest) fromHead(owner, branch string) bool {
+	return pr.Head.Ref == branch && (pr.Head.Repo == nil || strings.EqualFold(pr.Head.Repo.Owner.Login, owner))
+}
+
+// reviewStatus converts the pull request to a review status without reviews or checks.
+func (pr gitHubPullRequest) reviewStatus() *domain.ReviewStatus {
+	status := &domain.ReviewStatus{Number: pr.Number, URL: pr.HTMLURL, State: domain.PullRequestOpen, Draft: pr.Draft}
+
+	switch {
+	case pr.Merged || pr.MergedAt != nil:
+		status.State = domain.PullRequestMerged
+	case pr.State == "closed":
+		status.State = domain.PullRequestClosed
+	}
+
+	return status
+}
+
+// gitHubCommitStatus is the combined legacy commit status of a ref.
+type gitHubCommitStatus struct {
+	State      string `json:"state"`
+	TotalCount int    `json:"total_count"`
+}
+
+// CreatePullRequest opens a pull request.
+func (g *gitHub) CreatePullRequest(ctx context.Context, owner, name string, spec ports.PullRequestSpec) (*domain.PullRequest, error) {
+	in := map[string]interface{}{
+		"title": spec.Title,
+		"body":  spec.Body,
+		"head":  qualifiedHead(spec.Head, spec.HeadRepo),
+		"base":  spec.Base,
+		"draft": spec.Draft,
+	}
+
+	var out gitHubPullRequest
+	if err := g.api.do(ctx, "create pull request", http.MethodPost, gitHubRepoPath(owner, name)+"/pulls", in, &out); err != nil {
+		return nil, err
+	}
+
+	return &domain.PullRequest{Number: out.Number, URL: out.HTMLURL, Base: spec.Base, Head: spec.Head}, nil
+}
+
+// UpdatePullRequestBody replaces the description of a pull request.
+func (g *gitHub) UpdatePullRequestBody(ctx context.Context, owner, name string, number int, body string) error {
+	path := fmt.Sprintf("%s/pulls/%d", gitHubRepoPath(owner, name), number)
+
+	return g.api.do(ctx, "update pull request", http.MethodPatch, path, map[string]string{"body": body}, nil)
+}
+
+// ReviewStatus finds the most recent pull request from head and, while it is open, its review
+// decision and the combined result of its check runs and commit statuses.
+func (g *gitHub) ReviewStatus(ctx context.Context, owner, name, head string, headRepo giturl.Remote) (*domain.ReviewStatus, error) {
+	repoPath := gitHubRepoPath(owner, name)
+	query := url.Values{"state": {"all"}, "head": {headOwner(owner, headRepo) + ":" + head}, "per_page": {"1"}}
+
+	var prs []gitHubPullRequest
+	if err := g.api.do(ctx, "list pull requests", http.MethodGet, repoPath+"/pulls?"+query.Encode(), nil, &prs); err != nil {
+		return nil, err
+	}
+
+	if len(prs) == 0 {
+		return nil, nil
+	}
+
+	status := prs[0].reviewStatus()
+	if status.State != domain.PullRequestOpen {
+		return status, nil
+	}
+
+	var reviews []review
+	if err := g.api.do(ctx, "list reviews", http.MethodGet, fmt.Sprintf("%s/pulls/%d/reviews?per_page=100", repoPath, prs[0].Number), nil, &reviews); err != nil {
+		return nil, err
+	}
+
+	status.ReviewDecision = reviewDecision(reviews)
+
+	commitPath := repoPath + "/commits/" + url.PathEscape(prs[0].Head.SHA)
+
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := g.api.do(ctx, "list check runs", http.MethodGet, commitPath+"/check-runs?per_page=100", nil, &runs); err != nil {
+		return nil, err
+	}
+
+	var combined gitHubCommitStatus
+	if err := g.api.do(ctx, "get commit status", http.MethodGet, commitPath+"/status", nil, &combined); err != nil {
+		return nil, err
+	}
+
+	conclusions := []domain.CheckConclusion{commitStatusConclusion(combined.State, combined.TotalCount)}
+
+	for _, run := range runs.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			conclusions = append(conclusions, domain.ChecksPending)
+		case run.Conclusion == "success" || run.Conclusion == "neutral" || run.Conclusion == "skipped":
+			conclusions = append(conclusions, domain.ChecksSuccess)
+		default:
+			conclusions = append(conclusions, domain.ChecksFailure)
+		}
+	}
+
+	status.Checks = combineChecks(conclusions...)
+
+	return status, nil
+}
+
+// headOwner returns the owner of the repository holding a pull request's head branch.
+func headOwner(owner string, headRepo giturl.Remote) string {
+	if headRepo.Owner != "" {
+		return headRepo.Owner
+	}
+
+	return owner
+}
+
+// qualifiedHead returns the head of a pull request as GitHub and Gitea expect it: the branch,
+// prefixed with "<owner>:" when it is in a fork.
+func qualifiedHead(branch string, headRepo giturl.Remote) string {
+	if headRepo.Owner == "" {
+		return branch
+	}
+
+	return headRepo.Owner + ":" + branch
+}
+
+// gitHubRepoPath returns the API path of a repository. GitHub and Gitea share this layout.
+func gitHubRepoPath(owner, name string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
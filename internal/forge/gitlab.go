@@ -0,0 +1,195 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// gitLab talks to the GitLab REST API, where pull requests are merge requests.
+type gitLab struct {
+	api apiClient
+}
+
+func newGitLab(baseURL, token string, client *http.Client) *gitLab {
+	return &gitLab{api: apiClient{
+		forge:   config.ForgeTypeGitLab,
+		baseURL: baseURL,
+		client:  client,
+		authorize: func(req *http.Request) {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		},
+	}}
+}
+
+type gitLabMergeRequest struct {
+	IID             int    `json:"iid"`
+	WebURL          string `json:"web_url"`
+	State           string `json:"state"`
+	Draft           bool   `json:"draft"`
+	SourceProjectID int    `json:"source_project_id"`
+	TargetProjectID int    `json:"target_project_id"`
+	HeadPipeline    *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+// gitLabPageSize is how many recently updated merge requests from a source branch are searched
+// for one from the expected project, since the API cannot filter them by source project.
+const gitLabPageSize = 20
+
+// CreatePullRequest opens a merge request. Drafts use GitLab's "Draft:" title prefix. A merge
+// request from a fork is created in the fork, targeting the project.
+func (g *gitLab) CreatePullRequest(ctx context.Context, owner, name string, spec ports.PullRequestSpec) (*domain.PullRequest, error) {
+	title := spec.Title
+	if spec.Draft {
+		title = "Draft: " + title
+	}
+
+	in := map[string]interface{}{
+		"title":         title,
+		"description":   spec.Body,
+		"source_branch": spec.Head,
+		"target_branch": spec.Base,
+	}
+
+	sourcePath := gitLabProjectPath(owner, name)
+
+	if spec.HeadRepo.Owner != "" {
+		targetID, err := g.projectID(ctx, owner, name)
+		if err != nil {
+			return nil, err
+		}
+
+		in["target_project_id"] = targetID
+		sourcePath = gitLabProjectPath(spec.HeadRepo.Owner, spec.HeadRepo.Name)
+	}
+
+	var out gitLabMergeRequest
+	if err := g.api.do(ctx, "create merge request", http.MethodPost, sourcePath+"/merge_requests", in, &out); err != nil {
+		return nil, err
+	}
+
+	return &domain.PullRequest{Number: out.IID, URL: out.WebURL, Base: spec.Base, Head: spec.Head}, nil
+}
+
+// UpdatePullRequestBody replaces the description of a merge request.
+func (g *gitLab) UpdatePullRequestBody(ctx context.Context, owner, name string, number int, body string) error {
+	path := fmt.Sprintf("%s/merge_requests/%d", gitLabProjectPath(owner, name), number)
+
+	return g.api.do(ctx, "update merge request", http.MethodPut, path, map[string]string{"description": body}, nil)
+}
+
+// ReviewStatus finds the most recently updated merge request from head and, while it is open,
+// whether it is approved and the status of its head pipeline.
+func (g *gitLab) ReviewStatus(ctx context.Context, owner, name, head string, headRepo giturl.Remote) (*domain.ReviewStatus, error) {
+	projectPath := gitLabProjectPath(owner, name)
+	query := url.Values{"source_branch": {head}, "order_by": {"updated_at"}, "per_page": {fmt.Sprint(gitLabPageSize)}}
+
+	var mrs []gitLabMergeRequest
+	if err := g.api.do(ctx, "list merge requests", http.MethodGet, projectPath+"/merge_requests?"+query.Encode(), nil, &mrs); err != nil {
+		return nil, err
+	}
+
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+
+	// Without a fork, the merge request comes from the project itself
+	sourceID := 0
+
+	if headRepo.Owner != "" {
+		var err error
+		if sourceID, err = g.projectID(ctx, headRepo.Owner, headRepo.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var found *gitLabMergeRequest
+
+	for i := range mrs {
+		if (sourceID == 0 && mrs[i].SourceProjectID == mrs[i].TargetProjectID) || (sourceID != 0 && mrs[i].SourceProjectID == sourceID) {
+			found = &mrs[i]
+			break
+		}
+	}
+
+	if found == nil {
+		return nil, nil
+	}
+
+	status := &domain.ReviewStatus{Number: found.IID, URL: found.WebURL, State: domain.PullRequestOpen, Draft: found.Draft}
+
+	switch found.State {
+	case "merged":
+		status.State = domain.PullRequestMerged
+		return status, nil
+	case "closed", "locked":
+		status.State = domain.PullRequestClosed
+		return status, nil
+	}
+
+	mrPath := fmt.Sprintf("%s/merge_requests/%d", projectPath, found.IID)
+
+	// The list endpoint omits the head pipeline
+	var mr gitLabMergeRequest
+	if err := g.api.do(ctx, "get merge request", http.MethodGet, mrPath, nil, &mr); err != nil {
+		return nil, err
+	}
+
+	// "approved" is also true when no approval is required, so count actual approvers
+	var approvals struct {
+		ApprovalsLeft int               `json:"approvals_left"`
+		ApprovedBy    []json.RawMessage `json:"approved_by"`
+	}
+	if err := g.api.do(ctx, "get approvals", http.MethodGet, mrPath+"/approvals", nil, &approvals); err != nil {
+		return nil, err
+	}
+
+	status.ReviewDecision = domain.ReviewRequired
+	if approvals.ApprovalsLeft == 0 && len(approvals.ApprovedBy) > 0 {
+		status.ReviewDecision = domain.ReviewApproved
+	}
+
+	if mr.HeadPipeline != nil {
+		status.Checks = gitLabPipelineConclusion(mr.HeadPipeline.Status)
+	}
+
+	return status, nil
+}
+
+// gitLabPipelineConclusion maps a GitLab pipeline status.
+func gitLabPipelineConclusion(status string) domain.CheckConclusion {
+	switch status {
+	case "success", "skipped":
+		return domain.ChecksSuccess
+	case "failed", "canceled":
+		return domain.ChecksFailure
+	default:
+		return domain.ChecksPending
+	}
+}
+
+// projectID returns the numeric ID of a project.
+func (g *gitLab) projectID(ctx context.Context, owner, name string) (int, error) {
+	var project struct {
+		ID int `json:"id"`
+	}
+	if err := g.api.do(ctx, "get project", http.MethodGet, gitLabProjectPath(owner, name), nil, &project); err != nil {
+		return 0, err
+	}
+
+	return project.ID, nil
+}
+
+// gitLabProjectPath returns the API path of a project, addressed by its URL-encoded full path.
+func gitLabProjectPath(owner, name string) string {
+	return "/projects/" + url.PathEscape(owner+"/"+name)
+}
@@ -0,0 +1,86 @@
+package forge
+
+import (
+	"strings"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+)
+
+// review is a pull request review as returned by GitHub and Gitea.
+type review struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"`
+	// Dismissed is only reported by Gitea; GitHub uses the DISMISSED state.
+	Dismissed bool `json:"dismissed"`
+}
+
+// reviewDecision derives the review decision from each reviewer's latest approving or
+// blocking review. Comments do not change a reviewer's verdict.
+func reviewDecision(reviews []review) domain.ReviewDecision {
+	verdicts := make(map[string]string)
+
+	for _, r := range reviews {
+		state := strings.ToUpper(r.State)
+		if r.Dismissed {
+			state = "DISMISSED"
+		}
+
+		switch state {
+		case "APPROVED", "CHANGES_REQUESTED", "REQUEST_CHANGES", "DISMISSED":
+			verdicts[r.User.Login] = state
+		}
+	}
+
+	decision := domain.ReviewRequired
+
+	for _, state := range verdicts {
+		switch state {
+		case "CHANGES_REQUESTED", "REQUEST_CHANGES":
+			return domain.ReviewChangesRequested
+		case "APPROVED":
+			decision = domain.ReviewApproved
+		}
+	}
+
+	return decision
+}
+
+// combineChecks merges check conclusions: any failure fails, otherwise anything still
+// running is pending. Empty conclusions (no checks) are ignored.
+func combineChecks(conclusions ...domain.CheckConclusion) domain.CheckConclusion {
+	var combined domain.CheckConclusion
+
+	for _, c := range conclusions {
+		switch {
+		case c == domain.ChecksFailure:
+			return domain.ChecksFailure
+		case c == domain.ChecksPending:
+			combined = domain.ChecksPending
+		case c == domain.ChecksSuccess && combined == "":
+			combined = domain.ChecksSuccess
+		}
+	}
+
+	return combined
+}
+
+// commitStatusConclusion maps the combined commit status state used by GitHub and Gitea.
+// Gitea's "warning" does not block merging, so it counts as success.
+func commitStatusConclusion(state string, total int) domain.CheckConclusion {
+	if total == 0 {
+		return ""
+	}
+
+	switch state {
+	case "success", "warning":
+		return domain.ChecksSuccess
+	case "pending":
+		return domain.ChecksPending
+	case "":
+		return ""
+	default:
+		return domain.ChecksFailure
+	}
+}
@@ -105,3 +105,55 @@ func Sanitize(raw string) string {
 
 	return parsed.String()
 }
+
+// Remote identifies a repository on a hosting service.
+type Remote struct {
+	// Host is the hostname without port, e.g. github.com.
+	Host string
+	// Owner is the user, organization or group path (GitLab groups may be nested).
+	Owner string
+	// Name is the repository name without the .git suffix.
+	Name string
+}
+
+// FullName returns the "owner/name" path of the repository.
+func (r Remote) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// ParseRemote extracts the host, owner and repository name from a network Git URL.
+// It returns false for local paths, file URLs, and URLs without an owner.
+func ParseRemote(rawURL string) (Remote, bool) {
+	rawURL = strings.TrimSpace(rawURL)
+
+	var host, path string
+
+	switch {
+	case strings.Contains(rawURL, "://"):
+		parsed, err := url.Parse(rawURL)
+		if err != nil || parsed.Scheme == "file" {
+			return Remote{}, false
+		}
+
+		host, path = parsed.Hostname(), parsed.Path
+	case strings.Contains(rawURL, ":"):
+		// scp-style: [user@]host:owner/repo
+		hostPart, pathPart, _ := strings.Cut(rawURL, ":")
+		if at := strings.LastIndex(hostPart, "@"); at >= 0 {
+			hostPart = hostPart[at+1:]
+		}
+
+		host, path = hostPart, pathPart
+	default:
+		return Remote{}, false
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	slash := strings.LastIndex(path, "/")
+	if host == "" || slash <= 0 || slash == len(path)-1 {
+		return Remote{}, false
+	}
+
+	return Remote{Host: strings.ToLower(host), Owner: path[:slash], Name: path[slash+1:]}, true
+}
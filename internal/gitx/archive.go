@@ -0,0 +1,146 @@
+package gitx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// CreatePatch writes a binary patch of the worktree's uncommitted changes, including
+// untracked files, to patchPath, creating its directory if needed. It returns false and
+// writes nothing when there are no changes.
+// The diff is staged in a temporary index so the worktree's own index is left untouched.
+func (g *GitEngine) CreatePatch(ctx context.Context, path, patchPath string) (bool, error) {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	tmpDir, err := os.MkdirTemp("", "canopy-index-")
+	if err != nil {
+		return false, cerrors.NewIOFailed("create temporary index", err)
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+
+	if _, err := g.runChecked(ctx, path, env, "read-tree", "HEAD"); err != nil {
+		return false, err
+	}
+
+	if _, err := g.runChecked(ctx, path, env, "add", "-A"); err != nil {
+		return false, err
+	}
+
+	patch, err := g.runChecked(ctx, path, env, "diff", "--cached", "--binary", "HEAD")
+	if err != nil {
+		return false, err
+	}
+
+	if patch == "" {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(patchPath), 0o750); err != nil {
+		return false, cerrors.NewIOFailed("create patch directory", err)
+	}
+
+	if err := os.WriteFile(patchPath, []byte(patch), 0o600); err != nil {
+		return false, cerrors.NewIOFailed("write patch", err)
+	}
+
+	return true, nil
+}
+
+// CreateBundle writes a git bundle of the commits on HEAD that are not on any
+// remote-tracking or other local branch to bundlePath, and returns how many commits it holds.
+// Nothing is written when there are no local-only commits.
+func (g *GitEngine) CreateBundle(ctx context.Context, path, bundlePath string) (int, error) {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	branch, err := g.getBranchName(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Exclude commits reachable from remote-tracking refs and from other local branches,
+	// which in a canonical repository mirror the remote's branches.
+	revs := []string{"HEAD", "--not"}
+	if branch != "HEAD" {
+		revs = append(revs, "--exclude="+branch)
+	}
+
+	revs = append(revs, "--branches", "--remotes")
+
+	out, err := g.runChecked(ctx, path, nil, append([]string{"rev-list", "--count"}, revs...)...)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, cerrors.WrapGitError(err, "parse rev-list count")
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(bundlePath), 0o750); err != nil {
+		return 0, cerrors.NewIOFailed("create bundle directory", err)
+	}
+
+	if _, err := g.runChecked(ctx, path, nil, append([]string{"bundle", "create", bundlePath}, revs...)...); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ApplyBundle fetches HEAD from a bundle created by CreateBundle and moves the
+// worktree's current branch to it. It is meant for freshly created worktrees.
+func (g *GitEngine) ApplyBundle(ctx context.Context, path, bundlePath string) error {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	if _, err := g.runChecked(ctx, path, nil, "fetch", bundlePath, "HEAD"); err != nil {
+		return err
+	}
+
+	_, err := g.runChecked(ctx, path, nil, "reset", "--hard", "FETCH_HEAD")
+
+	return err
+}
+
+// ApplyPatch applies a patch created by CreatePatch to the worktree. The changes
+// are left unstaged.
+func (g *GitEngine) ApplyPatch(ctx context.Context, path, patchPath string) error {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	_, err := g.runChecked(ctx, path, nil, "apply", "--binary", patchPath)
+
+	return err
+}
+
+// runChecked runs a git command and returns its stdout, treating a non-zero exit code as an error.
+func (g *GitEngine) runChecked(ctx context.Context, path string, env []string, args ...string) (string, error) {
+	res, err := g.runCommandEnv(ctx, path, env, args...)
+	if err != nil {
+		return "", g.wrapContextError(err, "git "+args[0], path)
+	}
+
+	if res.ExitCode != 0 {
+		return "", cerrors.NewCommandFailed(
+			"git "+strings.Join(args, " "),
+			fmt.Errorf("exit code %d: %s", res.ExitCode, strings.TrimSpace(res.Stderr)),
+		)
+	}
+
+	return res.Stdout, nil
+}
@@ -0,0 +1,84 @@
+package gitx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/testutil"
+)
+
+func TestGitEngine_ArchiveRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	upstream, clone := setupPullFixture(t)
+	commitFile(t, clone, "LOCAL.md", "local", "local-only change")
+	testutil.MustWriteFile(t, filepath.Join(clone, "README.md"), "modified")
+	testutil.MustWriteFile(t, filepath.Join(clone, "UNTRACKED.md"), "untracked")
+
+	engine := New(t.TempDir())
+	ctx := context.Background()
+	archiveDir := t.TempDir()
+	patchPath := filepath.Join(archiveDir, "repo.patch")
+	bundlePath := filepath.Join(archiveDir, "repo.bundle")
+
+	written, err := engine.CreatePatch(ctx, clone, patchPath)
+	if err != nil || !written {
+		t.Fatalf("CreatePatch() = %v, %v; want true, nil", written, err)
+	}
+
+	if status := testutil.RunGitOutput(t, clone, "status", "--porcelain"); status != "M README.md\n?? UNTRACKED.md" {
+		t.Errorf("expected index to be untouched, got status %q", status)
+	}
+
+	count, err := engine.CreateBundle(ctx, clone, bundlePath)
+	if err != nil || count != 1 {
+		t.Fatalf("CreateBundle() = %d, %v; want 1, nil", count, err)
+	}
+
+	restored := filepath.Join(filepath.Dir(clone), "restored")
+	testutil.RunGit(t, filepath.Dir(clone), "clone", upstream, "restored")
+
+	if err := engine.ApplyBundle(ctx, restored, bundlePath); err != nil {
+		t.Fatalf("ApplyBundle failed: %v", err)
+	}
+
+	if err := engine.ApplyPatch(ctx, restored, patchPath); err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+
+	for name, want := range map[string]string{"LOCAL.md": "local", "README.md": "modified", "UNTRACKED.md": "untracked"} {
+		if got := testutil.MustReadFile(t, filepath.Join(restored, name)); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGitEngine_ArchiveCleanWorktree(t *testing.T) {
+	t.Parallel()
+
+	_, clone := setupPullFixture(t)
+
+	engine := New(t.TempDir())
+	archiveDir := t.TempDir()
+
+	written, err := engine.CreatePatch(context.Background(), clone, filepath.Join(archiveDir, "repo.patch"))
+	if err != nil || written {
+		t.Fatalf("CreatePatch() = %v, %v; want false, nil", written, err)
+	}
+
+	count, err := engine.CreateBundle(context.Background(), clone, filepath.Join(archiveDir, "repo.bundle"))
+	if err != nil || count != 0 {
+		t.Fatalf("CreateBundle() = %d, %v; want 0, nil", count, err)
+	}
+
+	entries, err := os.ReadDir(archiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Errorf("expected no artifacts for a clean worktree, got %d", len(entries))
+	}
+}
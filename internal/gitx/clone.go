@@ -0,0 +1,226 @@
+package gitx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-git/go-git/v5"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// allBranchesRefSpec maps every remote branch to a remote-tracking ref, as go-git clones do.
+const allBranchesRefSpec = "+refs/heads/*:refs/remotes/origin/*"
+
+// cloneWithCLI makes a bare partial, shallow or single-branch clone with the git CLI,
+// since go-git does not support partial clones. The fetch refspec is set so fetches
+// update refs/remotes/origin like go-git clones, restricted to the default branch for
+// single-branch clones.
+func (g *GitEngine) cloneWithCLI(ctx context.Context, url, path string, opts domain.CloneOptions) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return cerrors.NewIOFailed("create projects root", err)
+	}
+
+	args := []string{"clone", "--bare"}
+	if opts.Filter != "" {
+		args = append(args, "--filter="+opts.Filter)
+	}
+
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+	} else if opts.Depth > 0 {
+		// --depth implies --single-branch unless told otherwise
+		args = append(args, "--no-single-branch")
+	}
+
+	args = append(args, "--", url, path)
+
+	if _, err := g.runChecked(ctx, filepath.Dir(path), nil, args...); err != nil {
+		return err
+	}
+
+	refSpec := allBranchesRefSpec
+
+	if opts.SingleBranch {
+		head, err := g.runChecked(ctx, path, nil, "symbolic-ref", "--short", "HEAD")
+		if err != nil {
+			return err
+		}
+
+		branch := strings.TrimSpace(head)
+		refSpec = fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branch, branch)
+	}
+
+	if _, err := g.runChecked(ctx, path, nil, "config", "remote.origin.fetch", refSpec); err != nil {
+		return err
+	}
+
+	if err := g.trackClonedBranches(ctx, path); err != nil {
+		return err
+	}
+
+	_, err := g.runChecked(ctx, path, nil, "config", "canopy.upstreamUrl", url)
+
+	return err
+}
+
+// trackClonedBranches gives a bare CLI clone the layout of a go-git clone: "git clone --bare"
+// copies every branch to refs/heads and no remote-tracking refs, so fetch once to fill
+// refs/remotes/origin and keep only the default branch as a local branch.
+func (g *GitEngine) trackClonedBranches(ctx context.Context, path string) error {
+	if _, err := g.runChecked(ctx, path, nil, "fetch", "origin"); err != nil {
+		return err
+	}
+
+	head, err := g.runChecked(ctx, path, nil, "symbolic-ref", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	refs, err := g.runChecked(ctx, path, nil, "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	if err != nil {
+		return err
+	}
+
+	defaultBranch := strings.TrimPrefix(strings.TrimSpace(head), "refs/heads/")
+	stale := slices.DeleteFunc(strings.Fields(refs), func(branch string) bool { return branch == defaultBranch })
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	_, err = g.runChecked(ctx, path, nil, append([]string{"branch", "-D"}, stale...)...)
+
+	return err
+}
+
+// cloneCanonical clones url into path with retries, using go-git for full clones and the
+// git CLI otherwise. Partial clones are removed before each retry.
+func (g *GitEngine) cloneCanonical(ctx context.Context, url, path string, opts domain.CloneOptions) error {
+	return WithRetryNoResult(ctx, g.RetryConfig, func() error {
+		var cloneErr error
+		if opts.IsFull() {
+			_, cloneErr = git.PlainCloneContext(ctx, path, true, &git.CloneOptions{URL: url})
+		} else {
+			cloneErr = g.cloneWithCLI(ctx, url, path, opts)
+		}
+
+		if cloneErr != nil {
+			// Clean up partial clone on error before retry
+			if cleanupErr := os.RemoveAll(path); cleanupErr != nil {
+				log.Warn("failed to cleanup partial clone", "path", path, "error", cleanupErr)
+			}
+		}
+
+		return cloneErr
+	})
+}
+
+// GetCloneMode reports whether a canonical repository is a partial, shallow or
+// single-branch clone.
+func (g *GitEngine) GetCloneMode(repoName string) (domain.CloneMode, error) {
+	path := filepath.Join(g.ProjectsRoot, repoName)
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return domain.CloneMode{}, cerrors.WrapGitError(err, "open canonical repo")
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return domain.CloneMode{}, cerrors.WrapGitError(err, "get repo config")
+	}
+
+	var mode domain.CloneMode
+
+	mode.Filter = cfg.Raw.Section("remote").Subsection("origin").Option("partialclonefilter")
+
+	if _, err := os.Stat(filepath.Join(path, "shallow")); err == nil {
+		mode.Shallow = true
+	} else if !os.IsNotExist(err) {
+		return domain.CloneMode{}, cerrors.NewIOFailed(fmt.Sprintf("stat %s", filepath.Join(path, "shallow")), err)
+	}
+
+	if remote, ok := cfg.Remotes["origin"]; ok && len(remote.Fetch) > 0 {
+		mode.SingleBranch = true
+
+		for _, refSpec := range remote.Fetch {
+			if refSpec.IsWildcard() {
+				mode.SingleBranch = false
+			}
+		}
+	}
+
+	return mode, nil
+}
+
+// Deepen fetches what a partial, shallow or single-branch canonical repository left out.
+func (g *GitEngine) Deepen(ctx context.Context, repoName string, opts ports.DeepenOptions) error {
+	path := filepath.Join(g.ProjectsRoot, repoName)
+
+	mode, err := g.GetCloneMode(repoName)
+	if err != nil {
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return cerrors.NewRepoNotFound(repoName)
+		}
+
+		return err
+	}
+
+	ctx, cancel := g.withDefaultTimeout(ctx)
+	defer cancel()
+
+	if opts.AllBranches && mode.SingleBranch {
+		if _, err := g.runChecked(ctx, path, nil, "config", "--replace-all", "remote.origin.fetch", allBranchesRefSpec); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"fetch"}
+
+	if opts.Blobs && mode.Filter != "" {
+		for _, key := range []string{"remote.origin.partialclonefilter", "remote.origin.promisor"} {
+			if _, err := g.runChecked(ctx, path, nil, "config", "--unset", key); err != nil {
+				return err
+			}
+		}
+
+		args = append(args, "--refetch")
+	}
+
+	switch {
+	case opts.Depth > 0:
+		args = append(args, "--deepen="+strconv.Itoa(opts.Depth))
+	case mode.Shallow:
+		args = append(args, "--unshallow")
+	}
+
+	args = append(args, "origin")
+
+	return WithRetryNoResult(ctx, g.RetryConfig, func() error {
+		_, err := g.runChecked(ctx, path, nil, args...)
+		return err
+	})
+}
+
+// fetchWithCLI fetches a partial, shallow or single-branch canonical repository with the
+// git CLI, which keeps its filter, shallow boundary and refspec.
+func (g *GitEngine) fetchWithCLI(ctx context.Context, path string) error {
+	return WithRetryNoResult(ctx, g.RetryConfig, func() error {
+		_, err := g.runChecked(ctx, path, nil, "fetch", "--all")
+		return err
+	})
+}
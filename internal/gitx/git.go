@@ -34,6 +34,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -43,7 +44,9 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
 	"github.com/alexisbeaulieu97/canopy/internal/ports"
 )
 
@@ -83,7 +86,7 @@ func NewWithRetry(projectsRoot string, retryCfg RetryConfig) *GitEngine {
 }
 
 // EnsureCanonical ensures the repo is cloned in ProjectsRoot (bare)
-func (g *GitEngine) EnsureCanonical(ctx context.Context, repoURL, repoName string) (*git.Repository, error) {
+func (g *GitEngine) EnsureCanonical(ctx context.Context, repoURL, repoName string, opts domain.CloneOptions) (*git.Repository, error) {
 	path := filepath.Join(g.ProjectsRoot, repoName)
 
 	// Check if exists
@@ -97,19 +100,11 @@ func (g *GitEngine) EnsureCanonical(ctx context.Context, repoURL, repoName strin
 	defer cancel()
 
 	// Clone if not exists, with retry for transient failures
-	r, err = WithRetry(ctx, g.RetryConfig, func() (*git.Repository, error) {
-		repo, cloneErr := git.PlainCloneContext(ctx, path, true, &git.CloneOptions{
-			URL: repoURL,
-		})
-		if cloneErr != nil {
-			// Clean up partial clone on error before retry
-			if cleanupErr := os.RemoveAll(path); cleanupErr != nil {
-				log.Warn("failed to cleanup partial clone", "path", path, "error", cleanupErr)
-			}
-		}
+	err = g.cloneCanonical(ctx, repoURL, path, opts)
+	if err == nil {
+		r, err = git.PlainOpen(path)
+	}
 
-		return repo, cloneErr
-	})
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			return nil, cerrors.NewOperationCanceledWithTarget("clone", repoURL)
@@ -133,8 +128,9 @@ func (g *GitEngine) EnsureCanonical(ctx context.Context, repoURL, repoName strin
 
 // CreateWorktree creates a true git worktree for a workspace branch.
 // Uses git CLI via RunCommand as go-git's worktree API doesn't support
-// creating worktrees for non-existent branches.
-func (g *GitEngine) CreateWorktree(ctx context.Context, repoName, worktreePath, branchName string) error {
+// creating worktrees for non-existent branches. With sparsePaths, only those
+// directories are checked out (cone-mode sparse checkout).
+func (g *GitEngine) CreateWorktree(ctx context.Context, repoName, worktreePath, branchName string, sparsePaths []string) error {
 	// Apply default local timeout if context has no deadline
 	ctx, cancel := g.withLocalTimeout(ctx)
 	defer cancel()
@@ -152,7 +148,7 @@ func (g *GitEngine) CreateWorktree(ctx context.Context, repoName, worktreePath,
 	}
 
 	// Create the worktree (with existing or new branch)
-	if err := g.createWorktreeDir(ctx, canonicalPath, worktreePath, branchName, repoName); err != nil {
+	if err := g.createWorktreeDir(ctx, canonicalPath, worktreePath, branchName, repoName, sparsePaths); err != nil {
 		return err
 	}
 
@@ -162,24 +158,29 @@ func (g *GitEngine) CreateWorktree(ctx context.Context, repoName, worktreePath,
 	return nil
 }
 
-// createWorktreeDir creates the git worktree directory.
-func (g *GitEngine) createWorktreeDir(ctx context.Context, canonicalPath, worktreePath, branchName, repoName string) error {
+// createWorktreeDir creates the git worktree directory. A sparse worktree is added without
+// a checkout, and only populated once its sparse-checkout cone is configured.
+func (g *GitEngine) createWorktreeDir(ctx context.Context, canonicalPath, worktreePath, branchName, repoName string, sparsePaths []string) error {
 	// Check if branch already exists
 	branchExists, err := g.branchExistsWithContext(ctx, canonicalPath, branchName)
 	if err != nil {
 		return g.wrapContextError(err, "check branch exists", repoName)
 	}
 
-	var result *ports.CommandResult
+	args := []string{"worktree", "add"}
+	if len(sparsePaths) > 0 {
+		args = append(args, "--no-checkout")
+	}
 
 	if branchExists {
 		// Branch exists, create worktree for existing branch
-		result, err = g.RunCommand(ctx, canonicalPath, "worktree", "add", worktreePath, branchName)
+		args = append(args, worktreePath, branchName)
 	} else {
 		// Create the worktree with a new branch
-		result, err = g.RunCommand(ctx, canonicalPath, "worktree", "add", "-b", branchName, worktreePath)
+		args = append(args, "-b", branchName, worktreePath)
 	}
 
+	result, err := g.RunCommand(ctx, canonicalPath, args...)
 	if err != nil {
 		return g.wrapContextError(err, "git worktree add", repoName)
 	}
@@ -191,7 +192,114 @@ func (g *GitEngine) createWorktreeDir(ctx context.Context, canonicalPath, worktr
 		)
 	}
 
-	return nil
+	if !branchExists {
+		g.recordBranchBase(ctx, worktreePath, branchName)
+	}
+
+	if len(sparsePaths) == 0 {
+		return nil
+	}
+
+	if err := g.SetSparsePaths(ctx, worktreePath, sparsePaths); err != nil {
+		return err
+	}
+
+	// Populate the index and working tree from HEAD, restricted to the sparse cone
+	_, err = g.runChecked(ctx, worktreePath, nil, "read-tree", "-mu", "HEAD")
+
+	return err
+}
+
+// recordBranchBase stores the commit a new branch starts from under branch.<name>.canopyBase,
+// so a branch without commits of its own is not mistaken for a merged one.
+func (g *GitEngine) recordBranchBase(ctx context.Context, worktreePath, branchName string) {
+	head, err := g.runChecked(ctx, worktreePath, nil, "rev-parse", "HEAD")
+	if err != nil {
+		log.Warn("failed to resolve branch base", "branch", branchName, "error", err)
+		return
+	}
+
+	if _, err := g.runChecked(ctx, worktreePath, nil,
+		"config", branchBaseKey(branchName), strings.TrimSpace(head)); err != nil {
+		log.Warn("failed to record branch base", "branch", branchName, "error", err)
+	}
+}
+
+// branchBaseKey is the config key holding the commit a branch was created from.
+func branchBaseKey(branch string) string {
+	return "branch." + branch + ".canopyBase"
+}
+
+// SetSparsePaths restricts a worktree to the given directories with cone-mode sparse checkout,
+// or checks out the full tree when paths is empty. The sparse-checkout settings are stored in
+// the worktree's own config, so other worktrees of the canonical repository are unaffected.
+func (g *GitEngine) SetSparsePaths(ctx context.Context, path string, paths []string) error {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	if len(paths) == 0 {
+		_, err := g.runChecked(ctx, path, nil, "sparse-checkout", "disable")
+		return err
+	}
+
+	_, err := g.runChecked(ctx, path, nil, append([]string{"sparse-checkout", "set", "--cone", "--"}, paths...)...)
+
+	return err
+}
+
+// SetRemotes adds the extra remotes of a worktree and, when pushRemote is set to one of them,
+// stores it as the branch's pushRemote so pushes go there while pulls still come from origin.
+// With an upstream remote the branch pulls from upstream instead, and keeps pushing to origin
+// unless pushRemote says otherwise. Remotes live in the canonical repository's config, which
+// every worktree of the repository shares, so a remote that already exists with another URL
+// is rejected rather than rewritten.
+func (g *GitEngine) SetRemotes(ctx context.Context, path, branch string, remotes []domain.Remote, pushRemote string) error {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	existing, err := g.runChecked(ctx, path, nil, "remote")
+	if err != nil {
+		return err
+	}
+
+	names := strings.Fields(existing)
+
+	for _, remote := range remotes {
+		if !slices.Contains(names, remote.Name) {
+			if _, err := g.runChecked(ctx, path, nil, "remote", "add", remote.Name, remote.URL); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		url, err := g.runChecked(ctx, path, nil, "config", "--get", "remote."+remote.Name+".url")
+		if err != nil {
+			return err
+		}
+
+		if url = strings.TrimSpace(url); url != remote.URL {
+			return cerrors.NewInvalidArgument("remotes", fmt.Sprintf(
+				"remote %s of the canonical repository points at %s, not %s; every workspace of a repository shares its remotes",
+				remote.Name, giturl.Sanitize(url), giturl.Sanitize(remote.URL)))
+		}
+	}
+
+	if slices.ContainsFunc(remotes, func(remote domain.Remote) bool { return remote.Name == domain.UpstreamRemote }) {
+		if _, err := g.runChecked(ctx, path, nil, "config", fmt.Sprintf("branch.%s.remote", branch), domain.UpstreamRemote); err != nil {
+			return err
+		}
+
+		if pushRemote == "" {
+			pushRemote = domain.OriginRemote
+		}
+	} else if pushRemote == "" || pushRemote == domain.OriginRemote {
+		return nil
+	}
+
+	_, err = g.runChecked(ctx, path, nil, "config", fmt.Sprintf("branch.%s.pushRemote", branch), pushRemote)
+
+	return err
 }
 
 // configureWorktreeRemote configures the worktree's origin remote and branch tracking.
@@ -311,38 +419,65 @@ func (g *GitEngine) checkDirtyStatus(ctx context.Context, path string) (bool, er
 }
 
 // getAheadBehindCounts returns the number of commits ahead and behind the remote.
+// A branch that pushes to a different remote than it pulls from (a pushRemote) counts
+// unpushed commits against its push destination instead of its upstream.
 func (g *GitEngine) getAheadBehindCounts(ctx context.Context, path, branchName string) (unpushed, behind int) {
-	// Get the upstream branch for this branch
-	upstreamResult, upstreamErr := g.RunCommand(ctx, path, "rev-parse", "--symbolic-full-name", branchName+"@{upstream}")
-	if upstreamErr != nil || upstreamResult.ExitCode != 0 {
-		return 0, 0
-	}
+	remoteBranch := g.resolveRemoteBranch(ctx, path, branchName+"@{upstream}")
+	if remoteBranch != "" {
+		// Remote branch exists, count ahead/behind
+		revListResult, revListErr := g.RunCommand(ctx, path, "rev-list", "--count", "--left-right", fmt.Sprintf("%s...HEAD", remoteBranch))
+		if revListErr != nil || revListResult.ExitCode != 0 {
+			return 0, 0
+		}
 
-	remoteBranch := strings.TrimSpace(upstreamResult.Stdout)
+		parts := strings.Fields(strings.TrimSpace(revListResult.Stdout))
+		if len(parts) == 2 {
+			_, _ = fmt.Sscanf(parts[0], "%d", &behind)
+			_, _ = fmt.Sscanf(parts[1], "%d", &unpushed)
+		}
+	}
 
-	// Verify the remote branch exists
-	verifyResult, verifyErr := g.RunCommand(ctx, path, "rev-parse", "--verify", remoteBranch)
-	if verifyErr != nil || verifyResult.ExitCode != 0 {
-		return 0, 0
+	// A branch pushed to another remote than it pulls from counts against that remote. Its
+	// pushRemote is read directly since @{push} depends on push.default.
+	pushRemote, err := g.RunCommand(ctx, path, "config", "--get", "branch."+branchName+".pushRemote")
+	if err != nil || pushRemote.ExitCode != 0 {
+		return unpushed, behind
 	}
 
-	// Remote branch exists, count ahead/behind
-	revListResult, revListErr := g.RunCommand(ctx, path, "rev-list", "--count", "--left-right", fmt.Sprintf("%s...HEAD", remoteBranch))
-	if revListErr != nil || revListResult.ExitCode != 0 {
-		return 0, 0
+	pushBranch := g.resolveRemoteBranch(ctx, path, "refs/remotes/"+strings.TrimSpace(pushRemote.Stdout)+"/"+branchName)
+	if pushBranch == "" || pushBranch == remoteBranch {
+		return unpushed, behind
 	}
 
-	parts := strings.Fields(strings.TrimSpace(revListResult.Stdout))
-	if len(parts) == 2 {
-		_, _ = fmt.Sscanf(parts[0], "%d", &behind)
-		_, _ = fmt.Sscanf(parts[1], "%d", &unpushed)
+	countResult, countErr := g.RunCommand(ctx, path, "rev-list", "--count", pushBranch+"..HEAD")
+	if countErr == nil && countResult.ExitCode == 0 {
+		_, _ = fmt.Sscanf(strings.TrimSpace(countResult.Stdout), "%d", &unpushed)
 	}
 
 	return unpushed, behind
 }
 
+// resolveRemoteBranch resolves a remote-tracking ref such as branch@{upstream} to its full
+// name, or returns an empty string when it is not configured or does not exist locally.
+func (g *GitEngine) resolveRemoteBranch(ctx context.Context, path, ref string) string {
+	result, err := g.RunCommand(ctx, path, "rev-parse", "--symbolic-full-name", ref)
+	if err != nil || result.ExitCode != 0 {
+		return ""
+	}
+
+	remoteBranch := strings.TrimSpace(result.Stdout)
+
+	// Verify the remote branch exists
+	verifyResult, verifyErr := g.RunCommand(ctx, path, "rev-parse", "--verify", "--quiet", remoteBranch)
+	if verifyErr != nil || verifyResult.ExitCode != 0 {
+		return ""
+	}
+
+	return remoteBranch
+}
+
 // Clone clones a repository to the projects root (bare)
-func (g *GitEngine) Clone(ctx context.Context, url, name string) error {
+func (g *GitEngine) Clone(ctx context.Context, url, name string, opts domain.CloneOptions) error {
 	path := filepath.Join(g.ProjectsRoot, name)
 
 	// Check if exists
@@ -363,20 +498,8 @@ func (g *GitEngine) Clone(ctx context.Context, url, name string) error {
 	ctx, cancel := g.withDefaultTimeout(ctx)
 	defer cancel()
 
-	// Clone as bare using go-git, with retry for transient failures
-	err = WithRetryNoResult(ctx, g.RetryConfig, func() error {
-		_, cloneErr := git.PlainCloneContext(ctx, path, true, &git.CloneOptions{
-			URL: url,
-		})
-		if cloneErr != nil {
-			// Clean up partial clone on error before retry
-			if cleanupErr := os.RemoveAll(path); cleanupErr != nil {
-				log.Warn("failed to cleanup partial clone", "path", path, "error", cleanupErr)
-			}
-		}
-
-		return cloneErr
-	})
+	// Clone as bare, with retry for transient failures
+	err = g.cloneCanonical(ctx, url, path, opts)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			return cerrors.NewOperationCanceledWithTarget("clone", url)
@@ -417,6 +540,12 @@ func (g *GitEngine) Fetch(ctx context.Context, name string) error {
 	ctx, cancel := g.withDefaultTimeout(ctx)
 	defer cancel()
 
+	// go-git would fetch every object of every branch, so partial, shallow and
+	// single-branch clones are fetched with the git CLI
+	if mode, err := g.GetCloneMode(name); err == nil && !mode.IsFull() {
+		return g.fetchWithCLI(ctx, path)
+	}
+
 	// Fetch from all remotes
 	remotes, err := r.Remotes()
 	if err != nil {
@@ -451,70 +580,359 @@ func (g *GitEngine) Fetch(ctx context.Context, name string) error {
 	return nil
 }
 
-// Pull pulls updates for a repository worktree.
-func (g *GitEngine) Pull(ctx context.Context, path string) error {
+// Pull pulls updates for a repository worktree using the configured strategy.
+// When opts.Upstream is set, that local ref is integrated instead and no network access is made.
+// Rebase and merge pulls that stop on conflicts are aborted so the worktree is left
+// as it was, and the conflicting files are returned in the result.
+func (g *GitEngine) Pull(ctx context.Context, path string, opts ports.PullOptions) (*ports.PullResult, error) {
 	// Apply default timeout if context has no deadline
 	ctx, cancel := g.withDefaultTimeout(ctx)
 	defer cancel()
 
-	// Use CLI pull with --ff-only to avoid merge commits/conflicts in automated sync
-	res, err := g.RunCommand(ctx, path, "pull", "origin", "--ff-only")
+	args, err := pullArgs(opts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	res, err := g.RunCommand(ctx, path, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.ExitCode == 0 {
+		return &ports.PullResult{}, nil
+	}
+
+	result := &ports.PullResult{}
+
+	if opts.Strategy == domain.SyncStrategyRebase || opts.Strategy == domain.SyncStrategyMerge {
+		result.Conflicts = g.conflictedFiles(ctx, path)
+		g.abortPull(ctx, path, opts.Strategy)
+	}
+
+	// git returns exit code 1 if it can't fast-forward, hits conflicts, or other errors
+	return result, cerrors.WrapGitError(fmt.Errorf("%s", strings.TrimSpace(res.Stderr)), args[0])
+}
+
+// pullArgs builds the git arguments for the requested strategy.
+// A remote pull goes through "git pull <remote>"; a local upstream uses rebase or merge directly.
+func pullArgs(opts ports.PullOptions) ([]string, error) {
+	var args []string
+
+	switch opts.Strategy {
+	case "", domain.SyncStrategyFFOnly:
+		args = []string{"pull", "--ff-only"}
+		if opts.Upstream != "" {
+			args = []string{"merge", "--ff-only"}
+		}
+	case domain.SyncStrategyRebase:
+		args = []string{"pull", "--rebase"}
+		if opts.Upstream != "" {
+			args = []string{"rebase"}
+		}
+	case domain.SyncStrategyMerge:
+		args = []string{"pull", "--no-rebase", "--no-edit"}
+		if opts.Upstream != "" {
+			args = []string{"merge", "--no-edit"}
+		}
+	default:
+		return nil, cerrors.NewInvalidArgument("strategy", fmt.Sprintf("unsupported pull strategy %q", opts.Strategy))
+	}
+
+	if opts.Autostash {
+		args = append(args, "--autostash")
+	}
+
+	if opts.Upstream != "" {
+		return append(args, opts.Upstream), nil
+	}
+
+	if opts.Remote != "" {
+		return append(args, opts.Remote), nil
+	}
+
+	return append(args, domain.OriginRemote), nil
+}
+
+// CommitsBehind returns the number of commits reachable from ref that are not in HEAD.
+func (g *GitEngine) CommitsBehind(ctx context.Context, path, ref string) (int, error) {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	res, err := g.RunCommand(ctx, path, "rev-list", "--count", "HEAD.."+ref)
+	if err != nil {
+		return 0, err
 	}
 
 	if res.ExitCode != 0 {
-		// git pull returns exit code 1 if it can't fast-forward or other errors
-		return cerrors.WrapGitError(fmt.Errorf("%s", res.Stderr), "pull")
+		return 0, cerrors.WrapGitError(fmt.Errorf("%s", strings.TrimSpace(res.Stderr)), "rev-list")
 	}
 
-	return nil
+	var count int
+	if _, err := fmt.Sscanf(strings.TrimSpace(res.Stdout), "%d", &count); err != nil {
+		return 0, cerrors.WrapGitError(err, "parse rev-list count")
+	}
+
+	return count, nil
 }
 
-// Push pushes the current branch to its upstream.
-func (g *GitEngine) Push(ctx context.Context, path, branch string) error {
-	// Open the repository
+// BranchMergeState reports whether the worktree's branch was pushed to opts.PushRemote, merged
+// into opts.Base, and, when opts.CheckRemote is set, deleted from opts.PushRemote. The base is
+// resolved as <base remote>/<base>, falling back to the local branch for canonical repositories
+// that were never fetched. Empty remotes mean origin.
+func (g *GitEngine) BranchMergeState(ctx context.Context, path, branch string, opts ports.BranchMergeOptions) (*ports.BranchMergeState, error) {
+	ctx, cancel := g.withDefaultTimeout(ctx)
+	defer cancel()
+
+	pushRemote, baseRemote := opts.PushRemote, opts.BaseRemote
+	if pushRemote == "" {
+		pushRemote = domain.OriginRemote
+	}
+
+	if baseRemote == "" {
+		baseRemote = domain.OriginRemote
+	}
+
+	state := &ports.BranchMergeState{}
+
+	published, err := g.refExists(ctx, path, "refs/remotes/"+pushRemote+"/"+branch)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Published = published
+
+	baseRef := "refs/remotes/" + baseRemote + "/" + opts.Base
+	if exists, err := g.refExists(ctx, path, baseRef); err != nil {
+		return nil, err
+	} else if !exists {
+		baseRef = "refs/heads/" + opts.Base
+	}
+
+	res, err := g.RunCommand(ctx, path, "merge-base", "--is-ancestor", "HEAD", baseRef)
+	if err != nil {
+		return nil, g.wrapContextError(err, "merge-base", path)
+	}
+
+	if res.ExitCode != 0 && res.ExitCode != 1 {
+		return nil, cerrors.NewCommandFailed("git merge-base --is-ancestor HEAD "+baseRef,
+			fmt.Errorf("exit code %d: %s", res.ExitCode, strings.TrimSpace(res.Stderr)))
+	}
+
+	merged := res.ExitCode == 0
+
+	if state.OwnCommits, err = g.hasOwnCommits(ctx, path, branch, baseRef, merged); err != nil {
+		return nil, err
+	}
+
+	state.Merged = merged && state.OwnCommits
+
+	if !opts.CheckRemote || !published {
+		return state, nil
+	}
+
+	// --exit-code makes ls-remote exit with 2 when the branch is not on the remote
+	res, err = g.RunCommand(ctx, path, "ls-remote", "--exit-code", "--heads", pushRemote, "refs/heads/"+branch)
+	if err != nil {
+		return nil, g.wrapContextError(err, "ls-remote", path)
+	}
+
+	switch res.ExitCode {
+	case 0:
+	case 2:
+		state.RemoteDeleted = true
+	default:
+		return nil, cerrors.NewCommandFailed("git ls-remote "+pushRemote+" "+branch,
+			fmt.Errorf("exit code %d: %s", res.ExitCode, strings.TrimSpace(res.Stderr)))
+	}
+
+	return state, nil
+}
+
+// hasOwnCommits reports whether HEAD moved past the commit the branch was created from. When
+// canopy did not record that commit, a HEAD outside baseRef has commits of its own, and a HEAD
+// inside it must not lie on the first-parent history of baseRef, which is where a branch
+// without commits of its own points.
+func (g *GitEngine) hasOwnCommits(ctx context.Context, path, branch, baseRef string, inBase bool) (bool, error) {
+	res, err := g.RunCommand(ctx, path, "config", "--get", branchBaseKey(branch))
+	if err != nil {
+		return false, g.wrapContextError(err, "config", path)
+	}
+
+	if res.ExitCode == 0 {
+		count, err := g.runChecked(ctx, path, nil, "rev-list", "--count", strings.TrimSpace(res.Stdout)+"..HEAD")
+		if err != nil {
+			return false, err
+		}
+
+		return strings.TrimSpace(count) != "0", nil
+	}
+
+	if !inBase {
+		return true, nil
+	}
+
+	out, err := g.runChecked(ctx, path, nil, "rev-list", "--first-parent", "--reverse", "HEAD.."+baseRef)
+	if err != nil {
+		return false, err
+	}
+
+	oldest, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	if oldest == "" {
+		return false, nil
+	}
+
+	out, err = g.runChecked(ctx, path, nil, "rev-parse", "HEAD", oldest+"^1")
+	if err != nil {
+		return false, err
+	}
+
+	commits := strings.Fields(out)
+
+	return len(commits) == 2 && commits[0] != commits[1], nil
+}
+
+// refExists reports whether a fully qualified ref exists.
+func (g *GitEngine) refExists(ctx context.Context, path, ref string) (bool, error) {
+	res, err := g.RunCommand(ctx, path, "rev-parse", "--verify", "--quiet", ref)
+	if err != nil {
+		return false, g.wrapContextError(err, "rev-parse", path)
+	}
+
+	return res.ExitCode == 0, nil
+}
+
+// DefaultBranch returns the default branch of a canonical repository's remote, origin when
+// empty. It prefers refs/remotes/<remote>/HEAD and falls back to the bare repository's HEAD,
+// which mirrors origin's HEAD at clone time.
+func (g *GitEngine) DefaultBranch(repoName, remote string) (string, error) {
+	path := filepath.Join(g.ProjectsRoot, repoName)
+
+	if remote == "" {
+		remote = domain.OriginRemote
+	}
+
 	r, err := git.PlainOpen(path)
 	if err != nil {
-		return cerrors.WrapGitError(err, "open repo")
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return "", cerrors.NewRepoNotFound(repoName)
+		}
+
+		return "", cerrors.WrapGitError(err, "open canonical repo")
 	}
 
-	// Build push options
-	pushOpts := &git.PushOptions{
-		RemoteName: "origin",
+	if ref, err := r.Reference(plumbing.NewRemoteHEADReferenceName(remote), false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		return strings.TrimPrefix(ref.Target().Short(), remote+"/"), nil
 	}
 
-	// If branch is specified, set up the refspec for pushing and tracking
-	if branch != "" {
-		// Push the branch and set upstream tracking
-		refSpec := config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
-		pushOpts.RefSpecs = []config.RefSpec{refSpec}
-
-		// Set the branch to track the remote
-		cfg, err := r.Config()
-		if err == nil {
-			// Initialize Branches map if nil (can happen on freshly cloned repos)
-			if cfg.Branches == nil {
-				cfg.Branches = make(map[string]*config.Branch)
-			}
+	ref, err := r.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", cerrors.WrapGitError(err, "resolve HEAD")
+	}
 
-			cfg.Branches[branch] = &config.Branch{
-				Name:   branch,
-				Remote: "origin",
-				Merge:  plumbing.NewBranchReferenceName(branch),
-			}
+	if ref.Type() != plumbing.SymbolicReference || !ref.Target().IsBranch() {
+		return "", cerrors.WrapGitError(fmt.Errorf("HEAD of %s does not point to a branch", repoName), "resolve default branch")
+	}
 
-			if setErr := r.SetConfig(cfg); setErr != nil {
-				// Log but don't fail - tracking is nice-to-have, push is the priority
-				log.Warn("failed to set branch tracking config", "branch", branch, "error", setErr)
-			}
+	return ref.Target().Short(), nil
+}
+
+// UserName returns user.name from the global git config, falling back to the system config.
+// It returns an empty string when neither sets it.
+func (g *GitEngine) UserName() (string, error) {
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		cfg, err := config.LoadConfig(scope)
+		if err != nil {
+			return "", cerrors.WrapGitError(err, "load git config")
+		}
+
+		if cfg.User.Name != "" {
+			return cfg.User.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// conflictedFiles returns the unmerged paths in a worktree.
+func (g *GitEngine) conflictedFiles(ctx context.Context, path string) []string {
+	res, err := g.RunCommand(ctx, path, "diff", "--name-only", "--diff-filter=U")
+	if err != nil || res.ExitCode != 0 {
+		return nil
+	}
+
+	var files []string
+
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
 		}
 	}
 
+	return files
+}
+
+// abortPull restores the worktree after a failed rebase or merge pull.
+// Errors are ignored because there may be nothing in progress to abort.
+func (g *GitEngine) abortPull(ctx context.Context, path string, strategy domain.SyncStrategy) {
+	if strategy == domain.SyncStrategyRebase {
+		_, _ = g.RunCommand(ctx, path, "rebase", "--abort")
+		return
+	}
+
+	_, _ = g.RunCommand(ctx, path, "merge", "--abort")
+}
+
+// Push pushes a branch to opts.Remote (origin by default). Once the push succeeds, the branch
+// is set up to track the pushed remote branch when opts.RemoteBranch was given, or when it had
+// no upstream yet and opts.Remote is not its pushRemote; otherwise its upstream is left alone.
+// With ForceWithLease the push may rewrite the remote branch, but only while it still points at
+// the commit of its remote-tracking ref; without a remote-tracking ref to lease against, the push
+// stays fast-forward only.
+func (g *GitEngine) Push(ctx context.Context, path, branch string, opts ports.PushOptions) error {
+	// Open the repository
+	r, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return cerrors.WrapGitError(err, "open repo")
+	}
+
+	remote, remoteBranch := pushTarget(branch, opts)
+
 	// Apply default timeout if context has no deadline
 	ctx, cancel := g.withDefaultTimeout(ctx)
 	defer cancel()
 
+	if len(opts.Options) > 0 {
+		if err := g.pushWithCLI(ctx, path, branch, opts); err != nil {
+			return err
+		}
+
+		if branch != "" {
+			setPushTracking(r, branch, remote, plumbing.NewBranchReferenceName(remoteBranch), opts.RemoteBranch != "")
+		}
+
+		return nil
+	}
+
+	// Build push options
+	pushOpts := &git.PushOptions{RemoteName: remote}
+
+	// If branch is specified, set up the refspec for pushing and tracking
+	if branch != "" {
+		remoteRef := plumbing.NewBranchReferenceName(remoteBranch)
+		refSpec := fmt.Sprintf("refs/heads/%s:%s", branch, remoteRef)
+
+		if opts.ForceWithLease {
+			tracking, refErr := r.Reference(plumbing.NewRemoteReferenceName(remote, remoteBranch), true)
+			if refErr == nil {
+				refSpec = "+" + refSpec
+				pushOpts.RequireRemoteRefs = []config.RefSpec{config.RefSpec(tracking.Hash().String() + ":" + remoteRef.String())}
+			}
+		}
+
+		pushOpts.RefSpecs = []config.RefSpec{config.RefSpec(refSpec)}
+	}
+
 	// Push changes, with retry for transient failures
 	err = WithRetryNoResult(ctx, g.RetryConfig, func() error {
 		return r.PushContext(ctx, pushOpts)
@@ -531,9 +949,171 @@ func (g *GitEngine) Push(ctx context.Context, path, branch string) error {
 		return cerrors.WrapGitError(err, "push")
 	}
 
+	if branch != "" {
+		setPushTracking(r, branch, remote, plumbing.NewBranchReferenceName(remoteBranch), opts.RemoteBranch != "")
+	}
+
 	return nil
 }
 
+// setPushTracking sets a pushed branch to track remote/remoteRef when setUpstream is set, or
+// when the branch has no upstream and does not push to remote as its pushRemote. Failures are
+// logged: tracking is nice-to-have, the push already succeeded.
+func setPushTracking(r *git.Repository, branch, remote string, remoteRef plumbing.ReferenceName, setUpstream bool) {
+	cfg, err := r.Config()
+	if err != nil {
+		log.Warn("failed to read branch tracking config", "branch", branch, "error", err)
+		return
+	}
+
+	// Initialize Branches map if nil (can happen on freshly cloned repos)
+	if cfg.Branches == nil {
+		cfg.Branches = make(map[string]*config.Branch)
+	}
+
+	// Update an existing entry in place so options go-git does not model are kept
+	tracking, ok := cfg.Branches[branch]
+	if !setUpstream && ((ok && tracking.Remote != "") || cfg.Raw.Section("branch").Subsection(branch).Option("pushRemote") == remote) {
+		return
+	}
+
+	if !ok {
+		tracking = &config.Branch{Name: branch}
+		cfg.Branches[branch] = tracking
+	}
+
+	tracking.Remote = remote
+	tracking.Merge = remoteRef
+
+	if setErr := r.SetConfig(cfg); setErr != nil {
+		log.Warn("failed to set branch tracking config", "branch", branch, "error", setErr)
+	}
+}
+
+// pushTarget returns the remote and remote branch name that opts push branch to.
+func pushTarget(branch string, opts ports.PushOptions) (remote, remoteBranch string) {
+	remote = opts.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+
+	remoteBranch = opts.RemoteBranch
+	if remoteBranch == "" {
+		remoteBranch = branch
+	}
+
+	return remote, remoteBranch
+}
+
+// pushWithCLI pushes with the git CLI, which sends push options as given: go-git takes them
+// as a map, which collapses repeated keys and sends options without a value as "key=".
+func (g *GitEngine) pushWithCLI(ctx context.Context, path, branch string, opts ports.PushOptions) error {
+	remote, remoteBranch := pushTarget(branch, opts)
+
+	args := []string{"push"}
+	for _, option := range opts.Options {
+		args = append(args, "--push-option="+option)
+	}
+
+	// Lease against the remote-tracking ref, as the go-git push does
+	if branch != "" && opts.ForceWithLease {
+		res, err := g.RunCommand(ctx, path, "rev-parse", "--verify", "--quiet", "refs/remotes/"+remote+"/"+remoteBranch)
+		if err != nil {
+			return g.wrapContextError(err, "rev-parse", path)
+		}
+
+		if res.ExitCode == 0 {
+			args = append(args, fmt.Sprintf("--force-with-lease=refs/heads/%s:%s", remoteBranch, strings.TrimSpace(res.Stdout)))
+		}
+	}
+
+	args = append(args, remote)
+	if branch != "" {
+		args = append(args, "refs/heads/"+branch+":refs/heads/"+remoteBranch)
+	}
+
+	return WithRetryNoResult(ctx, g.RetryConfig, func() error {
+		_, err := g.runChecked(ctx, path, nil, args...)
+		return err
+	})
+}
+
+// PushPreflight checks with a dry-run push that branch can be pushed with opts without being
+// rejected, and reports the local and remote commits involved.
+func (g *GitEngine) PushPreflight(ctx context.Context, path, branch string, opts ports.PushOptions) (*ports.PushPreflight, error) {
+	ctx, cancel := g.withDefaultTimeout(ctx)
+	defer cancel()
+
+	remote, remoteBranch := pushTarget(branch, opts)
+	remoteRef := "refs/heads/" + remoteBranch
+
+	localHead, err := g.runChecked(ctx, path, nil, "rev-parse", "--verify", "refs/heads/"+branch)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteRefs, err := g.runChecked(ctx, path, nil, "ls-remote", remote, remoteRef)
+	if err != nil {
+		return nil, err
+	}
+
+	preflight := &ports.PushPreflight{LocalHead: strings.TrimSpace(localHead)}
+	if fields := strings.Fields(remoteRefs); len(fields) > 0 {
+		preflight.RemoteHead = fields[0]
+	}
+
+	args := []string{"push", "--dry-run", "--porcelain"}
+	if opts.ForceWithLease {
+		args = append(args, "--force-with-lease="+remoteRef)
+	}
+
+	args = append(args, remote, "refs/heads/"+branch+":"+remoteRef)
+
+	res, err := g.RunCommand(ctx, path, args...)
+	if err != nil {
+		return nil, g.wrapContextError(err, "push --dry-run", path)
+	}
+
+	if res.ExitCode != 0 {
+		return nil, cerrors.WrapGitError(fmt.Errorf("%s", pushRejection(res)), "push --dry-run")
+	}
+
+	return preflight, nil
+}
+
+// pushRejection extracts the reason from rejected lines of "git push --porcelain" output,
+// falling back to stderr.
+func pushRejection(res *ports.CommandResult) string {
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		// Rejected refs are flagged with "!": "!\t<from>:<to>\t[rejected] (non-fast-forward)"
+		if !strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		if fields := strings.Split(line, "\t"); len(fields) >= 3 {
+			return fields[1] + " " + fields[2]
+		}
+	}
+
+	return strings.TrimSpace(res.Stderr)
+}
+
+// RestoreRemoteBranch puts a branch pushed with opts back to remoteHead, deleting it when
+// remoteHead is empty. A lease on pushedHead keeps it from overwriting later pushes by others.
+func (g *GitEngine) RestoreRemoteBranch(ctx context.Context, path string, opts ports.PushOptions, branch, remoteHead, pushedHead string) error {
+	ctx, cancel := g.withDefaultTimeout(ctx)
+	defer cancel()
+
+	remote, remoteBranch := pushTarget(branch, opts)
+	ref := "refs/heads/" + remoteBranch
+	lease := fmt.Sprintf("--force-with-lease=%s:%s", ref, pushedHead)
+
+	// An empty source deletes the remote branch
+	_, err := g.runChecked(ctx, path, nil, "push", lease, remote, remoteHead+":"+ref)
+
+	return err
+}
+
 // List returns a list of repository names in the projects root
 func (g *GitEngine) List(ctx context.Context) ([]string, error) {
 	// Apply default local timeout if context has no deadline
@@ -747,6 +1327,11 @@ func (g *GitEngine) countCommitsTo(r *git.Repository, from, to plumbing.Hash) (i
 // Security note: The git binary path is hardcoded and arguments are passed
 // as separate parameters to prevent shell injection.
 func (g *GitEngine) RunCommand(ctx context.Context, repoPath string, args ...string) (*ports.CommandResult, error) {
+	return g.runCommandEnv(ctx, repoPath, nil, args...)
+}
+
+// runCommandEnv is RunCommand with extra environment variables appended to the process environment.
+func (g *GitEngine) runCommandEnv(ctx context.Context, repoPath string, env []string, args ...string) (*ports.CommandResult, error) {
 	if len(args) == 0 {
 		return nil, cerrors.NewInvalidArgument("args", "git command requires at least one argument")
 	}
@@ -754,6 +1339,10 @@ func (g *GitEngine) RunCommand(ctx context.Context, repoPath string, args ...str
 	cmdArgs := append([]string{"-C", repoPath}, args...)
 	cmd := exec.CommandContext(ctx, "git", cmdArgs...) //nolint:gosec // git binary is hardcoded, args passed safely as separate parameters
 
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
 	var stdout, stderr strings.Builder
 
 	cmd.Stdout = &stdout
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,7 +14,10 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+	"github.com/alexisbeaulieu97/canopy/internal/testutil"
 )
 
 // Helper function to create a test repository with commits
@@ -102,7 +106,7 @@ func TestGitEngine_Clone(t *testing.T) {
 		engine := New(projectsRoot)
 
 		// Clone
-		err := engine.Clone(context.Background(), sourcePath, "test-repo")
+		err := engine.Clone(context.Background(), sourcePath, "test-repo", domain.CloneOptions{})
 		if err != nil {
 			t.Fatalf("Clone failed: %v", err)
 		}
@@ -144,7 +148,7 @@ func TestGitEngine_Clone(t *testing.T) {
 		engine := New(projectsRoot)
 
 		// Clone should fail
-		err := engine.Clone(context.Background(), sourcePath, "test-repo")
+		err := engine.Clone(context.Background(), sourcePath, "test-repo", domain.CloneOptions{})
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -179,7 +183,7 @@ func TestGitEngine_CreateWorktree(t *testing.T) {
 		engine := New(projectsRoot)
 
 		// Create worktree
-		err := engine.CreateWorktree(context.Background(), "test-repo", worktreePath, "feature-branch")
+		err := engine.CreateWorktree(context.Background(), "test-repo", worktreePath, "feature-branch", nil)
 		if err != nil {
 			t.Fatalf("CreateWorktree failed: %v", err)
 		}
@@ -200,6 +204,56 @@ func TestGitEngine_CreateWorktree(t *testing.T) {
 		if _, err := os.Stat(readmePath); os.IsNotExist(err) {
 			t.Error("expected README.md to exist in worktree")
 		}
+
+		if base := testutil.RunGitOutput(t, worktreePath, "config", "branch.feature-branch.canopyBase"); base != testutil.RunGitOutput(t, worktreePath, "rev-parse", "HEAD") {
+			t.Errorf("expected the branch base to be recorded as HEAD, got %q", base)
+		}
+	})
+
+	t.Run("creates sparse worktree", func(t *testing.T) {
+		t.Parallel()
+
+		base := t.TempDir()
+		upstream := filepath.Join(base, "upstream")
+		testutil.CreateRepoWithCommit(t, upstream)
+		testutil.MustMkdir(t, filepath.Join(upstream, "services", "api"))
+		testutil.MustMkdir(t, filepath.Join(upstream, "docs"))
+		testutil.MustWriteFile(t, filepath.Join(upstream, "services", "api", "main.go"), "package main\n")
+		testutil.RunGit(t, upstream, "add", "services")
+		commitFile(t, upstream, "docs/guide.md", "# Guide\n", "add sources")
+
+		projectsRoot := t.TempDir()
+		testutil.RunGit(t, projectsRoot, "clone", "--bare", upstream, "test-repo")
+
+		engine := New(projectsRoot)
+		worktreePath := filepath.Join(t.TempDir(), "workspace")
+
+		if err := engine.CreateWorktree(context.Background(), "test-repo", worktreePath, "feature-branch", []string{"services/api"}); err != nil {
+			t.Fatalf("CreateWorktree failed: %v", err)
+		}
+
+		for _, name := range []string{"README.md", "services/api/main.go"} {
+			if _, err := os.Stat(filepath.Join(worktreePath, name)); err != nil {
+				t.Errorf("expected %s in sparse worktree: %v", name, err)
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(worktreePath, "docs", "guide.md")); !os.IsNotExist(err) {
+			t.Errorf("expected docs/guide.md outside the sparse cone, got err=%v", err)
+		}
+
+		isDirty, _, _, _, err := engine.Status(context.Background(), worktreePath)
+		if err != nil || isDirty {
+			t.Errorf("expected a clean sparse worktree, dirty=%v err=%v", isDirty, err)
+		}
+
+		if err := engine.SetSparsePaths(context.Background(), worktreePath, nil); err != nil {
+			t.Fatalf("SetSparsePaths failed: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(worktreePath, "docs", "guide.md")); err != nil {
+			t.Errorf("expected docs/guide.md after disabling sparse checkout: %v", err)
+		}
 	})
 }
 
@@ -603,7 +657,7 @@ func TestGitEngine_EnsureCanonical(t *testing.T) {
 		projectsRoot := t.TempDir()
 		engine := New(projectsRoot)
 
-		repo, err := engine.EnsureCanonical(context.Background(), sourcePath, "test-repo")
+		repo, err := engine.EnsureCanonical(context.Background(), sourcePath, "test-repo", domain.CloneOptions{})
 		if err != nil {
 			t.Fatalf("EnsureCanonical failed: %v", err)
 		}
@@ -628,7 +682,7 @@ func TestGitEngine_EnsureCanonical(t *testing.T) {
 
 		engine := New(projectsRoot)
 
-		repo, err := engine.EnsureCanonical(context.Background(), sourcePath, "test-repo")
+		repo, err := engine.EnsureCanonical(context.Background(), sourcePath, "test-repo", domain.CloneOptions{})
 		if err != nil {
 			t.Fatalf("EnsureCanonical failed: %v", err)
 		}
@@ -639,6 +693,85 @@ func TestGitEngine_EnsureCanonical(t *testing.T) {
 	})
 }
 
+func TestGitEngine_PartialCanonical(t *testing.T) {
+	t.Parallel()
+
+	upstream := filepath.Join(t.TempDir(), "upstream")
+	testutil.CreateRepoWithCommit(t, upstream)
+	testutil.RunGit(t, upstream, "config", "uploadpack.allowFilter", "true")
+	commitFile(t, upstream, "CHANGES.md", "v2", "second")
+	testutil.RunGit(t, upstream, "branch", "other")
+
+	// Shallow and partial clones are ignored for plain local paths
+	url := "file://" + filepath.ToSlash(upstream)
+	engine := New(t.TempDir())
+	ctx := context.Background()
+
+	opts := domain.CloneOptions{Filter: "blob:none", Depth: 1, SingleBranch: true}
+	if _, err := engine.EnsureCanonical(ctx, url, "test-repo", opts); err != nil {
+		t.Fatalf("EnsureCanonical failed: %v", err)
+	}
+
+	// Keep automatic maintenance after fetches in the foreground so cleanup does not race it
+	canonical := filepath.Join(engine.ProjectsRoot, "test-repo")
+	testutil.RunGit(t, canonical, "config", "gc.autoDetach", "false")
+	testutil.RunGit(t, canonical, "config", "maintenance.autoDetach", "false")
+
+	mode, err := engine.GetCloneMode("test-repo")
+	if err != nil {
+		t.Fatalf("GetCloneMode failed: %v", err)
+	}
+
+	if want := (domain.CloneMode{Filter: "blob:none", Shallow: true, SingleBranch: true}); mode != want {
+		t.Fatalf("clone mode = %+v, want %+v", mode, want)
+	}
+
+	if got, want := testutil.RunGitOutput(t, canonical, "rev-parse", "refs/remotes/origin/main"), testutil.RunGitOutput(t, upstream, "rev-parse", "HEAD"); got != want {
+		t.Errorf("origin/main = %s before any fetch, want %s", got, want)
+	}
+
+	worktreePath := filepath.Join(t.TempDir(), "workspace")
+	if err := engine.CreateWorktree(ctx, "test-repo", worktreePath, "feature", nil); err != nil {
+		t.Fatalf("CreateWorktree failed: %v", err)
+	}
+
+	if content := testutil.MustReadFile(t, filepath.Join(worktreePath, "CHANGES.md")); content != "v2" {
+		t.Errorf("expected blobs to be fetched on demand, got %q", content)
+	}
+
+	commitFile(t, upstream, "CHANGES.md", "v3", "third")
+
+	if err := engine.Fetch(ctx, "test-repo"); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	if got, want := testutil.RunGitOutput(t, canonical, "rev-parse", "refs/remotes/origin/main"), testutil.RunGitOutput(t, upstream, "rev-parse", "HEAD"); got != want {
+		t.Errorf("origin/main = %s after fetch, want %s", got, want)
+	}
+
+	if err := engine.Deepen(ctx, "test-repo", ports.DeepenOptions{AllBranches: true, Blobs: true}); err != nil {
+		t.Fatalf("Deepen failed: %v", err)
+	}
+
+	if mode, err = engine.GetCloneMode("test-repo"); err != nil || !mode.IsFull() {
+		t.Errorf("expected a full clone after deepening, got %+v (err=%v)", mode, err)
+	}
+
+	testutil.RunGit(t, canonical, "rev-parse", "--verify", "refs/remotes/origin/other")
+
+	// Like a go-git clone, a CLI clone of every branch keeps only the default one as a local branch
+	if _, err := engine.EnsureCanonical(ctx, url, "all-branches", domain.CloneOptions{Filter: "blob:none"}); err != nil {
+		t.Fatalf("EnsureCanonical failed: %v", err)
+	}
+
+	allBranches := filepath.Join(engine.ProjectsRoot, "all-branches")
+	refs := testutil.RunGitOutput(t, allBranches, "for-each-ref", "--format=%(refname)", "refs/heads/", "refs/remotes/")
+
+	if want := "refs/heads/main\nrefs/remotes/origin/main\nrefs/remotes/origin/other"; refs != want {
+		t.Errorf("refs after a CLI clone = %q, want %q", refs, want)
+	}
+}
+
 func TestGitEngine_ContextCancellation(t *testing.T) {
 	t.Parallel()
 
@@ -725,7 +858,7 @@ func TestGitEngine_ContextCancellation(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel() // Cancel immediately
 
-		err := engine.CreateWorktree(ctx, "test-repo", worktreePath, "feature-branch")
+		err := engine.CreateWorktree(ctx, "test-repo", worktreePath, "feature-branch", nil)
 		if err == nil {
 			t.Error("expected error with canceled context, got nil")
 		}
@@ -818,3 +951,844 @@ func TestGitEngine_GetRepoSize(t *testing.T) {
 		}
 	})
 }
+
+func TestGitEngine_Maintain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs tasks and records the time", func(t *testing.T) {
+		t.Parallel()
+
+		sourcePath := filepath.Join(t.TempDir(), "source")
+		sourceRepo := createTestRepo(t, sourcePath, false)
+
+		projectsRoot := t.TempDir()
+		canonicalPath := filepath.Join(projectsRoot, "test-repo")
+		cloneToBare(t, sourceRepo, canonicalPath)
+
+		engine := New(projectsRoot)
+
+		last, err := engine.LastMaintenanceTime("test-repo")
+		if err != nil || last != nil {
+			t.Fatalf("LastMaintenanceTime before maintenance = %v, %v, want nil", last, err)
+		}
+
+		before := time.Now().Add(-time.Second)
+
+		if err := engine.Maintain(context.Background(), "test-repo", domain.MaintenanceTasks); err != nil {
+			t.Fatalf("Maintain failed: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(canonicalPath, "objects", "info", "commit-graph")); err != nil {
+			t.Errorf("expected a commit-graph to be written: %v", err)
+		}
+
+		last, err = engine.LastMaintenanceTime("test-repo")
+		if err != nil {
+			t.Fatalf("LastMaintenanceTime failed: %v", err)
+		}
+
+		if last == nil || last.Before(before) {
+			t.Errorf("LastMaintenanceTime = %v, want after %v", last, before)
+		}
+	})
+
+	t.Run("skips incremental-repack without packs", func(t *testing.T) {
+		t.Parallel()
+
+		projectsRoot := t.TempDir()
+		testutil.RunGit(t, projectsRoot, "init", "--bare", "empty")
+
+		engine := New(projectsRoot)
+
+		if err := engine.Maintain(context.Background(), "empty", []string{"incremental-repack"}); err != nil {
+			t.Fatalf("Maintain failed: %v", err)
+		}
+	})
+
+	t.Run("returns not found for missing repo", func(t *testing.T) {
+		t.Parallel()
+
+		engine := New(t.TempDir())
+
+		if err := engine.Maintain(context.Background(), "missing", domain.MaintenanceTasks); err == nil {
+			t.Error("expected an error for a missing repository")
+		}
+	})
+}
+
+// setupPullFixture creates an upstream repo and a clone tracking its main branch.
+func setupPullFixture(t *testing.T) (upstream, clone string) {
+	t.Helper()
+
+	base := t.TempDir()
+	upstream = filepath.Join(base, "upstream")
+	clone = filepath.Join(base, "clone")
+
+	testutil.CreateRepoWithCommit(t, upstream)
+	testutil.RunGit(t, base, "clone", upstream, "clone")
+	testutil.RunGit(t, clone, "config", "user.email", "test@example.com")
+	testutil.RunGit(t, clone, "config", "user.name", "Test User")
+
+	return upstream, clone
+}
+
+func commitFile(t *testing.T, repoPath, name, content, message string) {
+	t.Helper()
+
+	testutil.MustWriteFile(t, filepath.Join(repoPath, name), content)
+	testutil.RunGit(t, repoPath, "add", name)
+	testutil.RunGit(t, repoPath, "commit", "-m", message)
+}
+
+func TestGitEngine_Pull(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ff-only fast-forwards", func(t *testing.T) {
+		t.Parallel()
+
+		upstream, clone := setupPullFixture(t)
+		commitFile(t, upstream, "NEW.md", "new", "upstream change")
+
+		engine := New(t.TempDir())
+
+		if _, err := engine.Pull(context.Background(), clone, ports.PullOptions{}); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(clone, "NEW.md")); err != nil {
+			t.Fatalf("expected NEW.md after pull: %v", err)
+		}
+	})
+
+	t.Run("ff-only fails on diverged branches", func(t *testing.T) {
+		t.Parallel()
+
+		upstream, clone := setupPullFixture(t)
+		commitFile(t, upstream, "UPSTREAM.md", "upstream", "upstream change")
+		commitFile(t, clone, "LOCAL.md", "local", "local change")
+
+		engine := New(t.TempDir())
+
+		res, err := engine.Pull(context.Background(), clone, ports.PullOptions{Strategy: domain.SyncStrategyFFOnly})
+		if err == nil {
+			t.Fatal("expected ff-only pull to fail on diverged branches")
+		}
+
+		if res == nil || len(res.Conflicts) != 0 {
+			t.Errorf("expected no conflicts for ff-only failure, got %+v", res)
+		}
+	})
+
+	t.Run("rebase replays local commits", func(t *testing.T) {
+		t.Parallel()
+
+		upstream, clone := setupPullFixture(t)
+		commitFile(t, upstream, "UPSTREAM.md", "upstream", "upstream change")
+		commitFile(t, clone, "LOCAL.md", "local", "local change")
+
+		engine := New(t.TempDir())
+
+		if _, err := engine.Pull(context.Background(), clone, ports.PullOptions{Strategy: domain.SyncStrategyRebase}); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+
+		parents := testutil.RunGitOutput(t, clone, "rev-list", "--count", "HEAD")
+		if parents != "3" {
+			t.Errorf("expected linear history of 3 commits, got %s", parents)
+		}
+	})
+
+	t.Run("merge autostashes dirty worktree", func(t *testing.T) {
+		t.Parallel()
+
+		upstream, clone := setupPullFixture(t)
+		commitFile(t, upstream, "UPSTREAM.md", "upstream", "upstream change")
+		commitFile(t, clone, "LOCAL.md", "local", "local change")
+		testutil.MustWriteFile(t, filepath.Join(clone, "README.md"), "dirty")
+
+		engine := New(t.TempDir())
+
+		if _, err := engine.Pull(context.Background(), clone, ports.PullOptions{Strategy: domain.SyncStrategyMerge, Autostash: true}); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+
+		if got := testutil.MustReadFile(t, filepath.Join(clone, "README.md")); got != "dirty" {
+			t.Errorf("expected local change to be restored, got %q", got)
+		}
+
+		if _, err := os.Stat(filepath.Join(clone, "UPSTREAM.md")); err != nil {
+			t.Fatalf("expected UPSTREAM.md after merge: %v", err)
+		}
+	})
+
+	t.Run("rebase conflict reports files and aborts", func(t *testing.T) {
+		t.Parallel()
+
+		upstream, clone := setupPullFixture(t)
+		commitFile(t, upstream, "README.md", "upstream", "upstream change")
+		commitFile(t, clone, "README.md", "local", "local change")
+
+		engine := New(t.TempDir())
+
+		res, err := engine.Pull(context.Background(), clone, ports.PullOptions{Strategy: domain.SyncStrategyRebase})
+		if err == nil {
+			t.Fatal("expected rebase conflict error")
+		}
+
+		if res == nil || len(res.Conflicts) != 1 || res.Conflicts[0] != "README.md" {
+			t.Fatalf("expected README.md conflict, got %+v", res)
+		}
+
+		if _, statErr := os.Stat(filepath.Join(clone, ".git", "rebase-merge")); !os.IsNotExist(statErr) {
+			t.Error("expected rebase to be aborted")
+		}
+
+		if got := testutil.MustReadFile(t, filepath.Join(clone, "README.md")); got != "local" {
+			t.Errorf("expected worktree restored to local content, got %q", got)
+		}
+	})
+
+	t.Run("rejects unknown strategy", func(t *testing.T) {
+		t.Parallel()
+
+		engine := New(t.TempDir())
+
+		_, err := engine.Pull(context.Background(), t.TempDir(), ports.PullOptions{Strategy: "squash"})
+
+		var cerr *cerrors.CanopyError
+		if !errors.As(err, &cerr) || cerr.Code != cerrors.ErrInvalidArgument {
+			t.Errorf("expected ErrInvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestGitEngine_PullUpstreamRef(t *testing.T) {
+	t.Parallel()
+
+	upstream, clone := setupPullFixture(t)
+	commitFile(t, upstream, "UPSTREAM.md", "upstream", "upstream change")
+	testutil.RunGit(t, clone, "fetch", "origin")
+	testutil.RunGit(t, clone, "checkout", "-b", "feature")
+	commitFile(t, clone, "FEATURE.md", "feature", "feature change")
+
+	engine := New(t.TempDir())
+
+	behind, err := engine.CommitsBehind(context.Background(), clone, "origin/main")
+	if err != nil {
+		t.Fatalf("CommitsBehind failed: %v", err)
+	}
+
+	if behind != 1 {
+		t.Fatalf("expected 1 commit behind origin/main, got %d", behind)
+	}
+
+	if _, err := engine.Pull(context.Background(), clone, ports.PullOptions{
+		Strategy: domain.SyncStrategyRebase,
+		Upstream: "origin/main",
+	}); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+
+	if branch := testutil.RunGitOutput(t, clone, "rev-parse", "--abbrev-ref", "HEAD"); branch != "feature" {
+		t.Errorf("expected to stay on feature, got %s", branch)
+	}
+
+	if _, err := os.Stat(filepath.Join(clone, "UPSTREAM.md")); err != nil {
+		t.Fatalf("expected UPSTREAM.md after rebase onto origin/main: %v", err)
+	}
+
+	behind, err = engine.CommitsBehind(context.Background(), clone, "origin/main")
+	if err != nil || behind != 0 {
+		t.Errorf("expected to be up to date with origin/main, got %d (err=%v)", behind, err)
+	}
+}
+
+func TestGitEngine_DefaultBranch(t *testing.T) {
+	t.Parallel()
+
+	sourcePath := filepath.Join(t.TempDir(), "source")
+	testutil.CreateRepoWithCommit(t, sourcePath)
+	testutil.RunGit(t, sourcePath, "checkout", "-b", "develop")
+
+	projectsRoot := t.TempDir()
+	testutil.CloneToBare(t, sourcePath, filepath.Join(projectsRoot, "repo"))
+
+	engine := New(projectsRoot)
+
+	branch, err := engine.DefaultBranch("repo", "")
+	if err != nil {
+		t.Fatalf("DefaultBranch failed: %v", err)
+	}
+
+	if branch != "develop" {
+		t.Errorf("expected develop, got %s", branch)
+	}
+
+	// Another remote's HEAD is used once it is known, otherwise the canonical HEAD
+	canonical := filepath.Join(projectsRoot, "repo")
+	testutil.RunGit(t, canonical, "update-ref", "refs/remotes/upstream/trunk", "HEAD")
+	testutil.RunGit(t, canonical, "symbolic-ref", "refs/remotes/upstream/HEAD", "refs/remotes/upstream/trunk")
+
+	if branch, err = engine.DefaultBranch("repo", "upstream"); err != nil || branch != "trunk" {
+		t.Errorf("expected trunk for upstream, got %q (err=%v)", branch, err)
+	}
+
+	if branch, err = engine.DefaultBranch("repo", "fork"); err != nil || branch != "develop" {
+		t.Errorf("expected develop for a remote without HEAD, got %q (err=%v)", branch, err)
+	}
+
+	_, err = engine.DefaultBranch("missing", "")
+
+	var cerr *cerrors.CanopyError
+	if !errors.As(err, &cerr) || cerr.Code != cerrors.ErrRepoNotFound {
+		t.Errorf("expected ErrRepoNotFound, got %v", err)
+	}
+}
+
+// setupPushFixture creates a bare remote and a clone with a local feature branch.
+func setupPushFixture(t *testing.T) (remote, clone string) {
+	t.Helper()
+
+	base := t.TempDir()
+	source := filepath.Join(base, "source")
+	remote = filepath.Join(base, "remote.git")
+	clone = filepath.Join(base, "clone")
+
+	testutil.CreateRepoWithCommit(t, source)
+	testutil.CloneToBare(t, source, remote)
+	testutil.RunGit(t, base, "clone", remote, "clone")
+	testutil.RunGit(t, clone, "config", "user.email", "test@example.com")
+	testutil.RunGit(t, clone, "config", "user.name", "Test User")
+	testutil.RunGit(t, clone, "checkout", "-b", "feature")
+	commitFile(t, clone, "FEATURE.md", "feature", "feature change")
+
+	return remote, clone
+}
+
+func TestGitEngine_PushPreflight(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new branch passes and can be rolled back", func(t *testing.T) {
+		t.Parallel()
+
+		remote, clone := setupPushFixture(t)
+		engine := New(t.TempDir())
+		ctx := context.Background()
+
+		preflight, err := engine.PushPreflight(ctx, clone, "feature", ports.PushOptions{})
+		if err != nil {
+			t.Fatalf("PushPreflight failed: %v", err)
+		}
+
+		if preflight.RemoteHead != "" || preflight.LocalHead == "" {
+			t.Fatalf("expected new remote branch, got %+v", preflight)
+		}
+
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+
+		if err := engine.RestoreRemoteBranch(ctx, clone, ports.PushOptions{}, "feature", preflight.RemoteHead, preflight.LocalHead); err != nil {
+			t.Fatalf("RestoreRemoteBranch failed: %v", err)
+		}
+
+		if refs := testutil.RunGitOutput(t, remote, "branch", "--list", "feature"); refs != "" {
+			t.Errorf("expected remote branch to be deleted, got %q", refs)
+		}
+	})
+
+	t.Run("existing branch is restored to its previous commit", func(t *testing.T) {
+		t.Parallel()
+
+		remote, clone := setupPushFixture(t)
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+		previous := testutil.RunGitOutput(t, clone, "rev-parse", "HEAD")
+		commitFile(t, clone, "MORE.md", "more", "more changes")
+
+		engine := New(t.TempDir())
+		ctx := context.Background()
+
+		preflight, err := engine.PushPreflight(ctx, clone, "feature", ports.PushOptions{})
+		if err != nil {
+			t.Fatalf("PushPreflight failed: %v", err)
+		}
+
+		if preflight.RemoteHead != previous {
+			t.Fatalf("expected remote head %s, got %s", previous, preflight.RemoteHead)
+		}
+
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+
+		if err := engine.RestoreRemoteBranch(ctx, clone, ports.PushOptions{}, "feature", preflight.RemoteHead, preflight.LocalHead); err != nil {
+			t.Fatalf("RestoreRemoteBranch failed: %v", err)
+		}
+
+		if head := testutil.RunGitOutput(t, remote, "rev-parse", "feature"); head != previous {
+			t.Errorf("expected remote feature at %s, got %s", previous, head)
+		}
+	})
+
+	t.Run("non-fast-forward is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, clone := setupPushFixture(t)
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+		testutil.RunGit(t, clone, "commit", "--amend", "-m", "rewritten")
+
+		engine := New(t.TempDir())
+
+		_, err := engine.PushPreflight(context.Background(), clone, "feature", ports.PushOptions{})
+		if err == nil || !strings.Contains(err.Error(), "non-fast-forward") {
+			t.Fatalf("expected non-fast-forward rejection, got %v", err)
+		}
+	})
+}
+
+func TestGitEngine_Push(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pushes to a differently named branch on another remote and tracks it", func(t *testing.T) {
+		t.Parallel()
+
+		_, clone := setupPushFixture(t)
+		fork := filepath.Join(t.TempDir(), "fork.git")
+		testutil.RunGit(t, clone, "clone", "--bare", ".", fork)
+		testutil.RunGit(t, clone, "remote", "add", "fork", fork)
+
+		engine := New(t.TempDir())
+
+		err := engine.Push(context.Background(), clone, "feature", ports.PushOptions{Remote: "fork", RemoteBranch: "review/feature"})
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+
+		want := testutil.RunGitOutput(t, clone, "rev-parse", "feature")
+		if head := testutil.RunGitOutput(t, fork, "rev-parse", "review/feature"); head != want {
+			t.Errorf("expected fork review/feature at %s, got %s", want, head)
+		}
+
+		if upstream := testutil.RunGitOutput(t, clone, "rev-parse", "--abbrev-ref", "feature@{upstream}"); upstream != "fork/review/feature" {
+			t.Errorf("expected upstream fork/review/feature, got %q", upstream)
+		}
+	})
+
+	t.Run("tracks the pushed branch only when it had no upstream", func(t *testing.T) {
+		t.Parallel()
+
+		_, clone := setupPushFixture(t)
+		fork := filepath.Join(t.TempDir(), "fork.git")
+		testutil.RunGit(t, clone, "clone", "--bare", ".", fork)
+		testutil.RunGit(t, clone, "remote", "add", "fork", fork)
+
+		engine := New(t.TempDir())
+		ctx := context.Background()
+
+		// A rejected push leaves the branch without an upstream
+		commitFile(t, clone, "AHEAD.md", "ahead", "change only the fork has")
+		testutil.RunGit(t, fork, "fetch", clone, "feature:feature")
+		testutil.RunGit(t, clone, "reset", "--hard", "HEAD~1")
+
+		if err := engine.Push(ctx, clone, "feature", ports.PushOptions{Remote: "fork"}); err == nil {
+			t.Fatal("expected a non-fast-forward push to fail")
+		}
+
+		if remote := testutil.RunGitOutput(t, clone, "config", "--default", "none", "branch.feature.remote"); remote != "none" {
+			t.Errorf("expected a failed push not to set up tracking, got remote %q", remote)
+		}
+
+		if err := engine.Push(ctx, clone, "feature", ports.PushOptions{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+
+		testutil.RunGit(t, fork, "update-ref", "-d", "refs/heads/feature")
+
+		if err := engine.Push(ctx, clone, "feature", ports.PushOptions{Remote: "fork"}); err != nil {
+			t.Fatalf("Push to fork failed: %v", err)
+		}
+
+		if upstream := testutil.RunGitOutput(t, clone, "rev-parse", "--abbrev-ref", "feature@{upstream}"); upstream != "origin/feature" {
+			t.Errorf("expected a push to another remote to keep upstream origin/feature, got %q", upstream)
+		}
+	})
+
+	t.Run("force with lease rewrites a branch that has not moved", func(t *testing.T) {
+		t.Parallel()
+
+		remote, clone := setupPushFixture(t)
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+		testutil.RunGit(t, clone, "commit", "--amend", "-m", "rewritten")
+
+		engine := New(t.TempDir())
+
+		if err := engine.Push(context.Background(), clone, "feature", ports.PushOptions{}); err == nil {
+			t.Fatal("expected non-fast-forward push without lease to fail")
+		}
+
+		if err := engine.Push(context.Background(), clone, "feature", ports.PushOptions{ForceWithLease: true}); err != nil {
+			t.Fatalf("Push with lease failed: %v", err)
+		}
+
+		want := testutil.RunGitOutput(t, clone, "rev-parse", "feature")
+		if head := testutil.RunGitOutput(t, remote, "rev-parse", "feature"); head != want {
+			t.Errorf("expected remote feature at %s, got %s", want, head)
+		}
+	})
+
+	t.Run("force with lease refuses a branch pushed by someone else", func(t *testing.T) {
+		t.Parallel()
+
+		remote, clone := setupPushFixture(t)
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+
+		other := filepath.Join(t.TempDir(), "other")
+		testutil.RunGit(t, clone, "clone", "--branch", "feature", remote, other)
+		testutil.RunGit(t, other, "config", "user.email", "other@example.com")
+		testutil.RunGit(t, other, "config", "user.name", "Other User")
+		commitFile(t, other, "OTHER.md", "other", "other change")
+		testutil.RunGit(t, other, "push", "origin", "feature")
+		theirs := testutil.RunGitOutput(t, other, "rev-parse", "HEAD")
+
+		testutil.RunGit(t, clone, "commit", "--amend", "-m", "rewritten")
+
+		engine := New(t.TempDir())
+
+		if err := engine.Push(context.Background(), clone, "feature", ports.PushOptions{ForceWithLease: true}); err == nil {
+			t.Fatal("expected lease to reject push")
+		}
+
+		if head := testutil.RunGitOutput(t, remote, "rev-parse", "feature"); head != theirs {
+			t.Errorf("expected remote feature to stay at %s, got %s", theirs, head)
+		}
+	})
+
+	t.Run("sends repeated and value-less push options as given", func(t *testing.T) {
+		t.Parallel()
+
+		remote, clone := setupPushFixture(t)
+		received := filepath.Join(t.TempDir(), "options")
+		testutil.RunGit(t, remote, "config", "receive.advertisePushOptions", "true")
+		testutil.MustWriteFile(t, filepath.Join(remote, "hooks", "pre-receive"),
+			"#!/bin/sh\ni=0\nwhile [ $i -lt \"$GIT_PUSH_OPTION_COUNT\" ]; do\n"+
+				"  eval \"echo \\$GIT_PUSH_OPTION_$i\" >> "+received+"\n  i=$((i+1))\ndone\n")
+
+		if err := os.Chmod(filepath.Join(remote, "hooks", "pre-receive"), 0o755); err != nil { //nolint:gosec // test hook must be executable
+			t.Fatalf("chmod hook: %v", err)
+		}
+
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+		testutil.RunGit(t, clone, "commit", "--amend", "-m", "rewritten")
+
+		engine := New(t.TempDir())
+		options := []string{"merge_request.label=a", "merge_request.label=b", "ci.skip"}
+
+		if err := engine.Push(context.Background(), clone, "feature", ports.PushOptions{ForceWithLease: true, Options: options}); err != nil {
+			t.Fatalf("Push with options failed: %v", err)
+		}
+
+		if got := testutil.MustReadFile(t, received); got != strings.Join(options, "\n")+"\n" {
+			t.Errorf("expected push options %q, got %q", options, got)
+		}
+
+		want := testutil.RunGitOutput(t, clone, "rev-parse", "feature")
+		if head := testutil.RunGitOutput(t, remote, "rev-parse", "feature"); head != want {
+			t.Errorf("expected remote feature at %s, got %s", want, head)
+		}
+	})
+
+	t.Run("pushes to the push remote of a worktree and keeps pulling from origin", func(t *testing.T) {
+		t.Parallel()
+
+		remote, clone := setupPushFixture(t)
+		projectsRoot := t.TempDir()
+		fork := filepath.Join(t.TempDir(), "fork.git")
+		testutil.RunGit(t, projectsRoot, "clone", "--bare", remote, "test-repo")
+		testutil.RunGit(t, filepath.Join(projectsRoot, "test-repo"), "config", "canopy.upstreamUrl", remote)
+		testutil.RunGit(t, clone, "clone", "--bare", remote, fork)
+
+		engine := New(projectsRoot)
+		ctx := context.Background()
+		worktree := filepath.Join(t.TempDir(), "worktree")
+
+		if err := engine.CreateWorktree(ctx, "test-repo", worktree, "ws-branch", nil); err != nil {
+			t.Fatalf("CreateWorktree failed: %v", err)
+		}
+
+		remotes := []domain.Remote{{Name: "fork", URL: fork}}
+		if err := engine.SetRemotes(ctx, worktree, "ws-branch", remotes, "fork"); err != nil {
+			t.Fatalf("SetRemotes failed: %v", err)
+		}
+
+		// Configuring the same remotes again, as for a second worktree, is a no-op
+		if err := engine.SetRemotes(ctx, worktree, "ws-branch", remotes, "fork"); err != nil {
+			t.Fatalf("SetRemotes failed on existing remotes: %v", err)
+		}
+
+		other := []domain.Remote{{Name: "fork", URL: filepath.Join(t.TempDir(), "other.git")}}
+		if err := engine.SetRemotes(ctx, worktree, "ws-branch", other, "fork"); err == nil {
+			t.Fatal("expected an error for a remote that exists with another URL")
+		}
+
+		if url := testutil.RunGitOutput(t, worktree, "remote", "get-url", "fork"); url != fork {
+			t.Errorf("expected the fork remote to keep %s, got %s", fork, url)
+		}
+
+		testutil.RunGit(t, worktree, "config", "user.email", "test@example.com")
+		testutil.RunGit(t, worktree, "config", "user.name", "Test User")
+		commitFile(t, worktree, "WS.md", "ws", "workspace change")
+
+		if err := engine.Push(ctx, worktree, "ws-branch", ports.PushOptions{Remote: "fork"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+
+		want := testutil.RunGitOutput(t, worktree, "rev-parse", "HEAD")
+		if head := testutil.RunGitOutput(t, fork, "rev-parse", "ws-branch"); head != want {
+			t.Errorf("expected fork ws-branch at %s, got %s", want, head)
+		}
+
+		if pullRemote := testutil.RunGitOutput(t, worktree, "config", "branch.ws-branch.remote"); pullRemote != "origin" {
+			t.Errorf("expected ws-branch to keep pulling from origin, got %q", pullRemote)
+		}
+
+		commitFile(t, worktree, "MORE.md", "more", "more changes")
+
+		if _, unpushed, _, _, err := engine.Status(ctx, worktree); err != nil || unpushed != 1 {
+			t.Errorf("expected 1 commit unpushed to the fork, got %d (err=%v)", unpushed, err)
+		}
+	})
+
+	t.Run("pulls from an upstream remote and keeps pushing to origin", func(t *testing.T) {
+		t.Parallel()
+
+		upstream, clone := setupPushFixture(t)
+		projectsRoot := t.TempDir()
+		fork := filepath.Join(t.TempDir(), "fork.git")
+		testutil.RunGit(t, clone, "clone", "--bare", upstream, fork)
+		testutil.RunGit(t, projectsRoot, "clone", "--bare", fork, "test-repo")
+		testutil.RunGit(t, filepath.Join(projectsRoot, "test-repo"), "config", "canopy.upstreamUrl", fork)
+
+		engine := New(projectsRoot)
+		ctx := context.Background()
+		worktree := filepath.Join(t.TempDir(), "worktree")
+
+		if err := engine.CreateWorktree(ctx, "test-repo", worktree, "feature", nil); err != nil {
+			t.Fatalf("CreateWorktree failed: %v", err)
+		}
+
+		if err := engine.SetRemotes(ctx, worktree, "feature", []domain.Remote{{Name: "upstream", URL: upstream}}, ""); err != nil {
+			t.Fatalf("SetRemotes failed: %v", err)
+		}
+
+		for key, want := range map[string]string{"branch.feature.remote": "upstream", "branch.feature.pushRemote": "origin"} {
+			if got := testutil.RunGitOutput(t, worktree, "config", key); got != want {
+				t.Errorf("%s = %q, want %q", key, got, want)
+			}
+		}
+
+		testutil.RunGit(t, clone, "push", "origin", "feature")
+
+		if err := engine.Fetch(ctx, "test-repo"); err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+
+		if _, err := engine.Pull(ctx, worktree, ports.PullOptions{Remote: "upstream"}); err != nil {
+			t.Fatalf("Pull from upstream failed: %v", err)
+		}
+
+		want := testutil.RunGitOutput(t, clone, "rev-parse", "feature")
+		if head := testutil.RunGitOutput(t, worktree, "rev-parse", "HEAD"); head != want {
+			t.Errorf("expected the worktree at upstream feature %s, got %s", want, head)
+		}
+
+		if err := engine.Push(ctx, worktree, "feature", ports.PushOptions{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+
+		if head := testutil.RunGitOutput(t, fork, "rev-parse", "feature"); head != want {
+			t.Errorf("expected fork feature at %s, got %s", want, head)
+		}
+	})
+
+	t.Run("pushes from a worktree of a bare repository", func(t *testing.T) {
+		t.Parallel()
+
+		remote, clone := setupPushFixture(t)
+		canonical := filepath.Join(t.TempDir(), "canonical.git")
+		worktree := filepath.Join(t.TempDir(), "worktree")
+		testutil.RunGit(t, clone, "clone", "--bare", remote, canonical)
+		testutil.RunGit(t, canonical, "worktree", "add", "-b", "ws-branch", worktree)
+		testutil.RunGit(t, worktree, "config", "user.email", "test@example.com")
+		testutil.RunGit(t, worktree, "config", "user.name", "Test User")
+		commitFile(t, worktree, "WS.md", "ws", "workspace change")
+
+		engine := New(t.TempDir())
+
+		if err := engine.Push(context.Background(), worktree, "ws-branch", ports.PushOptions{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+
+		want := testutil.RunGitOutput(t, worktree, "rev-parse", "HEAD")
+		if head := testutil.RunGitOutput(t, remote, "rev-parse", "ws-branch"); head != want {
+			t.Errorf("expected remote ws-branch at %s, got %s", want, head)
+		}
+	})
+}
+
+func TestGitEngine_BranchMergeState(t *testing.T) {
+	t.Parallel()
+
+	remote, clone := setupPushFixture(t)
+	base := strings.TrimPrefix(testutil.RunGitOutput(t, clone, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"), "origin/")
+	engine := New(t.TempDir())
+	ctx := context.Background()
+
+	testutil.RunGit(t, clone, "config", "branch.feature.canopyBase", testutil.RunGitOutput(t, clone, "rev-parse", "origin/"+base))
+
+	state, err := engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base, CheckRemote: true})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{OwnCommits: true}) {
+		t.Errorf("expected unpublished, unmerged branch, got %+v", state)
+	}
+
+	testutil.RunGit(t, clone, "push", "-u", "origin", "feature")
+
+	state, err = engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base, CheckRemote: true})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{Published: true, OwnCommits: true}) {
+		t.Errorf("expected published, unmerged branch, got %+v", state)
+	}
+
+	// Merge upstream and delete the branch on the remote, as a forge does after a pull request
+	testutil.RunGit(t, remote, "update-ref", "refs/heads/"+base, testutil.RunGitOutput(t, clone, "rev-parse", "feature"))
+	testutil.RunGit(t, remote, "branch", "-D", "feature")
+	testutil.RunGit(t, clone, "fetch", "origin")
+
+	state, err = engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{Published: true, OwnCommits: true, Merged: true}) {
+		t.Errorf("expected merged branch without remote check, got %+v", state)
+	}
+
+	state, err = engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base, CheckRemote: true})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{Published: true, OwnCommits: true, Merged: true, RemoteDeleted: true}) {
+		t.Errorf("expected merged branch deleted on the remote, got %+v", state)
+	}
+
+	// Without a recorded base, a fast-forwarded branch cannot be told from one without commits
+	testutil.RunGit(t, clone, "config", "--unset", "branch.feature.canopyBase")
+
+	state, err = engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{Published: true}) {
+		t.Errorf("expected an unrecorded fast-forwarded branch not to count as merged, got %+v", state)
+	}
+}
+
+func TestGitEngine_BranchMergeState_PushRemote(t *testing.T) {
+	t.Parallel()
+
+	remote, clone := setupPushFixture(t)
+	base := strings.TrimPrefix(testutil.RunGitOutput(t, clone, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"), "origin/")
+	engine := New(t.TempDir())
+	ctx := context.Background()
+
+	fork := filepath.Join(filepath.Dir(remote), "fork.git")
+	testutil.RunGit(t, "", "clone", "--bare", remote, fork)
+	testutil.RunGit(t, clone, "remote", "add", "fork", fork)
+	testutil.RunGit(t, clone, "push", "fork", "feature")
+
+	state, err := engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base, CheckRemote: true})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if state.Published {
+		t.Errorf("expected a branch pushed only to the fork not to be published on origin, got %+v", state)
+	}
+
+	state, err = engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base, PushRemote: "fork", CheckRemote: true})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{Published: true, OwnCommits: true}) {
+		t.Errorf("expected a branch published on the fork, got %+v", state)
+	}
+
+	testutil.RunGit(t, fork, "branch", "-D", "feature")
+
+	state, err = engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base, PushRemote: "fork", CheckRemote: true})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{Published: true, OwnCommits: true, RemoteDeleted: true}) {
+		t.Errorf("expected the branch to be deleted from the fork, got %+v", state)
+	}
+}
+
+func TestGitEngine_BranchMergeState_NoOwnCommits(t *testing.T) {
+	t.Parallel()
+
+	_, clone := setupPushFixture(t)
+	base := strings.TrimPrefix(testutil.RunGitOutput(t, clone, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"), "origin/")
+	engine := New(t.TempDir())
+	ctx := context.Background()
+
+	testutil.RunGit(t, clone, "push", "origin", "feature")
+	testutil.RunGit(t, clone, "checkout", "-b", "empty", "origin/"+base)
+
+	state, err := engine.BranchMergeState(ctx, clone, "empty", ports.BranchMergeOptions{Base: base})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{}) {
+		t.Errorf("expected a fresh branch to have no commits of its own, got %+v", state)
+	}
+
+	testutil.RunGit(t, clone, "push", "origin", "empty")
+
+	for _, recorded := range []bool{false, true} {
+		if recorded {
+			testutil.RunGit(t, clone, "config", "branch.empty.canopyBase", testutil.RunGitOutput(t, clone, "rev-parse", "HEAD"))
+		}
+
+		state, err := engine.BranchMergeState(ctx, clone, "empty", ports.BranchMergeOptions{Base: base})
+		if err != nil {
+			t.Fatalf("BranchMergeState failed: %v", err)
+		}
+
+		if *state != (ports.BranchMergeState{Published: true}) {
+			t.Errorf("expected a pushed branch without commits not to count as merged (recorded base %v), got %+v", recorded, state)
+		}
+	}
+
+	// A branch merged with a merge commit counts as merged without a recorded base
+	testutil.RunGit(t, clone, "merge", "--no-ff", "-m", "merge feature", "feature")
+	testutil.RunGit(t, clone, "push", "origin", "empty:"+base)
+	testutil.RunGit(t, clone, "checkout", "feature")
+	testutil.RunGit(t, clone, "fetch", "origin")
+
+	state, err = engine.BranchMergeState(ctx, clone, "feature", ports.BranchMergeOptions{Base: base})
+	if err != nil {
+		t.Fatalf("BranchMergeState failed: %v", err)
+	}
+
+	if *state != (ports.BranchMergeState{Published: true, OwnCommits: true, Merged: true}) {
+		t.Errorf("expected a branch merged with a merge commit to count as merged, got %+v", state)
+	}
+}
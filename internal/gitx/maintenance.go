@@ -0,0 +1,79 @@
+package gitx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// Maintain runs git maintenance tasks on a canonical repository, in the given order, and
+// records the time in the repository's canopy config section.
+func (g *GitEngine) Maintain(ctx context.Context, repoName string, tasks []string) error {
+	path := filepath.Join(g.ProjectsRoot, repoName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cerrors.NewRepoNotFound(repoName)
+	}
+
+	args := []string{"maintenance", "run", "--quiet"}
+
+	for _, task := range tasks {
+		// The multi-pack-index written by incremental-repack needs at least one pack
+		if task == "incremental-repack" && !hasPackFiles(path) {
+			continue
+		}
+
+		args = append(args, "--task="+task)
+	}
+
+	ctx, cancel := g.withDefaultTimeout(ctx)
+	defer cancel()
+
+	if len(args) > 3 {
+		if _, err := g.runChecked(ctx, path, nil, args...); err != nil {
+			return err
+		}
+	}
+
+	_, err := g.runChecked(ctx, path, nil, "config", "canopy.lastMaintenance", time.Now().UTC().Format(time.RFC3339))
+
+	return err
+}
+
+// LastMaintenanceTime returns when Maintain last ran on the canonical repository, or nil
+// if it never did.
+func (g *GitEngine) LastMaintenanceTime(repoName string) (*time.Time, error) {
+	repo, err := git.PlainOpen(filepath.Join(g.ProjectsRoot, repoName))
+	if err != nil {
+		return nil, cerrors.WrapGitError(err, "open canonical repo")
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, cerrors.WrapGitError(err, "get repo config")
+	}
+
+	value := cfg.Raw.Section("canopy").Option("lastMaintenance")
+	if value == "" {
+		return nil, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, cerrors.NewInvalidArgument("canopy.lastMaintenance", fmt.Sprintf("invalid time %q", value))
+	}
+
+	return &at, nil
+}
+
+// hasPackFiles reports whether a bare repository has any pack files.
+func hasPackFiles(path string) bool {
+	packs, err := filepath.Glob(filepath.Join(path, "objects", "pack", "*.pack"))
+
+	return err == nil && len(packs) > 0
+}
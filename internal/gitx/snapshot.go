@@ -0,0 +1,94 @@
+package gitx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// snapshotIdentity commits snapshots under a fixed identity, so they work without a
+// configured user and are recognizable in the canonical repository.
+var snapshotIdentity = []string{
+	"GIT_AUTHOR_NAME=canopy", "GIT_AUTHOR_EMAIL=canopy@localhost",
+	"GIT_COMMITTER_NAME=canopy", "GIT_COMMITTER_EMAIL=canopy@localhost",
+}
+
+// SnapshotWorktree records the uncommitted changes of a worktree, untracked files included,
+// without touching the worktree, its index or the stash list. The commit is laid out like a
+// stash: its tree is the worktree and its parents are HEAD and a commit of the index. An
+// empty string is returned when there is nothing to record.
+func (g *GitEngine) SnapshotWorktree(ctx context.Context, path, message string) (string, error) {
+	ctx, cancel := g.withLocalTimeout(ctx)
+	defer cancel()
+
+	head, err := g.runChecked(ctx, path, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	head = strings.TrimSpace(head)
+
+	headTree, err := g.runChecked(ctx, path, nil, "rev-parse", "HEAD^{tree}")
+	if err != nil {
+		return "", err
+	}
+
+	indexTree, err := g.runChecked(ctx, path, nil, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	worktreeTree, err := g.writeWorktreeTree(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	headTree, indexTree = strings.TrimSpace(headTree), strings.TrimSpace(indexTree)
+	if indexTree == headTree && worktreeTree == headTree {
+		return "", nil
+	}
+
+	indexCommit, err := g.runChecked(ctx, path, snapshotIdentity, "commit-tree", indexTree, "-p", head, "-m", "index of "+message)
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := g.runChecked(ctx, path, snapshotIdentity,
+		"commit-tree", worktreeTree, "-p", head, "-p", strings.TrimSpace(indexCommit), "-m", message)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(commit), nil
+}
+
+// writeWorktreeTree writes a tree of every tracked and untracked, non-ignored file in the
+// worktree, using a temporary index.
+func (g *GitEngine) writeWorktreeTree(ctx context.Context, path string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "canopy-index-")
+	if err != nil {
+		return "", cerrors.NewIOFailed("create temporary index", err)
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+
+	if _, err := g.runChecked(ctx, path, env, "read-tree", "HEAD"); err != nil {
+		return "", err
+	}
+
+	if _, err := g.runChecked(ctx, path, env, "add", "-A"); err != nil {
+		return "", err
+	}
+
+	tree, err := g.runChecked(ctx, path, env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(tree), nil
+}
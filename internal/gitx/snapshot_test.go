@@ -0,0 +1,47 @@
+package gitx
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/testutil"
+)
+
+func TestGitEngine_SnapshotWorktree(t *testing.T) {
+	t.Parallel()
+
+	_, clone := setupPullFixture(t)
+	engine := New(t.TempDir())
+	ctx := context.Background()
+
+	if commit, err := engine.SnapshotWorktree(ctx, clone, "clean"); err != nil || commit != "" {
+		t.Fatalf("SnapshotWorktree() on a clean worktree = %q, %v; want empty", commit, err)
+	}
+
+	testutil.MustWriteFile(t, filepath.Join(clone, "README.md"), "modified")
+	testutil.MustWriteFile(t, filepath.Join(clone, "STAGED.md"), "staged")
+	testutil.RunGit(t, clone, "add", "STAGED.md")
+	testutil.MustWriteFile(t, filepath.Join(clone, "UNTRACKED.md"), "untracked")
+
+	commit, err := engine.SnapshotWorktree(ctx, clone, "checkpoint")
+	if err != nil || commit == "" {
+		t.Fatalf("SnapshotWorktree() = %q, %v; want a commit", commit, err)
+	}
+
+	if status := testutil.RunGitOutput(t, clone, "status", "--porcelain"); status != "M README.md\nA  STAGED.md\n?? UNTRACKED.md" {
+		t.Errorf("expected the worktree and index to be untouched, got status %q", status)
+	}
+
+	if parent := testutil.RunGitOutput(t, clone, "rev-parse", commit+"^1"); parent != testutil.RunGitOutput(t, clone, "rev-parse", "HEAD") {
+		t.Errorf("expected HEAD as first parent, got %s", parent)
+	}
+
+	if got := testutil.RunGitOutput(t, clone, "show", commit+":UNTRACKED.md"); got != "untracked" {
+		t.Errorf("expected the untracked file in the snapshot, got %q", got)
+	}
+
+	if files := testutil.RunGitOutput(t, clone, "ls-tree", "--name-only", commit+"^2"); files != "README.md\nSTAGED.md" {
+		t.Errorf("expected the index commit to hold only tracked files, got %q", files)
+	}
+}
@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+)
+
+// evaluateCondition checks a hook's when clause for a single target.
+// It returns whether the hook should run and a human-readable reason.
+// repo is nil for hooks that run once in the workspace root.
+func evaluateCondition(when config.HookCondition, ctx domain.HookContext, repo *domain.Repo) (bool, string) {
+	if when.IsZero() {
+		return true, ""
+	}
+
+	var matched []string
+
+	if when.WorkspaceID != "" {
+		re, err := regexp.Compile(when.WorkspaceID)
+		if err != nil {
+			return false, fmt.Sprintf("when.workspace_id: invalid regex: %v", err)
+		}
+
+		if !re.MatchString(ctx.WorkspaceID) {
+			return false, fmt.Sprintf("workspace %s does not match %q", ctx.WorkspaceID, when.WorkspaceID)
+		}
+
+		matched = append(matched, fmt.Sprintf("workspace matches %q", when.WorkspaceID))
+	}
+
+	if when.Branch != "" {
+		ok, err := path.Match(when.Branch, ctx.BranchName)
+		if err != nil {
+			return false, fmt.Sprintf("when.branch: invalid glob: %v", err)
+		}
+
+		if !ok {
+			return false, fmt.Sprintf("branch %s does not match %q", ctx.BranchName, when.Branch)
+		}
+
+		matched = append(matched, fmt.Sprintf("branch matches %q", when.Branch))
+	}
+
+	if when.FileExists != "" {
+		base := ctx.WorkspacePath
+		if repo != nil {
+			base = filepath.Join(ctx.WorkspacePath, repo.Name)
+		}
+
+		if _, err := os.Stat(filepath.Join(base, filepath.FromSlash(when.FileExists))); err != nil {
+			return false, fmt.Sprintf("%s not found", when.FileExists)
+		}
+
+		matched = append(matched, fmt.Sprintf("%s exists", when.FileExists))
+	}
+
+	if when.RepoTag != "" {
+		if !hasRepoTag(ctx, repo, when.RepoTag) {
+			return false, fmt.Sprintf("no repo tagged %q", when.RepoTag)
+		}
+
+		matched = append(matched, fmt.Sprintf("repo tagged %q", when.RepoTag))
+	}
+
+	return true, strings.Join(matched, ", ")
+}
+
+// hasRepoTag reports whether the repo, or any workspace repo when repo is nil, carries the tag.
+func hasRepoTag(ctx domain.HookContext, repo *domain.Repo, tag string) bool {
+	candidates := ctx.Repos
+	if repo != nil {
+		candidates = []domain.Repo{*repo}
+	}
+
+	for _, r := range candidates {
+		if slices.ContainsFunc(ctx.RepoTags[r.Name], func(t string) bool {
+			return strings.EqualFold(t, tag)
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/logging"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "frontend"), 0o750); err != nil {
+		t.Fatalf("failed to create repo dir: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "frontend", "package.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	frontend := domain.Repo{Name: "frontend"}
+	backend := domain.Repo{Name: "backend"}
+	ctx := domain.HookContext{
+		WorkspaceID:   "PROJ-123",
+		WorkspacePath: tmpDir,
+		BranchName:    "feature/login",
+		Repos:         []domain.Repo{frontend, backend},
+		RepoTags:      map[string][]string{"backend": {"Go"}},
+	}
+
+	tests := []struct {
+		name string
+		when config.HookCondition
+		repo *domain.Repo
+		want bool
+	}{
+		{name: "no condition", want: true},
+		{name: "file exists in repo", when: config.HookCondition{FileExists: "package.json"}, repo: &frontend, want: true},
+		{name: "file missing in repo", when: config.HookCondition{FileExists: "package.json"}, repo: &backend, want: false},
+		{name: "file relative to workspace", when: config.HookCondition{FileExists: "frontend/package.json"}, want: true},
+		{name: "workspace id regex", when: config.HookCondition{WorkspaceID: "^PROJ-"}, want: true},
+		{name: "workspace id mismatch", when: config.HookCondition{WorkspaceID: "^OPS-"}, want: false},
+		{name: "branch glob", when: config.HookCondition{Branch: "feature/*"}, want: true},
+		{name: "branch glob mismatch", when: config.HookCondition{Branch: "release/*"}, want: false},
+		{name: "repo tag on repo", when: config.HookCondition{RepoTag: "go"}, repo: &backend, want: true},
+		{name: "repo tag missing on repo", when: config.HookCondition{RepoTag: "go"}, repo: &frontend, want: false},
+		{name: "repo tag on any workspace repo", when: config.HookCondition{RepoTag: "go"}, want: true},
+		{name: "all conditions must match", when: config.HookCondition{Branch: "feature/*", WorkspaceID: "^OPS-"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, reason := evaluateCondition(tt.when, ctx, tt.repo)
+			if got != tt.want {
+				t.Errorf("evaluateCondition() = %v (%s), want %v", got, reason, tt.want)
+			}
+
+			if !tt.when.IsZero() && reason == "" {
+				t.Error("expected a reason when conditions are set")
+			}
+		})
+	}
+}
+
+func TestExecuteHooks_DryRunReportsConditionReasons(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "frontend"), 0o750); err != nil {
+		t.Fatalf("failed to create repo dir: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "frontend", "package.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	executor := NewExecutor(logging.New(false))
+	hooks := []config.Hook{
+		{
+			Command: "npm install",
+			Repos:   []string{"frontend", "backend"},
+			When:    config.HookCondition{FileExists: "package.json"},
+		},
+	}
+
+	ctx := domain.HookContext{
+		WorkspaceID:   "test-ws",
+		WorkspacePath: tmpDir,
+		BranchName:    "main",
+		Repos:         []domain.Repo{{Name: "frontend"}, {Name: "backend"}},
+	}
+
+	results, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ExecuteHooks dry-run failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Skipped || results[0].MatchReason != "package.json exists" {
+		t.Errorf("expected frontend to be included, got %+v", results[0].HookCommandPreview)
+	}
+
+	if !results[1].Skipped || results[1].SkipReason != "package.json not found" {
+		t.Errorf("expected backend to be skipped, got %+v", results[1].HookCommandPreview)
+	}
+}
@@ -2,7 +2,8 @@
 //
 // Hooks are user-defined commands that execute at specific points in the workspace
 // lifecycle. They run sequentially in the order defined in configuration, and each
-// hook can be filtered to run only in specific repositories.
+// hook can be filtered to run only in specific repositories. Hooks that declare
+// needs run as a dependency graph instead, and parallel hooks fan out across repos.
 //
 // Security: Hooks execute arbitrary commands from user-controlled configuration.
 // The trust boundary is the user's config file - no sandboxing is applied.
@@ -16,6 +17,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"text/template"
 	"time"
 
@@ -32,30 +34,66 @@ var _ ports.HookExecutor = (*Executor)(nil)
 // DefaultTimeout is the default hook execution timeout.
 const DefaultTimeout = 30 * time.Second
 
+// maxCapturedOutput bounds the stdout/stderr kept in a HookResult.
+const maxCapturedOutput = 64 * 1024
+
+// ParallelRunner runs total independent tasks, possibly concurrently, and waits for all of them.
+type ParallelRunner func(ctx context.Context, total int, fn func(ctx context.Context, index int) error) error
+
 // Executor executes lifecycle hooks.
 type Executor struct {
 	logger *logging.Logger
+	runner ParallelRunner
+}
+
+// ExecutorOption is a functional option for configuring the Executor.
+type ExecutorOption func(*Executor)
+
+// WithParallelRunner sets the runner used for parallel hooks and dependency graphs.
+func WithParallelRunner(r ParallelRunner) ExecutorOption {
+	return func(e *Executor) {
+		e.runner = r
+	}
 }
 
-// NewExecutor creates a new hook executor.
-func NewExecutor(logger *logging.Logger) *Executor {
-	return &Executor{logger: logger}
+// NewExecutor creates a new hook executor. Without a parallel runner, hooks run sequentially.
+func NewExecutor(logger *logging.Logger, opts ...ExecutorOption) *Executor {
+	e := &Executor{logger: logger, runner: runSequential}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
 }
 
-// ExecuteHooks runs a list of hooks with the given context.
+// runSequential is the default ParallelRunner; it runs tasks one at a time in index order.
+func runSequential(ctx context.Context, total int, fn func(ctx context.Context, index int) error) error {
+	for i := 0; i < total; i++ {
+		if err := fn(ctx, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExecuteHooks runs a list of hooks with the given context and returns one result per command.
 // If continueOnError is true at the executor level, it continues even if a hook fails.
+// When any hook declares needs, hooks run as a dependency graph; otherwise they run in order.
 func (e *Executor) ExecuteHooks(
 	hks []config.Hook,
 	ctx domain.HookContext,
 	opts ports.HookExecuteOptions,
-) ([]domain.HookCommandPreview, error) {
-	var previews []domain.HookCommandPreview
+) ([]domain.HookResult, error) {
+	if hasDependencies(hks) {
+		return e.executeGraph(hks, ctx, opts)
+	}
+
+	var results []domain.HookResult
 
 	for i, hook := range hks {
-		hookPreviews, err := e.executeHook(hook, ctx, i, opts.DryRun)
-		if opts.DryRun {
-			previews = append(previews, hookPreviews...)
-		}
+		hookResults, err := e.executeHook(hook, ctx, i, opts.DryRun)
+		results = append(results, hookResults...)
 
 		if err != nil {
 			if hook.ContinueOnError || opts.ContinueOnError {
@@ -63,81 +101,143 @@ func (e *Executor) ExecuteHooks(
 				continue
 			}
 
-			return previews, err
+			for j := i + 1; j < len(hks); j++ {
+				results = append(results, skippedResult(hks[j], ctx, j, "previous hook failed"))
+			}
+
+			return results, err
 		}
 	}
 
-	return previews, nil
+	return results, nil
 }
 
-// executeHook runs a single hook command.
+// executeHook runs a single hook, once per matching repo or once in the workspace root.
 func (e *Executor) executeHook(
 	hook config.Hook,
 	ctx domain.HookContext,
 	index int,
 	dryRun bool,
-) ([]domain.HookCommandPreview, error) {
-	var previews []domain.HookCommandPreview
+) ([]domain.HookResult, error) {
+	// No repos filter - run once in workspace root
+	if len(hook.Repos) == 0 {
+		result, err := e.executeTarget(hook, ctx, nil, index, dryRun)
 
-	// Determine repos to run against
-	repos := ctx.Repos
-	if len(hook.Repos) > 0 {
-		repos = filterRepos(ctx.Repos, hook.Repos)
+		return []domain.HookResult{result}, err
 	}
 
-	// If repos filter specified, run once per matching repo
-	if len(hook.Repos) > 0 {
-		for _, repo := range repos {
-			repoPath := filepath.Join(ctx.WorkspacePath, repo.Name)
+	repos := filterRepos(ctx.Repos, hook.Repos)
+	if len(repos) == 0 {
+		return []domain.HookResult{skippedResult(hook, ctx, index, "no matching repos")}, nil
+	}
 
-			resolvedCommand, err := e.resolveCommand(hook, ctx, &repo)
-			if err != nil {
-				return previews, err
-			}
+	if hook.Parallel && len(repos) > 1 {
+		return e.executeParallel(hook, ctx, repos, index, dryRun)
+	}
 
-			if dryRun {
-				previews = append(previews, e.previewCommand(index, resolvedCommand, hook.Description, repoPath, ctx, &repo))
-				continue
-			}
+	results := make([]domain.HookResult, 0, len(repos))
 
-			if err := e.runCommand(hook, ctx, repoPath, &repo, index, resolvedCommand); err != nil {
-				return previews, err
-			}
+	for _, repo := range repos {
+		result, err := e.executeTarget(hook, ctx, &repo, index, dryRun)
+		results = append(results, result)
+
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// executeParallel runs a hook in every repo concurrently. Each repo keeps its own captured
+// output, and results are returned in repo order with the first failure as the error.
+func (e *Executor) executeParallel(
+	hook config.Hook,
+	ctx domain.HookContext,
+	repos []domain.Repo,
+	index int,
+	dryRun bool,
+) ([]domain.HookResult, error) {
+	results := make([]domain.HookResult, len(repos))
+	errs := make([]error, len(repos))
+
+	_ = e.runner(context.Background(), len(repos), func(_ context.Context, i int) error {
+		results[i], errs[i] = e.executeTarget(hook, ctx, &repos[i], index, dryRun)
+		return nil
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return results, err
 		}
+	}
+
+	return results, nil
+}
+
+// executeTarget resolves and runs a hook for a single repo, or the workspace root when repo is nil.
+func (e *Executor) executeTarget(
+	hook config.Hook,
+	ctx domain.HookContext,
+	repo *domain.Repo,
+	index int,
+	dryRun bool,
+) (domain.HookResult, error) {
+	data := newTemplateContext(ctx, repo)
+	result := domain.HookResult{HookCommandPreview: e.previewCommand(index, hook.Command, hook.Description, "", ctx, repo)}
+
+	matched, reason := evaluateCondition(hook.When, ctx, repo)
+	if !matched {
+		result.Skipped = true
+		result.SkipReason = reason
 
-		return previews, nil
+		return result, nil
 	}
 
-	// No repos filter - run once in workspace root
-	resolvedCommand, err := e.resolveCommand(hook, ctx, nil)
+	resolvedCommand, err := renderTemplate("command", hook.Command, data)
 	if err != nil {
-		return previews, err
+		result.Error = err.Error()
+		return result, err
 	}
 
+	workDir, err := resolveWorkingDir(hook, ctx, repo, data)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	env, err := resolveEnv(hook, data)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	result.HookCommandPreview = e.previewCommand(index, resolvedCommand, hook.Description, workDir, ctx, repo)
+	result.MatchReason = reason
+
 	if dryRun {
-		previews = append(previews, e.previewCommand(index, resolvedCommand, hook.Description, ctx.WorkspacePath, ctx, nil))
-		return previews, nil
+		return result, nil
 	}
 
-	return previews, e.runCommand(hook, ctx, ctx.WorkspacePath, nil, index, resolvedCommand)
+	return e.runCommand(hook, ctx, result, repo, env)
 }
 
-// runCommand executes the hook command in the specified directory.
+// runCommand executes the hook command in the result's working directory.
 func (e *Executor) runCommand(
 	hook config.Hook,
 	ctx domain.HookContext,
-	workDir string,
+	result domain.HookResult,
 	repo *domain.Repo,
-	index int,
-	resolvedCommand string,
-) error {
+	extraEnv []string,
+) (domain.HookResult, error) {
 	shell := resolveShell(hook.Shell)
 	timeout := resolveTimeout(hook.Timeout)
 
 	execCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	cmd := e.buildCommand(execCtx, shell, resolvedCommand, workDir, ctx, repo)
+	cmd := e.buildCommand(execCtx, shell, result.Command, result.WorkingDir, ctx, repo)
+	cmd.Env = append(cmd.Env, extraEnv...)
 
 	var stdout, stderr bytes.Buffer
 
@@ -145,9 +245,9 @@ func (e *Executor) runCommand(
 	cmd.Stderr = &stderr
 
 	e.logger.Debug("Executing hook",
-		"index", index,
-		"command", logging.RedactSensitive(resolvedCommand),
-		"working_dir", workDir,
+		"index", result.Index,
+		"command", logging.RedactSensitive(result.Command),
+		"working_dir", result.WorkingDir,
 		"timeout", timeout,
 	)
 
@@ -155,13 +255,108 @@ func (e *Executor) runCommand(
 	err := cmd.Run()
 	duration := time.Since(start)
 
+	result.DurationMs = duration.Milliseconds()
+	result.Stdout = truncateOutput(stdout.String())
+	result.Stderr = truncateOutput(stderr.String())
+
 	if err != nil {
-		return e.handleCommandError(execCtx, err, resolvedCommand, index, repo, timeout, stderr.String())
+		hookErr := e.handleCommandError(execCtx, err, result.Command, result.Index, repo, timeout, stderr.String())
+		result.ExitCode = exitCode(err)
+		result.Error = hookErr.Error()
+
+		return result, hookErr
 	}
 
-	e.logCommandSuccess(index, duration, stdout.String(), stderr.String())
+	e.logCommandSuccess(result.Index, duration, stdout.String(), stderr.String())
 
-	return nil
+	return result, nil
+}
+
+// skippedResult records a hook that was not run.
+func skippedResult(hook config.Hook, ctx domain.HookContext, index int, reason string) domain.HookResult {
+	return domain.HookResult{
+		HookCommandPreview: domain.HookCommandPreview{
+			Index:         index,
+			Command:       hook.Command,
+			Description:   hook.Description,
+			WorkspaceID:   ctx.WorkspaceID,
+			WorkspacePath: ctx.WorkspacePath,
+			BranchName:    ctx.BranchName,
+			Skipped:       true,
+			SkipReason:    reason,
+		},
+	}
+}
+
+// resolveWorkingDir returns the directory a hook runs in. working_dir is relative to the
+// repo directory for repo-filtered hooks and to the workspace root otherwise.
+func resolveWorkingDir(hook config.Hook, ctx domain.HookContext, repo *domain.Repo, data hookTemplateContext) (string, error) {
+	base := ctx.WorkspacePath
+	if repo != nil {
+		base = filepath.Join(ctx.WorkspacePath, repo.Name)
+	}
+
+	if hook.WorkingDir == "" {
+		return base, nil
+	}
+
+	dir, err := renderTemplate("working_dir", hook.WorkingDir, data)
+	if err != nil {
+		return "", err
+	}
+
+	dir = filepath.FromSlash(dir)
+	if !filepath.IsLocal(dir) {
+		return "", cerrors.NewInvalidArgument("working_dir", fmt.Sprintf("must stay inside %s, got %q", base, dir))
+	}
+
+	return filepath.Join(base, dir), nil
+}
+
+// resolveEnv renders the hook's env values, sorted by key for deterministic ordering.
+func resolveEnv(hook config.Hook, data hookTemplateContext) ([]string, error) {
+	if len(hook.Env) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(hook.Env))
+	for key := range hook.Env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		value, err := renderTemplate("env."+key, hook.Env[key], data)
+		if err != nil {
+			return nil, err
+		}
+
+		env = append(env, key+"="+value)
+	}
+
+	return env, nil
+}
+
+// truncateOutput keeps the tail of long command output.
+func truncateOutput(out string) string {
+	if len(out) <= maxCapturedOutput {
+		return out
+	}
+
+	return "...(truncated)\n" + out[len(out)-maxCapturedOutput:]
+}
+
+// exitCode extracts the process exit code, or -1 when the command did not exit normally.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
 }
 
 // resolveShell determines the shell to use for executing the hook.
@@ -220,12 +415,8 @@ func (e *Executor) buildEnvVars(ctx domain.HookContext, repo *domain.Repo) []str
 	return env
 }
 
-func (e *Executor) resolveCommand(hook config.Hook, ctx domain.HookContext, repo *domain.Repo) (string, error) {
-	tmpl, err := template.New("hook").Option("missingkey=error").Parse(hook.Command)
-	if err != nil {
-		return "", err
-	}
-
+// newTemplateContext builds the data available to hook templates.
+func newTemplateContext(ctx domain.HookContext, repo *domain.Repo) hookTemplateContext {
 	data := hookTemplateContext{
 		WorkspaceID:   ctx.WorkspaceID,
 		WorkspacePath: ctx.WorkspacePath,
@@ -239,6 +430,16 @@ func (e *Executor) resolveCommand(hook config.Hook, ctx domain.HookContext, repo
 		data.RepoPath = filepath.Join(ctx.WorkspacePath, repo.Name)
 	}
 
+	return data
+}
+
+// renderTemplate resolves a hook template string against the template context.
+func renderTemplate(name, text string, data hookTemplateContext) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
 		return "", err
@@ -287,19 +488,12 @@ func (e *Executor) handleCommandError(
 		return cerrors.NewHookTimeout(index, command, timeout)
 	}
 
-	exitCode := -1
-
-	var exitErr *exec.ExitError
-	if errors.As(err, &exitErr) {
-		exitCode = exitErr.ExitCode()
-	}
-
 	repoName := ""
 	if repo != nil {
 		repoName = repo.Name
 	}
 
-	return cerrors.NewHookFailed(index, command, exitCode, repoName, stderrOutput)
+	return cerrors.NewHookFailed(index, command, exitCode(err), repoName, stderrOutput)
 }
 
 // logCommandSuccess logs successful hook completion and any output.
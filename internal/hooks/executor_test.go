@@ -531,3 +531,154 @@ func TestExecuteHooks_EmptyHooks(t *testing.T) {
 		t.Fatalf("Nil hooks should succeed: %v", err)
 	}
 }
+
+func TestExecuteHooks_ReturnsResults(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logger := logging.New(false)
+	executor := NewExecutor(logger)
+
+	hooks := []config.Hook{
+		{Command: "echo out; echo err >&2", Description: "print"},
+		{Command: "exit 3"},
+		{Command: "echo never"},
+	}
+
+	ctx := domain.HookContext{
+		WorkspaceID:   "test-ws",
+		WorkspacePath: tmpDir,
+		BranchName:    "main",
+		Repos:         []domain.Repo{},
+	}
+
+	results, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{})
+	if err == nil {
+		t.Fatal("expected error from failing hook")
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if results[0].ExitCode != 0 || results[0].Stdout != "out\n" || results[0].Stderr != "err\n" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+
+	if results[0].WorkingDir != tmpDir || results[0].Description != "print" {
+		t.Errorf("expected working dir and description in result, got %+v", results[0])
+	}
+
+	if results[1].ExitCode != 3 || results[1].Error == "" {
+		t.Errorf("expected exit code 3 with error, got %+v", results[1])
+	}
+
+	if !results[2].Skipped || results[2].SkipReason != "previous hook failed" {
+		t.Errorf("expected remaining hook to be skipped, got %+v", results[2])
+	}
+}
+
+func TestExecuteHooks_SkipsHooksWithoutMatchingRepos(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logger := logging.New(false)
+	executor := NewExecutor(logger)
+
+	hooks := []config.Hook{
+		{Command: "echo hi", Repos: []string{"missing"}},
+	}
+
+	ctx := domain.HookContext{
+		WorkspaceID:   "test-ws",
+		WorkspacePath: tmpDir,
+		BranchName:    "main",
+		Repos:         []domain.Repo{{Name: "frontend"}},
+	}
+
+	results, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteHooks failed: %v", err)
+	}
+
+	if len(results) != 1 || !results[0].Skipped || results[0].SkipReason != "no matching repos" {
+		t.Fatalf("expected one skipped result, got %+v", results)
+	}
+}
+
+func TestExecuteHooks_EnvAndWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logger := logging.New(false)
+	executor := NewExecutor(logger)
+
+	repoDir := filepath.Join(tmpDir, "frontend", "web")
+	if err := os.MkdirAll(repoDir, 0o750); err != nil {
+		t.Fatalf("failed to create repo dir: %v", err)
+	}
+
+	hooks := []config.Hook{
+		{
+			Command:    "pwd; echo $GREETING $CANOPY_REPO_NAME",
+			Repos:      []string{"frontend"},
+			WorkingDir: "web",
+			Env:        map[string]string{"GREETING": "hello-{{.WorkspaceID}}"},
+		},
+	}
+
+	ctx := domain.HookContext{
+		WorkspaceID:   "test-ws",
+		WorkspacePath: tmpDir,
+		BranchName:    "main",
+		Repos:         []domain.Repo{{Name: "frontend"}},
+	}
+
+	results, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteHooks failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	if results[0].WorkingDir != repoDir {
+		t.Errorf("expected working dir %s, got %s", repoDir, results[0].WorkingDir)
+	}
+
+	lines := strings.Split(strings.TrimSpace(results[0].Stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected output %q", results[0].Stdout)
+	}
+
+	if resolved, _ := filepath.EvalSymlinks(repoDir); lines[0] != repoDir && lines[0] != resolved {
+		t.Errorf("expected hook to run in %s, got %s", repoDir, lines[0])
+	}
+
+	if lines[1] != "hello-test-ws frontend" {
+		t.Errorf("expected templated env on top of CANOPY_* vars, got %q", lines[1])
+	}
+}
+
+func TestExecuteHooks_WorkingDirMustStayInside(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	logger := logging.New(false)
+	executor := NewExecutor(logger)
+
+	hooks := []config.Hook{
+		{Command: "echo hi", WorkingDir: "{{.BranchName}}"},
+	}
+
+	ctx := domain.HookContext{
+		WorkspaceID:   "test-ws",
+		WorkspacePath: tmpDir,
+		BranchName:    "../escape",
+	}
+
+	if _, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{}); err == nil {
+		t.Fatal("expected error for working_dir outside the workspace")
+	}
+}
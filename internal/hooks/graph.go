@@ -0,0 +1,182 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// hasDependencies reports whether any hook declares needs.
+func hasDependencies(hks []config.Hook) bool {
+	for _, hook := range hks {
+		if len(hook.Needs) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// executeGraph runs hooks as soon as the hooks they need have succeeded, so independent
+// hooks overlap. Once a hook fails, hooks that have not started yet are skipped.
+// Results are returned in configuration order regardless of completion order.
+func (e *Executor) executeGraph(
+	hks []config.Hook,
+	ctx domain.HookContext,
+	opts ports.HookExecuteOptions,
+) ([]domain.HookResult, error) {
+	order, err := dependencyOrder(hks)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]int, len(hks))
+	for i, hook := range hks {
+		if hook.Name != "" {
+			byName[hook.Name] = i
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	perHook := make([][]domain.HookResult, len(hks))
+	failed := make([]bool, len(hks))
+	done := make([]chan struct{}, len(hks))
+
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	_ = e.runner(context.Background(), len(order), func(_ context.Context, pos int) error {
+		i := order[pos]
+		hook := hks[i]
+
+		defer close(done[i])
+
+		for _, need := range hook.Needs {
+			<-done[byName[need]]
+		}
+
+		mu.Lock()
+		reason := ""
+
+		if firstErr != nil {
+			reason = "previous hook failed"
+		}
+
+		for _, need := range hook.Needs {
+			if reason == "" && failed[byName[need]] {
+				reason = fmt.Sprintf("needs %s, which did not succeed", need)
+			}
+		}
+
+		if reason != "" {
+			failed[i] = true
+			perHook[i] = []domain.HookResult{skippedResult(hook, ctx, i, reason)}
+		}
+		mu.Unlock()
+
+		if reason != "" {
+			return nil
+		}
+
+		results, hookErr := e.executeHook(hook, ctx, i, opts.DryRun)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		perHook[i] = results
+
+		if hookErr != nil {
+			if hook.ContinueOnError || opts.ContinueOnError {
+				e.logger.Warn("Hook failed but continuing", "index", i, "command", hook.Command, "error", hookErr)
+				return nil
+			}
+
+			failed[i] = true
+
+			if firstErr == nil {
+				firstErr = hookErr
+			}
+		}
+
+		return nil
+	})
+
+	var results []domain.HookResult
+	for _, hookResults := range perHook {
+		results = append(results, hookResults...)
+	}
+
+	return results, firstErr
+}
+
+// dependencyOrder returns hook indexes in an order where every hook comes after the hooks it
+// needs, preserving configuration order among independent hooks.
+func dependencyOrder(hks []config.Hook) ([]int, error) {
+	byName := make(map[string]int, len(hks))
+
+	for i, hook := range hks {
+		if hook.Name != "" {
+			byName[hook.Name] = i
+		}
+	}
+
+	for _, hook := range hks {
+		for _, need := range hook.Needs {
+			if _, ok := byName[need]; !ok {
+				return nil, cerrors.NewInvalidArgument("needs", fmt.Sprintf("hook %q needs unknown hook %q", hook.Name, need))
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(hks))
+	order := make([]int, 0, len(hks))
+
+	var visit func(i int, path []string) error
+
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return cerrors.NewInvalidArgument("needs", fmt.Sprintf("dependency cycle: %s", strings.Join(append(path, hks[i].Name), " -> ")))
+		}
+
+		state[i] = visiting
+
+		for _, need := range hks[i].Needs {
+			if err := visit(byName[need], append(path, hks[i].Name)); err != nil {
+				return err
+			}
+		}
+
+		state[i] = visited
+		order = append(order, i)
+
+		return nil
+	}
+
+	for i := range hks {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,162 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/logging"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// concurrentRunner starts every task at once, like a ParallelExecutor with enough workers.
+func concurrentRunner(ctx context.Context, total int, fn func(ctx context.Context, index int) error) error {
+	var wg sync.WaitGroup
+
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			_ = fn(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// waitForFile polls for a marker file so a hook only succeeds if another hook runs alongside it.
+func waitForFile(name string) string {
+	return "i=0; while [ ! -f " + name + " ]; do i=$((i+1)); [ $i -gt 100 ] && exit 1; sleep 0.05; done"
+}
+
+func TestExecuteHooks_NeedsOverlapsIndependentHooks(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	executor := NewExecutor(logging.New(false), WithParallelRunner(concurrentRunner))
+
+	hooks := []config.Hook{
+		{Name: "deps", Command: "touch deps-started; " + waitForFile("assets-started") + "; touch deps-done"},
+		{Name: "assets", Command: "touch assets-started; " + waitForFile("deps-started")},
+		{Name: "build", Command: "test -f deps-done && touch built", Needs: []string{"deps", "assets"}},
+	}
+
+	ctx := domain.HookContext{WorkspaceID: "test-ws", WorkspacePath: tmpDir, BranchName: "main"}
+
+	results, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteHooks failed: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("expected results in configuration order, got index %d at %d", result.Index, i)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "built")); err != nil {
+		t.Error("expected build hook to run after its dependencies")
+	}
+}
+
+func TestExecuteHooks_NeedsSkipsDependentsOfFailedHook(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	executor := NewExecutor(logging.New(false))
+
+	hooks := []config.Hook{
+		{Name: "deps", Command: "exit 2"},
+		{Name: "build", Command: "touch built", Needs: []string{"deps"}},
+	}
+
+	ctx := domain.HookContext{WorkspaceID: "test-ws", WorkspacePath: tmpDir, BranchName: "main"}
+
+	results, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{})
+	if err == nil {
+		t.Fatal("expected error from failing dependency")
+	}
+
+	if len(results) != 2 || !results[1].Skipped {
+		t.Fatalf("expected dependent hook to be skipped, got %+v", results)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(tmpDir, "built")); !os.IsNotExist(statErr) {
+		t.Error("dependent hook should not run")
+	}
+}
+
+func TestExecuteHooks_NeedsRejectsCycles(t *testing.T) {
+	t.Parallel()
+
+	executor := NewExecutor(logging.New(false))
+
+	hooks := []config.Hook{
+		{Name: "a", Command: "true", Needs: []string{"b"}},
+		{Name: "b", Command: "true", Needs: []string{"a"}},
+	}
+
+	_, err := executor.ExecuteHooks(hooks, domain.HookContext{WorkspacePath: t.TempDir()}, ports.HookExecuteOptions{})
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected dependency cycle error, got %v", err)
+	}
+}
+
+func TestExecuteHooks_ParallelFansOutAcrossRepos(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	for _, name := range []string{"web", "admin"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, name), 0o750); err != nil {
+			t.Fatalf("failed to create repo dir: %v", err)
+		}
+	}
+
+	executor := NewExecutor(logging.New(false), WithParallelRunner(concurrentRunner))
+
+	// Each repo waits for the other to start, which only succeeds when they run concurrently.
+	hooks := []config.Hook{
+		{
+			Command:  "touch ../{{.RepoName}}.started; " + waitForFile("../{{if eq .RepoName \"web\"}}admin{{else}}web{{end}}.started") + "; echo {{.RepoName}}",
+			Repos:    []string{"web", "admin"},
+			Parallel: true,
+		},
+	}
+
+	ctx := domain.HookContext{
+		WorkspaceID:   "test-ws",
+		WorkspacePath: tmpDir,
+		BranchName:    "main",
+		Repos:         []domain.Repo{{Name: "web"}, {Name: "admin"}},
+	}
+
+	results, err := executor.ExecuteHooks(hooks, ctx, ports.HookExecuteOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteHooks failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	if results[0].RepoName != "web" || results[0].Stdout != "web\n" {
+		t.Errorf("expected separate output for web, got %+v", results[0])
+	}
+
+	if results[1].RepoName != "admin" || results[1].Stdout != "admin\n" {
+		t.Errorf("expected separate output for admin, got %+v", results[1])
+	}
+}
@@ -0,0 +1,176 @@
+// Package httpjson sends JSON requests to the HTTP APIs behind the forge and issue tracker adapters.
+package httpjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxErrorBody bounds how much of an error response is read and reported.
+const maxErrorBody = 4096
+
+// Options holds the dependencies shared by API adapters.
+type Options struct {
+	Client *http.Client
+	Getenv func(string) string
+}
+
+// Option is a functional option for configuring an API adapter.
+type Option func(*Options)
+
+// WithHTTPClient sets the HTTP client used for API requests.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// WithGetenv sets the function used to read API tokens from the environment.
+func WithGetenv(getenv func(string) string) Option {
+	return func(o *Options) {
+		o.Getenv = getenv
+	}
+}
+
+// NewOptions returns a client bounded by timeout and os.Getenv, overridden by opts.
+func NewOptions(timeout time.Duration, opts ...Option) Options {
+	o := Options{
+		Client: &http.Client{Timeout: timeout},
+		Getenv: os.Getenv,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Error is a request that failed once encoded: it could not be sent, got no response,
+// got a non-2xx status or returned a body that could not be decoded.
+type Error struct {
+	// Status is the HTTP status code, or 0 when no response was received.
+	Status int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Do sends in as the JSON body of a request and decodes the response into out. Either may be nil.
+// authorize, when set, adds the authentication headers. An in that cannot be encoded is reported
+// as the encoding error; every later failure is an *Error.
+func Do(ctx context.Context, client *http.Client, method, url string, authorize func(*http.Request), in, out interface{}) error {
+	var body io.Reader
+
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return &Error{Err: err}
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if authorize != nil {
+		authorize(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &Error{Err: err}
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &Error{Status: resp.StatusCode, Err: errors.New(errorMessage(resp.Status, data))}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return &Error{Status: resp.StatusCode, Err: fmt.Errorf("decode response: %w", err)}
+	}
+
+	return nil
+}
+
+// errorMessage extracts a readable message from an error response. Most APIs use a "message"
+// field (GitLab sometimes a list or object), GitLab OAuth errors use "error", GitHub adds
+// validation details in "errors" and Jira reports "errorMessages".
+func errorMessage(status string, data []byte) string {
+	var payload struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+		ErrorMessages []string `json:"errorMessages"`
+	}
+
+	if err := json.Unmarshal(data, &payload); err != nil {
+		if text := strings.TrimSpace(string(data)); text != "" {
+			return status + ": " + text
+		}
+
+		return status
+	}
+
+	var parts []string
+
+	var message string
+	if json.Unmarshal(payload.Message, &message) != nil && len(payload.Message) > 0 {
+		// Not a plain string: report the raw JSON
+		message = string(payload.Message)
+	}
+
+	if message != "" {
+		parts = append(parts, message)
+	}
+
+	if payload.Error != "" {
+		parts = append(parts, payload.Error)
+	}
+
+	for _, e := range payload.Errors {
+		if e.Message != "" {
+			parts = append(parts, e.Message)
+		}
+	}
+
+	parts = append(parts, payload.ErrorMessages...)
+
+	if len(parts) == 0 {
+		return status
+	}
+
+	return status + ": " + strings.Join(parts, "; ")
+}
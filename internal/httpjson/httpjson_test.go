@@ -0,0 +1,74 @@
+package httpjson
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path == "/missing" {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id": 7}`))
+	}))
+	t.Cleanup(server.Close)
+
+	authorize := func(req *http.Request) { req.Header.Set("Authorization", "Bearer secret") }
+	in := map[string]string{"title": "x"}
+
+	var out struct {
+		ID int `json:"id"`
+	}
+
+	if err := Do(context.Background(), server.Client(), http.MethodPost, server.URL+"/ok", authorize, in, &out); err != nil || out.ID != 7 {
+		t.Fatalf("Do = %v, id %d; want nil, id 7", err, out.ID)
+	}
+
+	err := Do(context.Background(), server.Client(), http.MethodPost, server.URL+"/missing", authorize, in, &out)
+
+	var reqErr *Error
+	if !errors.As(err, &reqErr) || reqErr.Status != http.StatusNotFound || reqErr.Error() != "404 Not Found: Not Found" {
+		t.Errorf("expected 404 request error, got %v", err)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "empty", body: "", want: "400 Bad Request"},
+		{name: "plain text", body: "bad input\n", want: "400 Bad Request: bad input"},
+		{name: "message", body: `{"message": "Validation Failed", "errors": [{"message": "already exists"}]}`, want: "400 Bad Request: Validation Failed; already exists"},
+		{name: "structured message", body: `{"message": {"title": ["is too long"]}}`, want: `400 Bad Request: {"title": ["is too long"]}`},
+		{name: "oauth error", body: `{"error": "invalid_token"}`, want: "400 Bad Request: invalid_token"},
+		{name: "jira", body: `{"errorMessages": ["Issue does not exist"]}`, want: "400 Bad Request: Issue does not exist"},
+		{name: "no details", body: `{}`, want: "400 Bad Request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := errorMessage("400 Bad Request", []byte(tt.body)); got != tt.want {
+				t.Errorf("errorMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
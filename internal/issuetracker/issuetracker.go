@@ -0,0 +1,115 @@
+// Package issuetracker implements ports.IssueTracker for Jira, GitHub Issues, Linear and
+// any HTTP endpoint returning the issue as JSON.
+//
+// The tracker is chosen with the "issue_tracker" config section. API tokens are read from
+// the environment: JIRA_API_TOKEN (with JIRA_USER for Jira Cloud), GITHUB_TOKEN or GH_TOKEN,
+// LINEAR_API_KEY, CANOPY_ISSUE_TOKEN for the HTTP adapter, or the variable named by token_env.
+package issuetracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/httpjson"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// DefaultTimeout bounds each issue tracker request.
+const DefaultTimeout = 30 * time.Second
+
+// defaultTokenEnvs lists the environment variables checked for each tracker type's token.
+var defaultTokenEnvs = map[string][]string{
+	config.IssueTrackerJira:   {"JIRA_API_TOKEN"},
+	config.IssueTrackerGitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	config.IssueTrackerLinear: {"LINEAR_API_KEY"},
+	config.IssueTrackerHTTP:   {"CANOPY_ISSUE_TOKEN"},
+}
+
+// New returns the issue tracker described by cfg, or nil when no issue tracker is configured.
+func New(cfg config.IssueTrackerConfig, opts ...httpjson.Option) ports.IssueTracker {
+	o := httpjson.NewOptions(DefaultTimeout, opts...)
+
+	tokenEnvs := defaultTokenEnvs[cfg.Type]
+	if cfg.TokenEnv != "" {
+		tokenEnvs = []string{cfg.TokenEnv}
+	}
+
+	api := apiClient{tracker: cfg.Type, client: o.Client, getenv: o.Getenv, tokenEnvs: tokenEnvs}
+	baseURL := strings.TrimSuffix(cfg.URL, "/")
+
+	switch cfg.Type {
+	case config.IssueTrackerJira:
+		userEnv := cfg.UserEnv
+		if userEnv == "" {
+			userEnv = "JIRA_USER"
+		}
+
+		return &jira{api: api, baseURL: baseURL, userEnv: userEnv}
+	case config.IssueTrackerGitHub:
+		if baseURL == "" {
+			baseURL = "https://api.github.com"
+		}
+
+		return &gitHub{api: api, baseURL: baseURL, repository: cfg.Repository}
+	case config.IssueTrackerLinear:
+		if baseURL == "" {
+			baseURL = "https://api.linear.app/graphql"
+		}
+
+		return &linear{api: api, endpoint: baseURL}
+	case config.IssueTrackerHTTP:
+		return &httpTracker{api: api, endpoint: cfg.URL}
+	default:
+		return nil
+	}
+}
+
+// apiClient sends JSON requests to an issue tracker API.
+type apiClient struct {
+	tracker   string
+	client    *http.Client
+	getenv    func(string) string
+	tokenEnvs []string
+}
+
+// token returns the first API token set in the tracker's environment variables.
+func (c *apiClient) token() string {
+	for _, env := range c.tokenEnvs {
+		if token := c.getenv(env); token != "" {
+			return token
+		}
+	}
+
+	return ""
+}
+
+// missingToken returns the error for a tracker that requires a token but has none.
+func (c *apiClient) missingToken(id string) error {
+	return cerrors.NewIssueLookupFailed(c.tracker, id, 0,
+		fmt.Errorf("no API token: set %s", strings.Join(c.tokenEnvs, " or ")))
+}
+
+// do sends in as the JSON body of a request for issue id and decodes the response into out.
+// in may be nil. Non-2xx responses become issue lookup errors.
+func (c *apiClient) do(ctx context.Context, id, method, url string, authorize func(*http.Request), in, out interface{}) error {
+	err := httpjson.Do(ctx, c.client, method, url, authorize, in, out)
+
+	var reqErr *httpjson.Error
+
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return cerrors.NewContextError(ctx, c.tracker+" issue lookup", id)
+	case errors.As(err, &reqErr):
+		return cerrors.NewIssueLookupFailed(c.tracker, id, reqErr.Status, reqErr.Err)
+	default:
+		return cerrors.NewInternalError("encode "+c.tracker+" request", err)
+	}
+}
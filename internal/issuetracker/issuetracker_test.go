@@ -0,0 +1,189 @@
+package issuetracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/httpjson"
+)
+
+func TestTrackers_GetIssue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      config.IssueTrackerConfig
+		env      map[string]string
+		id       string
+		path     string
+		response string
+		// checkRequest verifies authentication and, for GraphQL, the request body.
+		checkRequest func(t *testing.T, r *http.Request)
+		want         func(serverURL string) *domain.Issue
+	}{
+		{
+			name:     "jira cloud",
+			cfg:      config.IssueTrackerConfig{Type: config.IssueTrackerJira},
+			env:      map[string]string{"JIRA_API_TOKEN": "secret", "JIRA_USER": "me@example.com"},
+			id:       "PROJ-123",
+			path:     "/rest/api/2/issue/PROJ-123",
+			response: `{"key": "PROJ-123", "fields": {"summary": "Fix login redirect"}}`,
+			checkRequest: func(t *testing.T, r *http.Request) {
+				t.Helper()
+
+				if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "secret" {
+					t.Errorf("expected basic auth, got %q", r.Header.Get("Authorization"))
+				}
+
+				if r.URL.Query().Get("fields") != "summary" {
+					t.Errorf("expected fields=summary, got %q", r.URL.RawQuery)
+				}
+			},
+			want: func(serverURL string) *domain.Issue {
+				return &domain.Issue{ID: "PROJ-123", Title: "Fix login redirect", URL: serverURL + "/browse/PROJ-123"}
+			},
+		},
+		{
+			name:     "github issues",
+			cfg:      config.IssueTrackerConfig{Type: config.IssueTrackerGitHub, Repository: "org/app"},
+			env:      map[string]string{"GH_TOKEN": "secret"},
+			id:       "GH-42",
+			path:     "/repos/org/app/issues/42",
+			response: `{"title": "Crash on startup", "html_url": "https://github.com/org/app/issues/42"}`,
+			checkRequest: func(t *testing.T, r *http.Request) {
+				t.Helper()
+
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+			},
+			want: func(string) *domain.Issue {
+				return &domain.Issue{ID: "org/app#42", Title: "Crash on startup", URL: "https://github.com/org/app/issues/42"}
+			},
+		},
+		{
+			name:     "linear",
+			cfg:      config.IssueTrackerConfig{Type: config.IssueTrackerLinear},
+			env:      map[string]string{"LINEAR_API_KEY": "lin_api_secret"},
+			id:       "ENG-7",
+			path:     "/",
+			response: `{"data": {"issue": {"identifier": "ENG-7", "title": "Add export", "url": "https://linear.app/acme/issue/ENG-7"}}}`,
+			checkRequest: func(t *testing.T, r *http.Request) {
+				t.Helper()
+
+				if got := r.Header.Get("Authorization"); got != "lin_api_secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+
+				var body struct {
+					Variables map[string]string `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Variables["id"] != "ENG-7" {
+					t.Errorf("unexpected GraphQL variables: %v (%v)", body.Variables, err)
+				}
+			},
+			want: func(string) *domain.Issue {
+				return &domain.Issue{ID: "ENG-7", Title: "Add export", URL: "https://linear.app/acme/issue/ENG-7"}
+			},
+		},
+		{
+			name:     "generic http",
+			cfg:      config.IssueTrackerConfig{Type: config.IssueTrackerHTTP, TokenEnv: "STUB_TOKEN"},
+			env:      map[string]string{"STUB_TOKEN": "secret"},
+			id:       "TASK-9",
+			path:     "/issues/TASK-9.json",
+			response: `{"title": "Rotate keys", "url": "https://tracker.example.com/TASK-9"}`,
+			checkRequest: func(t *testing.T, r *http.Request) {
+				t.Helper()
+
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+			},
+			want: func(string) *domain.Issue {
+				return &domain.Issue{ID: "TASK-9", Title: "Rotate keys", URL: "https://tracker.example.com/TASK-9"}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					http.NotFound(w, r)
+					return
+				}
+
+				tt.checkRequest(t, r)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			t.Cleanup(server.Close)
+
+			cfg := tt.cfg
+			cfg.URL = server.URL
+			if cfg.Type == config.IssueTrackerHTTP {
+				cfg.URL = server.URL + "/issues/{id}.json"
+			}
+
+			tracker := New(cfg, httpjson.WithHTTPClient(server.Client()), httpjson.WithGetenv(func(key string) string { return tt.env[key] }))
+
+			got, err := tracker.GetIssue(context.Background(), tt.id)
+			if err != nil {
+				t.Fatalf("GetIssue failed: %v", err)
+			}
+
+			if want := tt.want(server.URL); !reflect.DeepEqual(got, want) {
+				t.Errorf("GetIssue = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestTracker_Errors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages": ["Issue does not exist or you do not have permission to see it."]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	env := map[string]string{"JIRA_API_TOKEN": "secret"}
+	tracker := New(config.IssueTrackerConfig{Type: config.IssueTrackerJira, URL: server.URL},
+		httpjson.WithHTTPClient(server.Client()), httpjson.WithGetenv(func(key string) string { return env[key] }))
+
+	_, err := tracker.GetIssue(context.Background(), "PROJ-404")
+
+	var cerr *cerrors.CanopyError
+	if !errors.As(err, &cerr) || cerr.Code != cerrors.ErrIssueLookupFailed || cerr.Context["status"] != "404" {
+		t.Fatalf("expected issue lookup error with status 404, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "Issue does not exist") {
+		t.Errorf("expected Jira error message, got %v", err)
+	}
+
+	noToken := New(config.IssueTrackerConfig{Type: config.IssueTrackerLinear}, httpjson.WithGetenv(func(string) string { return "" }))
+	if _, err := noToken.GetIssue(context.Background(), "ENG-1"); err == nil || !strings.Contains(err.Error(), "LINEAR_API_KEY") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+
+	if New(config.IssueTrackerConfig{}) != nil {
+		t.Error("expected no tracker when none is configured")
+	}
+}
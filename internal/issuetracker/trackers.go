@@ -0,0 +1,200 @@
+package issuetracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// jira looks up issues with the Jira REST API.
+type jira struct {
+	api     apiClient
+	baseURL string
+	userEnv string
+}
+
+// GetIssue fetches an issue by key. With a user (Jira Cloud account email) the token is sent
+// with basic auth, otherwise as a bearer personal access token (Jira Data Center).
+func (j *jira) GetIssue(ctx context.Context, id string) (*domain.Issue, error) {
+	token := j.api.token()
+	if token == "" {
+		return nil, j.api.missingToken(id)
+	}
+
+	user := j.api.getenv(j.userEnv)
+	authorize := func(req *http.Request) {
+		if user != "" {
+			req.SetBasicAuth(user, token)
+			return
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	var out struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+		} `json:"fields"`
+	}
+
+	issueURL := j.baseURL + "/rest/api/2/issue/" + url.PathEscape(id) + "?fields=summary"
+	if err := j.api.do(ctx, id, http.MethodGet, issueURL, authorize, nil, &out); err != nil {
+		return nil, err
+	}
+
+	key := out.Key
+	if key == "" {
+		key = id
+	}
+
+	return &domain.Issue{ID: key, Title: out.Fields.Summary, URL: j.baseURL + "/browse/" + url.PathEscape(key)}, nil
+}
+
+// issueNumber matches the issue number at the end of a workspace ID such as GH-42 or 42.
+var issueNumber = regexp.MustCompile(`(\d+)$`)
+
+// gitHub looks up issues in one repository with the GitHub REST API.
+type gitHub struct {
+	api        apiClient
+	baseURL    string
+	repository string
+}
+
+// GetIssue fetches the issue whose number ends the ID. The token is optional for public repositories.
+func (g *gitHub) GetIssue(ctx context.Context, id string) (*domain.Issue, error) {
+	match := issueNumber.FindStringSubmatch(id)
+	if match == nil {
+		return nil, cerrors.NewInvalidArgument("workspace-id", fmt.Sprintf("%q does not end with a GitHub issue number", id))
+	}
+
+	authorize := func(req *http.Request) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+
+		if token := g.api.token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	var out struct {
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	}
+
+	owner, name, _ := strings.Cut(g.repository, "/")
+	issueURL := fmt.Sprintf("%s/repos/%s/%s/issues/%s", g.baseURL, url.PathEscape(owner), url.PathEscape(name), match[1])
+
+	if err := g.api.do(ctx, id, http.MethodGet, issueURL, authorize, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &domain.Issue{ID: g.repository + "#" + match[1], Title: out.Title, URL: out.HTMLURL}, nil
+}
+
+// linearIssueQuery fetches an issue by identifier (ENG-123) or UUID.
+const linearIssueQuery = `query Issue($id: String!) { issue(id: $id) { identifier title url } }`
+
+// linear looks up issues with the Linear GraphQL API.
+type linear struct {
+	api      apiClient
+	endpoint string
+}
+
+// GetIssue fetches an issue by identifier.
+func (l *linear) GetIssue(ctx context.Context, id string) (*domain.Issue, error) {
+	token := l.api.token()
+	if token == "" {
+		return nil, l.api.missingToken(id)
+	}
+
+	authorize := func(req *http.Request) {
+		// Personal API keys are sent as-is; OAuth tokens already carry their "Bearer" prefix
+		req.Header.Set("Authorization", token)
+	}
+
+	in := map[string]interface{}{
+		"query":     linearIssueQuery,
+		"variables": map[string]string{"id": id},
+	}
+
+	var out struct {
+		Data struct {
+			Issue *struct {
+				Identifier string `json:"identifier"`
+				Title      string `json:"title"`
+				URL        string `json:"url"`
+			} `json:"issue"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	if err := l.api.do(ctx, id, http.MethodPost, l.endpoint, authorize, in, &out); err != nil {
+		return nil, err
+	}
+
+	if len(out.Errors) > 0 || out.Data.Issue == nil {
+		message := "issue not found"
+		if len(out.Errors) > 0 {
+			message = out.Errors[0].Message
+		}
+
+		return nil, cerrors.NewIssueLookupFailed(config.IssueTrackerLinear, id, http.StatusOK, errors.New(message))
+	}
+
+	issue := out.Data.Issue
+
+	return &domain.Issue{ID: issue.Identifier, Title: issue.Title, URL: issue.URL}, nil
+}
+
+// httpTracker looks up issues from any endpoint answering GET requests with
+// {"title": "...", "url": "..."}.
+type httpTracker struct {
+	api apiClient
+	// endpoint is the issue URL; "{id}" is replaced with the issue ID, which is
+	// otherwise appended as the last path segment.
+	endpoint string
+}
+
+// GetIssue fetches an issue from the endpoint. The token is optional and sent as a bearer token.
+func (h *httpTracker) GetIssue(ctx context.Context, id string) (*domain.Issue, error) {
+	issueURL := strings.TrimSuffix(h.endpoint, "/") + "/" + url.PathEscape(id)
+	if strings.Contains(h.endpoint, "{id}") {
+		issueURL = strings.ReplaceAll(h.endpoint, "{id}", url.PathEscape(id))
+	}
+
+	authorize := func(req *http.Request) {
+		if token := h.api.token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	var out struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	}
+
+	if err := h.api.do(ctx, id, http.MethodGet, issueURL, authorize, nil, &out); err != nil {
+		return nil, err
+	}
+
+	if out.Title == "" {
+		return nil, cerrors.NewIssueLookupFailed(config.IssueTrackerHTTP, id, http.StatusOK, errors.New(`response has no "title"`))
+	}
+
+	if out.ID == "" {
+		out.ID = id
+	}
+
+	return &domain.Issue{ID: out.ID, Title: out.Title, URL: out.URL}, nil
+}
@@ -47,6 +47,12 @@ type MockConfigProvider struct {
 	Hooks              config.Hooks
 	Templates          map[string]config.Template
 	RepoNames          []string
+	Forges             []config.ForgeConfig
+	IssueTracker       config.IssueTrackerConfig
+	GC                 config.GCConfig
+	ClosedRetention    config.ClosedRetentionConfig
+	BranchNaming       string
+	BranchVars         map[string]string
 }
 
 // NewMockConfigProvider creates a new MockConfigProvider with sensible defaults.
@@ -245,3 +251,49 @@ func (m *MockConfigProvider) GetGitRetryConfig() config.ParsedRetryConfig {
 		JitterFactor: 0.25,
 	}
 }
+
+// GetForges returns the configured Forges.
+func (m *MockConfigProvider) GetForges() []config.ForgeConfig {
+	return m.Forges
+}
+
+// GetIssueTracker returns the configured IssueTracker.
+func (m *MockConfigProvider) GetIssueTracker() config.IssueTrackerConfig {
+	return m.IssueTracker
+}
+
+// GetGC returns the configured GC policy.
+func (m *MockConfigProvider) GetGC() config.GCConfig {
+	return m.GC
+}
+
+// GetDaemonConfig returns the default background fetch configuration.
+func (m *MockConfigProvider) GetDaemonConfig() config.ParsedDaemonConfig {
+	return config.ParsedDaemonConfig{FetchInterval: config.DefaultFetchInterval, FetchJitter: config.DefaultFetchJitter}
+}
+
+// GetClosedRetention returns the configured ClosedRetention limits.
+func (m *MockConfigProvider) GetClosedRetention() config.ClosedRetentionConfig {
+	return m.ClosedRetention
+}
+
+// GetBranchNaming returns the configured BranchNaming for every workspace ID.
+func (m *MockConfigProvider) GetBranchNaming(_ string) (field, pattern string) {
+	return "branch_naming", m.BranchNaming
+}
+
+// GetBranchVars returns BranchVars with the template's variables merged over them.
+func (m *MockConfigProvider) GetBranchVars(tmpl *config.Template) map[string]string {
+	vars := make(map[string]string, len(m.BranchVars))
+	for k, v := range m.BranchVars {
+		vars[k] = v
+	}
+
+	if tmpl != nil {
+		for k, v := range tmpl.BranchVars {
+			vars[k] = v
+		}
+	}
+
+	return vars
+}
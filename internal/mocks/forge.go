@@ -0,0 +1,67 @@
+// Package mocks provides mock implementations for testing.
+package mocks
+
+import (
+	"context"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// Compile-time checks that the forge mocks implement their ports.
+var (
+	_ ports.Forge         = (*MockForge)(nil)
+	_ ports.ForgeResolver = (*MockForgeResolver)(nil)
+)
+
+// MockForge is a mock implementation of ports.Forge for testing.
+type MockForge struct {
+	CreatePullRequestFunc     func(ctx context.Context, owner, name string, spec ports.PullRequestSpec) (*domain.PullRequest, error)
+	UpdatePullRequestBodyFunc func(ctx context.Context, owner, name string, number int, body string) error
+	ReviewStatusFunc          func(ctx context.Context, owner, name, head string, headRepo giturl.Remote) (*domain.ReviewStatus, error)
+}
+
+// CreatePullRequest calls the mock function if set, otherwise returns pull request number 1.
+func (m *MockForge) CreatePullRequest(ctx context.Context, owner, name string, spec ports.PullRequestSpec) (*domain.PullRequest, error) {
+	if m.CreatePullRequestFunc != nil {
+		return m.CreatePullRequestFunc(ctx, owner, name, spec)
+	}
+
+	return &domain.PullRequest{Number: 1, Base: spec.Base, Head: spec.Head}, nil
+}
+
+// UpdatePullRequestBody calls the mock function if set, otherwise returns nil.
+func (m *MockForge) UpdatePullRequestBody(ctx context.Context, owner, name string, number int, body string) error {
+	if m.UpdatePullRequestBodyFunc != nil {
+		return m.UpdatePullRequestBodyFunc(ctx, owner, name, number, body)
+	}
+
+	return nil
+}
+
+// ReviewStatus calls the mock function if set, otherwise returns nil (no pull request).
+func (m *MockForge) ReviewStatus(ctx context.Context, owner, name, head string, headRepo giturl.Remote) (*domain.ReviewStatus, error) {
+	if m.ReviewStatusFunc != nil {
+		return m.ReviewStatusFunc(ctx, owner, name, head, headRepo)
+	}
+
+	return nil, nil
+}
+
+// MockForgeResolver is a mock implementation of ports.ForgeResolver for testing.
+type MockForgeResolver struct {
+	ForgeForFunc func(host string) (ports.Forge, error)
+
+	// Forge is returned for every host when ForgeForFunc is not set.
+	Forge ports.Forge
+}
+
+// ForgeFor calls the mock function if set, otherwise returns Forge.
+func (m *MockForgeResolver) ForgeFor(host string) (ports.Forge, error) {
+	if m.ForgeForFunc != nil {
+		return m.ForgeForFunc(host)
+	}
+
+	return m.Forge, nil
+}
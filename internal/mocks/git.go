@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-git/go-git/v5"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	"github.com/alexisbeaulieu97/canopy/internal/ports"
 )
 
@@ -15,22 +16,39 @@ var _ ports.GitOperations = (*MockGitOperations)(nil)
 
 // MockGitOperations is a mock implementation of ports.GitOperations for testing.
 type MockGitOperations struct {
-	EnsureCanonicalFunc func(ctx context.Context, repoURL, repoName string) (*git.Repository, error)
-	CreateWorktreeFunc  func(ctx context.Context, repoName, worktreePath, branchName string) error
-	StatusFunc          func(ctx context.Context, path string) (bool, int, int, string, error)
-	CloneFunc           func(ctx context.Context, url, name string) error
-	FetchFunc           func(ctx context.Context, name string) error
-	PullFunc            func(ctx context.Context, path string) error
-	PushFunc            func(ctx context.Context, path, branch string) error
-	ListFunc            func(ctx context.Context) ([]string, error)
-	CheckoutFunc        func(ctx context.Context, path, branchName string, create bool) error
-	RenameBranchFunc    func(ctx context.Context, repoPath, oldName, newName string) error
-	RunCommandFunc      func(ctx context.Context, repoPath string, args ...string) (*ports.CommandResult, error)
-	GetUpstreamURLFunc  func(repoName string) (string, error)
-	RemoveWorktreeFunc  func(ctx context.Context, repoName, worktreePath string) error
-	PruneWorktreesFunc  func(ctx context.Context, repoName string) error
-	LastFetchTimeFunc   func(repoName string) (*time.Time, error)
-	GetRepoSizeFunc     func(repoName string) (int64, error)
+	EnsureCanonicalFunc     func(ctx context.Context, repoURL, repoName string, opts domain.CloneOptions) (*git.Repository, error)
+	CreateWorktreeFunc      func(ctx context.Context, repoName, worktreePath, branchName string, sparsePaths []string) error
+	SetSparsePathsFunc      func(ctx context.Context, path string, paths []string) error
+	SetRemotesFunc          func(ctx context.Context, path, branch string, remotes []domain.Remote, pushRemote string) error
+	StatusFunc              func(ctx context.Context, path string) (bool, int, int, string, error)
+	CloneFunc               func(ctx context.Context, url, name string, opts domain.CloneOptions) error
+	DeepenFunc              func(ctx context.Context, repoName string, opts ports.DeepenOptions) error
+	FetchFunc               func(ctx context.Context, name string) error
+	PullFunc                func(ctx context.Context, path string, opts ports.PullOptions) (*ports.PullResult, error)
+	CommitsBehindFunc       func(ctx context.Context, path, ref string) (int, error)
+	BranchMergeStateFunc    func(ctx context.Context, path, branch string, opts ports.BranchMergeOptions) (*ports.BranchMergeState, error)
+	DefaultBranchFunc       func(repoName, remote string) (string, error)
+	UserNameFunc            func() (string, error)
+	PushFunc                func(ctx context.Context, path, branch string, opts ports.PushOptions) error
+	PushPreflightFunc       func(ctx context.Context, path, branch string, opts ports.PushOptions) (*ports.PushPreflight, error)
+	RestoreRemoteBranchFunc func(ctx context.Context, path string, opts ports.PushOptions, branch, remoteHead, pushedHead string) error
+	ListFunc                func(ctx context.Context) ([]string, error)
+	CheckoutFunc            func(ctx context.Context, path, branchName string, create bool) error
+	RenameBranchFunc        func(ctx context.Context, repoPath, oldName, newName string) error
+	RunCommandFunc          func(ctx context.Context, repoPath string, args ...string) (*ports.CommandResult, error)
+	GetUpstreamURLFunc      func(repoName string) (string, error)
+	RemoveWorktreeFunc      func(ctx context.Context, repoName, worktreePath string) error
+	PruneWorktreesFunc      func(ctx context.Context, repoName string) error
+	LastFetchTimeFunc       func(repoName string) (*time.Time, error)
+	GetRepoSizeFunc         func(repoName string) (int64, error)
+	GetCloneModeFunc        func(repoName string) (domain.CloneMode, error)
+	MaintainFunc            func(ctx context.Context, repoName string, tasks []string) error
+	LastMaintenanceTimeFunc func(repoName string) (*time.Time, error)
+	CreatePatchFunc         func(ctx context.Context, path, patchPath string) (bool, error)
+	CreateBundleFunc        func(ctx context.Context, path, bundlePath string) (int, error)
+	ApplyBundleFunc         func(ctx context.Context, path, bundlePath string) error
+	ApplyPatchFunc          func(ctx context.Context, path, patchPath string) error
+	SnapshotWorktreeFunc    func(ctx context.Context, path, message string) (string, error)
 }
 
 // NewMockGitOperations creates a new MockGitOperations with default no-op behavior.
@@ -39,18 +57,36 @@ func NewMockGitOperations() *MockGitOperations {
 }
 
 // EnsureCanonical calls the mock function if set, otherwise returns nil.
-func (m *MockGitOperations) EnsureCanonical(ctx context.Context, repoURL, repoName string) (*git.Repository, error) {
+func (m *MockGitOperations) EnsureCanonical(ctx context.Context, repoURL, repoName string, opts domain.CloneOptions) (*git.Repository, error) {
 	if m.EnsureCanonicalFunc != nil {
-		return m.EnsureCanonicalFunc(ctx, repoURL, repoName)
+		return m.EnsureCanonicalFunc(ctx, repoURL, repoName, opts)
 	}
 
 	return nil, nil
 }
 
 // CreateWorktree calls the mock function if set, otherwise returns nil.
-func (m *MockGitOperations) CreateWorktree(ctx context.Context, repoName, worktreePath, branchName string) error {
+func (m *MockGitOperations) CreateWorktree(ctx context.Context, repoName, worktreePath, branchName string, sparsePaths []string) error {
 	if m.CreateWorktreeFunc != nil {
-		return m.CreateWorktreeFunc(ctx, repoName, worktreePath, branchName)
+		return m.CreateWorktreeFunc(ctx, repoName, worktreePath, branchName, sparsePaths)
+	}
+
+	return nil
+}
+
+// SetSparsePaths calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) SetSparsePaths(ctx context.Context, path string, paths []string) error {
+	if m.SetSparsePathsFunc != nil {
+		return m.SetSparsePathsFunc(ctx, path, paths)
+	}
+
+	return nil
+}
+
+// SetRemotes calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) SetRemotes(ctx context.Context, path, branch string, remotes []domain.Remote, pushRemote string) error {
+	if m.SetRemotesFunc != nil {
+		return m.SetRemotesFunc(ctx, path, branch, remotes, pushRemote)
 	}
 
 	return nil
@@ -66,9 +102,18 @@ func (m *MockGitOperations) Status(ctx context.Context, path string) (bool, int,
 }
 
 // Clone calls the mock function if set, otherwise returns nil.
-func (m *MockGitOperations) Clone(ctx context.Context, url, name string) error {
+func (m *MockGitOperations) Clone(ctx context.Context, url, name string, opts domain.CloneOptions) error {
 	if m.CloneFunc != nil {
-		return m.CloneFunc(ctx, url, name)
+		return m.CloneFunc(ctx, url, name, opts)
+	}
+
+	return nil
+}
+
+// Deepen calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) Deepen(ctx context.Context, repoName string, opts ports.DeepenOptions) error {
+	if m.DeepenFunc != nil {
+		return m.DeepenFunc(ctx, repoName, opts)
 	}
 
 	return nil
@@ -83,19 +128,73 @@ func (m *MockGitOperations) Fetch(ctx context.Context, name string) error {
 	return nil
 }
 
-// Pull calls the mock function if set, otherwise returns nil.
-func (m *MockGitOperations) Pull(ctx context.Context, path string) error {
+// Pull calls the mock function if set, otherwise returns an empty result.
+func (m *MockGitOperations) Pull(ctx context.Context, path string, opts ports.PullOptions) (*ports.PullResult, error) {
 	if m.PullFunc != nil {
-		return m.PullFunc(ctx, path)
+		return m.PullFunc(ctx, path, opts)
 	}
 
-	return nil
+	return &ports.PullResult{}, nil
+}
+
+// CommitsBehind calls the mock function if set, otherwise returns 0.
+func (m *MockGitOperations) CommitsBehind(ctx context.Context, path, ref string) (int, error) {
+	if m.CommitsBehindFunc != nil {
+		return m.CommitsBehindFunc(ctx, path, ref)
+	}
+
+	return 0, nil
+}
+
+// BranchMergeState calls the mock function if set, otherwise reports an unpublished, unmerged branch.
+func (m *MockGitOperations) BranchMergeState(ctx context.Context, path, branch string, opts ports.BranchMergeOptions) (*ports.BranchMergeState, error) {
+	if m.BranchMergeStateFunc != nil {
+		return m.BranchMergeStateFunc(ctx, path, branch, opts)
+	}
+
+	return &ports.BranchMergeState{}, nil
+}
+
+// DefaultBranch calls the mock function if set, otherwise returns "main".
+func (m *MockGitOperations) DefaultBranch(repoName, remote string) (string, error) {
+	if m.DefaultBranchFunc != nil {
+		return m.DefaultBranchFunc(repoName, remote)
+	}
+
+	return "main", nil
+}
+
+// UserName calls the mock function if set, otherwise returns "Test User".
+func (m *MockGitOperations) UserName() (string, error) {
+	if m.UserNameFunc != nil {
+		return m.UserNameFunc()
+	}
+
+	return "Test User", nil
 }
 
 // Push calls the mock function if set, otherwise returns nil.
-func (m *MockGitOperations) Push(ctx context.Context, path, branch string) error {
+func (m *MockGitOperations) Push(ctx context.Context, path, branch string, opts ports.PushOptions) error {
 	if m.PushFunc != nil {
-		return m.PushFunc(ctx, path, branch)
+		return m.PushFunc(ctx, path, branch, opts)
+	}
+
+	return nil
+}
+
+// PushPreflight calls the mock function if set, otherwise reports an empty preflight.
+func (m *MockGitOperations) PushPreflight(ctx context.Context, path, branch string, opts ports.PushOptions) (*ports.PushPreflight, error) {
+	if m.PushPreflightFunc != nil {
+		return m.PushPreflightFunc(ctx, path, branch, opts)
+	}
+
+	return &ports.PushPreflight{}, nil
+}
+
+// RestoreRemoteBranch calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) RestoreRemoteBranch(ctx context.Context, path string, opts ports.PushOptions, branch, remoteHead, pushedHead string) error {
+	if m.RestoreRemoteBranchFunc != nil {
+		return m.RestoreRemoteBranchFunc(ctx, path, opts, branch, remoteHead, pushedHead)
 	}
 
 	return nil
@@ -181,3 +280,75 @@ func (m *MockGitOperations) GetRepoSize(repoName string) (int64, error) {
 
 	return 0, nil
 }
+
+// GetCloneMode calls the mock function if set, otherwise reports a full clone.
+func (m *MockGitOperations) GetCloneMode(repoName string) (domain.CloneMode, error) {
+	if m.GetCloneModeFunc != nil {
+		return m.GetCloneModeFunc(repoName)
+	}
+
+	return domain.CloneMode{}, nil
+}
+
+// Maintain calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) Maintain(ctx context.Context, repoName string, tasks []string) error {
+	if m.MaintainFunc != nil {
+		return m.MaintainFunc(ctx, repoName, tasks)
+	}
+
+	return nil
+}
+
+// LastMaintenanceTime calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) LastMaintenanceTime(repoName string) (*time.Time, error) {
+	if m.LastMaintenanceTimeFunc != nil {
+		return m.LastMaintenanceTimeFunc(repoName)
+	}
+
+	return nil, nil
+}
+
+// CreatePatch calls the mock function if set, otherwise returns false.
+func (m *MockGitOperations) CreatePatch(ctx context.Context, path, patchPath string) (bool, error) {
+	if m.CreatePatchFunc != nil {
+		return m.CreatePatchFunc(ctx, path, patchPath)
+	}
+
+	return false, nil
+}
+
+// CreateBundle calls the mock function if set, otherwise returns 0.
+func (m *MockGitOperations) CreateBundle(ctx context.Context, path, bundlePath string) (int, error) {
+	if m.CreateBundleFunc != nil {
+		return m.CreateBundleFunc(ctx, path, bundlePath)
+	}
+
+	return 0, nil
+}
+
+// ApplyBundle calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) ApplyBundle(ctx context.Context, path, bundlePath string) error {
+	if m.ApplyBundleFunc != nil {
+		return m.ApplyBundleFunc(ctx, path, bundlePath)
+	}
+
+	return nil
+}
+
+// ApplyPatch calls the mock function if set, otherwise returns nil.
+func (m *MockGitOperations) ApplyPatch(ctx context.Context, path, patchPath string) error {
+	if m.ApplyPatchFunc != nil {
+		return m.ApplyPatchFunc(ctx, path, patchPath)
+	}
+
+	return nil
+}
+
+// SnapshotWorktree calls the mock function if set, otherwise returns "".
+func (m *MockGitOperations) SnapshotWorktree(ctx context.Context, path, message string) (string, error) {
+	if m.SnapshotWorktreeFunc != nil {
+		return m.SnapshotWorktreeFunc(ctx, path, message)
+	}
+
+	return "", nil
+}
@@ -13,7 +13,7 @@ var _ ports.HookExecutor = (*MockHookExecutor)(nil)
 // MockHookExecutor is a mock implementation of ports.HookExecutor for testing.
 type MockHookExecutor struct {
 	// ExecuteHooksFunc is called when ExecuteHooks is invoked.
-	ExecuteHooksFunc func(hks []config.Hook, ctx domain.HookContext, opts ports.HookExecuteOptions) ([]domain.HookCommandPreview, error)
+	ExecuteHooksFunc func(hks []config.Hook, ctx domain.HookContext, opts ports.HookExecuteOptions) ([]domain.HookResult, error)
 
 	// ExecuteHooksCalls records all calls to ExecuteHooks for verification.
 	ExecuteHooksCalls []ExecuteHooksCall
@@ -21,8 +21,8 @@ type MockHookExecutor struct {
 	// ExecuteHooksErr is the error to return if ExecuteHooksFunc is not set.
 	ExecuteHooksErr error
 
-	// ExecuteHooksPreviews is returned when ExecuteHooksFunc is not set.
-	ExecuteHooksPreviews []domain.HookCommandPreview
+	// ExecuteHooksResults is returned when ExecuteHooksFunc is not set.
+	ExecuteHooksResults []domain.HookResult
 }
 
 // ExecuteHooksCall records a single call to ExecuteHooks.
@@ -44,7 +44,7 @@ func (m *MockHookExecutor) ExecuteHooks(
 	hks []config.Hook,
 	ctx domain.HookContext,
 	opts ports.HookExecuteOptions,
-) ([]domain.HookCommandPreview, error) {
+) ([]domain.HookResult, error) {
 	m.ExecuteHooksCalls = append(m.ExecuteHooksCalls, ExecuteHooksCall{
 		Hooks:   hks,
 		Ctx:     ctx,
@@ -55,7 +55,7 @@ func (m *MockHookExecutor) ExecuteHooks(
 		return m.ExecuteHooksFunc(hks, ctx, opts)
 	}
 
-	return m.ExecuteHooksPreviews, m.ExecuteHooksErr
+	return m.ExecuteHooksResults, m.ExecuteHooksErr
 }
 
 // ResetCalls clears the recorded calls.
@@ -0,0 +1,26 @@
+// Package mocks provides mock implementations for testing.
+package mocks
+
+import (
+	"context"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// Compile-time check that MockIssueTracker implements ports.IssueTracker.
+var _ ports.IssueTracker = (*MockIssueTracker)(nil)
+
+// MockIssueTracker is a mock implementation of ports.IssueTracker for testing.
+type MockIssueTracker struct {
+	GetIssueFunc func(ctx context.Context, id string) (*domain.Issue, error)
+}
+
+// GetIssue calls the mock function if set, otherwise returns an issue titled after its ID.
+func (m *MockIssueTracker) GetIssue(ctx context.Context, id string) (*domain.Issue, error) {
+	if m.GetIssueFunc != nil {
+		return m.GetIssueFunc(ctx, id)
+	}
+
+	return &domain.Issue{ID: id, Title: "Issue " + id}, nil
+}
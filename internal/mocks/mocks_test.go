@@ -53,7 +53,7 @@ func TestMockHookExecutor(t *testing.T) {
 
 		called := false
 		mock := mocks.NewMockHookExecutor()
-		mock.ExecuteHooksFunc = func(_ []config.Hook, _ domain.HookContext, _ ports.HookExecuteOptions) ([]domain.HookCommandPreview, error) {
+		mock.ExecuteHooksFunc = func(_ []config.Hook, _ domain.HookContext, _ ports.HookExecuteOptions) ([]domain.HookResult, error) {
 			called = true
 			return nil, nil
 		}
@@ -19,12 +19,14 @@ type MockWorkspaceStorage struct {
 	CreateFunc       func(ctx context.Context, ws domain.Workspace) error
 	SaveFunc         func(ctx context.Context, ws domain.Workspace) error
 	CloseFunc        func(ctx context.Context, id string, closedAt time.Time) (*domain.ClosedWorkspace, error)
+	SaveClosedFunc   func(ctx context.Context, ws domain.Workspace) error
 	ListFunc         func(ctx context.Context) ([]domain.Workspace, error)
 	ListClosedFunc   func(ctx context.Context) ([]domain.ClosedWorkspace, error)
 	LoadFunc         func(ctx context.Context, id string) (*domain.Workspace, error)
 	DeleteFunc       func(ctx context.Context, id string) error
 	RenameFunc       func(ctx context.Context, oldID, newID string) error
 	LatestClosedFunc func(ctx context.Context, id string) (*domain.ClosedWorkspace, error)
+	LoadClosedFunc   func(ctx context.Context, id string, closedAt time.Time) (*domain.ClosedWorkspace, error)
 	DeleteClosedFunc func(ctx context.Context, id string, closedAt time.Time) error
 
 	// Workspaces holds test workspace data keyed by ID.
@@ -81,6 +83,15 @@ func (m *MockWorkspaceStorage) Close(ctx context.Context, id string, closedAt ti
 	}, nil
 }
 
+// SaveClosed calls the mock function if set, otherwise returns nil.
+func (m *MockWorkspaceStorage) SaveClosed(ctx context.Context, ws domain.Workspace) error {
+	if m.SaveClosedFunc != nil {
+		return m.SaveClosedFunc(ctx, ws)
+	}
+
+	return nil
+}
+
 // List calls the mock function if set, otherwise returns Workspaces as slice.
 func (m *MockWorkspaceStorage) List(ctx context.Context) ([]domain.Workspace, error) {
 	if m.ListFunc != nil {
@@ -161,6 +172,15 @@ func (m *MockWorkspaceStorage) LatestClosed(ctx context.Context, id string) (*do
 	return nil, nil
 }
 
+// LoadClosed calls the mock function if set, otherwise returns nil.
+func (m *MockWorkspaceStorage) LoadClosed(ctx context.Context, id string, closedAt time.Time) (*domain.ClosedWorkspace, error) {
+	if m.LoadClosedFunc != nil {
+		return m.LoadClosedFunc(ctx, id, closedAt)
+	}
+
+	return nil, nil
+}
+
 // DeleteClosed calls the mock function if set, otherwise returns nil.
 func (m *MockWorkspaceStorage) DeleteClosed(ctx context.Context, id string, closedAt time.Time) error {
 	if m.DeleteClosedFunc != nil {
@@ -169,3 +189,30 @@ func (m *MockWorkspaceStorage) DeleteClosed(ctx context.Context, id string, clos
 
 	return nil
 }
+
+// Compile-time check that MockFetchStatusStore implements ports.FetchStatusStore.
+var _ ports.FetchStatusStore = (*MockFetchStatusStore)(nil)
+
+// MockFetchStatusStore is an in-memory implementation of ports.FetchStatusStore for testing.
+type MockFetchStatusStore struct {
+	SaveFunc func(run domain.FetchRun) error
+
+	// Run holds the last saved fetch run.
+	Run *domain.FetchRun
+}
+
+// Load returns the last saved fetch run.
+func (m *MockFetchStatusStore) Load() (*domain.FetchRun, error) {
+	return m.Run, nil
+}
+
+// Save calls the mock function if set, otherwise stores the run.
+func (m *MockFetchStatusStore) Save(run domain.FetchRun) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(run)
+	}
+
+	m.Run = &run
+
+	return nil
+}
@@ -9,6 +9,7 @@ const (
 	SeparatorChar       = "─"
 	RepoNameWidth       = 20
 	RepoSizeWidth       = 10
+	RepoCloneModeWidth  = 16
 	RepoLastFetchWidth  = 20
 	RepoWorkspacesWidth = 10
 	RepoAliasWidth      = 16
@@ -60,6 +60,29 @@ type ConfigProvider interface {
 	// GetGitRetryConfig returns the parsed git retry configuration.
 	GetGitRetryConfig() config.ParsedRetryConfig
 
+	// GetForges returns the configured forges.
+	GetForges() []config.ForgeConfig
+
+	// GetIssueTracker returns the issue tracker configuration.
+	GetIssueTracker() config.IssueTrackerConfig
+
+	// GetGC returns the garbage collection policy.
+	GetGC() config.GCConfig
+
+	// GetClosedRetention returns the closed entry retention limits.
+	GetClosedRetention() config.ClosedRetentionConfig
+
+	// GetDaemonConfig returns the parsed background fetch configuration.
+	GetDaemonConfig() config.ParsedDaemonConfig
+
+	// GetBranchNaming returns the branch naming template for a workspace ID, and the name of
+	// the setting it came from. The template is empty when branches are named after the ID.
+	GetBranchNaming(workspaceID string) (field, pattern string)
+
+	// GetBranchVars returns the custom branch naming variables, with the branch_vars of tmpl
+	// (which may be nil) merged over the global ones.
+	GetBranchVars(tmpl *config.Template) map[string]string
+
 	// GetTemplates returns configured workspace templates as a defensive copy.
 	// Callers should not mutate the returned map or template values.
 	GetTemplates() map[string]config.Template
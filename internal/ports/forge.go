@@ -0,0 +1,44 @@
+// Package ports defines interfaces for external dependencies (hexagonal architecture).
+package ports
+
+import (
+	"context"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+)
+
+// PullRequestSpec describes a pull request to open.
+type PullRequestSpec struct {
+	Title string
+	Body  string
+	// Head is the branch with the changes.
+	Head string
+	// HeadRepo is the fork holding Head. Its zero value means the repository the pull request
+	// is opened against.
+	HeadRepo giturl.Remote
+	// Base is the branch the changes should be merged into.
+	Base  string
+	Draft bool
+}
+
+// Forge defines the interface for a code hosting service's API (GitHub, GitLab, Gitea).
+// Repositories are identified by their owner (user, organization or group path) and name.
+type Forge interface {
+	// CreatePullRequest opens a pull request and returns its number and URL.
+	CreatePullRequest(ctx context.Context, owner, name string, spec PullRequestSpec) (*domain.PullRequest, error)
+
+	// UpdatePullRequestBody replaces the description of an existing pull request.
+	UpdatePullRequestBody(ctx context.Context, owner, name string, number int, body string) error
+
+	// ReviewStatus returns the state, review decision and CI checks of the most recent pull
+	// request opened from head in headRepo, or nil if there is none. A zero headRepo means
+	// the repository itself.
+	ReviewStatus(ctx context.Context, owner, name, head string, headRepo giturl.Remote) (*domain.ReviewStatus, error)
+}
+
+// ForgeResolver finds the forge serving a git host.
+type ForgeResolver interface {
+	// ForgeFor returns the forge for a host such as github.com.
+	ForgeFor(host string) (Forge, error)
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 )
 
 // CommandResult holds the output and exit code from a git command execution.
@@ -15,28 +17,142 @@ type CommandResult struct {
 	ExitCode int
 }
 
+// PullOptions configures how a worktree integrates upstream changes.
+type PullOptions struct {
+	// Strategy selects fast-forward, rebase, or merge integration. Empty means ff-only.
+	Strategy domain.SyncStrategy
+	// Autostash stashes local changes before integrating and reapplies them afterwards.
+	Autostash bool
+	// Upstream integrates a local ref (e.g. origin/main) instead of pulling from Remote.
+	Upstream string
+	// Remote is the remote a pull fetches from. Empty means origin.
+	Remote string
+}
+
+// PullResult describes the outcome of a pull.
+type PullResult struct {
+	// Conflicts lists files left unmerged when the pull stopped on conflicts.
+	Conflicts []string
+}
+
+// PushOptions configures where and how a branch is pushed.
+type PushOptions struct {
+	// Remote is the remote to push to. Empty means origin.
+	Remote string
+	// RemoteBranch is the name of the branch on the remote, which also becomes the upstream.
+	// Empty means the local branch name.
+	RemoteBranch string
+	// ForceWithLease allows a non-fast-forward push as long as the remote branch still points
+	// at the commit of its remote-tracking ref.
+	ForceWithLease bool
+	// Options are push options sent to the server, as with "git push -o".
+	Options []string
+}
+
+// PushPreflight describes a branch that passed a dry-run push.
+type PushPreflight struct {
+	// LocalHead is the commit that will be pushed.
+	LocalHead string
+	// RemoteHead is the commit the remote branch points to now, empty when it does not exist.
+	RemoteHead string
+}
+
+// BranchMergeState describes whether a worktree branch has landed upstream.
+type BranchMergeState struct {
+	// Published reports whether <push remote>/<branch> exists locally, i.e. the branch was pushed.
+	Published bool
+	// OwnCommits reports whether HEAD moved past the commit the branch was created from.
+	OwnCommits bool
+	// Merged reports whether the branch has commits of its own and HEAD is reachable from
+	// <base remote>/<base>.
+	Merged bool
+	// RemoteDeleted reports whether a published branch no longer exists on the push remote.
+	// It is only checked on request, since it needs network access.
+	RemoteDeleted bool
+}
+
+// BranchMergeOptions selects what BranchMergeState checks a worktree branch against.
+type BranchMergeOptions struct {
+	// Base is the branch the worktree branch is merged into.
+	Base string
+	// BaseRemote is the remote Base is read from. Empty means origin.
+	BaseRemote string
+	// PushRemote is the remote the branch is published to. Empty means origin.
+	PushRemote string
+	// CheckRemote also asks PushRemote whether the branch was deleted, which needs network access.
+	CheckRemote bool
+}
+
+// DeepenOptions selects what a partial, shallow or single-branch canonical repository fetches.
+type DeepenOptions struct {
+	// Depth deepens a shallow history by this many commits. Zero fetches the full history.
+	Depth int
+	// AllBranches widens a single-branch clone to every branch.
+	AllBranches bool
+	// Blobs fetches every object left out by the partial clone filter and drops the filter.
+	Blobs bool
+}
+
 // GitOperations defines the interface for git operations.
 type GitOperations interface {
-	// EnsureCanonical ensures the repo is cloned in ProjectsRoot (bare).
-	EnsureCanonical(ctx context.Context, repoURL, repoName string) (*git.Repository, error)
+	// EnsureCanonical ensures the repo is cloned in ProjectsRoot (bare), cloning it with opts
+	// when it is missing.
+	EnsureCanonical(ctx context.Context, repoURL, repoName string, opts domain.CloneOptions) (*git.Repository, error)
+
+	// CreateWorktree creates a worktree for a workspace branch. When sparsePaths is not empty,
+	// only those directories (and files at the root) are checked out, using cone-mode sparse checkout.
+	CreateWorktree(ctx context.Context, repoName, worktreePath, branchName string, sparsePaths []string) error
 
-	// CreateWorktree creates a worktree for a workspace branch.
-	CreateWorktree(ctx context.Context, repoName, worktreePath, branchName string) error
+	// SetSparsePaths changes the directories checked out in a worktree, or checks out the full
+	// tree when paths is empty.
+	SetSparsePaths(ctx context.Context, path string, paths []string) error
+
+	// SetRemotes adds or updates extra remotes of a worktree and, when pushRemote is set,
+	// makes branch push to it while it keeps pulling from origin.
+	SetRemotes(ctx context.Context, path, branch string, remotes []domain.Remote, pushRemote string) error
 
 	// Status returns isDirty, unpushedCommits, behindRemote, branchName, error.
 	Status(ctx context.Context, path string) (isDirty bool, unpushed, behind int, branch string, err error)
 
-	// Clone clones a repository to the projects root (bare).
-	Clone(ctx context.Context, url, name string) error
+	// Clone clones a repository to the projects root (bare). Partial, shallow and
+	// single-branch clones are described by opts.
+	Clone(ctx context.Context, url, name string, opts domain.CloneOptions) error
+
+	// Deepen fetches history, branches or objects that a partial, shallow or single-branch
+	// canonical repository left out.
+	Deepen(ctx context.Context, repoName string, opts DeepenOptions) error
 
 	// Fetch fetches updates for a canonical repository.
 	Fetch(ctx context.Context, name string) error
 
-	// Pull pulls updates for a repository worktree.
-	Pull(ctx context.Context, path string) error
+	// Pull pulls updates for a repository worktree using the given strategy.
+	// When the pull stops on conflicts it is aborted and the conflicting files are reported in the result.
+	Pull(ctx context.Context, path string, opts PullOptions) (*PullResult, error)
+
+	// CommitsBehind returns the number of commits reachable from ref that are not in HEAD.
+	CommitsBehind(ctx context.Context, path, ref string) (int, error)
+
+	// BranchMergeState reports whether the worktree's branch was pushed, merged into the base
+	// branch and, on request, deleted from its push remote.
+	BranchMergeState(ctx context.Context, path, branch string, opts BranchMergeOptions) (*BranchMergeState, error)
+
+	// DefaultBranch returns the default branch of a canonical repository's remote (origin
+	// when empty).
+	DefaultBranch(repoName, remote string) (string, error)
+
+	// UserName returns user.name from the git config, or an empty string when it is not set.
+	UserName() (string, error)
+
+	// Push pushes a branch and sets it up to track the pushed remote branch.
+	Push(ctx context.Context, path, branch string, opts PushOptions) error
+
+	// PushPreflight checks with a dry-run push that a branch can be pushed with the given options
+	// without being rejected.
+	PushPreflight(ctx context.Context, path, branch string, opts PushOptions) (*PushPreflight, error)
 
-	// Push pushes the current branch to its upstream.
-	Push(ctx context.Context, path, branch string) error
+	// RestoreRemoteBranch puts a branch pushed with opts back to remoteHead, deleting it when
+	// remoteHead is empty. The update only applies while the remote still points at pushedHead.
+	RestoreRemoteBranch(ctx context.Context, path string, opts PushOptions, branch, remoteHead, pushedHead string) error
 
 	// List returns a list of repository names in the projects root.
 	List(ctx context.Context) ([]string, error)
@@ -64,4 +180,33 @@ type GitOperations interface {
 
 	// GetRepoSize returns the disk usage of the canonical repository in bytes.
 	GetRepoSize(repoName string) (int64, error)
+
+	// Maintain runs git maintenance tasks (domain.MaintenanceTasks) on the canonical
+	// repository in the given order and records when it ran.
+	Maintain(ctx context.Context, repoName string, tasks []string) error
+
+	// LastMaintenanceTime returns when Maintain last ran on the canonical repository, or nil.
+	LastMaintenanceTime(repoName string) (*time.Time, error)
+
+	// GetCloneMode reports whether the canonical repository is a partial, shallow or
+	// single-branch clone.
+	GetCloneMode(repoName string) (domain.CloneMode, error)
+
+	// CreatePatch writes a binary patch of a worktree's uncommitted changes, including untracked files.
+	// It returns false and writes nothing when there are no changes.
+	CreatePatch(ctx context.Context, path, patchPath string) (bool, error)
+
+	// CreateBundle writes a bundle of the commits on HEAD that are not on any remote-tracking or other local branch
+	// and returns how many commits it holds. Nothing is written when there are none.
+	CreateBundle(ctx context.Context, path, bundlePath string) (int, error)
+
+	// ApplyBundle moves a worktree's current branch to the HEAD stored in a bundle.
+	ApplyBundle(ctx context.Context, path, bundlePath string) error
+
+	// ApplyPatch applies a patch created by CreatePatch to a worktree, leaving the changes unstaged.
+	ApplyPatch(ctx context.Context, path, patchPath string) error
+
+	// SnapshotWorktree commits a worktree's uncommitted changes, including untracked files, as a
+	// stash-like commit without touching the worktree. It returns "" when there are no changes.
+	SnapshotWorktree(ctx context.Context, path, message string) (string, error)
 }
@@ -10,8 +10,9 @@ import (
 type HookExecutor interface {
 	// ExecuteHooks runs a list of hooks with the given context.
 	// If ContinueOnError is true, it continues even if a hook fails.
-	// If DryRun is true, it returns command previews without executing.
-	ExecuteHooks(hooks []config.Hook, ctx domain.HookContext, opts HookExecuteOptions) ([]domain.HookCommandPreview, error)
+	// It returns one result per resolved command, including skipped ones.
+	// If DryRun is true, results only carry command previews and nothing is executed.
+	ExecuteHooks(hooks []config.Hook, ctx domain.HookContext, opts HookExecuteOptions) ([]domain.HookResult, error)
 }
 
 // HookExecuteOptions controls hook execution behavior.
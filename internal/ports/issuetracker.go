@@ -0,0 +1,15 @@
+// Package ports defines interfaces for external dependencies (hexagonal architecture).
+package ports
+
+import (
+	"context"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+)
+
+// IssueTracker defines the interface for looking up the issue a workspace ID refers to
+// (Jira, GitHub Issues, Linear).
+type IssueTracker interface {
+	// GetIssue returns the issue with the given key or number, such as PROJ-123.
+	GetIssue(ctx context.Context, id string) (*domain.Issue, error)
+}
@@ -0,0 +1,24 @@
+// Package ports defines interfaces for external dependencies (hexagonal architecture).
+package ports
+
+import (
+	"context"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+)
+
+// ReviewStatusProvider defines the interface for fetching and caching pull request review status.
+type ReviewStatusProvider interface {
+	// CachedReviewStatus returns the review status of the pull request opened against the
+	// repository at remote from branch in headRepo (the repository itself when zero), reusing
+	// a recent result when one is cached. It returns nil when there is no pull request or no
+	// forge with an API token for the remote's host.
+	CachedReviewStatus(ctx context.Context, remote, headRepo giturl.Remote, branch string) (*domain.ReviewStatus, error)
+
+	// InvalidateCache clears the cache entry for a branch.
+	InvalidateCache(remote, headRepo giturl.Remote, branch string)
+
+	// ClearCache clears all cached entries.
+	ClearCache()
+}
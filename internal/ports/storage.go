@@ -21,6 +21,9 @@ type WorkspaceStorage interface {
 	// Close archives a workspace and returns the closed entry.
 	Close(ctx context.Context, id string, closedAt time.Time) (*domain.ClosedWorkspace, error)
 
+	// SaveClosed persists changes to a closed entry's metadata, identified by the workspace ID and ClosedAt.
+	SaveClosed(ctx context.Context, ws domain.Workspace) error
+
 	// List returns all active workspaces.
 	List(ctx context.Context) ([]domain.Workspace, error)
 
@@ -39,6 +42,18 @@ type WorkspaceStorage interface {
 	// LatestClosed returns the most recent closed entry for a workspace.
 	LatestClosed(ctx context.Context, id string) (*domain.ClosedWorkspace, error)
 
+	// LoadClosed returns the closed entry for a workspace identified by its close timestamp.
+	LoadClosed(ctx context.Context, id string, closedAt time.Time) (*domain.ClosedWorkspace, error)
+
 	// DeleteClosed removes a closed workspace entry identified by workspace ID and close timestamp.
 	DeleteClosed(ctx context.Context, id string, closedAt time.Time) error
 }
+
+// FetchStatusStore persists the latest background fetch of the canonical repositories.
+type FetchStatusStore interface {
+	// Load returns the latest fetch run, or nil if none was recorded.
+	Load() (*domain.FetchRun, error)
+
+	// Save replaces the recorded fetch run.
+	Save(run domain.FetchRun) error
+}
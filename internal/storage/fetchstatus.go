@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// FetchStatusFileName is the name of the background fetch status file in projects_root.
+const FetchStatusFileName = ".canopy-fetch-status.json"
+
+// Compile-time check that FetchStatusFile implements ports.FetchStatusStore.
+var _ ports.FetchStatusStore = (*FetchStatusFile)(nil)
+
+// FetchStatusFile stores the latest background fetch run as JSON.
+type FetchStatusFile struct {
+	Path string
+}
+
+// NewFetchStatusFile returns the fetch status file kept in projectsRoot.
+func NewFetchStatusFile(projectsRoot string) *FetchStatusFile {
+	return &FetchStatusFile{Path: filepath.Join(projectsRoot, FetchStatusFileName)}
+}
+
+// Load returns the recorded fetch run, or nil if the file does not exist.
+func (f *FetchStatusFile) Load() (*domain.FetchRun, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, cerrors.NewIOFailed("read fetch status", err)
+	}
+
+	var run domain.FetchRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, cerrors.NewIOFailed("decode fetch status", err)
+	}
+
+	return &run, nil
+}
+
+// Save writes the fetch run. The file is replaced atomically so concurrent readers never
+// see a partial write.
+func (f *FetchStatusFile) Save(run domain.FetchRun) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return cerrors.NewIOFailed("encode fetch status", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o750); err != nil {
+		return cerrors.NewIOFailed("create fetch status directory", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), FetchStatusFileName+".*")
+	if err != nil {
+		return cerrors.NewIOFailed("create fetch status file", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return cerrors.NewIOFailed("write fetch status", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return cerrors.NewIOFailed("write fetch status", err)
+	}
+
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return cerrors.NewIOFailed("replace fetch status", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+)
+
+func TestFetchStatusFile_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	projectsRoot := t.TempDir()
+	store := NewFetchStatusFile(projectsRoot)
+
+	run, err := store.Load()
+	if err != nil || run != nil {
+		t.Fatalf("expected no run before the first save, got %+v, %v", run, err)
+	}
+
+	started := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	next := started.Add(15 * time.Minute)
+	want := domain.FetchRun{
+		StartedAt:  started,
+		FinishedAt: started.Add(time.Second),
+		NextRunAt:  &next,
+		Repos: []domain.RepoFetchResult{
+			{Name: "api", Outcome: domain.FetchOutcomeFetched},
+			{Name: "web", Outcome: domain.FetchOutcomeFailed, Error: "timeout"},
+		},
+	}
+
+	if err := store.Save(want); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got == nil || !got.NextRunAt.Equal(next) || got.Summary() != "1 fetched, 1 failed" || got.Repos[1].Error != "timeout" {
+		t.Errorf("Load = %+v, want %+v", got, want)
+	}
+
+	entries, err := os.ReadDir(projectsRoot)
+	if err != nil {
+		t.Fatalf("failed to read projects root: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Name() != FetchStatusFileName {
+		t.Errorf("expected only %s in projects root, got %v", FetchStatusFileName, entries)
+	}
+}
+
+func TestFetchStatusFile_LoadCorrupt(t *testing.T) {
+	t.Parallel()
+
+	projectsRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectsRoot, FetchStatusFileName), []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to write status file: %v", err)
+	}
+
+	if _, err := NewFetchStatusFile(projectsRoot).Load(); err == nil {
+		t.Error("expected an error for a corrupt status file")
+	}
+}
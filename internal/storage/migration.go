@@ -18,6 +18,7 @@ var migrationRegistry = map[int]MigrationFunc{
 	// Migration from version 0 to 1 is a no-op: just adds the version field
 	// which is handled automatically by saveMetadata setting CurrentWorkspaceVersion
 	0: migrateV0ToV1,
+	1: migrateV1ToV2,
 }
 
 // migrateV0ToV1 migrates a version 0 workspace to version 1.
@@ -29,6 +30,13 @@ func migrateV0ToV1(_ *domain.Workspace) error {
 	return nil
 }
 
+// migrateV1ToV2 migrates a version 1 workspace to version 2.
+// Version 2 adds the optional description, labels and notes fields, which are
+// simply empty for older workspaces, so no structural changes are needed.
+func migrateV1ToV2(_ *domain.Workspace) error {
+	return nil
+}
+
 // MigrateWorkspace applies all necessary migrations to bring a workspace
 // from its current version to the current schema version.
 // Returns true if any migrations were applied.
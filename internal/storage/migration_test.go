@@ -45,6 +45,30 @@ func TestMigrateWorkspace_V0ToV1(t *testing.T) {
 	}
 }
 
+func TestMigrateWorkspace_V1ToV2(t *testing.T) {
+	t.Parallel()
+
+	ws := &domain.Workspace{
+		Version:    1,
+		ID:         "test-ws",
+		BranchName: "main",
+		Template:   "backend",
+	}
+
+	migrated, err := MigrateWorkspace(ws)
+	if err != nil {
+		t.Fatalf("MigrateWorkspace failed: %v", err)
+	}
+
+	if !migrated || ws.Version != 2 {
+		t.Errorf("expected migration to version 2, got migrated=%v version=%d", migrated, ws.Version)
+	}
+
+	if ws.Template != "backend" || ws.Description != "" || len(ws.Labels) != 0 || ws.Notes != "" {
+		t.Errorf("expected existing fields preserved and new fields empty, got %+v", ws)
+	}
+}
+
 func TestMigrateWorkspace_AlreadyCurrent(t *testing.T) {
 	t.Parallel()
 
@@ -269,6 +269,24 @@ func (e *Engine) Close(_ context.Context, id string, closedAt time.Time) (*domai
 	}, nil
 }
 
+// SaveClosed persists changes to a closed entry's metadata.
+func (e *Engine) SaveClosed(_ context.Context, ws domain.Workspace) error {
+	if ws.ClosedAt == nil {
+		return cerrors.NewInvalidArgument("closed_at", "is required to save a closed workspace")
+	}
+
+	closedDir, err := e.resolveClosedDirectory(ws.ID, *ws.ClosedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := e.saveMetadata(filepath.Join(closedDir, "workspace.yaml"), ws); err != nil {
+		return cerrors.NewWorkspaceMetadataError(ws.ID, "write", err)
+	}
+
+	return nil
+}
+
 func (e *Engine) saveMetadata(path string, workspace domain.Workspace) error {
 	// Always write current schema version
 	workspace.Version = domain.CurrentWorkspaceVersion
@@ -570,6 +588,25 @@ func (e *Engine) LatestClosed(_ context.Context, id string) (*domain.ClosedWorks
 	return latest, nil
 }
 
+// LoadClosed returns the closed entry for a workspace identified by its close timestamp.
+func (e *Engine) LoadClosed(_ context.Context, id string, closedAt time.Time) (*domain.ClosedWorkspace, error) {
+	closedDir, err := e.resolveClosedDirectory(id, closedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	w, ok := e.tryLoadMetadata(closedDir)
+	if !ok {
+		return nil, cerrors.NewWorkspaceMetadataError(id, "read", errors.New("no readable workspace.yaml in "+closedDir))
+	}
+
+	return &domain.ClosedWorkspace{
+		DirName:  filepath.Base(filepath.Dir(closedDir)),
+		Path:     closedDir,
+		Metadata: w,
+	}, nil
+}
+
 // DeleteClosed removes a closed workspace entry identified by workspace ID and close timestamp.
 func (e *Engine) DeleteClosed(_ context.Context, id string, closedAt time.Time) error {
 	closedDir, err := e.resolveClosedDirectory(id, closedAt)
@@ -122,3 +122,90 @@ func TestClose_CreatesClosedEntry(t *testing.T) {
 		t.Fatalf("expected closed metadata to exist: %v", err)
 	}
 }
+
+func TestSaveClosed_UpdatesClosedEntry(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	workspacesRoot := filepath.Join(tmpDir, "workspaces")
+	closedRoot := filepath.Join(tmpDir, "closed")
+
+	if err := os.MkdirAll(workspacesRoot, 0o750); err != nil {
+		t.Fatalf("failed to create workspaces root: %v", err)
+	}
+
+	engine := New(workspacesRoot, closedRoot)
+	if err := engine.Create(context.Background(), domain.Workspace{ID: "ws-1"}); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	closedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	closed, err := engine.Close(context.Background(), "ws-1", closedAt)
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	ws := closed.Metadata
+	ws.Archives = []domain.RepoArchive{{Repo: "repo-a", Patch: "archive/repo-a.patch"}}
+
+	if err := engine.SaveClosed(context.Background(), ws); err != nil {
+		t.Fatalf("SaveClosed failed: %v", err)
+	}
+
+	latest, err := engine.LatestClosed(context.Background(), "ws-1")
+	if err != nil {
+		t.Fatalf("LatestClosed failed: %v", err)
+	}
+
+	if len(latest.Metadata.Archives) != 1 || latest.Metadata.Archives[0].Patch != "archive/repo-a.patch" {
+		t.Errorf("expected archive to be persisted, got %+v", latest.Metadata.Archives)
+	}
+
+	if err := engine.SaveClosed(context.Background(), domain.Workspace{ID: "ws-1"}); err == nil {
+		t.Error("expected error when ClosedAt is missing")
+	}
+}
+
+func TestLoadClosed_ByTimestamp(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	workspacesRoot := filepath.Join(tmpDir, "workspaces")
+	closedRoot := filepath.Join(tmpDir, "closed")
+
+	if err := os.MkdirAll(workspacesRoot, 0o750); err != nil {
+		t.Fatalf("failed to create workspaces root: %v", err)
+	}
+
+	engine := New(workspacesRoot, closedRoot)
+	older := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+
+	for _, closedAt := range []time.Time{older, newer} {
+		if err := engine.Create(context.Background(), domain.Workspace{ID: "ws-1", BranchName: closedAt.Format("b-20060102")}); err != nil {
+			t.Fatalf("failed to create workspace: %v", err)
+		}
+
+		if _, err := engine.Close(context.Background(), "ws-1", closedAt); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		if err := engine.Delete(context.Background(), "ws-1"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+	}
+
+	entry, err := engine.LoadClosed(context.Background(), "ws-1", older)
+	if err != nil {
+		t.Fatalf("LoadClosed failed: %v", err)
+	}
+
+	if entry.Metadata.BranchName != "b-20240102" || !entry.ClosedAt().Equal(older) {
+		t.Errorf("expected the older entry, got %+v", entry.Metadata)
+	}
+
+	if _, err := engine.LoadClosed(context.Background(), "ws-1", older.Add(time.Hour)); err == nil {
+		t.Error("expected error for an unknown timestamp")
+	}
+}
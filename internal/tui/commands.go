@@ -49,9 +49,13 @@ func (m Model) loadWorkspaces() tea.Msg {
 		totalUsage += w.DiskUsageBytes
 	}
 
+	// The background fetch status is only shown in the header, so a read error is ignored
+	fetchRun, _ := m.svc.LastFetchRun()
+
 	return workspaceListMsg{
 		items:      items,
 		totalUsage: totalUsage,
+		fetchRun:   fetchRun,
 	}
 }
 
@@ -89,6 +93,16 @@ func (m Model) loadWorkspaceDetails(id string) tea.Cmd {
 	}
 }
 
+// loadReviewStatus creates a command to load pull request review status for a workspace.
+// Failures are not reported: review status is supplementary to the detail view.
+func (m Model) loadReviewStatus(id string) tea.Cmd {
+	return func() tea.Msg {
+		reviews, _ := m.svc.GetReviewStatus(context.Background(), id)
+
+		return reviewStatusMsg{id: id, reviews: reviews}
+	}
+}
+
 // pushWorkspace creates a command to push all changes in a workspace.
 func (m Model) pushWorkspace(id string) tea.Cmd {
 	return func() tea.Msg {
@@ -164,15 +164,17 @@ func (d WorkspaceDelegate) Render(w io.Writer, m list.Model, index int, listItem
 		IsStale:           wsItem.Workspace.IsStale(d.staleThreshold),
 	}).Render()
 
-	// First line: cursor + selection + status + title + badges
-	line1 := fmt.Sprintf("%s %s %s %s %s", cursor, selectionStyle.Render(selectionIndicator), statusIndicator, title, badges)
-
-	// Build description line
 	descStyle := d.styles.NormalDesc
 	if isSelected {
 		descStyle = d.styles.SelectedDesc
 	}
 
+	// First line: cursor + selection + status + title + badges + description
+	line1 := fmt.Sprintf("%s %s %s %s %s", cursor, selectionStyle.Render(selectionIndicator), statusIndicator, title, badges)
+	if wsItem.Workspace.Description != "" {
+		line1 += "  " + descStyle.Render(truncateText(wsItem.Workspace.Description, maxListDescriptionWidth))
+	}
+
 	var secondary string
 
 	switch {
@@ -206,6 +208,23 @@ func (d WorkspaceDelegate) Render(w io.Writer, m list.Model, index int, listItem
 	_, _ = fmt.Fprintf(w, "    %s\n", statusLine)
 }
 
+// maxListDescriptionWidth bounds the workspace description shown next to the ID in the list.
+const maxListDescriptionWidth = 60
+
+// truncateText shortens s to at most width characters, ending it with an ellipsis when cut.
+func truncateText(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+
+	if width == 1 {
+		return "…"
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
 // HumanizeBytes formats a byte count into a human-readable string.
 func HumanizeBytes(size int64) string {
 	const unit = 1024
@@ -101,3 +101,23 @@ func TestFormatCount(t *testing.T) {
 		}
 	}
 }
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		text     string
+		width    int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"Fix login redirect", 10, "Fix login…"},
+		{"anything", 0, "anything"},
+	}
+
+	for _, tt := range tests {
+		result := truncateText(tt.text, tt.width)
+		if result != tt.expected {
+			t.Errorf("truncateText(%q, %d) = %q, want %q", tt.text, tt.width, result, tt.expected)
+		}
+	}
+}
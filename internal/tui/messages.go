@@ -7,6 +7,7 @@ import "github.com/alexisbeaulieu97/canopy/internal/domain"
 type workspaceListMsg struct {
 	items      []workspaceItem
 	totalUsage int64
+	fetchRun   *domain.FetchRun
 }
 
 // workspaceStatusMsg is sent when status for a single workspace is loaded.
@@ -51,6 +52,12 @@ type workspaceDetailsMsg struct {
 	orphans   []domain.OrphanedWorktree
 }
 
+// reviewStatusMsg is sent when pull request review status for a workspace is loaded.
+type reviewStatusMsg struct {
+	id      string
+	reviews map[string]*domain.ReviewStatus
+}
+
 // loadWorkspacesErrMsg is sent when loading workspaces fails.
 type loadWorkspacesErrMsg struct {
 	err error
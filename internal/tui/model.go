@@ -38,12 +38,16 @@ type Model struct {
 	wsStatus *domain.WorkspaceStatus
 	// wsOrphans holds orphaned worktrees for the detail view.
 	wsOrphans []domain.OrphanedWorktree
+	// wsReviews holds pull request review status per repo for the detail view.
+	wsReviews map[string]*domain.ReviewStatus
 	// lastFilterValue tracks the last filter value for change detection.
 	lastFilterValue string
 	// selectedIDs tracks workspaces selected for bulk operations.
 	selectedIDs map[string]bool
 	// selectionMode indicates whether multi-select mode is active.
 	selectionMode bool
+	// fetchRun holds the latest background fetch recorded by "canopy daemon".
+	fetchRun *domain.FetchRun
 }
 
 // NewModel creates a new TUI model.
@@ -84,6 +84,7 @@ func (m *Model) handleWorkspaceListMessage(msg tea.Msg) (tea.Cmd, bool) {
 	switch msg := msg.(type) {
 	case workspaceListMsg:
 		m.workspaces.SetItems(msg.items, msg.totalUsage)
+		m.fetchRun = msg.fetchRun
 		m.pruneSelectionIDs(msg.items)
 		m.applySelectionToItems()
 		m.applyFilters()
@@ -141,6 +142,12 @@ func (m *Model) handleWorkspaceDetailsMessage(msg tea.Msg) (tea.Cmd, bool) {
 			ds.Loading = false
 		}
 
+		return nil, true
+	case reviewStatusMsg:
+		if m.isDetailView() && m.selectedWS != nil && m.selectedWS.ID == msg.id {
+			m.wsReviews = msg.reviews
+		}
+
 		return nil, true
 	}
 
@@ -441,6 +448,7 @@ func (m *Model) handleDetailKeyWithState(state *DetailViewState, key string) (Vi
 		m.selectedWS = nil
 		m.wsStatus = nil
 		m.wsOrphans = nil
+		m.wsReviews = nil
 
 		return &ListViewState{}, nil, true
 	}
@@ -519,6 +527,10 @@ func (m *Model) handleEnterWithState() (ViewState, tea.Cmd, bool) {
 	}
 
 	wsCopy := selected.Workspace
+	// Select the workspace now so review status arriving before the details is kept
+	m.selectedWS = &wsCopy
+	m.wsReviews = nil
+
 	if cached, ok := m.workspaces.GetCachedStatus(selected.Workspace.ID); ok {
 		// Show cached status immediately, but still fetch full details (including orphans)
 		// in the background. The UI will update when the full details arrive.
@@ -527,12 +539,12 @@ func (m *Model) handleEnterWithState() (ViewState, tea.Cmd, bool) {
 			return workspaceDetailsMsg{workspace: &wsCopy, status: cached}
 		}
 
-		return detailState, tea.Batch(cachedMsg, m.loadWorkspaceDetails(selected.Workspace.ID)), true
+		return detailState, tea.Batch(cachedMsg, m.loadWorkspaceDetails(selected.Workspace.ID), m.loadReviewStatus(selected.Workspace.ID)), true
 	}
 
 	detailState := &DetailViewState{Loading: true}
 
-	return detailState, m.loadWorkspaceDetails(selected.Workspace.ID), true
+	return detailState, tea.Batch(m.loadWorkspaceDetails(selected.Workspace.ID), m.loadReviewStatus(selected.Workspace.ID)), true
 }
 
 // handlePushConfirmWithState initiates push confirmation using ViewState pattern.
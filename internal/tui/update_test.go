@@ -2,6 +2,7 @@ package tui
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -31,6 +32,28 @@ func TestUpdate_WorkspaceListMessage(t *testing.T) {
 	}
 }
 
+func TestUpdate_WorkspaceListMessageWithFetchRun(t *testing.T) {
+	t.Parallel()
+
+	model, _ := newTUITestModel(t)
+
+	run := &domain.FetchRun{Repos: []domain.RepoFetchResult{
+		{Name: "api", Outcome: domain.FetchOutcomeFetched},
+		{Name: "web", Outcome: domain.FetchOutcomeFailed, Error: "timeout"},
+	}}
+
+	updatedModel, _ := model.Update(workspaceListMsg{fetchRun: run})
+	updated := updatedModel.(Model)
+
+	if updated.fetchRun != run {
+		t.Fatalf("expected fetch run to be stored, got %+v", updated.fetchRun)
+	}
+
+	if header := updated.renderHeader(); !strings.Contains(header, "FETCH: 1 failed") {
+		t.Errorf("expected header to show the failed fetch, got %q", header)
+	}
+}
+
 func TestUpdate_WorkspaceStatusMessage(t *testing.T) {
 	t.Parallel()
 
@@ -85,6 +108,31 @@ func TestUpdate_WorkspaceDetailsMessage(t *testing.T) {
 	}
 }
 
+func TestUpdate_ReviewStatusMessage(t *testing.T) {
+	t.Parallel()
+
+	model, _ := newTUITestModel(t)
+	model.viewState = &DetailViewState{}
+	model.selectedWS = &domain.Workspace{ID: "ws-1"}
+
+	reviews := map[string]*domain.ReviewStatus{
+		"repo-a": {Number: 12, State: domain.PullRequestOpen, ReviewDecision: domain.ReviewRequired, Checks: domain.ChecksSuccess},
+	}
+
+	updatedModel, _ := model.Update(reviewStatusMsg{id: "other", reviews: reviews})
+	if updated := updatedModel.(Model); updated.wsReviews != nil {
+		t.Fatal("expected review status for another workspace to be ignored")
+	}
+
+	updatedModel, _ = model.Update(reviewStatusMsg{id: "ws-1", reviews: reviews})
+	updated := updatedModel.(Model)
+
+	line := updated.renderRepoLine(domain.RepoStatus{Name: "repo-a", Branch: "ws-1"})
+	if !strings.Contains(line, "PR #12") || !strings.Contains(line, "review required") || !strings.Contains(line, "checks success") {
+		t.Errorf("expected review status in repo line, got %q", line)
+	}
+}
+
 func TestUpdate_PushResultMessage(t *testing.T) {
 	t.Parallel()
 
@@ -110,6 +110,15 @@ func (m Model) renderHeader() string {
 		parts = append(parts, mutedTextStyle.Render(diskInfo))
 	}
 
+	// Background fetch
+	if m.fetchRun != nil {
+		if failed := m.fetchRun.Count(domain.FetchOutcomeFailed); failed > 0 {
+			parts = append(parts, badgeWarnStyle.Render(fmt.Sprintf("FETCH: %d failed", failed)))
+		} else {
+			parts = append(parts, mutedTextStyle.Render("fetched "+relativeTime(m.fetchRun.FinishedAt)))
+		}
+	}
+
 	// Active filters
 	var filters []string
 
@@ -247,6 +256,26 @@ func (m Model) renderDetailMetadata() string {
 		detailValueStyle.Render(m.selectedWS.BranchName)
 	rows = append(rows, row)
 
+	// Issue
+	if issue := m.selectedWS.Issue; issue != nil {
+		row = detailLabelStyle.Render("Issue:") + " " +
+			detailValueStyle.Render(strings.TrimSpace(issue.Title+" "+issue.URL))
+		rows = append(rows, row)
+	}
+
+	// Description and labels
+	if m.selectedWS.Description != "" {
+		row = detailLabelStyle.Render("Description:") + " " +
+			detailValueStyle.Render(m.selectedWS.Description)
+		rows = append(rows, row)
+	}
+
+	if len(m.selectedWS.Labels) > 0 {
+		row = detailLabelStyle.Render("Labels:") + " " +
+			detailValueStyle.Render(strings.Join(m.selectedWS.Labels, ", "))
+		rows = append(rows, row)
+	}
+
 	// Disk usage
 	row = detailLabelStyle.Render("Disk Usage:") + " " +
 		detailValueStyle.Render(humanizeBytes(m.selectedWS.DiskUsageBytes))
@@ -325,6 +354,10 @@ func (m Model) renderRepoLine(repo domain.RepoStatus) string {
 		statusParts = append(statusParts, statusCleanStyle.Render(fmt.Sprintf("%s clean", m.symbols.Check())))
 	}
 
+	if review, ok := m.wsReviews[repo.Name]; ok {
+		statusParts = append(statusParts, renderReviewStatus(*review))
+	}
+
 	statusStr := strings.Join(statusParts, " • ")
 
 	// Format: icon name [branch] status
@@ -335,6 +368,42 @@ func (m Model) renderRepoLine(repo domain.RepoStatus) string {
 		statusStr)
 }
 
+// renderReviewStatus renders a repository's pull request: red when changes are requested or
+// checks fail, yellow while awaiting review or checks, green when approved.
+func renderReviewStatus(review domain.ReviewStatus) string {
+	if review.Error != "" {
+		return statusDirtyStyle.Render("PR: error")
+	}
+
+	parts := []string{fmt.Sprintf("PR #%d", review.Number)}
+
+	switch {
+	case review.State != domain.PullRequestOpen:
+		parts = append(parts, string(review.State))
+	case review.Draft:
+		parts = append(parts, "draft")
+	}
+
+	if review.ReviewDecision != "" {
+		parts = append(parts, strings.ReplaceAll(string(review.ReviewDecision), "_", " "))
+	}
+
+	if review.Checks != "" {
+		parts = append(parts, "checks "+string(review.Checks))
+	}
+
+	text := strings.Join(parts, " ")
+
+	switch {
+	case review.ReviewDecision == domain.ReviewChangesRequested || review.Checks == domain.ChecksFailure:
+		return statusDirtyStyle.Render(text)
+	case review.AwaitingReview() || review.Checks == domain.ChecksPending:
+		return statusWarnStyle.Render(text)
+	default:
+		return statusCleanStyle.Render(text)
+	}
+}
+
 // renderDetailOrphans renders the orphaned worktrees section in the detail view.
 func (m Model) renderDetailOrphans() string {
 	var b strings.Builder
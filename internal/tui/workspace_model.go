@@ -119,7 +119,7 @@ func (wm *workspaceModel) ApplyFilters(searchValue string) []list.Item {
 			continue
 		}
 
-		if search != "" && !strings.Contains(strings.ToLower(it.Workspace.ID), search) {
+		if search != "" && !matchesSearch(it.Workspace, search) {
 			continue
 		}
 
@@ -128,3 +128,15 @@ func (wm *workspaceModel) ApplyFilters(searchValue string) []list.Item {
 
 	return items
 }
+
+// matchesSearch reports whether a workspace's ID, description or labels contain the lower-cased search text.
+func matchesSearch(ws domain.Workspace, search string) bool {
+	fields := append([]string{ws.ID, ws.Description}, ws.Labels...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+
+	return false
+}
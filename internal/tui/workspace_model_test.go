@@ -133,7 +133,7 @@ func TestWorkspaceModel_ApplyFilters_Search(t *testing.T) {
 	items := []workspaceItem{
 		{Workspace: domain.Workspace{ID: "project-alpha"}},
 		{Workspace: domain.Workspace{ID: "project-beta"}},
-		{Workspace: domain.Workspace{ID: "something-else"}},
+		{Workspace: domain.Workspace{ID: "something-else", Description: "Login redirect fix", Labels: []string{"auth"}}},
 	}
 
 	wm.SetItems(items, 0)
@@ -155,6 +155,13 @@ func TestWorkspaceModel_ApplyFilters_Search(t *testing.T) {
 	if len(result) != 2 {
 		t.Errorf("ApplyFilters('PROJECT') = %d items, want 2", len(result))
 	}
+
+	// Description and labels are searched too
+	for _, search := range []string{"redirect", "AUTH"} {
+		if result = wm.ApplyFilters(search); len(result) != 1 {
+			t.Errorf("ApplyFilters(%q) = %d items, want 1", search, len(result))
+		}
+	}
 }
 
 func TestWorkspaceModel_ApplyFilters_Stale(t *testing.T) {
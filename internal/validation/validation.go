@@ -3,6 +3,7 @@
 package validation
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -21,6 +22,12 @@ const (
 
 	// MaxRepoNameLength is the maximum allowed length for repository names.
 	MaxRepoNameLength = 255
+
+	// MaxSnapshotNameLength is the maximum allowed length for snapshot names.
+	MaxSnapshotNameLength = 100
+
+	// MaxLabelLength is the maximum allowed length for workspace labels.
+	MaxLabelLength = 50
 )
 
 // NormalizeWorkspaceDirName validates and normalizes a workspace directory name.
@@ -155,6 +162,51 @@ func ValidateBranchName(name string) error {
 	return nil
 }
 
+// ValidateSnapshotName validates a workspace snapshot name.
+// Snapshot names become a single git ref component, so they follow ref rules and cannot contain slashes.
+func ValidateSnapshotName(name string) error {
+	if name == "" {
+		return cerrors.NewInvalidArgument("snapshot", "cannot be empty")
+	}
+
+	if len(name) > MaxSnapshotNameLength {
+		return cerrors.NewInvalidArgument("snapshot", "exceeds maximum length of 100 characters")
+	}
+
+	if strings.ContainsRune(name, '/') || gitReservedNames[name] {
+		return cerrors.NewInvalidArgument("snapshot", "cannot contain slashes or be a reserved git name")
+	}
+
+	for _, pattern := range gitRefInvalidPatterns {
+		if pattern.MatchString(name) {
+			return cerrors.NewInvalidArgument("snapshot", "contains invalid characters or sequences for git refs")
+		}
+	}
+
+	return nil
+}
+
+// labelPattern matches workspace labels: a letter or digit followed by letters, digits, '.', '_', '-', ':' or '/'.
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/-]*$`)
+
+// ValidateLabel validates a workspace label.
+func ValidateLabel(label string) error {
+	if label == "" {
+		return cerrors.NewInvalidArgument("label", "cannot be empty")
+	}
+
+	if len(label) > MaxLabelLength {
+		return cerrors.NewInvalidArgument("label", "exceeds maximum length of 50 characters")
+	}
+
+	if !labelPattern.MatchString(label) {
+		return cerrors.NewInvalidArgument("label",
+			fmt.Sprintf("%q must start with a letter or digit and contain only letters, digits, '.', '_', '-', ':' or '/'", label))
+	}
+
+	return nil
+}
+
 // ValidateRepoName validates a repository name.
 // Returns an error if the name is invalid, nil otherwise.
 func ValidateRepoName(name string) error {
@@ -226,3 +278,24 @@ func ValidatePath(path string) error {
 
 	return nil
 }
+
+// NormalizeSparsePath validates a cone-mode sparse checkout directory and returns it in
+// canonical form: forward slashes, no leading "./" and no trailing slash.
+// Cone mode matches whole directories, so glob patterns and the repository root are rejected.
+func NormalizeSparsePath(path string) (string, error) {
+	trimmed := strings.TrimSpace(path)
+	if err := ValidatePath(trimmed); err != nil {
+		return "", err
+	}
+
+	if strings.ContainsAny(trimmed, "*?[") {
+		return "", cerrors.NewInvalidArgument("path", fmt.Sprintf("%q: sparse paths are directories, not patterns", path))
+	}
+
+	cleaned := filepath.ToSlash(filepath.Clean(trimmed))
+	if cleaned == "." {
+		return "", cerrors.NewInvalidArgument("path", "sparse paths cannot be the repository root")
+	}
+
+	return cleaned, nil
+}
@@ -419,3 +419,109 @@ func FuzzValidatePath(f *testing.F) {
 		}
 	})
 }
+
+func TestValidateSnapshotName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		snapshotName string
+		wantErr      bool
+	}{
+		// Valid cases
+		{name: "simple name", snapshotName: "before-refactor", wantErr: false},
+		{name: "timestamp", snapshotName: "20240102-030405", wantErr: false},
+		{name: "with dots", snapshotName: "v1.2", wantErr: false},
+
+		// Invalid cases
+		{name: "empty", snapshotName: "", wantErr: true},
+		{name: "exceeds max length", snapshotName: strings.Repeat("a", 101), wantErr: true},
+		{name: "contains slash", snapshotName: "a/b", wantErr: true},
+		{name: "reserved name", snapshotName: "HEAD", wantErr: true},
+		{name: "double dots", snapshotName: "a..b", wantErr: true},
+		{name: "whitespace", snapshotName: "my snapshot", wantErr: true},
+		{name: "lock suffix", snapshotName: "snap.lock", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validation.ValidateSnapshotName(tt.snapshotName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSnapshotName(%q) error = %v, wantErr %v", tt.snapshotName, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		label   string
+		wantErr bool
+	}{
+		// Valid cases
+		{name: "simple", label: "backend", wantErr: false},
+		{name: "scoped", label: "team:core", wantErr: false},
+		{name: "with slash and dots", label: "release/v1.2", wantErr: false},
+
+		// Invalid cases
+		{name: "empty", label: "", wantErr: true},
+		{name: "exceeds max length", label: strings.Repeat("a", 51), wantErr: true},
+		{name: "whitespace", label: "needs review", wantErr: true},
+		{name: "comma", label: "a,b", wantErr: true},
+		{name: "leading dash", label: "-urgent", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validation.ValidateLabel(tt.label)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLabel(%q) error = %v, wantErr %v", tt.label, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeSparsePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		// Valid cases
+		{name: "directory", path: "services", want: "services"},
+		{name: "nested with trailing slash", path: "services/api/", want: "services/api"},
+		{name: "leading dot slash", path: "./docs", want: "docs"},
+
+		// Invalid cases
+		{name: "empty", path: "", wantErr: true},
+		{name: "root", path: ".", wantErr: true},
+		{name: "absolute", path: "/services", wantErr: true},
+		{name: "traversal", path: "../other", wantErr: true},
+		{name: "glob", path: "services/*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := validation.NormalizeSparsePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeSparsePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("NormalizeSparsePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package workspaces
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/validation"
+)
+
+// AnnotateOptions describes changes to a workspace's description, labels and notes.
+type AnnotateOptions struct {
+	// Description replaces the description when non-nil; an empty string clears it.
+	Description *string
+	// AddLabels are added to the workspace's labels.
+	AddLabels []string
+	// RemoveLabels are removed from the workspace's labels.
+	RemoveLabels []string
+	// Notes replaces the notes when non-nil; an empty string clears them.
+	Notes *string
+	// AppendNote is added to the notes on a new line.
+	AppendNote string
+}
+
+// AnnotateWorkspace updates the description, labels and notes of a workspace and returns
+// the updated workspace.
+func (s *Service) AnnotateWorkspace(ctx context.Context, workspaceID string, opts AnnotateOptions) (*domain.Workspace, error) {
+	addLabels, err := normalizeLabels(opts.AddLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *domain.Workspace
+
+	err = s.withWorkspaceLock(ctx, workspaceID, false, func() error {
+		workspace, _, err := s.findWorkspace(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
+
+		ws := *workspace
+
+		if opts.Description != nil {
+			ws.Description = strings.TrimSpace(*opts.Description)
+		}
+
+		ws.Labels = removeLabels(mergeLabels(ws.Labels, addLabels), opts.RemoveLabels)
+
+		if opts.Notes != nil {
+			ws.Notes = strings.TrimSpace(*opts.Notes)
+		}
+
+		if note := strings.TrimSpace(opts.AppendNote); note != "" {
+			if ws.Notes != "" {
+				ws.Notes += "\n"
+			}
+
+			ws.Notes += note
+		}
+
+		if err := s.wsEngine.Save(ctx, ws); err != nil {
+			return cerrors.NewWorkspaceMetadataError(workspaceID, "update", err)
+		}
+
+		s.cache.Invalidate(workspaceID)
+
+		updated = &ws
+
+		return nil
+	})
+
+	return updated, err
+}
+
+// normalizeLabels trims, validates and de-duplicates labels, keeping their order.
+func normalizeLabels(labels []string) ([]string, error) {
+	var normalized []string
+
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if err := validation.ValidateLabel(label); err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(normalized, label) {
+			normalized = append(normalized, label)
+		}
+	}
+
+	return normalized, nil
+}
+
+// mergeLabels appends the labels in add that are not already in labels.
+func mergeLabels(labels, add []string) []string {
+	merged := slices.Clone(labels)
+
+	for _, label := range add {
+		if !slices.Contains(merged, label) {
+			merged = append(merged, label)
+		}
+	}
+
+	return merged
+}
+
+// removeLabels returns labels without the ones in remove.
+func removeLabels(labels, remove []string) []string {
+	return slices.DeleteFunc(labels, func(label string) bool {
+		return slices.ContainsFunc(remove, func(r string) bool { return strings.TrimSpace(r) == label })
+	})
+}
@@ -0,0 +1,110 @@
+package workspaces
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// closedArchiveDir is the directory inside a closed entry that holds repository artifacts.
+const closedArchiveDir = "archive"
+
+// archiveWorktrees saves each repository's uncommitted changes as a patch and its
+// local-only commits as a bundle inside the closed entry, and records them in its metadata.
+func (s *Service) archiveWorktrees(ctx context.Context, workspace *domain.Workspace, dirName string, closed *domain.ClosedWorkspace) error {
+	if s.gitEngine == nil {
+		return nil
+	}
+
+	var archives []domain.RepoArchive
+
+	for _, repo := range workspace.Repos {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			// Nothing to save for a worktree that is already gone
+			continue
+		}
+
+		entry := domain.RepoArchive{Repo: repo.Name}
+
+		patchName := filepath.Join(closedArchiveDir, repo.Name+".patch")
+
+		written, err := s.gitEngine.CreatePatch(ctx, worktreePath, filepath.Join(closed.Path, patchName))
+		if err != nil {
+			return cerrors.NewIOFailed("archive uncommitted changes for "+repo.Name, err)
+		}
+
+		if written {
+			entry.Patch = patchName
+		}
+
+		bundleName := filepath.Join(closedArchiveDir, repo.Name+".bundle")
+
+		commits, err := s.gitEngine.CreateBundle(ctx, worktreePath, filepath.Join(closed.Path, bundleName))
+		if err != nil {
+			return cerrors.NewIOFailed("archive local commits for "+repo.Name, err)
+		}
+
+		if commits > 0 {
+			entry.Bundle = bundleName
+			entry.Commits = commits
+		}
+
+		if entry.Patch != "" || entry.Bundle != "" {
+			archives = append(archives, entry)
+		}
+	}
+
+	if len(archives) == 0 {
+		return nil
+	}
+
+	closed.Metadata.Archives = archives
+
+	return s.wsEngine.SaveClosed(ctx, closed.Metadata)
+}
+
+// restoreArchives re-applies the bundles and patches saved in a closed entry to the
+// recreated worktrees. Bundles are applied first so patches land on the archived commits.
+func (s *Service) restoreArchives(ctx context.Context, closed *domain.ClosedWorkspace, dirName string) error {
+	for _, entry := range closed.Metadata.Archives {
+		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, entry.Repo)
+
+		if entry.Bundle != "" {
+			if err := s.gitEngine.ApplyBundle(ctx, worktreePath, filepath.Join(closed.Path, entry.Bundle)); err != nil {
+				return cerrors.NewIOFailed("restore archived commits for "+entry.Repo, err)
+			}
+		}
+
+		if entry.Patch != "" {
+			if err := s.gitEngine.ApplyPatch(ctx, worktreePath, filepath.Join(closed.Path, entry.Patch)); err != nil {
+				return cerrors.NewIOFailed("restore archived changes for "+entry.Repo, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// previewArchive describes the artifacts closing with keep would save for a repository.
+func previewArchive(status domain.RepoCloseStatus) (domain.RepoArchive, bool) {
+	entry := domain.RepoArchive{Repo: status.Name}
+
+	if status.IsDirty {
+		entry.Patch = filepath.Join(closedArchiveDir, status.Name+".patch")
+	}
+
+	if status.UnpushedCount > 0 {
+		entry.Bundle = filepath.Join(closedArchiveDir, status.Name+".bundle")
+		entry.Commits = status.UnpushedCount
+	}
+
+	return entry, entry.Patch != "" || entry.Bundle != ""
+}
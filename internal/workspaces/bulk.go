@@ -2,11 +2,15 @@ package workspaces
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/filter"
 )
 
 // BulkWorkspaceResult captures the outcome of a workspace-level bulk operation.
@@ -55,6 +59,140 @@ func (s *Service) ListWorkspacesMatching(ctx context.Context, pattern string) ([
 	return matched, nil
 }
 
+// WorkspaceQuery selects workspaces for list and bulk commands. At least one of Pattern
+// and Filter must be set; when both are, a workspace must match both.
+type WorkspaceQuery struct {
+	// Pattern is a regular expression matched against workspace IDs.
+	Pattern string
+	// Filter is a filter expression (see package filter).
+	Filter string
+	// StatusTimeout bounds the git status lookup of each workspace when the filter needs it.
+	StatusTimeout time.Duration
+}
+
+// QueryResult contains the workspaces selected by a query.
+type QueryResult struct {
+	Workspaces []domain.Workspace
+	// Skipped lists workspaces left out because their git status could not be read,
+	// so a filter such as "not dirty" never selects a workspace it could not check.
+	Skipped []BulkWorkspaceResult
+}
+
+// QueryWorkspaces returns the workspaces matching a pattern and/or filter expression.
+// Git status and lock state are only looked up when the filter uses them.
+func (s *Service) QueryWorkspaces(ctx context.Context, query WorkspaceQuery) (*QueryResult, error) {
+	if strings.TrimSpace(query.Filter) == "" {
+		matched, err := s.ListWorkspacesMatching(ctx, query.Pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return &QueryResult{Workspaces: matched}, nil
+	}
+
+	expr, err := filter.Parse(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var re *regexp.Regexp
+	if query.Pattern != "" {
+		if re, err = compileWorkspacePattern(query.Pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	req := filter.Requires(expr)
+
+	var candidates []domain.Workspace
+	if req.Activity {
+		candidates, err = s.ListWorkspaces(ctx)
+	} else {
+		candidates, err = s.wsEngine.List(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if re != nil {
+		candidates = slices.DeleteFunc(candidates, func(ws domain.Workspace) bool { return !re.MatchString(ws.ID) })
+	}
+
+	statuses := make(map[string]*domain.WorkspaceStatus)
+	result := &QueryResult{}
+
+	if req.Status && len(candidates) > 0 {
+		ids := make([]string, len(candidates))
+		for i, ws := range candidates {
+			ids[i] = ws.ID
+		}
+
+		statusResults, err := s.GetWorkspaceStatusBatch(ctx, ids, query.StatusTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, res := range statusResults {
+			if statusErr := workspaceStatusError(res); statusErr != nil {
+				result.Skipped = append(result.Skipped, BulkWorkspaceResult{WorkspaceID: res.WorkspaceID, Err: statusErr})
+				continue
+			}
+
+			statuses[res.WorkspaceID] = res.Status
+		}
+	}
+
+	for _, ws := range candidates {
+		env := filter.Env{Workspace: ws, StaleThresholdDays: s.config.GetStaleThresholdDays()}
+
+		if req.Status {
+			status, ok := statuses[ws.ID]
+			if !ok {
+				continue
+			}
+
+			env.Status = status
+		}
+
+		if req.Locks {
+			locked, err := s.WorkspaceLocked(ws.ID)
+			if err != nil {
+				result.Skipped = append(result.Skipped, BulkWorkspaceResult{WorkspaceID: ws.ID, Err: err})
+				continue
+			}
+
+			env.Locked = locked
+		}
+
+		if expr.Match(env) {
+			result.Workspaces = append(result.Workspaces, ws)
+		}
+	}
+
+	return result, nil
+}
+
+// workspaceStatusError returns why a status result cannot be trusted: the lookup failed
+// or a repository's status could not be read.
+func workspaceStatusError(res WorkspaceStatusResult) error {
+	if res.Err != nil {
+		return res.Err
+	}
+
+	if res.Status == nil {
+		return nil
+	}
+
+	for _, repo := range res.Status.Repos {
+		if repo.Error != "" {
+			return fmt.Errorf("%s: %s", repo.Name, repo.Error)
+		}
+	}
+
+	return nil
+}
+
 // CloseWorkspacesMatching closes workspaces that match the regex pattern sequentially.
 func (s *Service) CloseWorkspacesMatching(ctx context.Context, pattern string, force, keepMetadata bool, opts CloseOptions) (*BulkCloseResult, error) {
 	workspaces, err := s.ListWorkspacesMatching(ctx, pattern)
@@ -79,21 +217,35 @@ func (s *Service) CloseWorkspacesMatching(ctx context.Context, pattern string, f
 }
 
 // SyncWorkspacesMatching syncs workspaces that match the regex pattern in parallel.
+// Each canonical repository used by the matched workspaces is fetched once up front,
+// then worktrees are updated locally from the fetched refs.
 func (s *Service) SyncWorkspacesMatching(ctx context.Context, pattern string, opts SyncOptions) (*BulkSyncResult, error) {
 	workspaces, err := s.ListWorkspacesMatching(ctx, pattern)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.SyncWorkspaces(ctx, workspaces, opts)
+}
+
+// SyncWorkspaces syncs the given workspaces in parallel, fetching each canonical
+// repository they use once up front.
+func (s *Service) SyncWorkspaces(ctx context.Context, workspaces []domain.Workspace, opts SyncOptions) (*BulkSyncResult, error) {
 	if len(workspaces) == 0 {
 		return &BulkSyncResult{Results: []WorkspaceSyncResult{}}, nil
 	}
 
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultSyncTimeout
+	}
+
+	fetched := s.fetchCanonicalRepos(ctx, workspaces, opts.Timeout)
+
 	executor := NewParallelExecutor(s.config.GetParallelWorkers())
 
 	results, err := ParallelMap(ctx, executor, len(workspaces), func(runCtx context.Context, index int) (WorkspaceSyncResult, error) {
 		workspaceID := workspaces[index].ID
-		syncResult, syncErr := s.SyncWorkspace(runCtx, workspaceID, opts)
+		syncResult, syncErr := s.syncWorkspace(runCtx, workspaceID, opts, fetched)
 
 		return WorkspaceSyncResult{
 			WorkspaceID: workspaceID,
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/alexisbeaulieu97/canopy/internal/config"
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
@@ -59,24 +62,35 @@ func (c *CanonicalRepoService) List(ctx context.Context) ([]string, error) {
 }
 
 // Add clones a new repository to the canonical store and returns the canonical name.
-func (c *CanonicalRepoService) Add(ctx context.Context, url string) (string, error) {
+// Without clone options, those of a registry entry for the URL are used.
+func (c *CanonicalRepoService) Add(ctx context.Context, url string, opts domain.CloneOptions) (string, error) {
 	name := giturl.ExtractRepoName(url)
 	if name == "" {
 		return "", cerrors.NewInvalidArgument("url", fmt.Sprintf("could not determine repo name from URL: %s", url))
 	}
 
+	if err := config.ValidateCloneOptions(opts); err != nil {
+		return "", err
+	}
+
+	if opts.IsFull() && c.registry != nil {
+		if entry, ok := c.registry.ResolveByURL(url); ok {
+			opts = entry.CloneOptions()
+		}
+	}
+
 	if err := c.ensureCanonicalAvailable(name); err != nil {
 		return "", err
 	}
 
 	op := NewOperation(c.logger)
 	op.AddStep(func() error {
-		return c.gitEngine.Clone(ctx, url, name)
+		return c.gitEngine.Clone(ctx, url, name, opts)
 	}, func() error {
 		return c.removeCanonicalRepo(name)
 	})
 	op.AddStep(func() error {
-		return c.registerRepoAlias(name, url)
+		return c.registerRepoAlias(name, url, opts)
 	}, func() error {
 		return c.unregisterRepoAlias(name)
 	})
@@ -108,7 +122,7 @@ func (c *CanonicalRepoService) removeCanonicalRepo(name string) error {
 	return nil
 }
 
-func (c *CanonicalRepoService) registerRepoAlias(name, url string) error {
+func (c *CanonicalRepoService) registerRepoAlias(name, url string, opts domain.CloneOptions) error {
 	if c.registry == nil {
 		return nil
 	}
@@ -121,7 +135,8 @@ func (c *CanonicalRepoService) registerRepoAlias(name, url string) error {
 		return cerrors.NewRegistryError("register", fmt.Sprintf("alias '%s' already exists for %s", name, giturl.Sanitize(existing.URL)), nil)
 	}
 
-	if err := c.registry.Register(name, config.RegistryEntry{URL: url}, false); err != nil {
+	entry := config.RegistryEntry{URL: url, Filter: opts.Filter, Depth: opts.Depth, SingleBranch: opts.SingleBranch}
+	if err := c.registry.Register(name, entry, false); err != nil {
 		return err
 	}
 
@@ -218,6 +233,79 @@ func (c *CanonicalRepoService) Sync(ctx context.Context, name string) error {
 	return c.gitEngine.Fetch(ctx, name)
 }
 
+// Deepen fetches what a partial, shallow or single-branch canonical repository left out.
+func (c *CanonicalRepoService) Deepen(ctx context.Context, name string, opts ports.DeepenOptions) error {
+	if _, err := os.Stat(filepath.Join(c.projectsRoot, name)); os.IsNotExist(err) {
+		return cerrors.NewRepoNotFound(name)
+	}
+
+	if opts.Depth < 0 {
+		return cerrors.NewInvalidArgument("depth", fmt.Sprintf("must be zero or positive, got %d", opts.Depth))
+	}
+
+	return c.gitEngine.Deepen(ctx, name, opts)
+}
+
+// Maintain runs git maintenance tasks on a canonical repository and reports its size
+// before and after. Empty tasks run every task in domain.MaintenanceTasks.
+func (c *CanonicalRepoService) Maintain(ctx context.Context, name string, tasks []string) (domain.RepoMaintenanceResult, error) {
+	result := domain.RepoMaintenanceResult{Name: name}
+
+	if _, err := os.Stat(filepath.Join(c.projectsRoot, name)); os.IsNotExist(err) {
+		return result, cerrors.NewRepoNotFound(name)
+	}
+
+	tasks, err := resolveMaintenanceTasks(tasks)
+	if err != nil {
+		return result, err
+	}
+
+	result.Tasks = tasks
+
+	if result.SizeBeforeBytes, err = c.gitEngine.GetRepoSize(name); err != nil {
+		return result, err
+	}
+
+	start := time.Now()
+
+	if err := c.gitEngine.Maintain(ctx, name, tasks); err != nil {
+		return result, err
+	}
+
+	result.Duration = time.Since(start)
+
+	if result.SizeAfterBytes, err = c.gitEngine.GetRepoSize(name); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// resolveMaintenanceTasks checks tasks against domain.MaintenanceTasks and returns them in
+// the order they run, or every task when none are given.
+func resolveMaintenanceTasks(tasks []string) ([]string, error) {
+	if len(tasks) == 0 {
+		return slices.Clone(domain.MaintenanceTasks), nil
+	}
+
+	for _, task := range tasks {
+		if !slices.Contains(domain.MaintenanceTasks, task) {
+			return nil, cerrors.NewInvalidArgument("task",
+				fmt.Sprintf("unknown maintenance task %q (valid: %s)", task, strings.Join(domain.MaintenanceTasks, ", ")))
+		}
+	}
+
+	ordered := make([]string, 0, len(tasks))
+
+	for _, task := range domain.MaintenanceTasks {
+		if slices.Contains(tasks, task) {
+			ordered = append(ordered, task)
+		}
+	}
+
+	return ordered, nil
+}
+
 // GetWorkspacesUsingRepo returns the IDs of workspaces that use the given canonical repo.
 func (c *CanonicalRepoService) GetWorkspacesUsingRepo(ctx context.Context, repoName string) ([]string, error) {
 	workspaceList, err := c.wsStorage.List(ctx)
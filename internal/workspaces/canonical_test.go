@@ -76,7 +76,7 @@ func TestCanonicalRepoService_Add(t *testing.T) {
 		var clonedURL, clonedName string
 
 		mockGit := mocks.NewMockGitOperations()
-		mockGit.CloneFunc = func(_ context.Context, url, name string) error {
+		mockGit.CloneFunc = func(_ context.Context, url, name string, _ domain.CloneOptions) error {
 			clonedURL = url
 			clonedName = name
 
@@ -87,7 +87,7 @@ func TestCanonicalRepoService_Add(t *testing.T) {
 
 		svc := NewCanonicalRepoService(mockGit, mockStorage, "/projects", nil, nil, nil)
 
-		name, err := svc.Add(context.Background(), "https://github.com/org/my-repo.git")
+		name, err := svc.Add(context.Background(), "https://github.com/org/my-repo.git", domain.CloneOptions{})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -105,6 +105,51 @@ func TestCanonicalRepoService_Add(t *testing.T) {
 		}
 	})
 
+	t.Run("uses and records clone options", func(t *testing.T) {
+		t.Parallel()
+
+		registry, err := config.LoadRepoRegistry(filepath.Join(t.TempDir(), "repos.yaml"))
+		if err != nil {
+			t.Fatalf("failed to load registry: %v", err)
+		}
+
+		monoURL := "https://github.com/org/mono.git"
+		if err := registry.Register("mono", config.RegistryEntry{URL: monoURL, Filter: "blob:none"}, false); err != nil {
+			t.Fatalf("failed to register: %v", err)
+		}
+
+		cloned := map[string]domain.CloneOptions{}
+
+		mockGit := mocks.NewMockGitOperations()
+		mockGit.CloneFunc = func(_ context.Context, _, name string, opts domain.CloneOptions) error {
+			cloned[name] = opts
+			return nil
+		}
+
+		svc := NewCanonicalRepoService(mockGit, mocks.NewMockWorkspaceStorage(), t.TempDir(), nil, nil, registry)
+
+		if _, err := svc.Add(context.Background(), monoURL, domain.CloneOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		shallow := domain.CloneOptions{Depth: 1, SingleBranch: true}
+		if _, err := svc.Add(context.Background(), "https://github.com/org/my-repo.git", shallow); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if cloned["mono"] != (domain.CloneOptions{Filter: "blob:none"}) || cloned["my-repo"] != shallow {
+			t.Errorf("unexpected clone options: %+v", cloned)
+		}
+
+		if entry, ok := registry.Resolve("my-repo"); !ok || entry.CloneOptions() != shallow {
+			t.Errorf("expected clone options to be registered, got %+v", entry)
+		}
+
+		if _, err := svc.Add(context.Background(), "https://github.com/org/other.git", domain.CloneOptions{Filter: "sparse:oid=abc"}); err == nil {
+			t.Error("expected an error for an unsupported filter")
+		}
+	})
+
 	t.Run("returns error for invalid URL", func(t *testing.T) {
 		t.Parallel()
 
@@ -113,7 +158,7 @@ func TestCanonicalRepoService_Add(t *testing.T) {
 
 		svc := NewCanonicalRepoService(mockGit, mockStorage, "/projects", nil, nil, nil)
 
-		_, err := svc.Add(context.Background(), "")
+		_, err := svc.Add(context.Background(), "", domain.CloneOptions{})
 		if err == nil {
 			t.Fatal("expected error for empty URL")
 		}
@@ -123,7 +168,7 @@ func TestCanonicalRepoService_Add(t *testing.T) {
 		t.Parallel()
 
 		mockGit := mocks.NewMockGitOperations()
-		mockGit.CloneFunc = func(context.Context, string, string) error {
+		mockGit.CloneFunc = func(context.Context, string, string, domain.CloneOptions) error {
 			return os.ErrPermission
 		}
 
@@ -131,7 +176,7 @@ func TestCanonicalRepoService_Add(t *testing.T) {
 
 		svc := NewCanonicalRepoService(mockGit, mockStorage, "/projects", nil, nil, nil)
 
-		name, err := svc.Add(context.Background(), "https://github.com/org/my-repo.git")
+		name, err := svc.Add(context.Background(), "https://github.com/org/my-repo.git", domain.CloneOptions{})
 		if err == nil {
 			t.Fatal("expected error from clone")
 		}
@@ -159,14 +204,14 @@ func TestCanonicalRepoService_Add(t *testing.T) {
 		}
 
 		mockGit := mocks.NewMockGitOperations()
-		mockGit.CloneFunc = func(_ context.Context, _, name string) error {
+		mockGit.CloneFunc = func(_ context.Context, _, name string, _ domain.CloneOptions) error {
 			return os.MkdirAll(filepath.Join(projectsRoot, name), 0o750)
 		}
 
 		mockStorage := mocks.NewMockWorkspaceStorage()
 		svc := NewCanonicalRepoService(mockGit, mockStorage, projectsRoot, nil, nil, registry)
 
-		_, err = svc.Add(context.Background(), "https://github.com/org/my-repo.git")
+		_, err = svc.Add(context.Background(), "https://github.com/org/my-repo.git", domain.CloneOptions{})
 		if err == nil {
 			t.Fatalf("expected error from registry save failure")
 		}
@@ -14,6 +14,8 @@ import (
 type CloseOptions struct {
 	SkipHooks         bool // Skip pre_close hooks
 	ContinueOnHookErr bool // Continue if hooks fail
+	// HookResults, when non-nil, receives the outcome of each pre_close hook command.
+	HookResults *[]domain.HookResult
 }
 
 // CloseWorkspace removes a workspace with safety checks
@@ -57,6 +59,7 @@ func (s *Service) closeWorkspaceWithOptionsUnlocked(ctx context.Context, workspa
 
 	// Remove worktrees from canonical repos after successful deletion
 	s.removeWorkspaceWorktrees(ctx, targetWorkspace, dirName)
+	s.gitService.deleteUnusedSnapshotRefs(ctx, targetWorkspace.Snapshots)
 
 	// Invalidate cache after workspace deletion
 	s.cache.Invalidate(workspaceID)
@@ -112,6 +115,15 @@ func (s *Service) closeWorkspaceKeepMetadataWithOptionsUnlocked(ctx context.Cont
 		return nil, err
 	}
 
+	// Save uncommitted changes and local-only commits before the worktrees are removed
+	if err := s.archiveWorktrees(ctx, targetWorkspace, dirName, archived); err != nil {
+		if rollbackErr := s.wsEngine.DeleteClosed(ctx, workspaceID, closedAt); rollbackErr != nil {
+			return nil, joinErrors(err, cerrors.NewIOFailed("rollback closed entry", rollbackErr))
+		}
+
+		return nil, err
+	}
+
 	// Delete workspace first, then clean up worktrees
 	if err := s.wsEngine.Delete(ctx, workspaceID); err != nil {
 		rollbackErr := s.wsEngine.DeleteClosed(ctx, workspaceID, closedAt)
@@ -148,16 +160,16 @@ func (s *Service) executePreCloseHooks(workspace *domain.Workspace, workspaceID,
 		return nil
 	}
 
-	hookCtx := domain.HookContext{
-		WorkspaceID:   workspaceID,
-		WorkspacePath: filepath.Join(s.config.GetWorkspacesRoot(), dirName),
-		BranchName:    workspace.BranchName,
-		Repos:         workspace.Repos,
-	}
+	hookCtx := s.newHookContext(workspaceID, dirName, workspace.BranchName, workspace.Repos)
 
-	if _, err := s.hookExecutor.ExecuteHooks(hooksConfig.PreClose, hookCtx, ports.HookExecuteOptions{
+	results, err := s.hookExecutor.ExecuteHooks(hooksConfig.PreClose, hookCtx, ports.HookExecuteOptions{
 		ContinueOnError: opts.ContinueOnHookErr,
-	}); err != nil {
+	})
+	if opts.HookResults != nil {
+		*opts.HookResults = results
+	}
+
+	if err != nil {
 		s.logger.Error("pre_close hooks failed", "error", err)
 		// Per design.md: pre_close failure aborts close operation
 		if !opts.ContinueOnHookErr {
@@ -214,6 +226,16 @@ func (s *Service) PreviewCloseWorkspace(ctx context.Context, workspaceID string,
 		s.logger.Debug("Failed to calculate workspace usage for preview", "workspace", workspaceID, "error", sizeErr)
 	}
 
+	var archives []domain.RepoArchive
+
+	if keepMetadata {
+		for _, status := range repoStatuses {
+			if entry, ok := previewArchive(status); ok {
+				archives = append(archives, entry)
+			}
+		}
+	}
+
 	return &domain.WorkspaceClosePreview{
 		WorkspaceID:    workspaceID,
 		WorkspacePath:  wsPath,
@@ -222,6 +244,7 @@ func (s *Service) PreviewCloseWorkspace(ctx context.Context, workspaceID string,
 		RepoStatuses:   repoStatuses,
 		DiskUsageBytes: usage,
 		KeepMetadata:   keepMetadata,
+		Archives:       archives,
 	}, nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/alexisbeaulieu97/canopy/internal/config"
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
@@ -21,6 +22,14 @@ type CreateOptions struct {
 	SkipHooks         bool // Skip post_create hooks
 	ContinueOnHookErr bool // Continue if hooks fail
 	Template          *config.Template
+	// Issue is the issue the workspace is for. It is stored in the metadata and its title
+	// is available to branch naming templates.
+	Issue *domain.Issue
+	// Description and Labels are stored in the workspace metadata.
+	Description string
+	Labels      []string
+	// HookResults, when non-nil, receives the outcome of each post_create hook command.
+	HookResults *[]domain.HookResult
 }
 
 // CreateWorkspace creates a new workspace directory and returns the directory name
@@ -34,7 +43,12 @@ func (s *Service) CreateWorkspaceWithOptions(ctx context.Context, id, branchName
 		branchName = opts.Template.DefaultBranch
 	}
 
-	branchName, err := s.resolveCreateBranchName(id, branchName)
+	branchName, err := s.resolveCreateBranchName(id, branchName, opts)
+	if err != nil {
+		return "", err
+	}
+
+	labels, err := normalizeLabels(opts.Labels)
 	if err != nil {
 		return "", err
 	}
@@ -44,8 +58,34 @@ func (s *Service) CreateWorkspaceWithOptions(ctx context.Context, id, branchName
 		return "", err
 	}
 
+	var templateName string
+	if opts.Template != nil {
+		templateName = opts.Template.Name
+	}
+
+	repos, err = s.withSparsePaths(templateName, repos)
+	if err != nil {
+		return "", err
+	}
+
+	repos, err = s.withRemotes(repos)
+	if err != nil {
+		return "", err
+	}
+
 	if err := s.withWorkspaceLock(ctx, id, true, func() error {
-		return s.createWorkspaceWithOptionsUnlocked(ctx, id, dirName, branchName, repos, opts)
+		ws := domain.Workspace{
+			ID:          id,
+			BranchName:  branchName,
+			Repos:       repos,
+			DirName:     dirName,
+			Issue:       opts.Issue,
+			Description: strings.TrimSpace(opts.Description),
+			Labels:      labels,
+			Template:    templateName,
+		}
+
+		return s.createWorkspaceWithOptionsUnlocked(ctx, ws, opts)
 	}); err != nil {
 		return dirName, err
 	}
@@ -53,18 +93,15 @@ func (s *Service) CreateWorkspaceWithOptions(ctx context.Context, id, branchName
 	return dirName, nil
 }
 
-func (s *Service) createWorkspaceWithOptionsUnlocked(ctx context.Context, id, dirName, branchName string, repos []domain.Repo, opts CreateOptions) error {
+// createWorkspaceWithOptionsUnlocked creates the workspace described by ws, which must have
+// its ID, DirName, BranchName, and Repos set. Other metadata fields are persisted as given.
+func (s *Service) createWorkspaceWithOptionsUnlocked(ctx context.Context, ws domain.Workspace, opts CreateOptions) error {
+	id, dirName, branchName, repos := ws.ID, ws.DirName, ws.BranchName, ws.Repos
+
 	if err := s.ensureWorkspaceAvailable(id, dirName); err != nil {
 		return err
 	}
 
-	ws := domain.Workspace{
-		ID:         id,
-		BranchName: branchName,
-		Repos:      repos,
-		DirName:    dirName,
-	}
-
 	if err := s.executeWorkspaceCreate(ctx, ws, repos, dirName); err != nil {
 		return err
 	}
@@ -149,7 +186,7 @@ func shellCommand(ctx context.Context, command string) *exec.Cmd {
 	return exec.CommandContext(ctx, "sh", "-c", command)
 }
 
-func (s *Service) resolveCreateBranchName(id, branchName string) (string, error) {
+func (s *Service) resolveCreateBranchName(id, branchName string, opts CreateOptions) (string, error) {
 	// Validate inputs
 	if err := validation.ValidateWorkspaceID(id); err != nil {
 		return "", err
@@ -159,6 +196,12 @@ func (s *Service) resolveCreateBranchName(id, branchName string) (string, error)
 		return "", err
 	}
 
+	if branchName == "" {
+		if field, pattern := s.branchNamingFor(id, opts); pattern != "" {
+			return config.RenderBranchName(field, pattern, s.branchNamingData(id, opts))
+		}
+	}
+
 	// Default branch name is the workspace ID
 	if branchName == "" {
 		branchName = id
@@ -171,6 +214,57 @@ func (s *Service) resolveCreateBranchName(id, branchName string) (string, error)
 	return branchName, nil
 }
 
+// branchNamingFor picks the branch naming template for a new workspace: the workspace
+// template's, then the issue tracker's when an issue was found, then the matching workspace
+// pattern's or the global one.
+func (s *Service) branchNamingFor(id string, opts CreateOptions) (field, pattern string) {
+	if opts.Template != nil && opts.Template.BranchNaming != "" {
+		return fmt.Sprintf("templates.%s.branch_naming", opts.Template.Name), opts.Template.BranchNaming
+	}
+
+	if tracker := s.config.GetIssueTracker(); opts.Issue != nil && tracker.BranchNaming != "" {
+		return "issue_tracker.branch_naming", tracker.BranchNaming
+	}
+
+	return s.config.GetBranchNaming(id)
+}
+
+// branchNamingData collects the values available to branch naming templates.
+func (s *Service) branchNamingData(id string, opts CreateOptions) config.BranchNamingTemplateData {
+	data := config.BranchNamingTemplateData{
+		ID:   id,
+		Date: time.Now().Format(time.DateOnly),
+		Vars: s.config.GetBranchVars(opts.Template),
+	}
+
+	if opts.Issue != nil {
+		data.Title = opts.Issue.Title
+	}
+
+	if opts.Template != nil {
+		data.Template = opts.Template.Name
+	}
+
+	user, err := s.gitEngine.UserName()
+	if err != nil && s.logger != nil {
+		s.logger.Warn("Failed to read git user name for branch naming", "error", err)
+	}
+
+	data.User = user
+
+	return data
+}
+
+// LookupIssue fetches the issue a workspace ID refers to from the configured issue tracker.
+// It returns nil when no issue tracker is configured.
+func (s *Service) LookupIssue(ctx context.Context, id string) (*domain.Issue, error) {
+	if s.issues == nil {
+		return nil, nil
+	}
+
+	return s.issues.GetIssue(ctx, id)
+}
+
 func (s *Service) executeWorkspaceCreate(ctx context.Context, ws domain.Workspace, repos []domain.Repo, dirName string) error {
 	op := NewOperation(s.logger)
 	op.AddStep(func() error {
@@ -237,7 +331,7 @@ func (s *Service) cloneWorkspaceRepos(ctx context.Context, repos []domain.Repo,
 	err := executor.Run(ctx, len(repos), func(runCtx context.Context, index int) error {
 		repo := repos[index]
 
-		_, err := s.gitEngine.EnsureCanonical(runCtx, repo.URL, repo.Name)
+		_, err := s.gitEngine.EnsureCanonical(runCtx, repo.URL, repo.Name, s.cloneOptionsForRepo(repo))
 		if err != nil {
 			return cerrors.WrapGitError(err, "ensure canonical for "+repo.Name)
 		}
@@ -255,10 +349,8 @@ func (s *Service) cloneWorkspaceRepos(ctx context.Context, repos []domain.Repo,
 			return cerrors.NewContextError(ctx, "create workspace", dirName)
 		}
 
-		// Create worktree
-		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
-		if err := s.gitEngine.CreateWorktree(ctx, repo.Name, worktreePath, branchName); err != nil {
-			return cerrors.WrapGitError(err, fmt.Sprintf("create worktree for %s", repo.Name))
+		if err := s.createRepoWorktree(ctx, repo, dirName, branchName); err != nil {
+			return err
 		}
 	}
 
@@ -277,17 +369,17 @@ func (s *Service) runPostCreateHooks(id, dirName, branchName string, repos []dom
 		return nil
 	}
 
-	hookCtx := domain.HookContext{
-		WorkspaceID:   id,
-		WorkspacePath: filepath.Join(s.config.GetWorkspacesRoot(), dirName),
-		BranchName:    branchName,
-		Repos:         repos,
-	}
+	hookCtx := s.newHookContext(id, dirName, branchName, repos)
 
 	//nolint:contextcheck // Hooks manage their own timeout context per-hook
-	if _, err := s.hookExecutor.ExecuteHooks(hooksConfig.PostCreate, hookCtx, ports.HookExecuteOptions{
+	results, err := s.hookExecutor.ExecuteHooks(hooksConfig.PostCreate, hookCtx, ports.HookExecuteOptions{
 		ContinueOnError: opts.ContinueOnHookErr,
-	}); err != nil {
+	})
+	if opts.HookResults != nil {
+		*opts.HookResults = results
+	}
+
+	if err != nil {
 		s.logger.Error("post_create hooks failed", "error", err)
 
 		if !opts.ContinueOnHookErr {
@@ -65,8 +65,11 @@ func (s *WorkspaceExportService) ExportWorkspace(ctx context.Context, workspaceI
 
 	for _, repo := range workspace.Repos {
 		repoExport := domain.RepoExport{
-			Name: repo.Name,
-			URL:  repo.URL,
+			Name:        repo.Name,
+			URL:         repo.URL,
+			SparsePaths: repo.SparsePaths,
+			Remotes:     repo.Remotes,
+			PushRemote:  repo.PushRemote,
 		}
 
 		// Try to find registry alias for this URL
@@ -196,6 +199,9 @@ func (s *WorkspaceExportService) resolveExportedRepos(exportedRepos []domain.Rep
 			return nil, cerrors.NewUnknownRepository(exported.Name, true).WithContext("workspace_id", workspaceID)
 		}
 
+		repo.SparsePaths = exported.SparsePaths
+		repo.Remotes = exported.Remotes
+		repo.PushRemote = exported.PushRemote
 		repos = append(repos, repo)
 	}
 
@@ -0,0 +1,296 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/logging"
+)
+
+// FetchAllCanonicalRepos fetches every canonical repository once, in parallel and with the
+// git engine's retry policy. The workspaces using a repository are locked while it is fetched,
+// so the fetch does not move its refs under a workspace operation; a repository used by a
+// workspace that is already locked is skipped.
+func (s *Service) FetchAllCanonicalRepos(ctx context.Context) ([]domain.RepoFetchResult, error) {
+	names, err := s.canonical.List(ctx)
+	if err != nil {
+		return nil, cerrors.WrapGitError(err, "list canonical repos")
+	}
+
+	usageMap, err := s.buildRepoUsageMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.RepoFetchResult, len(names))
+	locks := s.newRunLocks()
+	executor := NewParallelExecutor(s.config.GetParallelWorkers())
+
+	_ = executor.Run(ctx, len(names), func(runCtx context.Context, index int) error {
+		results[index] = s.fetchCanonicalRepo(runCtx, names[index], usageMap[names[index]], locks)
+		return nil
+	}, ParallelOptions{ContinueOnError: true})
+
+	for i := range results {
+		if results[i].Outcome == "" {
+			// Never started because the context was cancelled
+			results[i] = domain.RepoFetchResult{
+				Name:    names[i],
+				Outcome: domain.FetchOutcomeFailed,
+				Error:   cerrors.NewContextError(ctx, "fetch", names[i]).Error(),
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// fetchCanonicalRepo fetches one canonical repository while holding the locks of the workspaces
+// using it, and skips it when one of them is locked by another operation.
+func (s *Service) fetchCanonicalRepo(ctx context.Context, name string, usedBy []string, locks *runLocks) domain.RepoFetchResult {
+	result := domain.RepoFetchResult{Name: name, Outcome: domain.FetchOutcomeFetched}
+
+	held, lockedID, err := locks.acquire(usedBy)
+	defer locks.release(held)
+
+	switch {
+	case err != nil:
+		result.Outcome = domain.FetchOutcomeFailed
+		result.Error = fmt.Sprintf("lock workspace %s: %v", lockedID, err)
+
+		return result
+	case lockedID != "":
+		result.Outcome = domain.FetchOutcomeSkipped
+		result.Error = fmt.Sprintf("workspace %s is locked", lockedID)
+
+		return result
+	}
+
+	if err := s.gitEngine.Fetch(ctx, name); err != nil {
+		result.Outcome = domain.FetchOutcomeFailed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// runLocks holds workspace locks for one fetch run. The parallel fetches of a run share the
+// lock of a workspace that uses several of their repositories, instead of skipping each other.
+type runLocks struct {
+	lockManager *LockManager
+	logger      *logging.Logger
+
+	mu   sync.Mutex
+	held map[string]*runLock
+}
+
+// runLock is a workspace lock and the number of fetches holding it.
+type runLock struct {
+	handle *LockHandle
+	refs   int
+}
+
+// newRunLocks returns the locks of a fetch run, or nil when workspaces are not locked.
+func (s *Service) newRunLocks() *runLocks {
+	if s.lockManager == nil {
+		return nil
+	}
+
+	return &runLocks{lockManager: s.lockManager, logger: s.logger, held: make(map[string]*runLock)}
+}
+
+// acquire locks the given workspaces without waiting. It stops at the first workspace that is
+// locked by another operation, returning its ID, or that fails to lock, returning its ID and the
+// error. The IDs locked so far are returned either way, to be passed to release. A workspace
+// whose directory does not exist has nothing to lock and is skipped.
+func (l *runLocks) acquire(ids []string) ([]string, string, error) {
+	if l == nil {
+		return nil, "", nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	held := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if lock, ok := l.held[id]; ok {
+			lock.refs++
+			held = append(held, id)
+
+			continue
+		}
+
+		handle, err := l.lockManager.TryAcquire(id)
+		if err == nil {
+			l.held[id] = &runLock{handle: handle, refs: 1}
+			held = append(held, id)
+
+			continue
+		}
+
+		switch {
+		case isWorkspaceNotFound(err):
+			continue
+		case errors.Is(err, cerrors.WorkspaceLocked):
+			return held, id, nil
+		default:
+			return held, id, err
+		}
+	}
+
+	return held, "", nil
+}
+
+// release drops the given workspace locks, releasing each once no fetch holds it anymore.
+func (l *runLocks) release(ids []string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range ids {
+		lock := l.held[id]
+
+		lock.refs--
+		if lock.refs > 0 {
+			continue
+		}
+
+		delete(l.held, id)
+
+		if err := lock.handle.Release(); err != nil && l.logger != nil {
+			l.logger.Warn("workspace lock release failed", "workspace_id", id, "error", err)
+		}
+	}
+}
+
+// LastFetchRun returns the latest recorded background fetch, or nil if none was recorded.
+func (s *Service) LastFetchRun() (*domain.FetchRun, error) {
+	if s.fetchStatus == nil {
+		return nil, nil
+	}
+
+	return s.fetchStatus.Load()
+}
+
+// lastFetchRunOrNil returns the latest background fetch for status reports, logging and
+// ignoring a status file that cannot be read.
+func (s *Service) lastFetchRunOrNil() *domain.FetchRun {
+	run, err := s.LastFetchRun()
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("failed to read background fetch status", "error", err)
+		}
+
+		return nil
+	}
+
+	return run
+}
+
+// recordFetchRun saves a background fetch run, if a status store is configured.
+func (s *Service) recordFetchRun(run domain.FetchRun) error {
+	if s.fetchStatus == nil {
+		return nil
+	}
+
+	return s.fetchStatus.Save(run)
+}
+
+// FetchScheduler fetches every canonical repository on an interval with random jitter and
+// records each run. It backs "canopy daemon".
+type FetchScheduler struct {
+	svc      *Service
+	interval time.Duration
+	jitter   time.Duration
+	now      func() time.Time
+	after    func(time.Duration) <-chan time.Time
+	randN    func(n int64) int64
+}
+
+// NewFetchScheduler creates a FetchScheduler that waits interval plus up to jitter between runs.
+func NewFetchScheduler(svc *Service, interval, jitter time.Duration) *FetchScheduler {
+	return &FetchScheduler{
+		svc:      svc,
+		interval: interval,
+		jitter:   jitter,
+		now:      time.Now,
+		after:    time.After,
+		randN:    rand.Int64N, //nolint:gosec // G404: jitter does not need a secure source
+	}
+}
+
+// RunOnce fetches every canonical repository and records the run.
+func (f *FetchScheduler) RunOnce(ctx context.Context) (domain.FetchRun, error) {
+	run, err := f.fetch(ctx)
+	if err != nil {
+		return run, err
+	}
+
+	return run, f.svc.recordFetchRun(run)
+}
+
+// Run fetches right away and then again after every interval plus jitter, until ctx is
+// cancelled. onRun, if not nil, is called after each run. A failed run is reported to
+// onRun and does not stop the schedule.
+func (f *FetchScheduler) Run(ctx context.Context, onRun func(domain.FetchRun, error)) error {
+	for {
+		run, err := f.fetch(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		delay := f.nextDelay()
+
+		if err == nil {
+			next := run.FinishedAt.Add(delay)
+			run.NextRunAt = &next
+			err = f.svc.recordFetchRun(run)
+		}
+
+		if onRun != nil {
+			onRun(run, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				// Nothing is scheduled anymore
+				run.NextRunAt = nil
+				_ = f.svc.recordFetchRun(run)
+			}
+
+			return nil
+		case <-f.after(delay):
+		}
+	}
+}
+
+// fetch runs one fetch of every canonical repository.
+func (f *FetchScheduler) fetch(ctx context.Context) (domain.FetchRun, error) {
+	run := domain.FetchRun{StartedAt: f.now()}
+
+	repos, err := f.svc.FetchAllCanonicalRepos(ctx)
+	run.FinishedAt = f.now()
+	run.Repos = repos
+
+	return run, err
+}
+
+// nextDelay returns the interval plus a random jitter.
+func (f *FetchScheduler) nextDelay() time.Duration {
+	if f.jitter <= 0 {
+		return f.interval
+	}
+
+	return f.interval + time.Duration(f.randN(int64(f.jitter)+1))
+}
@@ -0,0 +1,218 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/gitx"
+	"github.com/alexisbeaulieu97/canopy/internal/mocks"
+	"github.com/alexisbeaulieu97/canopy/internal/storage"
+)
+
+func TestFetchAllCanonicalRepos(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.svc.lockManager = NewLockManager(deps.config.WorkspacesRoot, time.Second, time.Minute, nil, nil)
+
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:    "ws-busy",
+		Repos: []domain.Repo{{Name: "busy"}},
+	})
+	mustMkdir(t, filepath.Join(deps.config.WorkspacesRoot, "ws-busy"))
+
+	if err := os.WriteFile(filepath.Join(deps.config.WorkspacesRoot, "ws-busy", lockFileName), nil, 0o600); err != nil {
+		t.Fatalf("failed to write lock: %v", err)
+	}
+
+	// ws-shared uses two repositories that are fetched in parallel
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:    "ws-shared",
+		Repos: []domain.Repo{{Name: "api"}, {Name: "web"}},
+	})
+	mustMkdir(t, filepath.Join(deps.config.WorkspacesRoot, "ws-shared"))
+
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:    "bad/id",
+		Repos: []domain.Repo{{Name: "broken"}},
+	})
+
+	deps.git.ListFunc = func(_ context.Context) ([]string, error) {
+		return []string{"api", "busy", "web", "broken"}, nil
+	}
+
+	sharedLock := filepath.Join(deps.config.WorkspacesRoot, "ws-shared", lockFileName)
+
+	var (
+		mu      sync.Mutex
+		fetched []string
+	)
+
+	deps.git.FetchFunc = func(_ context.Context, name string) error {
+		if _, err := os.Stat(sharedLock); err != nil {
+			t.Errorf("expected ws-shared to be locked while %s is fetched: %v", name, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		fetched = append(fetched, name)
+		if name == "web" {
+			return errors.New("connection refused")
+		}
+
+		return nil
+	}
+
+	results, err := deps.svc.FetchAllCanonicalRepos(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAllCanonicalRepos failed: %v", err)
+	}
+
+	want := []domain.FetchOutcome{domain.FetchOutcomeFetched, domain.FetchOutcomeSkipped, domain.FetchOutcomeFailed, domain.FetchOutcomeFailed}
+	for i, res := range results {
+		if res.Outcome != want[i] {
+			t.Errorf("%s: outcome %s, want %s (%s)", res.Name, res.Outcome, want[i], res.Error)
+		}
+	}
+
+	if msg := results[3].Error; msg == "workspace bad/id is locked" {
+		t.Errorf("expected a lock error to be reported as such, got %q", msg)
+	}
+
+	for _, name := range fetched {
+		if name == "busy" || name == "broken" {
+			t.Errorf("expected %s not to be fetched", name)
+		}
+	}
+
+	if _, err := os.Stat(sharedLock); !os.IsNotExist(err) {
+		t.Errorf("expected the ws-shared lock to be released, got %v", err)
+	}
+}
+
+func TestFetchScheduler_Run(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	store := &mocks.MockFetchStatusStore{}
+	deps.svc.fetchStatus = store
+
+	deps.git.ListFunc = func(_ context.Context) ([]string, error) {
+		return []string{"api"}, nil
+	}
+
+	fetches := 0
+	deps.git.FetchFunc = func(_ context.Context, _ string) error {
+		fetches++
+		return nil
+	}
+
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	clock := start
+
+	var delays []time.Duration
+
+	scheduler := NewFetchScheduler(deps.svc, 10*time.Minute, time.Minute)
+	scheduler.now = func() time.Time { return clock }
+	scheduler.randN = func(n int64) int64 { return n / 2 }
+	scheduler.after = func(d time.Duration) <-chan time.Time {
+		delays = append(delays, d)
+		clock = clock.Add(d)
+
+		if len(delays) > 1 {
+			return nil // never fires; the second run cancels the context
+		}
+
+		elapsed := make(chan time.Time, 1)
+		elapsed <- clock
+
+		return elapsed
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs []domain.FetchRun
+
+	err := scheduler.Run(ctx, func(run domain.FetchRun, err error) {
+		if err != nil {
+			t.Errorf("run failed: %v", err)
+		}
+
+		runs = append(runs, run)
+		if len(runs) == 2 {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+
+	if fetches != 2 || len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d runs and %d fetches", len(runs), fetches)
+	}
+
+	delay := 10*time.Minute + 30*time.Second
+	if !reflect.DeepEqual(delays, []time.Duration{delay, delay}) {
+		t.Errorf("delays = %v, want interval plus jitter %v", delays, delay)
+	}
+
+	if next := runs[1].NextRunAt; !runs[1].StartedAt.Equal(start.Add(delay)) || next == nil || !next.Equal(start.Add(2*delay)) {
+		t.Errorf("second run started %v with next run %v, want %v and %v", runs[1].StartedAt, next, start.Add(delay), start.Add(2*delay))
+	}
+
+	if store.Run == nil || store.Run.NextRunAt != nil || !store.Run.StartedAt.Equal(runs[1].StartedAt) {
+		t.Errorf("expected the last run to be recorded with nothing scheduled, got %+v", store.Run)
+	}
+}
+
+func TestFetchScheduler_RunOnceWithBareRepos(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	projectsRoot := filepath.Join(base, "projects")
+	upstream := filepath.Join(base, "upstream")
+
+	createRepoWithCommit(t, upstream)
+	mustMkdir(t, projectsRoot)
+	runGit(t, projectsRoot, "clone", "--bare", "--quiet", upstream, "repo")
+
+	// Add a commit upstream that only a fetch brings into the canonical repository
+	runGit(t, upstream, "commit", "--allow-empty", "-m", "second")
+	head := runGitOutput(t, upstream, "rev-parse", "HEAD")
+
+	cfg := &config.Config{
+		ProjectsRoot:   projectsRoot,
+		WorkspacesRoot: filepath.Join(base, "workspaces"),
+		ClosedRoot:     filepath.Join(base, "closed"),
+	}
+	store := storage.NewFetchStatusFile(projectsRoot)
+	svc := NewService(cfg, gitx.New(projectsRoot), storage.New(cfg.WorkspacesRoot, cfg.ClosedRoot), nil, WithFetchStatusStore(store))
+
+	run, err := NewFetchScheduler(svc, time.Hour, 0).RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	if run.Count(domain.FetchOutcomeFetched) != 1 {
+		t.Fatalf("expected the repository to be fetched, got %+v", run.Repos)
+	}
+
+	if got := runGitOutput(t, filepath.Join(projectsRoot, "repo"), "rev-parse", "refs/remotes/origin/main"); got != head {
+		t.Errorf("origin/main = %s, want %s", got, head)
+	}
+
+	recorded, err := svc.LastFetchRun()
+	if err != nil || recorded == nil || recorded.Summary() != "1 fetched" {
+		t.Errorf("LastFetchRun = %+v, %v", recorded, err)
+	}
+}
@@ -0,0 +1,208 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// GCPlan lists the workspaces garbage collection would close.
+type GCPlan struct {
+	Candidates []domain.GCCandidate
+	// Skipped lists workspaces whose git state could not be read.
+	Skipped []BulkWorkspaceResult
+}
+
+// gcEvaluation is the git state of one workspace, as far as the criteria are concerned.
+type gcEvaluation struct {
+	allMerged  bool
+	allDeleted bool
+	blocked    []string
+	err        error
+}
+
+// PlanGC finds the workspaces matching any of the criteria: stale workspaces, and workspaces
+// whose branch was pushed and then merged upstream, or deleted from its push remote, in every
+// repository.
+// Empty criteria fall back to the gc config, then to all criteria. Workspaces carrying an
+// excluded label are ignored. Candidates with uncommitted changes or commits that exist only
+// locally are marked blocked.
+func (s *Service) PlanGC(ctx context.Context, criteria []domain.GCReason) (*GCPlan, error) {
+	policy := s.config.GetGC()
+
+	if len(criteria) == 0 {
+		criteria = policy.Criteria
+	}
+
+	if len(criteria) == 0 {
+		criteria = domain.GCReasons()
+	}
+
+	workspaces, err := s.ListWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces = slices.DeleteFunc(workspaces, func(ws domain.Workspace) bool {
+		return slices.ContainsFunc(policy.ExcludeLabels, func(label string) bool { return ws.HasLabels(label) })
+	})
+
+	slices.SortFunc(workspaces, func(a, b domain.Workspace) int { return strings.Compare(a.ID, b.ID) })
+
+	// Resolve base branches once, before the workspaces are read in parallel
+	bases := make([][]string, len(workspaces))
+	baseErrs := make([]error, len(workspaces))
+
+	for i := range workspaces {
+		bases[i], baseErrs[i] = s.resolveDefaultBranches(&workspaces[i])
+	}
+
+	checkRemote := slices.Contains(criteria, domain.GCReasonRemoteDeleted)
+	executor := NewParallelExecutor(s.config.GetParallelWorkers())
+
+	results, err := ParallelMap(ctx, executor, len(workspaces), func(runCtx context.Context, index int) (gcEvaluation, error) {
+		if baseErrs[index] != nil {
+			return gcEvaluation{err: baseErrs[index]}, nil
+		}
+
+		return s.evaluateGC(runCtx, workspaces[index], bases[index], checkRemote), nil
+	}, ParallelOptions{ContinueOnError: true})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &GCPlan{}
+
+	for i, res := range ExtractValues(results) {
+		var candidate *domain.GCCandidate
+		if res.err == nil {
+			candidate, res.err = s.gcCandidate(ctx, workspaces[i], res, criteria)
+		}
+
+		switch {
+		case res.err != nil:
+			plan.Skipped = append(plan.Skipped, BulkWorkspaceResult{WorkspaceID: workspaces[i].ID, Err: res.err})
+		case candidate != nil:
+			plan.Candidates = append(plan.Candidates, *candidate)
+		}
+	}
+
+	return plan, nil
+}
+
+// resolveDefaultBranches returns the default branch of each repo of the workspace.
+func (s *Service) resolveDefaultBranches(ws *domain.Workspace) ([]string, error) {
+	bases := make([]string, len(ws.Repos))
+
+	for i, repo := range ws.Repos {
+		base, err := s.resolveDefaultBranch(ws, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		bases[i] = base
+	}
+
+	return bases, nil
+}
+
+// evaluateGC reads the git state of every repo of the workspace against its base branch.
+func (s *Service) evaluateGC(ctx context.Context, ws domain.Workspace, bases []string, checkRemote bool) gcEvaluation {
+	eval := gcEvaluation{allMerged: len(ws.Repos) > 0, allDeleted: len(ws.Repos) > 0}
+
+	for i, repo := range ws.Repos {
+		path := filepath.Join(s.config.GetWorkspacesRoot(), ws.DirName, repo.Name)
+
+		isDirty, unpushed, _, branch, err := s.gitEngine.Status(ctx, path)
+		if err != nil {
+			return gcEvaluation{err: err}
+		}
+
+		// A detached HEAD or the default branch itself is never merged or deleted upstream
+		if branch == "HEAD" || branch == bases[i] {
+			eval.allMerged, eval.allDeleted = false, false
+		} else {
+			state, err := s.gitEngine.BranchMergeState(ctx, path, branch, ports.BranchMergeOptions{
+				Base:        bases[i],
+				BaseRemote:  repo.SyncRemote(),
+				PushRemote:  repo.PushRemote,
+				CheckRemote: checkRemote,
+			})
+			if err != nil {
+				return gcEvaluation{err: err}
+			}
+
+			eval.allMerged = eval.allMerged && state.Published && state.Merged
+			eval.allDeleted = eval.allDeleted && state.RemoteDeleted
+
+			if state.OwnCommits && !state.Published && !state.Merged {
+				eval.blocked = append(eval.blocked, repo.Name+": commits never pushed")
+			}
+		}
+
+		if isDirty {
+			eval.blocked = append(eval.blocked, repo.Name+": uncommitted changes")
+		}
+
+		if unpushed > 0 {
+			eval.blocked = append(eval.blocked, fmt.Sprintf("%s: %d unpushed commits", repo.Name, unpushed))
+		}
+	}
+
+	return eval
+}
+
+// gcCandidate returns the workspace as a candidate when it matches a criterion, or nil.
+func (s *Service) gcCandidate(ctx context.Context, ws domain.Workspace, eval gcEvaluation, criteria []domain.GCReason) (*domain.GCCandidate, error) {
+	var reasons []domain.GCReason
+
+	for _, criterion := range criteria {
+		switch {
+		case criterion == domain.GCReasonStale && ws.IsStale(s.config.GetStaleThresholdDays()),
+			criterion == domain.GCReasonMerged && eval.allMerged,
+			criterion == domain.GCReasonRemoteDeleted && eval.allDeleted:
+			reasons = append(reasons, criterion)
+		}
+	}
+
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	preview, err := s.PreviewCloseWorkspace(ctx, ws.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.GCCandidate{
+		WorkspaceID: ws.ID,
+		Reasons:     reasons,
+		Blocked:     strings.Join(eval.blocked, "; "),
+		Preview:     preview,
+	}, nil
+}
+
+// CollectGarbage closes the plan's candidates, keeping their metadata so they can be reopened.
+// Blocked candidates are left alone unless force is set, in which case their uncommitted
+// changes and local commits are saved in the closed entry's archive.
+func (s *Service) CollectGarbage(ctx context.Context, plan *GCPlan, force bool) (*BulkCloseResult, error) {
+	var ids []string
+
+	for _, candidate := range plan.Candidates {
+		if candidate.Blocked == "" || force {
+			ids = append(ids, regexp.QuoteMeta(candidate.WorkspaceID))
+		}
+	}
+
+	if len(ids) == 0 {
+		return &BulkCloseResult{Results: []BulkWorkspaceResult{}}, nil
+	}
+
+	return s.CloseWorkspacesMatching(ctx, "^(?:"+strings.Join(ids, "|")+")$", force, true, CloseOptions{})
+}
@@ -0,0 +1,195 @@
+package workspaces
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+	"github.com/alexisbeaulieu97/canopy/internal/testutil"
+)
+
+func TestPlanGC(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.config.GC = config.GCConfig{ExcludeLabels: []string{"keep"}}
+
+	states := map[string]ports.BranchMergeState{
+		"ws-merged":  {Published: true, OwnCommits: true, Merged: true},
+		"ws-deleted": {Published: true, OwnCommits: true, RemoteDeleted: true},
+		"ws-active":  {Published: true, OwnCommits: true},
+		"ws-keep":    {Published: true, OwnCommits: true, Merged: true},
+		"ws-stale":   {OwnCommits: true, Merged: true},
+		"ws-local":   {OwnCommits: true},
+		"ws-fresh":   {},
+	}
+
+	for id := range states {
+		ws := domain.Workspace{
+			ID:         id,
+			DirName:    id,
+			BranchName: "feature",
+			Repos:      []domain.Repo{{Name: "backend", URL: "git@example.com:backend.git"}},
+		}
+		if id == "ws-keep" {
+			ws.Labels = []string{"keep"}
+		}
+
+		// Published to a fork rather than origin
+		if id == "ws-merged" {
+			ws.Repos[0].Remotes = []domain.Remote{{Name: "fork", URL: "git@example.com:me/backend.git"}}
+			ws.Repos[0].PushRemote = "fork"
+		}
+
+		// Cloned from a fork, merged into the repository it was forked from
+		if id == "ws-deleted" {
+			ws.Repos[0].Remotes = []domain.Remote{{Name: "upstream", URL: "git@example.com:upstream/backend.git"}}
+		}
+
+		addWorkspaceFixture(deps.storage, ws)
+		testutil.MustMkdir(t, filepath.Join(deps.config.WorkspacesRoot, id, "backend"))
+	}
+
+	workspaceOf := func(path string) string {
+		return filepath.Base(filepath.Dir(path))
+	}
+
+	deps.git.StatusFunc = func(_ context.Context, path string) (bool, int, int, string, error) {
+		return workspaceOf(path) == "ws-deleted", 0, 0, "feature", nil
+	}
+	deps.git.BranchMergeStateFunc = func(_ context.Context, path, branch string, opts ports.BranchMergeOptions) (*ports.BranchMergeState, error) {
+		want := ports.BranchMergeOptions{Base: "main", BaseRemote: "origin", CheckRemote: true}
+		switch workspaceOf(path) {
+		case "ws-merged":
+			want.PushRemote = "fork"
+		case "ws-deleted":
+			want.BaseRemote = "upstream"
+		}
+
+		if branch != "feature" || opts != want {
+			t.Errorf("unexpected BranchMergeState(%s, %+v), want %+v", branch, opts, want)
+		}
+
+		state := states[workspaceOf(path)]
+
+		return &state, nil
+	}
+	deps.disk.CachedUsageFunc = func(root string) (int64, time.Time, error) {
+		if name := filepath.Base(root); name == "ws-stale" || name == "ws-local" || name == "ws-fresh" {
+			return 0, time.Now().AddDate(0, 0, -30), nil
+		}
+
+		return 0, time.Now(), nil
+	}
+
+	plan, err := deps.svc.PlanGC(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("PlanGC failed: %v", err)
+	}
+
+	got := map[string][]domain.GCReason{}
+	blocked := map[string]string{}
+
+	for _, candidate := range plan.Candidates {
+		got[candidate.WorkspaceID] = candidate.Reasons
+		blocked[candidate.WorkspaceID] = candidate.Blocked
+
+		if candidate.Preview == nil || !candidate.Preview.KeepMetadata {
+			t.Errorf("expected an archive preview for %s, got %+v", candidate.WorkspaceID, candidate.Preview)
+		}
+	}
+
+	want := map[string][]domain.GCReason{
+		"ws-merged":  {domain.GCReasonMerged},
+		"ws-deleted": {domain.GCReasonRemoteDeleted},
+		"ws-stale":   {domain.GCReasonStale},
+		"ws-local":   {domain.GCReasonStale},
+		"ws-fresh":   {domain.GCReasonStale},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+
+	if blocked["ws-merged"] != "" || blocked["ws-stale"] != "" || blocked["ws-fresh"] != "" {
+		t.Errorf("expected clean candidates to be unblocked, got %v", blocked)
+	}
+
+	if !strings.Contains(blocked["ws-deleted"], "uncommitted changes") || !strings.Contains(blocked["ws-local"], "never pushed") {
+		t.Errorf("expected dirty and local-only candidates to be blocked, got %v", blocked)
+	}
+
+	result, err := deps.svc.CollectGarbage(context.Background(), plan, false)
+	if err != nil {
+		t.Fatalf("CollectGarbage failed: %v", err)
+	}
+
+	var closed []string
+	for _, res := range result.Results {
+		if res.Err != nil {
+			t.Errorf("closing %s failed: %v", res.WorkspaceID, res.Err)
+		}
+
+		closed = append(closed, res.WorkspaceID)
+	}
+
+	if !reflect.DeepEqual(closed, []string{"ws-fresh", "ws-merged", "ws-stale"}) {
+		t.Errorf("expected only unblocked candidates to be closed, got %v", closed)
+	}
+
+	for _, id := range []string{"ws-deleted", "ws-local", "ws-active", "ws-keep"} {
+		if _, ok := deps.storage.Workspaces[id]; !ok {
+			t.Errorf("expected %s to be kept", id)
+		}
+	}
+}
+
+func TestPlanGC_Criteria(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.config.GC = config.GCConfig{Criteria: []domain.GCReason{domain.GCReasonMerged}}
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-1",
+		DirName: "ws-1",
+		Repos:   []domain.Repo{{Name: "backend", URL: "git@example.com:backend.git"}},
+	})
+
+	deps.git.StatusFunc = func(_ context.Context, _ string) (bool, int, int, string, error) {
+		return false, 0, 0, "feature", nil
+	}
+	deps.git.BranchMergeStateFunc = func(_ context.Context, _, _ string, opts ports.BranchMergeOptions) (*ports.BranchMergeState, error) {
+		if opts.CheckRemote {
+			t.Error("expected no remote check without the remote_deleted criterion")
+		}
+
+		return &ports.BranchMergeState{Published: true, RemoteDeleted: opts.CheckRemote}, nil
+	}
+	deps.disk.CachedUsageFunc = func(_ string) (int64, time.Time, error) {
+		return 0, time.Now().AddDate(0, 0, -30), nil
+	}
+
+	// Stale, but the policy only collects merged workspaces
+	plan, err := deps.svc.PlanGC(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("PlanGC failed: %v", err)
+	}
+
+	if len(plan.Candidates) != 0 {
+		t.Errorf("expected no candidates, got %+v", plan.Candidates)
+	}
+
+	plan, err = deps.svc.PlanGC(context.Background(), []domain.GCReason{domain.GCReasonStale})
+	if err != nil {
+		t.Fatalf("PlanGC failed: %v", err)
+	}
+
+	if len(plan.Candidates) != 1 || plan.Candidates[0].Reasons[0] != domain.GCReasonStale {
+		t.Errorf("expected explicit criteria to override the policy, got %+v", plan.Candidates)
+	}
+}
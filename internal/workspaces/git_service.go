@@ -17,11 +17,20 @@ type GitService interface {
 	// PushWorkspace pushes all repos for a workspace.
 	PushWorkspace(ctx context.Context, workspaceID string) error
 
+	// PushWorkspaceWithOptions pushes all repos for a workspace and reports the outcome per repo.
+	PushWorkspaceWithOptions(ctx context.Context, workspaceID string, opts PushOptions) (*domain.PushResult, error)
+
 	// RunGitInWorkspace executes an arbitrary git command across all repos in a workspace.
 	RunGitInWorkspace(ctx context.Context, workspaceID string, args []string, opts GitRunOptions) ([]RepoGitResult, error)
 
 	// SwitchBranch switches the branch for all repos in a workspace.
 	SwitchBranch(ctx context.Context, workspaceID, branchName string, create bool) error
+
+	// SnapshotWorkspace records a named checkpoint of all repos in a workspace.
+	SnapshotWorkspace(ctx context.Context, workspaceID, name string) (*domain.Snapshot, error)
+
+	// RollbackWorkspace restores all repos in a workspace to a snapshot.
+	RollbackWorkspace(ctx context.Context, workspaceID, name string, force bool) (*domain.Snapshot, error)
 }
 
 // WorkspaceFinder is the interface for finding workspaces (used to avoid circular dependencies).
@@ -60,32 +69,9 @@ func NewGitService(
 
 // PushWorkspace pushes all repos for a workspace.
 func (s *WorkspaceGitService) PushWorkspace(ctx context.Context, workspaceID string) error {
-	targetWorkspace, dirName, err := s.workspaceFinder.FindWorkspace(ctx, workspaceID)
-	if err != nil {
-		return err
-	}
-
-	for _, repo := range targetWorkspace.Repos {
-		// Check for context cancellation before each push
-		if ctx.Err() != nil {
-			return cerrors.NewContextError(ctx, "push workspace", workspaceID)
-		}
-
-		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
-		branchName := targetWorkspace.BranchName
-
-		if branchName == "" {
-			if s.logger != nil {
-				s.logger.Debug("Branch missing in metadata, will let git infer", "workspace", workspaceID, "repo", repo.Name)
-			}
-		}
+	_, err := s.PushWorkspaceWithOptions(ctx, workspaceID, PushOptions{})
 
-		if err := s.gitEngine.Push(ctx, worktreePath, branchName); err != nil {
-			return cerrors.WrapGitError(err, fmt.Sprintf("push repo %s", repo.Name))
-		}
-	}
-
-	return nil
+	return err
 }
 
 // RunGitInWorkspace executes an arbitrary git command across all repos in a workspace.
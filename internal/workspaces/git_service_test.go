@@ -126,7 +126,7 @@ func TestWorkspaceGitService_PushWorkspace(t *testing.T) {
 
 			pushCount := 0
 			mockGit := mocks.NewMockGitOperations()
-			mockGit.PushFunc = func(_ context.Context, _, _ string) error {
+			mockGit.PushFunc = func(_ context.Context, _, _ string, _ ports.PushOptions) error {
 				pushCount++
 				return tt.pushErr
 			}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
 )
 
@@ -27,3 +29,26 @@ func isCanopyError(err error, target **cerrors.CanopyError) bool {
 func isDeadlineExceeded(err error) bool {
 	return errors.Is(err, context.DeadlineExceeded)
 }
+
+// cloneOptionsForRepo returns the clone options of the repo's registry entry, if any.
+func (s *Service) cloneOptionsForRepo(repo domain.Repo) domain.CloneOptions {
+	entry, _ := s.registryEntryForRepo(repo)
+
+	return entry.CloneOptions()
+}
+
+// registryEntryForRepo looks up the registry entry for a workspace repo by URL, then by alias.
+func (s *Service) registryEntryForRepo(repo domain.Repo) (config.RegistryEntry, bool) {
+	registry := s.config.GetRegistry()
+	if registry == nil {
+		return config.RegistryEntry{}, false
+	}
+
+	if repo.URL != "" {
+		if entry, ok := registry.ResolveByURL(repo.URL); ok {
+			return entry, true
+		}
+	}
+
+	return registry.Resolve(repo.Name)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/alexisbeaulieu97/canopy/internal/config"
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/hooks"
 	"github.com/alexisbeaulieu97/canopy/internal/ports"
 )
 
@@ -19,8 +20,108 @@ const (
 	HookPhasePostCreate HookPhase = "post_create"
 	// HookPhasePreClose executes pre_close hooks.
 	HookPhasePreClose HookPhase = "pre_close"
+	// HookPhasePostSync executes post_sync hooks.
+	HookPhasePostSync HookPhase = "post_sync"
+	// HookPhasePrePush executes pre_push hooks.
+	HookPhasePrePush HookPhase = "pre_push"
+	// HookPhasePostPush executes post_push hooks.
+	HookPhasePostPush HookPhase = "post_push"
+	// HookPhasePostRename executes post_rename hooks.
+	HookPhasePostRename HookPhase = "post_rename"
+	// HookPhasePostRestore executes post_restore hooks.
+	HookPhasePostRestore HookPhase = "post_restore"
+	// HookPhasePostRepoAdd executes post_repo_add hooks.
+	HookPhasePostRepoAdd HookPhase = "post_repo_add"
 )
 
+// newHookRunner adapts a ParallelExecutor for parallel hooks and hook dependency graphs.
+// Every task runs to completion; the hook executor decides how failures propagate.
+func newHookRunner(workers int) hooks.ParallelRunner {
+	executor := NewParallelExecutor(workers)
+
+	return func(ctx context.Context, total int, fn func(ctx context.Context, index int) error) error {
+		return executor.Run(ctx, total, fn, ParallelOptions{ContinueOnError: true})
+	}
+}
+
+// HookPhases returns all supported lifecycle hook phases.
+func HookPhases() []HookPhase {
+	return []HookPhase{
+		HookPhasePostCreate,
+		HookPhasePreClose,
+		HookPhasePostSync,
+		HookPhasePrePush,
+		HookPhasePostPush,
+		HookPhasePostRename,
+		HookPhasePostRestore,
+		HookPhasePostRepoAdd,
+	}
+}
+
+// newHookContext builds the hook context for a workspace, including registry tags for its repos.
+func (s *Service) newHookContext(workspaceID, dirName, branchName string, repos []domain.Repo) domain.HookContext {
+	hookCtx := domain.HookContext{
+		WorkspaceID:   workspaceID,
+		WorkspacePath: filepath.Join(s.config.GetWorkspacesRoot(), dirName),
+		BranchName:    branchName,
+		Repos:         repos,
+	}
+
+	for _, repo := range repos {
+		if entry, ok := s.registryEntryForRepo(repo); ok && len(entry.Tags) > 0 {
+			if hookCtx.RepoTags == nil {
+				hookCtx.RepoTags = make(map[string][]string)
+			}
+
+			hookCtx.RepoTags[repo.Name] = entry.Tags
+		}
+	}
+
+	return hookCtx
+}
+
+// hooksForPhase returns the configured hooks for a lifecycle phase.
+func hooksForPhase(hooksConfig config.Hooks, phase HookPhase) ([]config.Hook, error) {
+	selected, ok := hooksConfig.ForPhase(string(phase))
+	if !ok {
+		return nil, cerrors.NewInvalidArgument("hook_phase", fmt.Sprintf("unsupported hook phase %q", phase))
+	}
+
+	return selected, nil
+}
+
+// runPhaseHooks executes the hooks of a lifecycle phase for a workspace.
+// Failures are returned unless continueOnError is set; the operation that
+// triggered the phase is never rolled back.
+//
+//nolint:contextcheck // Hooks manage their own timeout context per-hook
+func (s *Service) runPhaseHooks(phase HookPhase, workspace domain.Workspace, dirName string, continueOnError bool) error {
+	selected, err := hooksForPhase(s.config.GetHooks(), phase)
+	if err != nil {
+		return err
+	}
+
+	if len(selected) == 0 {
+		return nil
+	}
+
+	hookCtx := s.newHookContext(workspace.ID, dirName, workspace.BranchName, workspace.Repos)
+
+	if _, err := s.hookExecutor.ExecuteHooks(selected, hookCtx, ports.HookExecuteOptions{
+		ContinueOnError: continueOnError,
+	}); err != nil {
+		if s.logger != nil {
+			s.logger.Error(fmt.Sprintf("%s hooks failed", phase), "error", err)
+		}
+
+		if !continueOnError {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // RunHooks executes lifecycle hooks for an existing workspace without performing other actions.
 //
 //nolint:contextcheck // Hooks manage their own timeout context per-hook
@@ -30,29 +131,16 @@ func (s *Service) RunHooks(ctx context.Context, workspaceID string, phase HookPh
 		return err
 	}
 
-	hooksConfig := s.config.GetHooks()
-
-	var selected []config.Hook
-
-	switch phase {
-	case HookPhasePostCreate:
-		selected = hooksConfig.PostCreate
-	case HookPhasePreClose:
-		selected = hooksConfig.PreClose
-	default:
-		return cerrors.NewInvalidArgument("hook_phase", fmt.Sprintf("unsupported hook phase %q", phase))
+	selected, err := hooksForPhase(s.config.GetHooks(), phase)
+	if err != nil {
+		return err
 	}
 
 	if len(selected) == 0 {
 		return nil
 	}
 
-	hookCtx := domain.HookContext{
-		WorkspaceID:   workspaceID,
-		WorkspacePath: filepath.Join(s.config.GetWorkspacesRoot(), dirName),
-		BranchName:    workspace.BranchName,
-		Repos:         workspace.Repos,
-	}
+	hookCtx := s.newHookContext(workspaceID, dirName, workspace.BranchName, workspace.Repos)
 
 	if _, err := s.hookExecutor.ExecuteHooks(selected, hookCtx, ports.HookExecuteOptions{
 		ContinueOnError: continueOnError,
@@ -78,31 +166,18 @@ func (s *Service) PreviewHooks(ctx context.Context, workspaceID string, phase Ho
 		return nil, err
 	}
 
-	hooksConfig := s.config.GetHooks()
-
-	var selected []config.Hook
-
-	switch phase {
-	case HookPhasePostCreate:
-		selected = hooksConfig.PostCreate
-	case HookPhasePreClose:
-		selected = hooksConfig.PreClose
-	default:
-		return nil, cerrors.NewInvalidArgument("hook_phase", fmt.Sprintf("unsupported hook phase %q", phase))
+	selected, err := hooksForPhase(s.config.GetHooks(), phase)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(selected) == 0 {
 		return nil, nil
 	}
 
-	hookCtx := domain.HookContext{
-		WorkspaceID:   workspaceID,
-		WorkspacePath: filepath.Join(s.config.GetWorkspacesRoot(), dirName),
-		BranchName:    workspace.BranchName,
-		Repos:         workspace.Repos,
-	}
+	hookCtx := s.newHookContext(workspaceID, dirName, workspace.BranchName, workspace.Repos)
 
-	previews, err := s.hookExecutor.ExecuteHooks(selected, hookCtx, ports.HookExecuteOptions{
+	results, err := s.hookExecutor.ExecuteHooks(selected, hookCtx, ports.HookExecuteOptions{
 		DryRun: true,
 	})
 	if err != nil {
@@ -113,5 +188,5 @@ func (s *Service) PreviewHooks(ctx context.Context, workspaceID string, phase Ho
 		return nil, err
 	}
 
-	return previews, nil
+	return domain.HookPreviews(results), nil
 }
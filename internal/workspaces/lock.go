@@ -17,6 +17,9 @@ import (
 
 const lockFileName = ".canopy.lock"
 
+// errLockExists reports that another operation already holds the lock file.
+var errLockExists = errors.New("lock file exists")
+
 // LockManager manages workspace-level file locks.
 type LockManager struct {
 	root           string
@@ -71,26 +74,9 @@ func (m *LockManager) Acquire(ctx context.Context, workspaceID string, createDir
 			return nil, cerrors.NewContextError(ctx, "acquire lock", workspaceID)
 		}
 
-		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec // lockPath is derived from workspace root and ID
-		if err == nil {
-			handle := &LockHandle{
-				workspaceID: workspaceID,
-				path:        lockPath,
-				file:        file,
-				logger:      m.logger,
-			}
-
-			handle.startHeartbeat(m.staleThreshold, m.now)
-
-			if m.logger != nil {
-				m.logger.Debug("workspace lock acquired", "workspace_id", workspaceID, "path", lockPath)
-			}
-
-			return handle, nil
-		}
-
-		if !errors.Is(err, os.ErrExist) {
-			return nil, cerrors.NewIOFailed(fmt.Sprintf("acquire lock %s", lockPath), err)
+		handle, err := m.createLock(lockPath, workspaceID)
+		if !errors.Is(err, errLockExists) {
+			return handle, err
 		}
 
 		stale, staleErr := m.removeIfStale(lockPath)
@@ -110,6 +96,58 @@ func (m *LockManager) Acquire(ctx context.Context, workspaceID string, createDir
 	}
 }
 
+// TryAcquire obtains an exclusive lock for an existing workspace without waiting, and fails
+// with ErrWorkspaceLocked when another operation holds it.
+func (m *LockManager) TryAcquire(workspaceID string) (*LockHandle, error) {
+	lockPath, err := m.lockPath(workspaceID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		handle, err := m.createLock(lockPath, workspaceID)
+		if !errors.Is(err, errLockExists) {
+			return handle, err
+		}
+
+		stale, staleErr := m.removeIfStale(lockPath)
+		if staleErr != nil {
+			return nil, staleErr
+		}
+
+		if !stale {
+			return nil, cerrors.NewWorkspaceLocked(workspaceID)
+		}
+	}
+}
+
+// createLock creates the lock file, failing with errLockExists when it already exists.
+func (m *LockManager) createLock(lockPath, workspaceID string) (*LockHandle, error) {
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec // lockPath is derived from workspace root and ID
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, errLockExists
+		}
+
+		return nil, cerrors.NewIOFailed(fmt.Sprintf("acquire lock %s", lockPath), err)
+	}
+
+	handle := &LockHandle{
+		workspaceID: workspaceID,
+		path:        lockPath,
+		file:        file,
+		logger:      m.logger,
+	}
+
+	handle.startHeartbeat(m.staleThreshold, m.now)
+
+	if m.logger != nil {
+		m.logger.Debug("workspace lock acquired", "workspace_id", workspaceID, "path", lockPath)
+	}
+
+	return handle, nil
+}
+
 // IsLocked reports whether a non-stale lock exists for the workspace.
 func (m *LockManager) IsLocked(workspaceID string) (bool, error) {
 	lockPath, err := m.lockPath(workspaceID, false)
@@ -60,6 +60,42 @@ func TestLockManagerTimeout(t *testing.T) {
 	}
 }
 
+func TestLockManagerTryAcquire(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	lm := NewLockManager(root, time.Minute, time.Minute, nil, nil)
+
+	if _, err := lm.TryAcquire("WS-1"); !errors.Is(err, cerrors.WorkspaceNotFound) {
+		t.Fatalf("expected ErrWorkspaceNotFound without a workspace directory, got %v", err)
+	}
+
+	handle, err := lm.Acquire(context.Background(), "WS-1", true)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+
+	_, err = lm.TryAcquire("WS-1")
+
+	var canopyErr *cerrors.CanopyError
+	if !errors.As(err, &canopyErr) || canopyErr.Code != cerrors.ErrWorkspaceLocked {
+		t.Fatalf("expected ErrWorkspaceLocked without waiting, got %v", err)
+	}
+
+	if err := handle.Release(); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+
+	handle, err = lm.TryAcquire("WS-1")
+	if err != nil {
+		t.Fatalf("TryAcquire after release failed: %v", err)
+	}
+
+	if err := handle.Release(); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+}
+
 func TestLockManagerStaleCleanup(t *testing.T) {
 	t.Parallel()
 
@@ -3,12 +3,21 @@ package workspaces
 import (
 	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alexisbeaulieu97/canopy/internal/config"
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/mocks"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+	"github.com/alexisbeaulieu97/canopy/internal/testutil"
 )
 
 func TestCreateWorkspace_UsesTemplateDefaultBranch(t *testing.T) {
@@ -39,6 +48,119 @@ func TestCreateWorkspace_UsesTemplateDefaultBranch(t *testing.T) {
 	}
 }
 
+func TestCreateWorkspace_NamesBranchAfterIssue(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.config.IssueTracker = config.IssueTrackerConfig{Type: config.IssueTrackerJira, BranchNaming: "{{.ID}}-{{slug .Title}}"}
+
+	issue, err := deps.svc.LookupIssue(context.Background(), "PROJ-123")
+	if err != nil || issue != nil {
+		t.Fatalf("expected no issue without a tracker, got %+v, %v", issue, err)
+	}
+
+	issue = &domain.Issue{ID: "PROJ-123", Title: "Fix login redirect", URL: "https://example.atlassian.net/browse/PROJ-123"}
+
+	if _, err := deps.svc.CreateWorkspaceWithOptions(context.Background(), "PROJ-123", "", nil, CreateOptions{Issue: issue}); err != nil {
+		t.Fatalf("CreateWorkspaceWithOptions failed: %v", err)
+	}
+
+	ws := deps.storage.Workspaces["PROJ-123"]
+	if ws.BranchName != "PROJ-123-fix-login-redirect" {
+		t.Errorf("expected branch named after the issue, got %q", ws.BranchName)
+	}
+
+	if ws.Issue == nil || ws.Issue.Title != issue.Title || ws.Issue.URL != issue.URL {
+		t.Errorf("expected issue in metadata, got %+v", ws.Issue)
+	}
+
+	// An explicit branch wins over the template
+	if _, err := deps.svc.CreateWorkspaceWithOptions(context.Background(), "PROJ-124", "custom", nil, CreateOptions{Issue: issue}); err != nil {
+		t.Fatalf("CreateWorkspaceWithOptions failed: %v", err)
+	}
+
+	if got := deps.storage.Workspaces["PROJ-124"].BranchName; got != "custom" {
+		t.Errorf("expected explicit branch, got %q", got)
+	}
+}
+
+func TestAnnotateWorkspace(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+
+	opts := CreateOptions{Description: "Fix login redirect", Labels: []string{"auth", " auth", "urgent"}}
+	if _, err := deps.svc.CreateWorkspaceWithOptions(context.Background(), "PROJ-1", "", nil, opts); err != nil {
+		t.Fatalf("CreateWorkspaceWithOptions failed: %v", err)
+	}
+
+	if ws := deps.storage.Workspaces["PROJ-1"]; ws.Description != "Fix login redirect" || !reflect.DeepEqual(ws.Labels, []string{"auth", "urgent"}) {
+		t.Fatalf("expected description and de-duplicated labels, got %q %v", ws.Description, ws.Labels)
+	}
+
+	notes := "Blocked on API review"
+	ws, err := deps.svc.AnnotateWorkspace(context.Background(), "PROJ-1", AnnotateOptions{
+		AddLabels:    []string{"backend"},
+		RemoveLabels: []string{"urgent"},
+		Notes:        &notes,
+		AppendNote:   "Unblocked",
+	})
+	if err != nil {
+		t.Fatalf("AnnotateWorkspace failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(ws.Labels, []string{"auth", "backend"}) || ws.Notes != "Blocked on API review\nUnblocked" {
+		t.Errorf("unexpected annotations: labels %v, notes %q", ws.Labels, ws.Notes)
+	}
+
+	if stored := deps.storage.Workspaces["PROJ-1"]; stored.Description != "Fix login redirect" || stored.Notes != ws.Notes {
+		t.Errorf("expected annotations saved without touching the description, got %+v", stored)
+	}
+
+	if _, err := deps.svc.AnnotateWorkspace(context.Background(), "PROJ-1", AnnotateOptions{AddLabels: []string{"needs review"}}); err == nil {
+		t.Error("expected invalid label to be rejected")
+	}
+}
+
+func TestCreateWorkspace_BranchNamingTemplates(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.config.BranchNaming = "{{.Vars.team}}/{{slug .User}}/{{.ID}}"
+	deps.config.BranchVars = map[string]string{"team": "core"}
+	deps.git.UserNameFunc = func() (string, error) { return "Jane Doe", nil }
+
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "PROJ-1", "", nil); err != nil {
+		t.Fatalf("CreateWorkspace failed: %v", err)
+	}
+
+	if got := deps.storage.Workspaces["PROJ-1"].BranchName; got != "core/jane-doe/PROJ-1" {
+		t.Errorf("expected branch from branch_naming, got %q", got)
+	}
+
+	// A template's branch_naming and variables override the global ones
+	tmpl := &config.Template{Name: "hotfix", BranchNaming: "{{.Template}}/{{.Vars.team}}/{{.ID | lower}}", BranchVars: map[string]string{"team": "ops"}}
+
+	if _, err := deps.svc.CreateWorkspaceWithOptions(context.Background(), "PROJ-2", "", nil, CreateOptions{Template: tmpl}); err != nil {
+		t.Fatalf("CreateWorkspaceWithOptions failed: %v", err)
+	}
+
+	if got := deps.storage.Workspaces["PROJ-2"].BranchName; got != "hotfix/ops/proj-2" {
+		t.Errorf("expected branch from template branch_naming, got %q", got)
+	}
+
+	// A template's default_branch is used as-is
+	tmpl = &config.Template{Name: "release", DefaultBranch: "release-next"}
+
+	if _, err := deps.svc.CreateWorkspaceWithOptions(context.Background(), "PROJ-3", "", nil, CreateOptions{Template: tmpl}); err != nil {
+		t.Fatalf("CreateWorkspaceWithOptions failed: %v", err)
+	}
+
+	if got := deps.storage.Workspaces["PROJ-3"].BranchName; got != "release-next" {
+		t.Errorf("expected template default branch, got %q", got)
+	}
+}
+
 func TestCloseWorkspaceKeepMetadata_ArchivesAndDeletes(t *testing.T) {
 	t.Parallel()
 
@@ -68,6 +190,42 @@ func TestCloseWorkspaceKeepMetadata_ArchivesAndDeletes(t *testing.T) {
 	}
 }
 
+func TestCloseWorkspaceKeepMetadata_ArchiveFailureRollsBack(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-1",
+		DirName: "ws-1",
+		Repos:   []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+	testutil.MustMkdir(t, filepath.Join(deps.config.WorkspacesRoot, "ws-1", "repo-1"))
+
+	deps.git.CreatePatchFunc = func(_ context.Context, _, _ string) (bool, error) {
+		return false, errors.New("disk full")
+	}
+
+	var deletedClosed bool
+
+	deps.storage.DeleteClosedFunc = func(_ context.Context, id string, _ time.Time) error {
+		deletedClosed = id == "ws-1"
+		return nil
+	}
+
+	_, err := deps.svc.CloseWorkspaceKeepMetadataWithOptions(context.Background(), "ws-1", true, CloseOptions{SkipHooks: true})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected archive error, got %v", err)
+	}
+
+	if !deletedClosed {
+		t.Error("expected closed entry to be rolled back")
+	}
+
+	if _, ok := deps.storage.Workspaces["ws-1"]; !ok {
+		t.Error("expected workspace to remain after failed archive")
+	}
+}
+
 func TestSyncWorkspace_AggregatesResults(t *testing.T) {
 	t.Parallel()
 
@@ -96,8 +254,8 @@ func TestSyncWorkspace_AggregatesResults(t *testing.T) {
 
 		return false, 0, 0, "main", nil
 	}
-	deps.git.PullFunc = func(_ context.Context, _ string) error {
-		return nil
+	deps.git.PullFunc = func(_ context.Context, _ string, _ ports.PullOptions) (*ports.PullResult, error) {
+		return &ports.PullResult{}, nil
 	}
 
 	result, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{Timeout: 5 * time.Second})
@@ -118,76 +276,942 @@ func TestSyncWorkspace_AggregatesResults(t *testing.T) {
 	}
 }
 
-func TestRenameWorkspace_RenamesBranchAndMetadata(t *testing.T) {
+func TestSyncWorkspace_ResolvesStrategy(t *testing.T) {
 	t.Parallel()
 
 	deps := newMockService(t)
 	addWorkspaceFixture(deps.storage, domain.Workspace{
-		ID:         "old-id",
-		DirName:    "old-id",
-		BranchName: "old-id",
-		Repos:      []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+		ID:       "ws-1",
+		DirName:  "ws-1",
+		Template: "backend",
+		Repos: []domain.Repo{
+			{Name: "registered", URL: "git@example.com:registered.git"},
+			{Name: "templated", URL: "git@example.com:templated.git"},
+		},
 	})
 
-	var renameCalls []string
+	deps.config.Registry = &config.RepoRegistry{Repos: map[string]config.RegistryEntry{
+		"registered": {URL: "git@example.com:registered.git", SyncStrategy: "rebase"},
+	}}
+	deps.config.Templates = map[string]config.Template{
+		"backend": {Repos: []string{"registered", "templated"}, SyncStrategy: "merge"},
+	}
+	deps.git.StatusFunc = func(_ context.Context, _ string) (bool, int, int, string, error) {
+		return true, 1, 1, "ws-1", nil
+	}
 
-	deps.git.RenameBranchFunc = func(_ context.Context, _, oldName, newName string) error {
-		renameCalls = append(renameCalls, oldName+"->"+newName)
-		return nil
+	var mu sync.Mutex
+
+	pulls := map[string]ports.PullOptions{}
+	deps.git.PullFunc = func(_ context.Context, path string, opts ports.PullOptions) (*ports.PullResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		pulls[filepath.Base(path)] = opts
+
+		return &ports.PullResult{}, nil
 	}
 
-	if err := deps.svc.RenameWorkspace(context.Background(), "old-id", "new-id", true, false); err != nil {
-		t.Fatalf("RenameWorkspace failed: %v", err)
+	if _, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{}); err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
 	}
 
-	ws, ok := deps.storage.Workspaces["new-id"]
-	if !ok {
-		t.Fatal("expected workspace to be renamed in storage")
+	if got := pulls["registered"]; got.Strategy != domain.SyncStrategyRebase || !got.Autostash {
+		t.Errorf("expected registry rebase strategy with autostash, got %+v", got)
 	}
 
-	if ws.BranchName != "new-id" {
-		t.Errorf("expected branch name new-id, got %q", ws.BranchName)
+	if got := pulls["templated"]; got.Strategy != domain.SyncStrategyMerge {
+		t.Errorf("expected template merge strategy, got %+v", got)
 	}
 
-	if len(renameCalls) != 1 {
-		t.Fatalf("expected 1 rename call, got %d", len(renameCalls))
+	if _, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{Strategy: domain.SyncStrategyFFOnly}); err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
+	}
+
+	for name, opts := range pulls {
+		if opts.Strategy != domain.SyncStrategyFFOnly {
+			t.Errorf("expected explicit ff-only strategy for %s, got %s", name, opts.Strategy)
+		}
 	}
 }
 
-func TestRestoreWorkspace_CreatesWorkspaceAndDeletesClosedEntry(t *testing.T) {
+func TestSyncWorkspace_FetchOnlySkipsPull(t *testing.T) {
 	t.Parallel()
 
 	deps := newMockService(t)
-	called := false
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-1",
+		DirName: "ws-1",
+		Repos:   []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
 
-	deps.storage.LatestClosedFunc = func(_ context.Context, id string) (*domain.ClosedWorkspace, error) {
-		if id != "ws-1" {
-			return nil, errors.New("unexpected id")
+	deps.git.StatusFunc = func(_ context.Context, _ string) (bool, int, int, string, error) {
+		return false, 0, 3, "ws-1", nil
+	}
+	deps.git.PullFunc = func(_ context.Context, _ string, _ ports.PullOptions) (*ports.PullResult, error) {
+		t.Error("pull should not run with fetch-only strategy")
+		return &ports.PullResult{}, nil
+	}
+
+	result, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{Strategy: domain.SyncStrategyFetchOnly})
+	if err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
+	}
+
+	if result.Repos[0].Status != domain.SyncStatusFetched || result.Repos[0].Updated != 3 {
+		t.Errorf("expected fetched status with 3 pending commits, got %+v", result.Repos[0])
+	}
+
+	if result.TotalUpdated != 0 || result.TotalErrors != 0 {
+		t.Errorf("expected no updates or errors, got %d/%d", result.TotalUpdated, result.TotalErrors)
+	}
+}
+
+func TestSyncWorkspace_ReportsConflicts(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-1",
+		DirName: "ws-1",
+		Repos:   []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+
+	deps.git.StatusFunc = func(_ context.Context, _ string) (bool, int, int, string, error) {
+		return false, 1, 1, "ws-1", nil
+	}
+	deps.git.PullFunc = func(_ context.Context, _ string, _ ports.PullOptions) (*ports.PullResult, error) {
+		return &ports.PullResult{Conflicts: []string{"main.go", "go.mod"}}, errors.New("rebase failed")
+	}
+
+	result, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{Strategy: domain.SyncStrategyRebase})
+	if err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
+	}
+
+	repo := result.Repos[0]
+	if repo.Status != domain.SyncStatusConflict {
+		t.Fatalf("expected conflict status, got %s", repo.Status)
+	}
+
+	if len(repo.Conflicts) != 2 || repo.Conflicts[0] != "main.go" {
+		t.Errorf("expected conflicting files to be reported, got %v", repo.Conflicts)
+	}
+
+	if result.TotalErrors != 1 {
+		t.Errorf("expected conflict to count as error, got %d", result.TotalErrors)
+	}
+}
+
+func TestSyncWorkspace_RejectsUnknownStrategy(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{ID: "ws-1", DirName: "ws-1"})
+
+	_, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{Strategy: "squash"})
+	if !errors.Is(err, cerrors.NewInvalidArgument("strategy", "")) {
+		t.Fatalf("expected invalid argument error, got %v", err)
+	}
+}
+
+func TestSyncWorkspace_OntoDefaultResolvesBranch(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:       "ws-1",
+		DirName:  "ws-1",
+		Template: "backend",
+		Repos: []domain.Repo{
+			{Name: "registered", URL: "git@example.com:registered.git"},
+			{Name: "templated", URL: "git@example.com:templated.git"},
+		},
+	})
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-2",
+		DirName: "ws-2",
+		Repos:   []domain.Repo{{Name: "remote-head", URL: "git@example.com:remote-head.git"}},
+	})
+
+	deps.config.Registry = &config.RepoRegistry{Repos: map[string]config.RegistryEntry{
+		"registered": {URL: "git@example.com:registered.git", DefaultBranch: "develop"},
+	}}
+	deps.config.Templates = map[string]config.Template{
+		"backend": {Repos: []string{"registered"}, DefaultBranch: "release"},
+	}
+	deps.git.DefaultBranchFunc = func(_, _ string) (string, error) {
+		return "trunk", nil
+	}
+	deps.git.CommitsBehindFunc = func(_ context.Context, _, _ string) (int, error) {
+		return 2, nil
+	}
+
+	var mu sync.Mutex
+
+	upstreams := map[string]string{}
+	deps.git.PullFunc = func(_ context.Context, path string, opts ports.PullOptions) (*ports.PullResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		upstreams[filepath.Base(path)] = opts.Upstream
+
+		return &ports.PullResult{}, nil
+	}
+
+	opts := SyncOptions{OntoDefault: true, Strategy: domain.SyncStrategyRebase}
+
+	result, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", opts)
+	if err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
+	}
+
+	if _, err := deps.svc.SyncWorkspace(context.Background(), "ws-2", opts); err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
+	}
+
+	want := map[string]string{
+		"registered":  "origin/develop",
+		"templated":   "origin/release",
+		"remote-head": "origin/trunk",
+	}
+
+	for name, upstream := range want {
+		if upstreams[name] != upstream {
+			t.Errorf("expected %s to integrate %s, got %q", name, upstream, upstreams[name])
 		}
+	}
 
-		return &domain.ClosedWorkspace{
-			DirName: "ws-1",
-			Path:    "closed/ws-1",
-			Metadata: domain.Workspace{
-				ID:         "ws-1",
-				BranchName: "main",
-			},
-		}, nil
+	for _, repo := range result.Repos {
+		if repo.Status != domain.SyncStatusUpdated || repo.Onto != want[repo.Name] {
+			t.Errorf("unexpected result for %s: %+v", repo.Name, repo)
+		}
 	}
-	deps.storage.DeleteClosedFunc = func(_ context.Context, _ string, _ time.Time) error {
-		called = true
-		return nil
+
+	if result.TotalUpdated != 4 {
+		t.Errorf("expected TotalUpdated 4, got %d", result.TotalUpdated)
 	}
+}
 
-	if err := deps.svc.RestoreWorkspace(context.Background(), "ws-1", false); err != nil {
-		t.Fatalf("RestoreWorkspace failed: %v", err)
+func TestSyncWorkspace_OntoDefaultUpToDate(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-1",
+		DirName: "ws-1",
+		Repos:   []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+
+	deps.git.PullFunc = func(_ context.Context, _ string, _ ports.PullOptions) (*ports.PullResult, error) {
+		t.Error("pull should not run when already up to date with the default branch")
+		return &ports.PullResult{}, nil
 	}
 
-	if !called {
-		t.Fatal("expected closed entry to be deleted")
+	result, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{OntoDefault: true})
+	if err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
 	}
 
-	if _, ok := deps.storage.Workspaces["ws-1"]; !ok {
-		t.Fatal("expected workspace to be restored")
+	if result.Repos[0].Status != domain.SyncStatusUpToDate || result.Repos[0].Onto != "origin/main" {
+		t.Errorf("expected up-to-date onto origin/main, got %+v", result.Repos[0])
+	}
+}
+
+func TestQueryWorkspaces_Filter(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	for _, id := range []string{"ws-clean", "ws-dirty", "ws-broken", "ws-other"} {
+		labels := []string{"team"}
+		if id == "ws-other" {
+			labels = nil
+		}
+
+		addWorkspaceFixture(deps.storage, domain.Workspace{
+			ID:      id,
+			DirName: id,
+			Labels:  labels,
+			Repos:   []domain.Repo{{Name: "backend", URL: "git@example.com:backend.git"}},
+		})
+	}
+
+	deps.git.StatusFunc = func(_ context.Context, path string) (bool, int, int, string, error) {
+		switch {
+		case strings.Contains(path, "ws-dirty"):
+			return true, 0, 0, "main", nil
+		case strings.Contains(path, "ws-broken"):
+			return false, 0, 0, "", errors.New("not a git repository")
+		default:
+			return false, 0, 0, "main", nil
+		}
+	}
+	deps.disk.CachedUsageFunc = func(root string) (int64, time.Time, error) {
+		if strings.HasSuffix(root, "ws-clean") {
+			return 0, time.Now().AddDate(0, 0, -30), nil
+		}
+
+		return 0, time.Now(), nil
+	}
+
+	result, err := deps.svc.QueryWorkspaces(context.Background(), WorkspaceQuery{Pattern: "^ws-", Filter: "label:team and not dirty"})
+	if err != nil {
+		t.Fatalf("QueryWorkspaces failed: %v", err)
+	}
+
+	// ws-broken is not reported clean when its status cannot be read
+	if len(result.Workspaces) != 1 || result.Workspaces[0].ID != "ws-clean" {
+		t.Errorf("expected only ws-clean, got %+v", result.Workspaces)
+	}
+
+	if len(result.Skipped) != 1 || result.Skipped[0].WorkspaceID != "ws-broken" {
+		t.Errorf("expected ws-broken to be skipped, got %+v", result.Skipped)
+	}
+
+	result, err = deps.svc.QueryWorkspaces(context.Background(), WorkspaceQuery{Filter: "stale or repo:api"})
+	if err != nil {
+		t.Fatalf("QueryWorkspaces failed: %v", err)
+	}
+
+	if len(result.Workspaces) != 1 || result.Workspaces[0].ID != "ws-clean" || len(result.Skipped) != 0 {
+		t.Errorf("expected only stale ws-clean, got %+v (skipped %+v)", result.Workspaces, result.Skipped)
+	}
+
+	if _, err := deps.svc.QueryWorkspaces(context.Background(), WorkspaceQuery{Filter: "dirty and"}); !errors.Is(err, cerrors.InvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err)
+	}
+}
+
+func TestSyncWorkspacesMatching_FetchesCanonicalOnce(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	for _, id := range []string{"ws-1", "ws-2", "ws-3"} {
+		addWorkspaceFixture(deps.storage, domain.Workspace{
+			ID:      id,
+			DirName: id,
+			Repos: []domain.Repo{
+				{Name: "shared", URL: "git@example.com:shared.git"},
+				{Name: "only-" + id, URL: "git@example.com:only-" + id + ".git"},
+			},
+		})
+	}
+
+	var mu sync.Mutex
+
+	fetches := map[string]int{}
+	deps.git.FetchFunc = func(_ context.Context, name string) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fetches[name]++
+
+		if name == "only-ws-3" {
+			return errors.New("network unreachable")
+		}
+
+		return nil
+	}
+	deps.git.StatusFunc = func(_ context.Context, _ string) (bool, int, int, string, error) {
+		return false, 0, 1, "main", nil
+	}
+
+	var upstreams []string
+
+	deps.git.PullFunc = func(_ context.Context, _ string, opts ports.PullOptions) (*ports.PullResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		upstreams = append(upstreams, opts.Upstream)
+
+		return &ports.PullResult{}, nil
+	}
+
+	result, err := deps.svc.SyncWorkspacesMatching(context.Background(), "^ws-", SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncWorkspacesMatching failed: %v", err)
+	}
+
+	if len(fetches) != 4 {
+		t.Fatalf("expected 4 canonical repos fetched, got %v", fetches)
+	}
+
+	for name, count := range fetches {
+		if count != 1 {
+			t.Errorf("expected %s to be fetched once, got %d", name, count)
+		}
+	}
+
+	if len(upstreams) != 5 {
+		t.Fatalf("expected 5 local integrations, got %d", len(upstreams))
+	}
+
+	for _, upstream := range upstreams {
+		if upstream != "@{upstream}" {
+			t.Errorf("expected worktrees to integrate the fetched upstream locally, got %q", upstream)
+		}
+	}
+
+	for _, res := range result.Results {
+		wantErrors := 0
+		if res.WorkspaceID == "ws-3" {
+			wantErrors = 1
+		}
+
+		if res.Result == nil || res.Result.TotalErrors != wantErrors {
+			t.Errorf("expected %d repo errors for %s, got %+v", wantErrors, res.WorkspaceID, res.Result)
+		}
+	}
+}
+
+func TestRenameWorkspace_RenamesBranchAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "old-id",
+		DirName:    "old-id",
+		BranchName: "old-id",
+		Repos:      []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+
+	var renameCalls []string
+
+	deps.git.RenameBranchFunc = func(_ context.Context, _, oldName, newName string) error {
+		renameCalls = append(renameCalls, oldName+"->"+newName)
+		return nil
+	}
+
+	if err := deps.svc.RenameWorkspace(context.Background(), "old-id", "new-id", true, false); err != nil {
+		t.Fatalf("RenameWorkspace failed: %v", err)
+	}
+
+	ws, ok := deps.storage.Workspaces["new-id"]
+	if !ok {
+		t.Fatal("expected workspace to be renamed in storage")
+	}
+
+	if ws.BranchName != "new-id" {
+		t.Errorf("expected branch name new-id, got %q", ws.BranchName)
+	}
+
+	if len(renameCalls) != 1 {
+		t.Fatalf("expected 1 rename call, got %d", len(renameCalls))
+	}
+}
+
+func TestRestoreWorkspace_CreatesWorkspaceAndDeletesClosedEntry(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	called := false
+
+	deps.storage.LatestClosedFunc = func(_ context.Context, id string) (*domain.ClosedWorkspace, error) {
+		if id != "ws-1" {
+			return nil, errors.New("unexpected id")
+		}
+
+		return &domain.ClosedWorkspace{
+			DirName: "ws-1",
+			Path:    "closed/ws-1",
+			Metadata: domain.Workspace{
+				ID:         "ws-1",
+				BranchName: "main",
+			},
+		}, nil
+	}
+	deps.storage.DeleteClosedFunc = func(_ context.Context, _ string, _ time.Time) error {
+		called = true
+		return nil
+	}
+
+	if err := deps.svc.RestoreWorkspace(context.Background(), "ws-1", false); err != nil {
+		t.Fatalf("RestoreWorkspace failed: %v", err)
+	}
+
+	if !called {
+		t.Fatal("expected closed entry to be deleted")
+	}
+
+	if _, ok := deps.storage.Workspaces["ws-1"]; !ok {
+		t.Fatal("expected workspace to be restored")
+	}
+}
+
+func TestPushWorkspace_PrePushHookVetoesPush(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "ws-1",
+		Repos:      []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+	deps.config.Hooks = config.Hooks{
+		PrePush:  []config.Hook{{Command: "make test"}},
+		PostPush: []config.Hook{{Command: "notify"}},
+	}
+
+	hookExecutor := mocks.NewMockHookExecutor()
+	hookExecutor.ExecuteHooksErr = errors.New("tests failed")
+	deps.svc.hookExecutor = hookExecutor
+
+	pushed := false
+	deps.git.PushFunc = func(_ context.Context, _, _ string, _ ports.PushOptions) error {
+		pushed = true
+		return nil
+	}
+
+	if err := deps.svc.PushWorkspace(context.Background(), "ws-1"); err == nil {
+		t.Fatal("expected failing pre_push hook to abort the push")
+	}
+
+	if pushed {
+		t.Error("expected push to be skipped after pre_push failure")
+	}
+
+	if hookExecutor.CallCount() != 1 || hookExecutor.ExecuteHooksCalls[0].Hooks[0].Command != "make test" {
+		t.Errorf("expected only pre_push hooks to run, got %+v", hookExecutor.ExecuteHooksCalls)
+	}
+}
+
+func TestPushWorkspace_RunsPushHooksAroundPush(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "ws-1",
+		Repos:      []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+	deps.config.Hooks = config.Hooks{
+		PrePush:  []config.Hook{{Command: "make test"}},
+		PostPush: []config.Hook{{Command: "notify"}},
+	}
+
+	var events []string
+
+	hookExecutor := mocks.NewMockHookExecutor()
+	hookExecutor.ExecuteHooksFunc = func(hks []config.Hook, _ domain.HookContext, _ ports.HookExecuteOptions) ([]domain.HookResult, error) {
+		events = append(events, hks[0].Command)
+		return nil, nil
+	}
+	deps.svc.hookExecutor = hookExecutor
+
+	deps.git.PushFunc = func(_ context.Context, _, _ string, _ ports.PushOptions) error {
+		events = append(events, "push")
+		return nil
+	}
+
+	if err := deps.svc.PushWorkspace(context.Background(), "ws-1"); err != nil {
+		t.Fatalf("PushWorkspace failed: %v", err)
+	}
+
+	if got := strings.Join(events, ","); got != "make test,push,notify" {
+		t.Errorf("expected pre_push, push, post_push order, got %s", got)
+	}
+}
+
+func TestPushWorkspace_PassesPushOptions(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "feature",
+		Repos:      []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+
+	var got ports.PushOptions
+
+	deps.git.PushFunc = func(_ context.Context, _, _ string, opts ports.PushOptions) error {
+		got = opts
+		return nil
+	}
+
+	_, err := deps.svc.PushWorkspaceWithOptions(context.Background(), "ws-1", PushOptions{
+		Remote:         "fork",
+		RemoteBranch:   "review/feature",
+		ForceWithLease: true,
+		PushOptions:    []string{"ci.skip"},
+	})
+	if err != nil {
+		t.Fatalf("PushWorkspaceWithOptions failed: %v", err)
+	}
+
+	want := ports.PushOptions{Remote: "fork", RemoteBranch: "review/feature", ForceWithLease: true, Options: []string{"ci.skip"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("push options = %+v, want %+v", got, want)
+	}
+}
+
+func TestCreatePullRequests_CrossLinksSiblings(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "PROJ-1",
+		DirName:    "PROJ-1",
+		BranchName: "PROJ-1",
+		Repos: []domain.Repo{
+			{Name: "backend", URL: "git@github.com:org/backend.git"},
+			{Name: "frontend", URL: "https://github.com/org/frontend.git"},
+			{Name: "docs", URL: "/local/docs"},
+		},
+	})
+
+	deps.git.GetUpstreamURLFunc = func(repoName string) (string, error) {
+		if repoName == "docs" {
+			return "", errors.New("no upstream")
+		}
+
+		return "", nil
+	}
+
+	var (
+		mu      sync.Mutex
+		created = map[string]ports.PullRequestSpec{}
+		bodies  = map[int]string{}
+	)
+
+	deps.forge.CreatePullRequestFunc = func(_ context.Context, owner, name string, spec ports.PullRequestSpec) (*domain.PullRequest, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		created[owner+"/"+name] = spec
+		number := len(created)
+
+		return &domain.PullRequest{Number: number, URL: fmt.Sprintf("https://github.com/%s/%s/pull/%d", owner, name, number)}, nil
+	}
+	deps.forge.UpdatePullRequestBodyFunc = func(_ context.Context, _, _ string, number int, body string) error {
+		bodies[number] = body
+		return nil
+	}
+
+	result, err := deps.svc.CreatePullRequests(context.Background(), "PROJ-1", PullRequestOptions{Title: "Add feature", Body: "Shared body"})
+	if err != nil {
+		t.Fatalf("CreatePullRequests failed: %v", err)
+	}
+
+	if result.TotalCreated != 2 || result.TotalErrors != 1 {
+		t.Fatalf("expected 2 created and 1 error, got %+v", result)
+	}
+
+	if result.PullRequests[2].Repo != "docs" || result.PullRequests[2].Error == "" {
+		t.Errorf("expected docs to fail without a forge remote, got %+v", result.PullRequests[2])
+	}
+
+	spec := created["org/backend"]
+	if spec.Title != "Add feature" || spec.Head != "PROJ-1" || spec.Base != "main" {
+		t.Errorf("unexpected pull request spec: %+v", spec)
+	}
+
+	for _, pr := range result.PullRequests[:2] {
+		body := bodies[pr.Number]
+		if !strings.HasPrefix(body, "Shared body\n\n") {
+			t.Errorf("expected %s body to keep the shared body, got %q", pr.Repo, body)
+		}
+
+		if strings.Contains(body, pr.URL) {
+			t.Errorf("expected %s body to link only siblings, got %q", pr.Repo, body)
+		}
+
+		if strings.Count(body, "https://github.com/org/") != 1 {
+			t.Errorf("expected one sibling link in %s body, got %q", pr.Repo, body)
+		}
+	}
+}
+
+func TestCreatePullRequests_RequiresTitleAndBranch(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{ID: "ws-1", DirName: "ws-1"})
+
+	if _, err := deps.svc.CreatePullRequests(context.Background(), "ws-1", PullRequestOptions{}); err == nil {
+		t.Error("expected error for missing title")
+	}
+
+	_, err := deps.svc.CreatePullRequests(context.Background(), "ws-1", PullRequestOptions{Title: "t"})
+
+	var cerr *cerrors.CanopyError
+	if !errors.As(err, &cerr) || cerr.Code != cerrors.ErrMissingBranchConfig {
+		t.Errorf("expected ErrMissingBranchConfig, got %v", err)
+	}
+}
+
+func TestGetReviewStatus_CachesPerBranch(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "PROJ-1",
+		DirName:    "PROJ-1",
+		BranchName: "PROJ-1",
+		Repos: []domain.Repo{
+			{Name: "backend", URL: "git@github.com:org/backend.git"},
+			{Name: "frontend", URL: "https://github.com/org/frontend.git"},
+			{Name: "api", URL: "https://github.com/org/api.git"},
+			{Name: "docs", URL: "/local/docs"},
+		},
+	})
+
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+
+	deps.forge.ReviewStatusFunc = func(_ context.Context, _, name, _ string, _ giturl.Remote) (*domain.ReviewStatus, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+
+		switch name {
+		case "backend":
+			return &domain.ReviewStatus{Number: 4, State: domain.PullRequestOpen, ReviewDecision: domain.ReviewRequired}, nil
+		case "frontend":
+			return nil, errors.New("rate limited")
+		default:
+			return nil, nil
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		statuses, err := deps.svc.GetReviewStatus(context.Background(), "PROJ-1")
+		if err != nil {
+			t.Fatalf("GetReviewStatus failed: %v", err)
+		}
+
+		if len(statuses) != 2 {
+			t.Fatalf("expected status for backend and frontend only, got %v", statuses)
+		}
+
+		if backend := statuses["backend"]; backend == nil || backend.Number != 4 || !backend.AwaitingReview() {
+			t.Errorf("unexpected backend status: %+v", backend)
+		}
+
+		if frontend := statuses["frontend"]; frontend == nil || frontend.Error == "" {
+			t.Errorf("expected frontend lookup error, got %+v", frontend)
+		}
+	}
+
+	if calls != 3 {
+		t.Errorf("expected 3 forge lookups with the second call served from cache, got %d", calls)
+	}
+}
+
+func atomicPushFixture(t *testing.T) mockServiceDeps {
+	t.Helper()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "feature",
+		Repos: []domain.Repo{
+			{Name: "repo-1", URL: "git@example.com:repo-1.git"},
+			{Name: "repo-2", URL: "git@example.com:repo-2.git"},
+			{Name: "repo-3", URL: "git@example.com:repo-3.git"},
+		},
+	})
+
+	deps.git.PushPreflightFunc = func(_ context.Context, path, _ string, _ ports.PushOptions) (*ports.PushPreflight, error) {
+		return &ports.PushPreflight{LocalHead: "local-" + filepath.Base(path), RemoteHead: "remote-" + filepath.Base(path)}, nil
+	}
+
+	return deps
+}
+
+func TestPushWorkspaceAtomic_PreflightRejectionPushesNothing(t *testing.T) {
+	t.Parallel()
+
+	deps := atomicPushFixture(t)
+	deps.git.PushPreflightFunc = func(_ context.Context, path, _ string, _ ports.PushOptions) (*ports.PushPreflight, error) {
+		if filepath.Base(path) == "repo-2" {
+			return nil, errors.New("non-fast-forward")
+		}
+
+		return &ports.PushPreflight{}, nil
+	}
+
+	var pushes int32
+
+	var mu sync.Mutex
+
+	deps.git.PushFunc = func(_ context.Context, _, _ string, _ ports.PushOptions) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		pushes++
+
+		return nil
+	}
+
+	result, err := deps.svc.PushWorkspaceWithOptions(context.Background(), "ws-1", PushOptions{Atomic: true})
+	if err == nil {
+		t.Fatal("expected pre-flight rejection error")
+	}
+
+	if pushes != 0 {
+		t.Errorf("expected no pushes after pre-flight rejection, got %d", pushes)
+	}
+
+	want := []domain.PushStatus{domain.PushStatusSkipped, domain.PushStatusRejected, domain.PushStatusSkipped}
+	for i, status := range result.Repos {
+		if status.Status != want[i] {
+			t.Errorf("repo %s status = %s, want %s", status.Name, status.Status, want[i])
+		}
+	}
+
+	if result.TotalErrors != 1 || result.TotalPushed != 0 {
+		t.Errorf("unexpected totals: %+v", result)
+	}
+}
+
+func TestPushWorkspaceAtomic_FailedPushRollsBackPushedRepos(t *testing.T) {
+	t.Parallel()
+
+	deps := atomicPushFixture(t)
+	deps.git.PushFunc = func(_ context.Context, path, _ string, _ ports.PushOptions) error {
+		if filepath.Base(path) == "repo-2" {
+			return errors.New("connection reset")
+		}
+
+		return nil
+	}
+
+	var restored []string
+
+	deps.git.RestoreRemoteBranchFunc = func(_ context.Context, path string, _ ports.PushOptions, branch, remoteHead, pushedHead string) error {
+		restored = append(restored, fmt.Sprintf("%s %s %s->%s", filepath.Base(path), branch, pushedHead, remoteHead))
+		return nil
+	}
+
+	result, err := deps.svc.PushWorkspaceWithOptions(context.Background(), "ws-1", PushOptions{Atomic: true})
+	if err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("expected push error, got %v", err)
+	}
+
+	if got := strings.Join(restored, ","); got != "repo-1 feature local-repo-1->remote-repo-1" {
+		t.Errorf("expected only repo-1 to be restored, got %q", got)
+	}
+
+	want := []domain.PushStatus{domain.PushStatusRolledBack, domain.PushStatusFailed, domain.PushStatusSkipped}
+	for i, status := range result.Repos {
+		if status.Status != want[i] {
+			t.Errorf("repo %s status = %s, want %s", status.Name, status.Status, want[i])
+		}
+	}
+}
+
+func TestPushWorkspaceAtomic_RequiresBranch(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{ID: "ws-1", DirName: "ws-1"})
+
+	_, err := deps.svc.PushWorkspaceWithOptions(context.Background(), "ws-1", PushOptions{Atomic: true})
+
+	var cerr *cerrors.CanopyError
+	if !errors.As(err, &cerr) || cerr.Code != cerrors.ErrMissingBranchConfig {
+		t.Errorf("expected ErrMissingBranchConfig, got %v", err)
+	}
+}
+
+func TestSyncWorkspace_RunsPostSyncHooks(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-1",
+		DirName: "ws-1",
+		Repos:   []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+	deps.config.Hooks = config.Hooks{PostSync: []config.Hook{{Command: "make deps"}}}
+	deps.config.Registry = &config.RepoRegistry{Repos: map[string]config.RegistryEntry{
+		"repo-1": {URL: "git@example.com:repo-1.git", Tags: []string{"backend"}},
+	}}
+
+	hookExecutor := mocks.NewMockHookExecutor()
+	hookExecutor.ExecuteHooksErr = errors.New("deps failed")
+	deps.svc.hookExecutor = hookExecutor
+
+	result, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{})
+	if err == nil {
+		t.Fatal("expected post_sync hook failure to be reported")
+	}
+
+	if result == nil || len(result.Repos) != 1 {
+		t.Fatalf("expected sync result alongside hook error, got %+v", result)
+	}
+
+	if hookExecutor.CallCount() != 1 {
+		t.Fatalf("expected post_sync hooks to run once, got %d", hookExecutor.CallCount())
+	}
+
+	hookCtx := hookExecutor.ExecuteHooksCalls[0].Ctx
+	if hookCtx.WorkspacePath != filepath.Join(deps.config.WorkspacesRoot, "ws-1") {
+		t.Errorf("unexpected hook workspace path %q", hookCtx.WorkspacePath)
+	}
+
+	if tags := hookCtx.RepoTags["repo-1"]; len(tags) != 1 || tags[0] != "backend" {
+		t.Errorf("expected registry tags in hook context, got %v", hookCtx.RepoTags)
+	}
+}
+
+func TestRenameWorkspace_RunsPostRenameHooks(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "old-id",
+		DirName:    "old-id",
+		BranchName: "old-id",
+		Repos:      []domain.Repo{{Name: "repo-1", URL: "git@example.com:repo-1.git"}},
+	})
+	deps.config.Hooks = config.Hooks{PostRename: []config.Hook{{Command: "echo renamed"}}}
+
+	hookExecutor := mocks.NewMockHookExecutor()
+	deps.svc.hookExecutor = hookExecutor
+
+	if err := deps.svc.RenameWorkspace(context.Background(), "old-id", "new-id", true, false); err != nil {
+		t.Fatalf("RenameWorkspace failed: %v", err)
+	}
+
+	if hookExecutor.CallCount() != 1 {
+		t.Fatalf("expected post_rename hooks to run once, got %d", hookExecutor.CallCount())
+	}
+
+	hookCtx := hookExecutor.ExecuteHooksCalls[0].Ctx
+	if hookCtx.WorkspaceID != "new-id" || hookCtx.BranchName != "new-id" {
+		t.Errorf("expected hook context for renamed workspace, got %+v", hookCtx)
+	}
+}
+
+func TestRestoreWorkspace_RunsPostRestoreHooks(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.storage.LatestClosedFunc = func(_ context.Context, _ string) (*domain.ClosedWorkspace, error) {
+		return &domain.ClosedWorkspace{
+			DirName:  "ws-1",
+			Path:     "closed/ws-1",
+			Metadata: domain.Workspace{ID: "ws-1", BranchName: "main"},
+		}, nil
+	}
+	deps.config.Hooks = config.Hooks{PostRestore: []config.Hook{{Command: "make deps"}}}
+
+	hookExecutor := mocks.NewMockHookExecutor()
+	deps.svc.hookExecutor = hookExecutor
+
+	if err := deps.svc.RestoreWorkspace(context.Background(), "ws-1", false); err != nil {
+		t.Fatalf("RestoreWorkspace failed: %v", err)
+	}
+
+	if hookExecutor.CallCount() != 1 || hookExecutor.ExecuteHooksCalls[0].Ctx.WorkspaceID != "ws-1" {
+		t.Fatalf("expected post_restore hooks to run for ws-1, got %+v", hookExecutor.ExecuteHooksCalls)
 	}
 }
@@ -0,0 +1,286 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// PullRequestOptions configures pull request creation for a workspace.
+type PullRequestOptions struct {
+	Title string
+	Body  string
+	Draft bool
+	// Base is the branch to merge into. Empty means each repo's default branch.
+	Base string
+}
+
+// repoPullRequest tracks where a repository's pull request was opened.
+type repoPullRequest struct {
+	forge  ports.Forge
+	remote giturl.Remote
+	pr     *domain.PullRequest
+}
+
+// CreatePullRequests opens one pull request per repo from the workspace branch, all with the
+// same title and body, then adds links to the sibling pull requests to each description.
+// Failures in one repo do not stop the others; they are reported per repo in the result.
+func (s *Service) CreatePullRequests(ctx context.Context, workspaceID string, opts PullRequestOptions) (*domain.PullRequestResult, error) {
+	if strings.TrimSpace(opts.Title) == "" {
+		return nil, cerrors.NewInvalidArgument("title", "pull request title is required")
+	}
+
+	workspace, _, err := s.findWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspace.BranchName == "" {
+		return nil, cerrors.NewMissingBranchConfig(workspaceID)
+	}
+
+	// Resolve base branches once, before the repos are handled in parallel
+	bases := make([]string, len(workspace.Repos))
+	baseErrs := make([]error, len(workspace.Repos))
+
+	for i, repo := range workspace.Repos {
+		bases[i] = opts.Base
+		if bases[i] == "" {
+			bases[i], baseErrs[i] = s.resolveDefaultBranch(workspace, repo)
+		}
+	}
+
+	executor := NewParallelExecutor(s.config.GetParallelWorkers())
+
+	created, err := ParallelMap(ctx, executor, len(workspace.Repos), func(runCtx context.Context, index int) (repoPullRequest, error) {
+		if baseErrs[index] != nil {
+			return repoPullRequest{}, baseErrs[index]
+		}
+
+		return s.createRepoPullRequest(runCtx, workspace, workspace.Repos[index], bases[index], opts)
+	}, ParallelOptions{ContinueOnError: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PullRequestResult{WorkspaceID: workspaceID, Title: opts.Title}
+
+	var opened []repoPullRequest
+
+	for i, repo := range workspace.Repos {
+		if created[i].Err != nil {
+			result.PullRequests = append(result.PullRequests, domain.PullRequest{Repo: repo.Name, Head: workspace.BranchName, Error: created[i].Err.Error()})
+			continue
+		}
+
+		created[i].Value.pr.Repo = repo.Name
+		opened = append(opened, created[i].Value)
+		result.PullRequests = append(result.PullRequests, *created[i].Value.pr)
+	}
+
+	if len(opened) > 1 {
+		s.linkSiblingPullRequests(ctx, workspaceID, opts.Body, opened, result)
+	}
+
+	for _, pr := range result.PullRequests {
+		if pr.Error != "" {
+			result.TotalErrors++
+		}
+
+		if pr.URL != "" || pr.Number != 0 {
+			result.TotalCreated++
+		}
+	}
+
+	return result, nil
+}
+
+// originURL returns the URL of a repo's origin, falling back to the URL recorded in the
+// workspace when the canonical repo has no origin.
+func (s *Service) originURL(repo domain.Repo) string {
+	upstream, err := s.gitEngine.GetUpstreamURL(repo.Name)
+	if err != nil || upstream == "" {
+		return repo.URL
+	}
+
+	return upstream
+}
+
+// repoRemote returns the forge host, owner and name of the repository a repo's pull requests
+// target: its upstream remote when it has one, otherwise origin.
+func (s *Service) repoRemote(repo domain.Repo) (giturl.Remote, error) {
+	url := s.originURL(repo)
+	if upstream, ok := repo.Remote(domain.UpstreamRemote); ok {
+		url = upstream.URL
+	}
+
+	remote, ok := giturl.ParseRemote(url)
+	if !ok {
+		return giturl.Remote{}, cerrors.NewInvalidArgument("url", fmt.Sprintf("cannot determine owner and repository from %q", giturl.Sanitize(url)))
+	}
+
+	return remote, nil
+}
+
+// headRepo returns the fork a repo's branch is pushed to, or the zero Remote when the branch is
+// pushed to the repository its pull requests target.
+func headRepo(repo domain.Repo, originURL string, target giturl.Remote) (giturl.Remote, error) {
+	name, url := repo.PushRemoteName(), originURL
+
+	if name != domain.OriginRemote {
+		remote, ok := repo.Remote(name)
+		if !ok {
+			return giturl.Remote{}, cerrors.NewInvalidArgument("push_remote", fmt.Sprintf("unknown remote %q", name))
+		}
+
+		url = remote.URL
+	} else if repo.SyncRemote() == domain.OriginRemote {
+		return giturl.Remote{}, nil
+	}
+
+	fork, ok := giturl.ParseRemote(url)
+	if !ok || !strings.EqualFold(fork.Host, target.Host) {
+		return giturl.Remote{}, cerrors.NewInvalidArgument("push_remote",
+			fmt.Sprintf("push remote %s (%s) is not a repository on %s", name, giturl.Sanitize(url), target.Host))
+	}
+
+	if strings.EqualFold(fork.FullName(), target.FullName()) {
+		return giturl.Remote{}, nil
+	}
+
+	return fork, nil
+}
+
+// createRepoPullRequest opens the pull request for one repo on the forge hosting its upstream,
+// from the fork its branch is pushed to when that is another repository.
+func (s *Service) createRepoPullRequest(ctx context.Context, workspace *domain.Workspace, repo domain.Repo, base string, opts PullRequestOptions) (repoPullRequest, error) {
+	remote, err := s.repoRemote(repo)
+	if err != nil {
+		return repoPullRequest{}, err
+	}
+
+	fork, err := headRepo(repo, s.originURL(repo), remote)
+	if err != nil {
+		return repoPullRequest{}, err
+	}
+
+	forge, err := s.forges.ForgeFor(remote.Host)
+	if err != nil {
+		return repoPullRequest{}, err
+	}
+
+	pr, err := forge.CreatePullRequest(ctx, remote.Owner, remote.Name, ports.PullRequestSpec{
+		Title:    opts.Title,
+		Body:     opts.Body,
+		Head:     workspace.BranchName,
+		HeadRepo: fork,
+		Base:     base,
+		Draft:    opts.Draft,
+	})
+	if err != nil {
+		return repoPullRequest{}, err
+	}
+
+	s.reviews.InvalidateCache(remote, fork, workspace.BranchName)
+
+	return repoPullRequest{forge: forge, remote: remote, pr: pr}, nil
+}
+
+// GetReviewStatus returns the review status of the pull request opened from the workspace
+// branch in each repo, or in the fork it is pushed to, keyed by repo name. Repos without a pull request, or whose forge has
+// no API token, are left out; a failed lookup is reported in the entry's Error.
+func (s *Service) GetReviewStatus(ctx context.Context, workspaceID string) (map[string]*domain.ReviewStatus, error) {
+	workspace, _, err := s.findWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]*domain.ReviewStatus)
+
+	if workspace.BranchName == "" {
+		return statuses, nil
+	}
+
+	var (
+		repos   []domain.Repo
+		remotes []giturl.Remote
+		forks   []giturl.Remote
+	)
+
+	for _, repo := range workspace.Repos {
+		remote, err := s.repoRemote(repo)
+		if err != nil {
+			// Local or unrecognized remotes are not hosted on a forge
+			continue
+		}
+
+		fork, err := headRepo(repo, s.originURL(repo), remote)
+		if err != nil {
+			statuses[repo.Name] = &domain.ReviewStatus{Error: err.Error()}
+			continue
+		}
+
+		repos = append(repos, repo)
+		remotes = append(remotes, remote)
+		forks = append(forks, fork)
+	}
+
+	executor := NewParallelExecutor(s.config.GetParallelWorkers())
+
+	results, err := ParallelMap(ctx, executor, len(repos), func(runCtx context.Context, index int) (*domain.ReviewStatus, error) {
+		return s.reviews.CachedReviewStatus(runCtx, remotes[index], forks[index], workspace.BranchName)
+	}, ParallelOptions{ContinueOnError: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, repo := range repos {
+		switch {
+		case results[i].Err != nil:
+			statuses[repo.Name] = &domain.ReviewStatus{Error: results[i].Err.Error()}
+		case results[i].Value != nil:
+			// Copy so callers cannot modify the cached entry
+			status := *results[i].Value
+			statuses[repo.Name] = &status
+		}
+	}
+
+	return statuses, nil
+}
+
+// linkSiblingPullRequests appends the other pull requests of the workspace to each description.
+// A failed update leaves the pull request open and is reported on its entry in result.
+func (s *Service) linkSiblingPullRequests(ctx context.Context, workspaceID, body string, opened []repoPullRequest, result *domain.PullRequestResult) {
+	for _, current := range opened {
+		var links []string
+
+		for _, sibling := range opened {
+			if sibling.pr != current.pr {
+				links = append(links, fmt.Sprintf("- %s: %s", sibling.remote.FullName(), sibling.pr.URL))
+			}
+		}
+
+		description := strings.TrimRight(body, "\n")
+		if description != "" {
+			description += "\n\n"
+		}
+
+		description += fmt.Sprintf("Related pull requests in workspace %s:\n%s\n", workspaceID, strings.Join(links, "\n"))
+
+		err := current.forge.UpdatePullRequestBody(ctx, current.remote.Owner, current.remote.Name, current.pr.Number, description)
+		if err == nil {
+			continue
+		}
+
+		for i := range result.PullRequests {
+			if result.PullRequests[i].Repo == current.pr.Repo {
+				result.PullRequests[i].Error = "link sibling pull requests: " + err.Error()
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// PushOptions configures workspace push behavior.
+type PushOptions struct {
+	// Atomic pre-flights every repo with a dry-run push before pushing any of them, and
+	// restores the remote branches already pushed if a later push fails.
+	Atomic bool
+	// Remote is the remote to push to. Empty means each repo's push remote, origin by default.
+	Remote string
+	// RemoteBranch pushes to, and sets as upstream, a differently named remote branch.
+	RemoteBranch string
+	// ForceWithLease allows rewriting remote branches that have not moved since the last fetch.
+	ForceWithLease bool
+	// PushOptions are sent to the server as with "git push -o".
+	PushOptions []string
+}
+
+// gitPushOptions returns the per-repo push options.
+func (o PushOptions) gitPushOptions() ports.PushOptions {
+	return ports.PushOptions{
+		Remote:         o.Remote,
+		RemoteBranch:   o.RemoteBranch,
+		ForceWithLease: o.ForceWithLease,
+		Options:        o.PushOptions,
+	}
+}
+
+// repoPushOptions returns the push options for a repo, pushing to its push remote unless a
+// remote was requested.
+func repoPushOptions(repo domain.Repo, pushOpts ports.PushOptions) ports.PushOptions {
+	if pushOpts.Remote == "" {
+		pushOpts.Remote = repo.PushRemote
+	}
+
+	return pushOpts
+}
+
+// PushWorkspaceWithOptions pushes all repos for a workspace and reports the outcome per repo.
+func (s *WorkspaceGitService) PushWorkspaceWithOptions(ctx context.Context, workspaceID string, opts PushOptions) (*domain.PushResult, error) {
+	targetWorkspace, dirName, err := s.workspaceFinder.FindWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PushResult{
+		WorkspaceID: workspaceID,
+		Branch:      targetWorkspace.BranchName,
+		Atomic:      opts.Atomic,
+	}
+
+	if targetWorkspace.BranchName == "" && (opts.RemoteBranch != "" || opts.ForceWithLease) {
+		return nil, cerrors.NewMissingBranchConfig(workspaceID)
+	}
+
+	if opts.Atomic {
+		err = s.pushAtomic(ctx, targetWorkspace, dirName, opts.gitPushOptions(), result)
+	} else {
+		err = s.pushSequential(ctx, targetWorkspace, dirName, opts.gitPushOptions(), result)
+	}
+
+	tallyPushResult(result)
+
+	return result, err
+}
+
+// pushSequential pushes repos one after another and stops at the first failure.
+func (s *WorkspaceGitService) pushSequential(ctx context.Context, workspace *domain.Workspace, dirName string, pushOpts ports.PushOptions, result *domain.PushResult) error {
+	for i, repo := range workspace.Repos {
+		// Check for context cancellation before each push
+		if ctx.Err() != nil {
+			appendSkipped(result, workspace.Repos[i:])
+			return cerrors.NewContextError(ctx, "push workspace", workspace.ID)
+		}
+
+		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
+		branchName := workspace.BranchName
+
+		if branchName == "" {
+			if s.logger != nil {
+				s.logger.Debug("Branch missing in metadata, will let git infer", "workspace", workspace.ID, "repo", repo.Name)
+			}
+		}
+
+		if err := s.gitEngine.Push(ctx, worktreePath, branchName, repoPushOptions(repo, pushOpts)); err != nil {
+			result.Repos = append(result.Repos, domain.RepoPushStatus{Name: repo.Name, Status: domain.PushStatusFailed, Error: err.Error()})
+			appendSkipped(result, workspace.Repos[i+1:])
+
+			return cerrors.WrapGitError(err, fmt.Sprintf("push repo %s", repo.Name))
+		}
+
+		result.Repos = append(result.Repos, domain.RepoPushStatus{Name: repo.Name, Status: domain.PushStatusPushed})
+	}
+
+	return nil
+}
+
+// pushAtomic pre-flights every repo, pushes them in order, and on a failed push restores the
+// remote branches that were already pushed so the remote is not left half-updated.
+func (s *WorkspaceGitService) pushAtomic(ctx context.Context, workspace *domain.Workspace, dirName string, pushOpts ports.PushOptions, result *domain.PushResult) error {
+	branch := workspace.BranchName
+	if branch == "" {
+		return cerrors.NewMissingBranchConfig(workspace.ID)
+	}
+
+	paths := make([]string, len(workspace.Repos))
+	repoOpts := make([]ports.PushOptions, len(workspace.Repos))
+
+	for i, repo := range workspace.Repos {
+		paths[i] = filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
+		repoOpts[i] = repoPushOptions(repo, pushOpts)
+	}
+
+	// Step 1: dry-run push every repo; nothing is pushed unless all of them pass
+	executor := NewParallelExecutor(s.config.GetParallelWorkers())
+
+	preflights, err := ParallelMap(ctx, executor, len(paths), func(runCtx context.Context, index int) (*ports.PushPreflight, error) {
+		return s.gitEngine.PushPreflight(runCtx, paths[index], branch, repoOpts[index])
+	}, ParallelOptions{ContinueOnError: true})
+	if err != nil {
+		return err
+	}
+
+	if failed := CountErrors(preflights); failed > 0 {
+		for i, repo := range workspace.Repos {
+			status := domain.RepoPushStatus{Name: repo.Name, Status: domain.PushStatusSkipped}
+			if preflights[i].Err != nil {
+				status.Status = domain.PushStatusRejected
+				status.Error = preflights[i].Err.Error()
+			}
+
+			result.Repos = append(result.Repos, status)
+		}
+
+		return cerrors.NewCommandFailed("atomic push pre-flight",
+			fmt.Errorf("%d of %d repos rejected, nothing was pushed: %w", failed, len(paths), FirstError(preflights)))
+	}
+
+	// Step 2: push every repo, stopping at the first failure
+	for i, repo := range workspace.Repos {
+		preflight := preflights[i].Value
+		status := domain.RepoPushStatus{
+			Name:       repo.Name,
+			Status:     domain.PushStatusPushed,
+			LocalHead:  preflight.LocalHead,
+			RemoteHead: preflight.RemoteHead,
+		}
+
+		pushErr := ctx.Err()
+		if pushErr == nil {
+			pushErr = s.gitEngine.Push(ctx, paths[i], branch, repoOpts[i])
+		}
+
+		if pushErr == nil {
+			result.Repos = append(result.Repos, status)
+			continue
+		}
+
+		status.Status = domain.PushStatusFailed
+		status.Error = pushErr.Error()
+		result.Repos = append(result.Repos, status)
+		appendSkipped(result, workspace.Repos[i+1:])
+
+		// Step 3: restore the remote branches that were already pushed
+		pushErr = cerrors.WrapGitError(pushErr, fmt.Sprintf("push repo %s", repo.Name))
+		if rollbackErr := s.rollbackPushed(ctx, result, paths, branch, repoOpts); rollbackErr != nil {
+			return joinErrors(pushErr, rollbackErr)
+		}
+
+		return pushErr
+	}
+
+	return nil
+}
+
+// rollbackPushed restores every pushed repo in result to its pre-push remote commit.
+func (s *WorkspaceGitService) rollbackPushed(ctx context.Context, result *domain.PushResult, paths []string, branch string, repoOpts []ports.PushOptions) error {
+	var errs []error
+
+	for i := range result.Repos {
+		status := &result.Repos[i]
+		if status.Status != domain.PushStatusPushed {
+			continue
+		}
+
+		// Rollback must run even when the push was canceled
+		if err := s.gitEngine.RestoreRemoteBranch(context.WithoutCancel(ctx), paths[i], repoOpts[i], branch, status.RemoteHead, status.LocalHead); err != nil {
+			status.Status = domain.PushStatusRollbackFailed
+			status.Error = err.Error()
+			errs = append(errs, cerrors.WrapGitError(err, fmt.Sprintf("restore remote branch for repo %s", status.Name)))
+
+			continue
+		}
+
+		status.Status = domain.PushStatusRolledBack
+	}
+
+	return joinErrors(errs...)
+}
+
+func appendSkipped(result *domain.PushResult, repos []domain.Repo) {
+	for _, repo := range repos {
+		result.Repos = append(result.Repos, domain.RepoPushStatus{Name: repo.Name, Status: domain.PushStatusSkipped})
+	}
+}
+
+func tallyPushResult(result *domain.PushResult) {
+	for _, status := range result.Repos {
+		switch status.Status {
+		case domain.PushStatusPushed:
+			result.TotalPushed++
+		case domain.PushStatusRejected, domain.PushStatusFailed, domain.PushStatusRollbackFailed:
+			result.TotalErrors++
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// resolveRemotes fills in the extra remotes and push remote of a repo of a new workspace.
+// A layout already recorded on the repo (restore, import) wins over the registry entry.
+func (s *Service) resolveRemotes(repo domain.Repo) (domain.Repo, error) {
+	if len(repo.Remotes) == 0 && repo.PushRemote == "" {
+		if entry, ok := s.registryEntryForRepo(repo); ok {
+			repo.Remotes = entry.RemoteList()
+			repo.PushRemote = entry.PushRemote
+		}
+	}
+
+	if err := config.ValidateRemotes(repo.Remotes, repo.PushRemote); err != nil {
+		return repo, cerrors.NewInvalidArgument("remotes", fmt.Sprintf("repo %s: %v", repo.Name, err))
+	}
+
+	return repo, nil
+}
+
+// withRemotes returns a copy of repos with their remote layout resolved.
+func (s *Service) withRemotes(repos []domain.Repo) ([]domain.Repo, error) {
+	resolved := make([]domain.Repo, len(repos))
+
+	for i, repo := range repos {
+		var err error
+		if resolved[i], err = s.resolveRemotes(repo); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+// createRepoWorktree creates the worktree of a repo in a workspace directory and configures
+// its extra remotes.
+func (s *Service) createRepoWorktree(ctx context.Context, repo domain.Repo, dirName, branchName string) error {
+	worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
+	if err := s.gitEngine.CreateWorktree(ctx, repo.Name, worktreePath, branchName, repo.SparsePaths); err != nil {
+		return cerrors.WrapGitError(err, fmt.Sprintf("create worktree for %s", repo.Name))
+	}
+
+	if len(repo.Remotes) == 0 && repo.PushRemote == "" {
+		return nil
+	}
+
+	if err := s.gitEngine.SetRemotes(ctx, worktreePath, branchName, repo.Remotes, repo.PushRemote); err != nil {
+		return cerrors.WrapGitError(err, fmt.Sprintf("configure remotes for %s", repo.Name))
+	}
+
+	return nil
+}
@@ -0,0 +1,350 @@
+package workspaces
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+func TestCreateWorkspace_Remotes(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.config.Registry = &config.RepoRegistry{Repos: map[string]config.RegistryEntry{
+		"api": {
+			URL:        "https://example.com/upstream/api.git",
+			Remotes:    map[string]string{"fork": "https://example.com/me/api.git"},
+			PushRemote: "fork",
+		},
+	}}
+
+	type setRemotesCall struct {
+		branch     string
+		remotes    []domain.Remote
+		pushRemote string
+	}
+
+	got := map[string]setRemotesCall{}
+
+	deps.git.SetRemotesFunc = func(_ context.Context, path, branch string, remotes []domain.Remote, pushRemote string) error {
+		got[path] = setRemotesCall{branch: branch, remotes: remotes, pushRemote: pushRemote}
+		return nil
+	}
+
+	repos := []domain.Repo{
+		{Name: "api", URL: "https://example.com/upstream/api.git"},
+		{Name: "web", URL: "https://example.com/upstream/web.git"},
+	}
+
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "ws-1", "feature", repos); err != nil {
+		t.Fatalf("CreateWorkspace failed: %v", err)
+	}
+
+	fork := []domain.Remote{{Name: "fork", URL: "https://example.com/me/api.git"}}
+	want := map[string]setRemotesCall{
+		filepath.Join(deps.config.WorkspacesRoot, "ws-1", "api"): {branch: "feature", remotes: fork, pushRemote: "fork"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SetRemotes calls = %+v, want %+v", got, want)
+	}
+
+	recorded := deps.storage.Workspaces["ws-1"].Repos
+	if !reflect.DeepEqual(recorded[0].Remotes, fork) || recorded[0].PushRemote != "fork" {
+		t.Errorf("recorded api remotes = %v push %q, want %v push fork", recorded[0].Remotes, recorded[0].PushRemote, fork)
+	}
+
+	if recorded[1].Remotes != nil || recorded[1].PushRemote != "" {
+		t.Errorf("expected no remotes for web, got %v push %q", recorded[1].Remotes, recorded[1].PushRemote)
+	}
+}
+
+func TestCreateWorkspace_RejectsUnknownPushRemote(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+
+	repos := []domain.Repo{{Name: "api", URL: "https://example.com/upstream/api.git", PushRemote: "fork"}}
+
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "ws-1", "feature", repos); err == nil {
+		t.Fatal("expected an error for a push remote that is not configured")
+	}
+
+	if _, ok := deps.storage.Workspaces["ws-1"]; ok {
+		t.Error("expected no workspace to be created")
+	}
+}
+
+func TestPushWorkspace_UsesRepoPushRemote(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "feature",
+		Repos: []domain.Repo{
+			{
+				Name:       "api",
+				URL:        "https://example.com/upstream/api.git",
+				Remotes:    []domain.Remote{{Name: "fork", URL: "https://example.com/me/api.git"}},
+				PushRemote: "fork",
+			},
+			{Name: "web", URL: "https://example.com/upstream/web.git"},
+		},
+	})
+
+	pushed := map[string]string{}
+
+	deps.git.PushFunc = func(_ context.Context, path, _ string, opts ports.PushOptions) error {
+		pushed[path] = opts.Remote
+		return nil
+	}
+
+	root := filepath.Join(deps.config.WorkspacesRoot, "ws-1")
+
+	if _, err := deps.svc.PushWorkspaceWithOptions(context.Background(), "ws-1", PushOptions{}); err != nil {
+		t.Fatalf("PushWorkspaceWithOptions failed: %v", err)
+	}
+
+	if want := map[string]string{filepath.Join(root, "api"): "fork", filepath.Join(root, "web"): ""}; !reflect.DeepEqual(pushed, want) {
+		t.Errorf("pushed remotes = %v, want %v", pushed, want)
+	}
+
+	if _, err := deps.svc.PushWorkspaceWithOptions(context.Background(), "ws-1", PushOptions{Remote: "origin"}); err != nil {
+		t.Fatalf("PushWorkspaceWithOptions failed: %v", err)
+	}
+
+	if want := map[string]string{filepath.Join(root, "api"): "origin", filepath.Join(root, "web"): "origin"}; !reflect.DeepEqual(pushed, want) {
+		t.Errorf("pushed remotes with --remote = %v, want %v", pushed, want)
+	}
+}
+
+func TestExportImport_KeepsRemotes(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+
+	remotes := []domain.Remote{{Name: "fork", URL: "https://example.com/me/api.git"}}
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "feature",
+		Repos:      []domain.Repo{{Name: "api", URL: "https://example.com/upstream/api.git", Remotes: remotes, PushRemote: "fork"}},
+	})
+
+	export, err := deps.svc.ExportWorkspace(context.Background(), "ws-1")
+	if err != nil {
+		t.Fatalf("ExportWorkspace failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(export.Repos[0].Remotes, remotes) || export.Repos[0].PushRemote != "fork" {
+		t.Fatalf("exported remotes = %v push %q, want %v push fork", export.Repos[0].Remotes, export.Repos[0].PushRemote, remotes)
+	}
+
+	var configured []domain.Remote
+
+	deps.git.SetRemotesFunc = func(_ context.Context, _, _ string, remotes []domain.Remote, _ string) error {
+		configured = remotes
+		return nil
+	}
+
+	if _, err := deps.svc.ImportWorkspace(context.Background(), export, "ws-2", "", false); err != nil {
+		t.Fatalf("ImportWorkspace failed: %v", err)
+	}
+
+	repo := deps.storage.Workspaces["ws-2"].Repos[0]
+	if !reflect.DeepEqual(configured, remotes) || !reflect.DeepEqual(repo.Remotes, remotes) || repo.PushRemote != "fork" {
+		t.Errorf("imported remotes = %v (configured %v) push %q, want %v push fork", repo.Remotes, configured, repo.PushRemote, remotes)
+	}
+}
+
+func TestSyncWorkspace_PullsFromUpstreamRemote(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "feature",
+		Repos: []domain.Repo{
+			{
+				Name:    "api",
+				URL:     "https://example.com/me/api.git",
+				Remotes: []domain.Remote{{Name: "upstream", URL: "https://example.com/upstream/api.git"}},
+			},
+			{Name: "web", URL: "https://example.com/upstream/web.git"},
+		},
+	})
+
+	var (
+		mu          sync.Mutex
+		pulls       = map[string]ports.PullOptions{}
+		headsLooked = map[string]string{}
+	)
+
+	deps.git.StatusFunc = func(_ context.Context, _ string) (bool, int, int, string, error) {
+		return false, 0, 1, "feature", nil
+	}
+	deps.git.CommitsBehindFunc = func(_ context.Context, _, _ string) (int, error) {
+		return 1, nil
+	}
+	deps.git.DefaultBranchFunc = func(repoName, remote string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		headsLooked[repoName] = remote
+
+		return "main", nil
+	}
+	deps.git.PullFunc = func(_ context.Context, path string, opts ports.PullOptions) (*ports.PullResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		pulls[filepath.Base(path)] = opts
+
+		return &ports.PullResult{}, nil
+	}
+
+	if _, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{}); err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
+	}
+
+	if pulls["api"].Remote != "upstream" || pulls["web"].Remote != "origin" {
+		t.Errorf("pulled from api %q and web %q, want upstream and origin", pulls["api"].Remote, pulls["web"].Remote)
+	}
+
+	if _, err := deps.svc.SyncWorkspace(context.Background(), "ws-1", SyncOptions{OntoDefault: true}); err != nil {
+		t.Fatalf("SyncWorkspace --onto-default failed: %v", err)
+	}
+
+	if pulls["api"].Upstream != "upstream/main" || pulls["web"].Upstream != "origin/main" {
+		t.Errorf("integrated api %q and web %q, want upstream/main and origin/main", pulls["api"].Upstream, pulls["web"].Upstream)
+	}
+
+	if want := map[string]string{"api": "upstream", "web": "origin"}; !reflect.DeepEqual(headsLooked, want) {
+		t.Errorf("default branches looked up on %v, want %v", headsLooked, want)
+	}
+}
+
+func TestPullRequests_TargetUpstreamRemote(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "feature",
+		Repos: []domain.Repo{{
+			Name:    "api",
+			URL:     "https://github.com/me/api-fork.git",
+			Remotes: []domain.Remote{{Name: "upstream", URL: "https://github.com/upstream/api.git"}},
+		}},
+	})
+
+	deps.git.GetUpstreamURLFunc = func(string) (string, error) {
+		return "", nil
+	}
+
+	var (
+		target string
+		spec   ports.PullRequestSpec
+	)
+
+	deps.forge.CreatePullRequestFunc = func(_ context.Context, owner, name string, got ports.PullRequestSpec) (*domain.PullRequest, error) {
+		target, spec = owner+"/"+name, got
+
+		return &domain.PullRequest{Number: 1, Head: got.Head}, nil
+	}
+
+	if _, err := deps.svc.CreatePullRequests(context.Background(), "ws-1", PullRequestOptions{Title: "Add feature", Base: "main"}); err != nil {
+		t.Fatalf("CreatePullRequests failed: %v", err)
+	}
+
+	fork := giturl.Remote{Host: "github.com", Owner: "me", Name: "api-fork"}
+	if target != "upstream/api" || spec.HeadRepo != fork {
+		t.Errorf("pull request opened on %s from %+v, want upstream/api from %+v", target, spec.HeadRepo, fork)
+	}
+}
+
+func TestPullRequests_FromForkPushRemote(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:         "ws-1",
+		DirName:    "ws-1",
+		BranchName: "feature",
+		Repos: []domain.Repo{
+			{
+				Name:       "api",
+				URL:        "https://github.com/upstream/api.git",
+				Remotes:    []domain.Remote{{Name: "fork", URL: "git@github.com:me/api-fork.git"}},
+				PushRemote: "fork",
+			},
+			{Name: "web", URL: "https://github.com/upstream/web.git"},
+		},
+	})
+
+	deps.git.GetUpstreamURLFunc = func(string) (string, error) {
+		return "", nil
+	}
+
+	var (
+		mu      sync.Mutex
+		specs   = map[string]ports.PullRequestSpec{}
+		lookups = map[string]giturl.Remote{}
+	)
+
+	deps.forge.CreatePullRequestFunc = func(_ context.Context, owner, name string, spec ports.PullRequestSpec) (*domain.PullRequest, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		specs[owner+"/"+name] = spec
+
+		return &domain.PullRequest{Number: len(specs), Head: spec.Head}, nil
+	}
+
+	deps.forge.ReviewStatusFunc = func(_ context.Context, owner, name, head string, headRepo giturl.Remote) (*domain.ReviewStatus, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if head != "feature" {
+			t.Errorf("review status looked up for %q, want feature", head)
+		}
+
+		lookups[owner+"/"+name] = headRepo
+
+		return nil, nil
+	}
+
+	if _, err := deps.svc.CreatePullRequests(context.Background(), "ws-1", PullRequestOptions{Title: "Add feature", Base: "main"}); err != nil {
+		t.Fatalf("CreatePullRequests failed: %v", err)
+	}
+
+	fork := giturl.Remote{Host: "github.com", Owner: "me", Name: "api-fork"}
+
+	if got := specs["upstream/api"]; got.Head != "feature" || got.HeadRepo != fork {
+		t.Errorf("api pull request head = %q from %+v, want feature from %+v", got.Head, got.HeadRepo, fork)
+	}
+
+	if got := specs["upstream/web"]; got.HeadRepo != (giturl.Remote{}) {
+		t.Errorf("expected the web pull request from upstream itself, got head repo %+v", got.HeadRepo)
+	}
+
+	if _, err := deps.svc.GetReviewStatus(context.Background(), "ws-1"); err != nil {
+		t.Fatalf("GetReviewStatus failed: %v", err)
+	}
+
+	want := map[string]giturl.Remote{"upstream/api": fork, "upstream/web": {}}
+	if !reflect.DeepEqual(lookups, want) {
+		t.Errorf("review status head repos = %+v, want %+v", lookups, want)
+	}
+}
@@ -13,6 +13,7 @@ import (
 // RenameWorkspace renames a workspace to a new ID.
 // If renameBranch is true and the branch name matches the old ID, it will also rename branches.
 // If force is true, an existing workspace with the new ID will be deleted first.
+// post_rename hooks run against the renamed workspace once the rename succeeds.
 func (s *Service) RenameWorkspace(ctx context.Context, oldID, newID string, renameBranch, force bool) error {
 	if s.lockManager == nil {
 		_, err := s.renameWorkspaceUnlocked(ctx, oldID, newID, renameBranch, force)
@@ -25,7 +26,7 @@ func (s *Service) RenameWorkspace(ctx context.Context, oldID, newID string, rena
 	}
 
 	newDirName, renameErr := s.renameWorkspaceUnlocked(ctx, oldID, newID, renameBranch, force)
-	if renameErr == nil && newDirName != "" {
+	if newDirName != "" {
 		handle.UpdateLocation(newID, filepath.Join(s.config.GetWorkspacesRoot(), newDirName, lockFileName))
 	}
 
@@ -72,6 +73,19 @@ func (s *Service) renameWorkspaceUnlocked(ctx context.Context, oldID, newID stri
 
 	s.invalidateWorkspaceCache(oldID, newID)
 
+	renamed := *workspace
+	renamed.ID = newID
+	renamed.DirName = newDirName
+
+	if shouldRenameBranch {
+		renamed.BranchName = newID
+	}
+
+	// The rename is complete at this point; a hook failure does not undo it.
+	if err := s.runPhaseHooks(HookPhasePostRename, renamed, newDirName, false); err != nil {
+		return newDirName, err
+	}
+
 	return newDirName, nil
 }
 
@@ -197,10 +211,15 @@ func (s *Service) updateBranchMetadata(ctx context.Context, workspaceID, newBran
 	}
 
 	ws.BranchName = newBranchName
+	previous := s.gitService.moveSnapshotRefs(ctx, ws)
+
 	if err := s.wsEngine.Save(ctx, *ws); err != nil {
+		s.gitService.deleteUnusedSnapshotRefs(ctx, ws.Snapshots)
 		return cerrors.NewWorkspaceMetadataError(workspaceID, "save", err)
 	}
 
+	s.gitService.deleteUnusedSnapshotRefs(ctx, previous)
+
 	return nil
 }
 
@@ -48,7 +48,8 @@ func (s *Service) ResolveRepos(workspaceID string, requestedRepos []string) ([]d
 	return repos, nil
 }
 
-// AddRepoToWorkspace adds a repository to an existing workspace
+// AddRepoToWorkspace adds a repository to an existing workspace.
+// post_repo_add hooks run with only the added repository in their context.
 func (s *Service) AddRepoToWorkspace(ctx context.Context, workspaceID, repoName string) error {
 	return s.withWorkspaceLock(ctx, workspaceID, false, func() error {
 		if err := validateAddRepoInputs(workspaceID, repoName); err != nil {
@@ -69,6 +70,14 @@ func (s *Service) AddRepoToWorkspace(ctx context.Context, workspaceID, repoName
 			return err
 		}
 
+		if repo.SparsePaths, err = s.resolveSparsePaths(workspace.Template, repo); err != nil {
+			return err
+		}
+
+		if repo, err = s.resolveRemotes(repo); err != nil {
+			return err
+		}
+
 		branchName, err := s.workspaceBranchName(workspaceID, workspace.BranchName)
 		if err != nil {
 			return err
@@ -90,7 +99,10 @@ func (s *Service) AddRepoToWorkspace(ctx context.Context, workspaceID, repoName
 
 		s.cache.Invalidate(workspaceID)
 
-		return nil
+		added := *workspace
+		added.Repos = []domain.Repo{repo}
+
+		return s.runPhaseHooks(HookPhasePostRepoAdd, added, dirName, false)
 	})
 }
 
@@ -140,16 +152,11 @@ func (s *Service) workspaceBranchName(workspaceID, branchName string) (string, e
 }
 
 func (s *Service) ensureWorkspaceWorktree(ctx context.Context, repo domain.Repo, dirName, branchName string) error {
-	if _, err := s.gitEngine.EnsureCanonical(ctx, repo.URL, repo.Name); err != nil {
+	if _, err := s.gitEngine.EnsureCanonical(ctx, repo.URL, repo.Name, s.cloneOptionsForRepo(repo)); err != nil {
 		return cerrors.WrapGitError(err, fmt.Sprintf("ensure canonical for %s", repo.Name))
 	}
 
-	worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
-	if err := s.gitEngine.CreateWorktree(ctx, repo.Name, worktreePath, branchName); err != nil {
-		return cerrors.WrapGitError(err, fmt.Sprintf("create worktree for %s", repo.Name))
-	}
-
-	return nil
+	return s.createRepoWorktree(ctx, repo, dirName, branchName)
 }
 
 func (s *Service) saveWorkspaceRepo(ctx context.Context, workspaceID string, workspace *domain.Workspace, repo domain.Repo) error {
@@ -3,33 +3,104 @@ package workspaces
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
 )
 
-// RestoreWorkspace recreates a workspace from the newest closed entry.
+// closedTimestampLayout is the format of the close timestamp in closed entry directory names.
+const closedTimestampLayout = "20060102T150405Z"
+
+// RestoreOptions configures which closed entry is restored, and under which ID.
+type RestoreOptions struct {
+	Force bool // Replace an active workspace with the target ID
+	// At selects the closed entry: empty for the newest, an index as listed by
+	// ListClosedEntries (0 is the newest), or the close timestamp.
+	At string
+	// As restores the workspace under a different ID.
+	As string
+	// RenameBranch renames branches named after the old ID to the new one when As is set.
+	RenameBranch bool
+}
+
+// RestoreWorkspace recreates a workspace from the newest closed entry, re-applies any archived
+// commits and uncommitted changes, and then runs post_restore hooks.
 func (s *Service) RestoreWorkspace(ctx context.Context, workspaceID string, force bool) error {
-	return s.withWorkspaceLock(ctx, workspaceID, true, func() error {
-		archive, err := s.wsEngine.LatestClosed(ctx, workspaceID)
+	return s.RestoreWorkspaceWithOptions(ctx, workspaceID, RestoreOptions{Force: force})
+}
+
+// RestoreWorkspaceWithOptions recreates a workspace from the closed entry selected by opts.At,
+// optionally under the ID opts.As, re-applies any archived commits and uncommitted changes, and
+// then runs post_restore hooks. The closed entry is removed once the workspace is restored.
+func (s *Service) RestoreWorkspaceWithOptions(ctx context.Context, workspaceID string, opts RestoreOptions) error {
+	targetID := workspaceID
+	if opts.As != "" {
+		if err := s.validateRenameInputs(opts.As); err != nil {
+			return err
+		}
+
+		targetID = opts.As
+	}
+
+	return s.withRestoreLocks(ctx, workspaceID, targetID, func() error {
+		archive, err := s.FindClosedEntry(ctx, workspaceID, opts.At)
 		if err != nil {
 			return err
 		}
 
-		if err := s.ensureRestoreTargetAvailable(ctx, workspaceID, force); err != nil {
+		if err := s.ensureRestoreTargetAvailable(ctx, targetID, opts.Force); err != nil {
 			return err
 		}
 
 		ws := archive.Metadata
+		ws.ID = targetID
 		ws.ClosedAt = nil
+		ws.Archives = nil
 
 		dirName, err := s.config.ComputeWorkspaceDir(ws.ID)
 		if err != nil {
 			return err
 		}
 
+		ws.DirName = dirName
+
+		renameBranch := targetID != workspaceID && opts.RenameBranch && ws.BranchName == workspaceID
+
+		// An active workspace may still have the archived branch checked out; the restored
+		// workspace then gets its own branch, started from the archived one
+		var startPoints map[string]string
+
+		if renameBranch {
+			inUse, err := s.branchCheckedOutBy(ctx, workspaceID, ws.BranchName)
+			if err != nil {
+				return err
+			}
+
+			if inUse {
+				startPoints, err = s.renamedBranchStartPoints(ctx, ws, workspaceID, targetID)
+				if err != nil {
+					return err
+				}
+
+				ws.BranchName = targetID
+				renameBranch = false
+			}
+		}
+
 		op := NewOperation(s.logger)
 		op.AddStep(func() error {
-			if err := s.createWorkspaceWithOptionsUnlocked(ctx, ws.ID, dirName, ws.BranchName, ws.Repos, CreateOptions{}); err != nil {
+			return s.createBranches(ctx, startPoints, targetID)
+		}, func() error {
+			return s.deleteBranches(ctx, startPoints, targetID)
+		})
+		op.AddStep(func() error {
+			if err := s.createWorkspaceWithOptionsUnlocked(ctx, ws, CreateOptions{}); err != nil {
 				// Preserve original error type if it's already typed
 				var canopyErr *cerrors.CanopyError
 				if isCanopyError(err, &canopyErr) {
@@ -48,6 +119,31 @@ func (s *Service) RestoreWorkspace(ctx context.Context, workspaceID string, forc
 
 			return nil
 		})
+		op.AddStep(func() error {
+			// Re-apply archived commits and changes; a failure rolls back the restored workspace
+			return s.restoreArchives(ctx, archive, dirName)
+		}, nil)
+
+		if renameBranch {
+			op.AddStep(func() error {
+				// Rename the archived branch the way a workspace rename does, so it does not linger
+				if err := s.renameBranchesInRepos(ctx, ws, dirName, workspaceID, targetID); err != nil {
+					s.rollbackBranchRenames(ctx, ws, dirName, workspaceID, targetID)
+					return err
+				}
+
+				if err := s.updateBranchMetadata(ctx, targetID, targetID); err != nil {
+					s.rollbackBranchRenames(ctx, ws, dirName, workspaceID, targetID)
+					return err
+				}
+
+				ws.BranchName = targetID
+				s.invalidateWorkspaceCache(targetID)
+
+				return nil
+			}, nil)
+		}
+
 		op.AddStep(func() error {
 			// Delete the closed entry using ID and timestamp
 			closedAt := archive.ClosedAt()
@@ -58,8 +154,186 @@ func (s *Service) RestoreWorkspace(ctx context.Context, workspaceID string, forc
 			return nil
 		}, nil)
 
-		return op.Execute()
+		if err := op.Execute(); err != nil {
+			return err
+		}
+
+		return s.runPhaseHooks(HookPhasePostRestore, ws, dirName, false)
+	})
+}
+
+// withRestoreLocks locks both the ID a workspace is restored from and the ID it is restored as,
+// in a fixed order so that restores in opposite directions cannot deadlock. A directory created
+// only to hold the source lock is removed again.
+func (s *Service) withRestoreLocks(ctx context.Context, sourceID, targetID string, fn func() error) error {
+	if sourceID == targetID || s.lockManager == nil {
+		return s.withWorkspaceLock(ctx, targetID, true, fn)
+	}
+
+	sourceDirName, err := s.config.ComputeWorkspaceDir(sourceID)
+	if err != nil {
+		return err
+	}
+
+	sourceDir := filepath.Join(s.config.GetWorkspacesRoot(), sourceDirName)
+	_, statErr := os.Stat(sourceDir)
+
+	first, second := sourceID, targetID
+	if second < first {
+		first, second = second, first
+	}
+
+	err = s.withWorkspaceLock(ctx, first, true, func() error {
+		return s.withWorkspaceLock(ctx, second, true, fn)
+	})
+
+	if os.IsNotExist(statErr) {
+		// Only succeeds while the directory is still empty
+		_ = os.Remove(sourceDir)
+	}
+
+	return err
+}
+
+// branchCheckedOutBy reports whether an active workspace with the given ID uses branch.
+func (s *Service) branchCheckedOutBy(ctx context.Context, workspaceID, branch string) (bool, error) {
+	active, _, err := s.findWorkspace(ctx, workspaceID)
+	if err != nil {
+		if isWorkspaceNotFound(err) {
+			return false, nil
+		}
+
+		return false, cerrors.NewIOFailed("check existing workspace", err)
+	}
+
+	return active.BranchName == branch, nil
+}
+
+// renamedBranchStartPoints returns, per repository, the branch that a workspace restored under a
+// new ID starts its own branch from: the archived branch, when it exists in the canonical
+// repository and the new branch does not.
+func (s *Service) renamedBranchStartPoints(ctx context.Context, ws domain.Workspace, oldBranch, newBranch string) (map[string]string, error) {
+	startPoints := make(map[string]string)
+
+	for _, repo := range ws.Repos {
+		canonicalPath := filepath.Join(s.config.GetProjectsRoot(), repo.Name)
+
+		oldExists, err := s.localBranchExists(ctx, canonicalPath, oldBranch)
+		if err != nil {
+			return nil, err
+		}
+
+		newExists, err := s.localBranchExists(ctx, canonicalPath, newBranch)
+		if err != nil {
+			return nil, err
+		}
+
+		if oldExists && !newExists {
+			startPoints[repo.Name] = oldBranch
+		}
+	}
+
+	return startPoints, nil
+}
+
+// createBranches creates branch in each canonical repository at its start point.
+func (s *Service) createBranches(ctx context.Context, startPoints map[string]string, branch string) error {
+	for repo, startPoint := range startPoints {
+		if err := s.runCanonicalGit(ctx, repo, "branch", branch, startPoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteBranches deletes the branches created by createBranches.
+func (s *Service) deleteBranches(ctx context.Context, startPoints map[string]string, branch string) error {
+	var errs []error
+
+	for repo := range startPoints {
+		if err := s.runCanonicalGit(ctx, repo, "branch", "-D", branch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return joinErrors(errs...)
+}
+
+// runCanonicalGit runs a git command in a canonical repository and fails on a non-zero exit.
+func (s *Service) runCanonicalGit(ctx context.Context, repo string, args ...string) error {
+	result, err := s.gitEngine.RunCommand(ctx, filepath.Join(s.config.GetProjectsRoot(), repo), args...)
+	if err != nil {
+		return err
+	}
+
+	if result.ExitCode != 0 {
+		return cerrors.NewCommandFailed("git "+strings.Join(args, " "),
+			fmt.Errorf("exit code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr)))
+	}
+
+	return nil
+}
+
+// localBranchExists reports whether a branch exists in a repository.
+func (s *Service) localBranchExists(ctx context.Context, repoPath, branch string) (bool, error) {
+	result, err := s.gitEngine.RunCommand(ctx, repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ExitCode == 0, nil
+}
+
+// ListClosedEntries returns every closed entry for a workspace ID, newest first, with the disk
+// usage of each entry.
+func (s *Service) ListClosedEntries(ctx context.Context, workspaceID string) ([]domain.ClosedWorkspace, error) {
+	closed, err := s.ListClosedWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	closed = slices.DeleteFunc(closed, func(entry domain.ClosedWorkspace) bool {
+		return entry.Metadata.ID != workspaceID
 	})
+
+	if len(closed) == 0 {
+		return nil, cerrors.NewWorkspaceNotFound(workspaceID).WithContext("state", "closed")
+	}
+
+	return closed, nil
+}
+
+// FindClosedEntry returns the closed entry of a workspace selected by at: the newest entry when
+// at is empty, the entry at that index in ListClosedEntries when at is a number, or the entry
+// closed at that time when at is an RFC 3339 or closed directory (20060102T150405Z) timestamp.
+func (s *Service) FindClosedEntry(ctx context.Context, workspaceID, at string) (*domain.ClosedWorkspace, error) {
+	if at == "" {
+		return s.wsEngine.LatestClosed(ctx, workspaceID)
+	}
+
+	if index, err := strconv.Atoi(at); err == nil {
+		entries, err := s.ListClosedEntries(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if index < 0 || index >= len(entries) {
+			return nil, cerrors.NewInvalidArgument("at",
+				fmt.Sprintf("index %d is out of range; workspace %s has %d closed entries", index, workspaceID, len(entries)))
+		}
+
+		return &entries[index], nil
+	}
+
+	for _, layout := range []string{time.RFC3339, closedTimestampLayout} {
+		if closedAt, err := time.Parse(layout, at); err == nil {
+			return s.wsEngine.LoadClosed(ctx, workspaceID, closedAt)
+		}
+	}
+
+	return nil, cerrors.NewInvalidArgument("at",
+		fmt.Sprintf("%q is neither an entry index nor a timestamp such as 2024-01-02T15:04:05Z", at))
 }
 
 func (s *Service) ensureRestoreTargetAvailable(ctx context.Context, workspaceID string, force bool) error {
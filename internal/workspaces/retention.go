@@ -0,0 +1,98 @@
+package workspaces
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+)
+
+// PlanClosedPrune lists the closed entries that exceed the closed_retention limits, newest
+// first. Entries older than max_age_days are pruned, then each workspace ID keeps its newest
+// max_per_workspace entries, then the oldest remaining entries are pruned until the rest fit in
+// max_total_size. Entries without a recorded close time are never pruned.
+func (s *Service) PlanClosedPrune(ctx context.Context) ([]domain.ClosedPruneCandidate, error) {
+	policy := s.config.GetClosedRetention()
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	maxTotalBytes, err := policy.MaxTotalBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := s.ListClosedWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	closed = slices.DeleteFunc(closed, func(entry domain.ClosedWorkspace) bool {
+		return entry.ClosedAt().IsZero()
+	})
+
+	reasons := make([]domain.RetentionReason, len(closed))
+	cutoff := time.Now().AddDate(0, 0, -policy.MaxAgeDays)
+	kept := make(map[string]int)
+
+	var keptBytes int64
+
+	for i, entry := range closed {
+		switch {
+		case policy.MaxAgeDays > 0 && entry.ClosedAt().Before(cutoff):
+			reasons[i] = domain.RetentionReasonMaxAge
+		case policy.MaxPerWorkspace > 0 && kept[entry.Metadata.ID] >= policy.MaxPerWorkspace:
+			reasons[i] = domain.RetentionReasonMaxPerWorkspace
+		default:
+			kept[entry.Metadata.ID]++
+			keptBytes += entry.Metadata.DiskUsageBytes
+		}
+	}
+
+	// Entries are newest first, so walk backwards to drop the oldest
+	for i := len(closed) - 1; i >= 0 && maxTotalBytes > 0 && keptBytes > maxTotalBytes; i-- {
+		if reasons[i] == "" {
+			reasons[i] = domain.RetentionReasonMaxTotalSize
+			keptBytes -= closed[i].Metadata.DiskUsageBytes
+		}
+	}
+
+	var candidates []domain.ClosedPruneCandidate
+
+	for i, entry := range closed {
+		if reasons[i] == "" {
+			continue
+		}
+
+		candidates = append(candidates, domain.ClosedPruneCandidate{
+			WorkspaceID:    entry.Metadata.ID,
+			ClosedAt:       entry.ClosedAt(),
+			Path:           entry.Path,
+			DiskUsageBytes: entry.Metadata.DiskUsageBytes,
+			Reason:         reasons[i],
+		})
+	}
+
+	return candidates, nil
+}
+
+// PruneClosed deletes the given closed entries, continuing past failures.
+// The result holds one entry per candidate, in order.
+func (s *Service) PruneClosed(ctx context.Context, candidates []domain.ClosedPruneCandidate) []BulkWorkspaceResult {
+	results := make([]BulkWorkspaceResult, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		// The entry is loaded first so the refs of its snapshots can be deleted with it
+		entry, _ := s.wsEngine.LoadClosed(ctx, candidate.WorkspaceID, candidate.ClosedAt)
+
+		err := s.wsEngine.DeleteClosed(ctx, candidate.WorkspaceID, candidate.ClosedAt)
+		if err == nil && entry != nil {
+			s.gitService.deleteUnusedSnapshotRefs(ctx, entry.Metadata.Snapshots)
+		}
+
+		results = append(results, BulkWorkspaceResult{WorkspaceID: candidate.WorkspaceID, Err: err})
+	}
+
+	return results
+}
@@ -0,0 +1,158 @@
+package workspaces
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+)
+
+func TestPlanClosedPrune(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	entry := func(id string, daysAgo int, size int64) domain.ClosedWorkspace {
+		closedAt := now.AddDate(0, 0, -daysAgo)
+
+		return domain.ClosedWorkspace{
+			Path:     filepath.Join("closed", id, closedAt.Format("20060102T150405Z")),
+			Metadata: domain.Workspace{ID: id, ClosedAt: &closedAt, DiskUsageBytes: size},
+		}
+	}
+
+	// Newest first, as returned by storage
+	closed := []domain.ClosedWorkspace{
+		entry("ws-a", 1, 400),
+		entry("ws-b", 2, 400),
+		entry("ws-a", 3, 100),
+		entry("ws-a", 5, 100),
+		entry("ws-b", 10, 400),
+		entry("ws-c", 100, 10),
+		{Path: "closed/ws-d/unknown", Metadata: domain.Workspace{ID: "ws-d"}},
+	}
+
+	tests := []struct {
+		name   string
+		policy config.ClosedRetentionConfig
+		want   map[string]domain.RetentionReason // keyed by "<id>/<days ago>"
+	}{
+		{
+			name:   "disabled",
+			policy: config.ClosedRetentionConfig{},
+			want:   map[string]domain.RetentionReason{},
+		},
+		{
+			name:   "max age",
+			policy: config.ClosedRetentionConfig{MaxAgeDays: 30},
+			want:   map[string]domain.RetentionReason{"ws-c/100": domain.RetentionReasonMaxAge},
+		},
+		{
+			name:   "max per workspace",
+			policy: config.ClosedRetentionConfig{MaxPerWorkspace: 1},
+			want: map[string]domain.RetentionReason{
+				"ws-a/3":  domain.RetentionReasonMaxPerWorkspace,
+				"ws-a/5":  domain.RetentionReasonMaxPerWorkspace,
+				"ws-b/10": domain.RetentionReasonMaxPerWorkspace,
+			},
+		},
+		{
+			name:   "max total size drops the oldest first",
+			policy: config.ClosedRetentionConfig{MaxTotalSize: "1000B"},
+			want: map[string]domain.RetentionReason{
+				"ws-c/100": domain.RetentionReasonMaxTotalSize,
+				"ws-b/10":  domain.RetentionReasonMaxTotalSize,
+			},
+		},
+		{
+			name:   "size counts only entries kept by the other limits",
+			policy: config.ClosedRetentionConfig{MaxAgeDays: 30, MaxPerWorkspace: 2, MaxTotalSize: "900"},
+			want: map[string]domain.RetentionReason{
+				"ws-c/100": domain.RetentionReasonMaxAge,
+				"ws-a/5":   domain.RetentionReasonMaxPerWorkspace,
+				"ws-b/10":  domain.RetentionReasonMaxTotalSize,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			deps := newMockService(t)
+			deps.config.ClosedRetention = tt.policy
+			deps.storage.ListClosedFunc = func(_ context.Context) ([]domain.ClosedWorkspace, error) {
+				return append([]domain.ClosedWorkspace(nil), closed...), nil
+			}
+			deps.disk.CalculateFunc = func(root string) (int64, time.Time, error) {
+				for _, c := range closed {
+					if c.Path == root {
+						return c.Metadata.DiskUsageBytes, time.Time{}, nil
+					}
+				}
+
+				return 0, time.Time{}, nil
+			}
+
+			candidates, err := deps.svc.PlanClosedPrune(context.Background())
+			if err != nil {
+				t.Fatalf("PlanClosedPrune failed: %v", err)
+			}
+
+			got := map[string]domain.RetentionReason{}
+			for _, c := range candidates {
+				daysAgo := int(now.Sub(c.ClosedAt).Hours() / 24)
+				got[c.WorkspaceID+"/"+strconv.Itoa(daysAgo)] = c.Reason
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("candidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneClosed(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+
+	closedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var deleted []string
+
+	deps.storage.DeleteClosedFunc = func(_ context.Context, id string, at time.Time) error {
+		if !at.Equal(closedAt) {
+			t.Errorf("DeleteClosed(%s) closedAt = %v, want %v", id, at, closedAt)
+		}
+
+		if id == "ws-b" {
+			return errors.New("permission denied")
+		}
+
+		deleted = append(deleted, id)
+
+		return nil
+	}
+
+	results := deps.svc.PruneClosed(context.Background(), []domain.ClosedPruneCandidate{
+		{WorkspaceID: "ws-a", ClosedAt: closedAt},
+		{WorkspaceID: "ws-b", ClosedAt: closedAt},
+		{WorkspaceID: "ws-c", ClosedAt: closedAt},
+	})
+
+	if len(results) != 3 || results[0].Err != nil || results[1].Err == nil || results[2].Err != nil {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	if !reflect.DeepEqual(deleted, []string{"ws-a", "ws-c"}) {
+		t.Errorf("expected pruning to continue past failures, deleted %v", deleted)
+	}
+}
@@ -0,0 +1,103 @@
+package workspaces
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	"github.com/alexisbeaulieu97/canopy/internal/giturl"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
+)
+
+// Compile-time check that ReviewStatusCache implements ports.ReviewStatusProvider.
+var _ ports.ReviewStatusProvider = (*ReviewStatusCache)(nil)
+
+// DefaultReviewStatusTTL is how long fetched review status is reused.
+const DefaultReviewStatusTTL = time.Minute
+
+// reviewEntry caches the review status of a branch.
+type reviewEntry struct {
+	status    *domain.ReviewStatus
+	fetchedAt time.Time
+	err       error
+}
+
+// ReviewStatusCache fetches pull request review status from forges and caches it.
+type ReviewStatusCache struct {
+	forges ports.ForgeResolver
+	cache  map[string]reviewEntry
+	mu     sync.Mutex
+	ttl    time.Duration
+}
+
+// NewReviewStatusCache creates a new ReviewStatusCache with the specified cache TTL.
+func NewReviewStatusCache(forges ports.ForgeResolver, ttl time.Duration) *ReviewStatusCache {
+	return &ReviewStatusCache{
+		forges: forges,
+		cache:  make(map[string]reviewEntry),
+		ttl:    ttl,
+	}
+}
+
+// CachedReviewStatus returns the cached review status of a branch, fetching it from the
+// forge when the entry is missing or older than the TTL. Errors are cached too, so an
+// unreachable forge is not queried again on every refresh.
+func (r *ReviewStatusCache) CachedReviewStatus(ctx context.Context, remote, headRepo giturl.Remote, branch string) (*domain.ReviewStatus, error) {
+	key := reviewCacheKey(remote, headRepo, branch)
+
+	r.mu.Lock()
+
+	entry, ok := r.cache[key]
+	if ok && time.Since(entry.fetchedAt) < r.ttl {
+		r.mu.Unlock()
+		return entry.status, entry.err
+	}
+
+	r.mu.Unlock()
+
+	// Hosts without a forge or an API token simply have no review status
+	forge, err := r.forges.ForgeFor(remote.Host)
+	if err != nil {
+		return nil, nil
+	}
+
+	status, err := forge.ReviewStatus(ctx, remote.Owner, remote.Name, branch, headRepo)
+	if err != nil && ctx.Err() != nil {
+		// Do not cache cancellations
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = reviewEntry{
+		status:    status,
+		fetchedAt: time.Now(),
+		err:       err,
+	}
+	r.mu.Unlock()
+
+	return status, err
+}
+
+// InvalidateCache clears the cache entry for a branch.
+func (r *ReviewStatusCache) InvalidateCache(remote, headRepo giturl.Remote, branch string) {
+	r.mu.Lock()
+	delete(r.cache, reviewCacheKey(remote, headRepo, branch))
+	r.mu.Unlock()
+}
+
+// ClearCache clears all cached entries.
+func (r *ReviewStatusCache) ClearCache() {
+	r.mu.Lock()
+	r.cache = make(map[string]reviewEntry)
+	r.mu.Unlock()
+}
+
+func reviewCacheKey(remote, headRepo giturl.Remote, branch string) string {
+	key := remote.Host + "/" + remote.FullName() + "@" + branch
+	if headRepo != (giturl.Remote{}) {
+		key += "@" + headRepo.FullName()
+	}
+
+	return key
+}
@@ -9,9 +9,15 @@
 //
 // Workspace lifecycle:
 //   - CreateWorkspace: Creates a new workspace with repositories
+//   - LookupIssue: Fetches the issue a workspace ID refers to from the issue tracker
 //   - CloseWorkspace: Removes a workspace (with optional archival)
 //   - ReopenWorkspace: Restores an archived workspace
 //   - RenameWorkspace: Renames workspace and associated branches
+//   - AnnotateWorkspace: Updates the description, labels and notes of a workspace
+//
+// Forge operations:
+//   - CreatePullRequests: Opens cross-linked pull requests for every repository
+//   - GetReviewStatus: Returns pull request review and CI status per repository
 //
 // Repository operations:
 //   - AddRepoToWorkspace: Adds a repository to an existing workspace
@@ -48,7 +54,9 @@ import (
 	"github.com/alexisbeaulieu97/canopy/internal/config"
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
 	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/forge"
 	"github.com/alexisbeaulieu97/canopy/internal/hooks"
+	"github.com/alexisbeaulieu97/canopy/internal/issuetracker"
 	"github.com/alexisbeaulieu97/canopy/internal/logging"
 	"github.com/alexisbeaulieu97/canopy/internal/ports"
 )
@@ -71,6 +79,16 @@ type Service struct {
 
 	lockManager *LockManager
 
+	// forges opens pull requests on the services hosting repositories
+	forges  ports.ForgeResolver
+	reviews ports.ReviewStatusProvider
+
+	// issues looks up the issue a workspace ID refers to; nil when no tracker is configured
+	issues ports.IssueTracker
+
+	// fetchStatus records background fetch runs; nil when they are not recorded
+	fetchStatus ports.FetchStatusStore
+
 	// Extracted sub-services
 	gitService    *WorkspaceGitService
 	orphanService *WorkspaceOrphanService
@@ -85,10 +103,14 @@ type ServiceOption func(*serviceOptions)
 
 // serviceOptions holds optional dependencies that can be injected.
 type serviceOptions struct {
-	hookExecutor ports.HookExecutor
-	diskUsage    ports.DiskUsage
-	cache        ports.WorkspaceCache
-	lockManager  *LockManager
+	hookExecutor  ports.HookExecutor
+	diskUsage     ports.DiskUsage
+	cache         ports.WorkspaceCache
+	lockManager   *LockManager
+	forgeResolver ports.ForgeResolver
+	reviewStatus  ports.ReviewStatusProvider
+	issueTracker  ports.IssueTracker
+	fetchStatus   ports.FetchStatusStore
 }
 
 // WithHookExecutor sets a custom HookExecutor implementation.
@@ -119,6 +141,34 @@ func WithLockManager(l *LockManager) ServiceOption {
 	}
 }
 
+// WithForgeResolver sets a custom ForgeResolver implementation.
+func WithForgeResolver(r ports.ForgeResolver) ServiceOption {
+	return func(o *serviceOptions) {
+		o.forgeResolver = r
+	}
+}
+
+// WithReviewStatusProvider sets a custom ReviewStatusProvider implementation.
+func WithReviewStatusProvider(r ports.ReviewStatusProvider) ServiceOption {
+	return func(o *serviceOptions) {
+		o.reviewStatus = r
+	}
+}
+
+// WithIssueTracker sets a custom IssueTracker implementation.
+func WithIssueTracker(t ports.IssueTracker) ServiceOption {
+	return func(o *serviceOptions) {
+		o.issueTracker = t
+	}
+}
+
+// WithFetchStatusStore sets where background fetch runs are recorded.
+func WithFetchStatusStore(f ports.FetchStatusStore) ServiceOption {
+	return func(o *serviceOptions) {
+		o.fetchStatus = f
+	}
+}
+
 // NewService creates a new workspace service.
 // Options can be provided to override default implementations for testing.
 func NewService(cfg ports.ConfigProvider, gitEngine ports.GitOperations, wsEngine ports.WorkspaceStorage, logger *logging.Logger, opts ...ServiceOption) *Service {
@@ -131,7 +181,7 @@ func NewService(cfg ports.ConfigProvider, gitEngine ports.GitOperations, wsEngin
 	// Use provided hook executor or create default
 	hookExecutor := options.hookExecutor
 	if hookExecutor == nil {
-		hookExecutor = hooks.NewExecutor(logger)
+		hookExecutor = hooks.NewExecutor(logger, hooks.WithParallelRunner(newHookRunner(cfg.GetParallelWorkers())))
 	}
 
 	// Use provided disk usage or create default
@@ -154,6 +204,21 @@ func NewService(cfg ports.ConfigProvider, gitEngine ports.GitOperations, wsEngin
 		}
 	}
 
+	forges := options.forgeResolver
+	if forges == nil {
+		forges = forge.NewResolver(cfg.GetForges())
+	}
+
+	reviews := options.reviewStatus
+	if reviews == nil {
+		reviews = NewReviewStatusCache(forges, DefaultReviewStatusTTL)
+	}
+
+	issues := options.issueTracker
+	if issues == nil {
+		issues = issuetracker.New(cfg.GetIssueTracker())
+	}
+
 	svc := &Service{
 		config:       cfg,
 		gitEngine:    gitEngine,
@@ -165,6 +230,10 @@ func NewService(cfg ports.ConfigProvider, gitEngine ports.GitOperations, wsEngin
 		canonical:    NewCanonicalRepoService(gitEngine, wsEngine, cfg.GetProjectsRoot(), logger, diskUsage, cfg.GetRegistry()),
 		cache:        cache,
 		lockManager:  lockManager,
+		forges:       forges,
+		reviews:      reviews,
+		issues:       issues,
+		fetchStatus:  options.fetchStatus,
 	}
 
 	// Initialize sub-services with the main service as the workspace finder/creator
@@ -239,8 +308,8 @@ func (s *Service) ListCanonicalRepos(ctx context.Context) ([]string, error) {
 }
 
 // AddCanonicalRepo adds a new repository to the cache and returns the canonical name.
-func (s *Service) AddCanonicalRepo(ctx context.Context, url string) (string, error) {
-	return s.canonical.Add(ctx, url)
+func (s *Service) AddCanonicalRepo(ctx context.Context, url string, opts domain.CloneOptions) (string, error) {
+	return s.canonical.Add(ctx, url, opts)
 }
 
 // RemoveCanonicalRepo removes a repository from the cache
@@ -258,11 +327,39 @@ func (s *Service) SyncCanonicalRepo(ctx context.Context, name string) error {
 	return s.canonical.Sync(ctx, name)
 }
 
+// DeepenCanonicalRepo fetches what a partial, shallow or single-branch cached repository left out.
+func (s *Service) DeepenCanonicalRepo(ctx context.Context, name string, opts ports.DeepenOptions) error {
+	return s.canonical.Deepen(ctx, name, opts)
+}
+
 // Git operations - delegated to WorkspaceGitService
 
-// PushWorkspace pushes all repos for a workspace.
+// PushWorkspace pushes all repos for a workspace, surrounded by pre_push and post_push hooks.
+// A failing pre_push hook vetoes the push.
 func (s *Service) PushWorkspace(ctx context.Context, workspaceID string) error {
-	return s.gitService.PushWorkspace(ctx, workspaceID)
+	_, err := s.PushWorkspaceWithOptions(ctx, workspaceID, PushOptions{})
+
+	return err
+}
+
+// PushWorkspaceWithOptions runs pre_push hooks, pushes all repos for a workspace, and runs
+// post_push hooks when every repo was pushed. The result reports the outcome per repo.
+func (s *Service) PushWorkspaceWithOptions(ctx context.Context, workspaceID string, opts PushOptions) (*domain.PushResult, error) {
+	workspace, dirName, err := s.findWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.runPhaseHooks(HookPhasePrePush, *workspace, dirName, false); err != nil {
+		return nil, err
+	}
+
+	result, err := s.gitService.PushWorkspaceWithOptions(ctx, workspaceID, opts)
+	if err != nil {
+		return result, err
+	}
+
+	return result, s.runPhaseHooks(HookPhasePostPush, *workspace, dirName, false)
 }
 
 // GitRunOptions contains options for running git commands across workspace repos.
@@ -290,6 +387,41 @@ func (s *Service) SwitchBranch(ctx context.Context, workspaceID, branchName stri
 	return s.gitService.SwitchBranch(ctx, workspaceID, branchName, create)
 }
 
+// SnapshotWorkspace records a named checkpoint of all repos in a workspace.
+func (s *Service) SnapshotWorkspace(ctx context.Context, workspaceID, name string) (*domain.Snapshot, error) {
+	var snapshot *domain.Snapshot
+
+	err := s.withWorkspaceLock(ctx, workspaceID, false, func() error {
+		var err error
+		snapshot, err = s.gitService.SnapshotWorkspace(ctx, workspaceID, name)
+
+		return err
+	})
+
+	return snapshot, err
+}
+
+// RollbackWorkspace restores all repos in a workspace to a snapshot.
+func (s *Service) RollbackWorkspace(ctx context.Context, workspaceID, name string, force bool) (*domain.Snapshot, error) {
+	var snapshot *domain.Snapshot
+
+	err := s.withWorkspaceLock(ctx, workspaceID, false, func() error {
+		var err error
+		snapshot, err = s.gitService.RollbackWorkspace(ctx, workspaceID, name, force)
+
+		return err
+	})
+
+	return snapshot, err
+}
+
+// DeleteSnapshot removes a snapshot from a workspace and deletes its refs.
+func (s *Service) DeleteSnapshot(ctx context.Context, workspaceID, name string) error {
+	return s.withWorkspaceLock(ctx, workspaceID, false, func() error {
+		return s.gitService.DeleteSnapshot(ctx, workspaceID, name)
+	})
+}
+
 // Orphan detection - delegated to WorkspaceOrphanService
 
 // DetectOrphans finds orphaned worktrees across all workspaces.
@@ -360,7 +492,7 @@ func (s *Service) GetCanonicalRepoStatus(ctx context.Context, name string) (*dom
 		return nil, err
 	}
 
-	return s.getCanonicalRepoStatus(ctx, name, usageMap)
+	return s.getCanonicalRepoStatus(ctx, name, usageMap, s.lastFetchRunOrNil())
 }
 
 // GetAllCanonicalRepoStatuses returns status for all canonical repositories.
@@ -379,6 +511,7 @@ func (s *Service) GetAllCanonicalRepoStatuses(ctx context.Context) ([]domain.Can
 		return nil, err
 	}
 
+	lastRun := s.lastFetchRunOrNil()
 	executor := NewParallelExecutor(s.config.GetParallelWorkers())
 
 	results, err := ParallelMap(ctx, executor, len(repoNames), func(runCtx context.Context, index int) (*domain.CanonicalRepoStatus, error) {
@@ -386,7 +519,7 @@ func (s *Service) GetAllCanonicalRepoStatuses(ctx context.Context) ([]domain.Can
 			return nil, runCtx.Err()
 		}
 
-		return s.getCanonicalRepoStatus(runCtx, repoNames[index], usageMap)
+		return s.getCanonicalRepoStatus(runCtx, repoNames[index], usageMap, lastRun)
 	}, ParallelOptions{ContinueOnError: true})
 	if err != nil {
 		return nil, err
@@ -411,8 +544,55 @@ func (s *Service) GetAllCanonicalRepoStatuses(ctx context.Context) ([]domain.Can
 	return statuses, nil
 }
 
-// getCanonicalRepoStatus is a helper that performs the status lookup with a precomputed usage map.
-func (s *Service) getCanonicalRepoStatus(_ context.Context, name string, usageMap map[string][]string) (*domain.CanonicalRepoStatus, error) {
+// MaintainCanonicalRepos runs git maintenance tasks on the named canonical repositories in
+// parallel, or on every canonical repository when names is empty. A failure is recorded in
+// the repository's result and does not stop the others.
+func (s *Service) MaintainCanonicalRepos(ctx context.Context, names, tasks []string) ([]domain.RepoMaintenanceResult, error) {
+	if _, err := resolveMaintenanceTasks(tasks); err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		var err error
+
+		names, err = s.canonical.List(ctx)
+		if err != nil {
+			return nil, cerrors.WrapGitError(err, "list canonical repos")
+		}
+	}
+
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(s.config.GetProjectsRoot(), name)); os.IsNotExist(err) {
+			return nil, cerrors.NewRepoNotFound(name)
+		}
+	}
+
+	executor := NewParallelExecutor(s.config.GetParallelWorkers())
+
+	results, err := ParallelMap(ctx, executor, len(names), func(runCtx context.Context, index int) (domain.RepoMaintenanceResult, error) {
+		return s.canonical.Maintain(runCtx, names[index], tasks)
+	}, ParallelOptions{ContinueOnError: true})
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+
+	maintained := make([]domain.RepoMaintenanceResult, len(results))
+
+	for i, result := range results {
+		maintained[i] = result.Value
+		maintained[i].Name = names[i]
+
+		if result.Err != nil {
+			maintained[i].Error = result.Err.Error()
+		}
+	}
+
+	return maintained, nil
+}
+
+// getCanonicalRepoStatus is a helper that performs the status lookup with a precomputed usage map
+// and the latest background fetch run, which may be nil.
+func (s *Service) getCanonicalRepoStatus(_ context.Context, name string, usageMap map[string][]string, lastRun *domain.FetchRun) (*domain.CanonicalRepoStatus, error) {
 	path := filepath.Join(s.config.GetProjectsRoot(), name)
 
 	// Check if repo exists
@@ -427,21 +607,44 @@ func (s *Service) getCanonicalRepoStatus(_ context.Context, name string, usageMa
 		return nil, cerrors.NewIOFailed(fmt.Sprintf("get repo size for %s", name), err)
 	}
 
+	cloneMode, err := s.gitEngine.GetCloneMode(name)
+	if err != nil {
+		return nil, cerrors.WrapGitError(err, fmt.Sprintf("get clone mode for %s", name))
+	}
+
 	// Get last fetch time
 	lastFetch, err := s.gitEngine.LastFetchTime(name)
 	if err != nil {
 		return nil, cerrors.WrapGitError(err, fmt.Sprintf("get last fetch time for %s", name))
 	}
 
+	lastMaintenance, err := s.gitEngine.LastMaintenanceTime(name)
+	if err != nil {
+		return nil, cerrors.WrapGitError(err, fmt.Sprintf("get last maintenance time for %s", name))
+	}
+
 	usedBy := usageMap[name]
 
+	var backgroundFetch *domain.RepoFetchResult
+
+	if lastRun != nil {
+		for i := range lastRun.Repos {
+			if lastRun.Repos[i].Name == name {
+				backgroundFetch = &lastRun.Repos[i]
+			}
+		}
+	}
+
 	return &domain.CanonicalRepoStatus{
-		Name:           name,
-		Path:           path,
-		DiskUsageBytes: size,
-		LastFetchTime:  lastFetch,
-		UsedByCount:    len(usedBy),
-		UsedBy:         usedBy,
+		Name:                name,
+		Path:                path,
+		DiskUsageBytes:      size,
+		CloneMode:           cloneMode,
+		LastFetchTime:       lastFetch,
+		LastMaintenanceTime: lastMaintenance,
+		UsedByCount:         len(usedBy),
+		UsedBy:              usedBy,
+		BackgroundFetch:     backgroundFetch,
 	}, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -363,6 +364,403 @@ func TestCloseRestoreCycle(t *testing.T) {
 	}
 }
 
+func TestCloseRestoreCycle_ArchivesLocalWork(t *testing.T) {
+	deps := newTestService(t)
+
+	sourceRepo := filepath.Join(deps.projectsRoot, "source-archive")
+	createRepoWithCommit(t, sourceRepo)
+
+	canonicalPath := filepath.Join(deps.projectsRoot, "sample-archive")
+	runGit(t, "", "clone", "--bare", sourceRepo, canonicalPath)
+
+	repoURL := "file://" + sourceRepo
+
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "PROJ-3", "", []domain.Repo{{Name: "sample-archive", URL: repoURL}}); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	worktreePath := filepath.Join(deps.workspacesRoot, "PROJ-3", "sample-archive")
+
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "LOCAL.md"), "local")
+	runGit(t, worktreePath, "add", "LOCAL.md")
+	runGit(t, worktreePath, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "local work")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "README.md"), "edited")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "NOTES.md"), "notes")
+
+	preview, err := deps.svc.PreviewCloseWorkspace(context.Background(), "PROJ-3", true)
+	if err != nil {
+		t.Fatalf("PreviewCloseWorkspace failed: %v", err)
+	}
+
+	if len(preview.Archives) != 1 || preview.Archives[0].Patch == "" {
+		t.Fatalf("expected preview to list a patch, got %+v", preview.Archives)
+	}
+
+	archived, err := deps.svc.CloseWorkspaceKeepMetadata(context.Background(), "PROJ-3", true)
+	if err != nil {
+		t.Fatalf("CloseWorkspaceKeepMetadata failed: %v", err)
+	}
+
+	archives := archived.Metadata.Archives
+	if len(archives) != 1 || archives[0].Patch == "" || archives[0].Bundle == "" || archives[0].Commits != 1 {
+		t.Fatalf("expected patch and single-commit bundle, got %+v", archives)
+	}
+
+	for _, name := range []string{archives[0].Patch, archives[0].Bundle} {
+		if _, err := os.Stat(filepath.Join(archived.Path, name)); err != nil {
+			t.Fatalf("expected archived artifact %s: %v", name, err)
+		}
+	}
+
+	// Drop the branch so the restored worktree can only recover the commit from the bundle
+	runGit(t, canonicalPath, "branch", "-D", "PROJ-3")
+
+	if err := deps.svc.RestoreWorkspace(context.Background(), "PROJ-3", false); err != nil {
+		t.Fatalf("RestoreWorkspace failed: %v", err)
+	}
+
+	for name, want := range map[string]string{"LOCAL.md": "local", "README.md": "edited", "NOTES.md": "notes"} {
+		if got := testutil.MustReadFile(t, filepath.Join(worktreePath, name)); got != want {
+			t.Errorf("%s = %q after restore, want %q", name, got, want)
+		}
+	}
+
+	ws, err := deps.wsEngine.Load(context.Background(), "PROJ-3")
+	if err != nil {
+		t.Fatalf("failed to load restored workspace: %v", err)
+	}
+
+	if len(ws.Archives) != 0 {
+		t.Errorf("expected restored metadata to drop archives, got %+v", ws.Archives)
+	}
+}
+
+func TestRestoreWorkspaceWithOptions_AtAndAs(t *testing.T) {
+	deps := newTestService(t)
+	ctx := context.Background()
+
+	sourceRepo := filepath.Join(deps.projectsRoot, "source-history")
+	createRepoWithCommit(t, sourceRepo)
+	runGit(t, "", "clone", "--bare", sourceRepo, filepath.Join(deps.projectsRoot, "sample-history"))
+
+	repos := []domain.Repo{{Name: "sample-history", URL: "file://" + sourceRepo}}
+
+	// An older closed entry with different metadata
+	olderAt := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	if err := deps.wsEngine.Create(ctx, domain.Workspace{ID: "PROJ-5", Description: "older"}); err != nil {
+		t.Fatalf("failed to seed workspace: %v", err)
+	}
+
+	if _, err := deps.wsEngine.Close(ctx, "PROJ-5", olderAt); err != nil {
+		t.Fatalf("failed to seed closed entry: %v", err)
+	}
+
+	if err := deps.wsEngine.Delete(ctx, "PROJ-5"); err != nil {
+		t.Fatalf("failed to remove seeded workspace: %v", err)
+	}
+
+	if _, err := deps.svc.CreateWorkspace(ctx, "PROJ-5", "", repos); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	worktreePath := filepath.Join(deps.workspacesRoot, "PROJ-5", "sample-history")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "LOCAL.md"), "local")
+	runGit(t, worktreePath, "add", "LOCAL.md")
+	runGit(t, worktreePath, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "local work")
+
+	if _, err := deps.svc.CloseWorkspaceKeepMetadata(ctx, "PROJ-5", true); err != nil {
+		t.Fatalf("CloseWorkspaceKeepMetadata failed: %v", err)
+	}
+
+	for _, at := range []string{"1", olderAt.Format(time.RFC3339), olderAt.Format("20060102T150405Z")} {
+		entry, err := deps.svc.FindClosedEntry(ctx, "PROJ-5", at)
+		if err != nil {
+			t.Fatalf("FindClosedEntry(%q) failed: %v", at, err)
+		}
+
+		if entry.Metadata.Description != "older" {
+			t.Errorf("FindClosedEntry(%q) returned %+v, want the older entry", at, entry.Metadata)
+		}
+	}
+
+	for _, at := range []string{"2", "-1", "last week"} {
+		if _, err := deps.svc.FindClosedEntry(ctx, "PROJ-5", at); err == nil {
+			t.Errorf("FindClosedEntry(%q) succeeded, want error", at)
+		}
+	}
+
+	// Reopening under the same ID checks out the PROJ-5 branch, so the copy needs its own
+	if _, err := deps.svc.CreateWorkspace(ctx, "PROJ-5", "", repos); err != nil {
+		t.Fatalf("failed to recreate workspace: %v", err)
+	}
+
+	if err := deps.svc.RestoreWorkspaceWithOptions(ctx, "PROJ-5", RestoreOptions{At: "0", As: "PROJ-6", RenameBranch: true}); err != nil {
+		t.Fatalf("RestoreWorkspaceWithOptions failed: %v", err)
+	}
+
+	restoredPath := filepath.Join(deps.workspacesRoot, "PROJ-6", "sample-history")
+	if got := testutil.MustReadFile(t, filepath.Join(restoredPath, "LOCAL.md")); got != "local" {
+		t.Errorf("LOCAL.md = %q after restore, want %q", got, "local")
+	}
+
+	if branch := runGitOutput(t, restoredPath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "PROJ-6" {
+		t.Errorf("expected branch PROJ-6 after restoring as PROJ-6, got %s", branch)
+	}
+
+	restored, err := deps.wsEngine.Load(ctx, "PROJ-6")
+	if err != nil || restored.BranchName != "PROJ-6" {
+		t.Fatalf("expected PROJ-6 metadata with branch PROJ-6, got %+v, %v", restored, err)
+	}
+
+	entries, err := deps.svc.ListClosedEntries(ctx, "PROJ-5")
+	if err != nil {
+		t.Fatalf("ListClosedEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Metadata.Description != "older" {
+		t.Errorf("expected only the older entry to remain, got %+v", entries)
+	}
+
+	if _, err := deps.wsEngine.Load(ctx, "PROJ-5"); err != nil {
+		t.Errorf("expected active PROJ-5 to be untouched: %v", err)
+	}
+}
+
+func TestRestoreWorkspaceWithOptions_AsRenamesArchivedBranch(t *testing.T) {
+	deps := newTestService(t)
+	ctx := context.Background()
+
+	sourceRepo := filepath.Join(deps.projectsRoot, "source-rename")
+	createRepoWithCommit(t, sourceRepo)
+
+	canonicalPath := filepath.Join(deps.projectsRoot, "sample-rename")
+	runGit(t, "", "clone", "--bare", sourceRepo, canonicalPath)
+
+	repos := []domain.Repo{{Name: "sample-rename", URL: "file://" + sourceRepo}}
+
+	if _, err := deps.svc.CreateWorkspace(ctx, "PROJ-7", "", repos); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	worktreePath := filepath.Join(deps.workspacesRoot, "PROJ-7", "sample-rename")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "LOCAL.md"), "local")
+	runGit(t, worktreePath, "add", "LOCAL.md")
+	runGit(t, worktreePath, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-m", "local work")
+
+	if _, err := deps.svc.CloseWorkspaceKeepMetadata(ctx, "PROJ-7", true); err != nil {
+		t.Fatalf("CloseWorkspaceKeepMetadata failed: %v", err)
+	}
+
+	if err := deps.svc.RestoreWorkspaceWithOptions(ctx, "PROJ-7", RestoreOptions{As: "PROJ-8", RenameBranch: true}); err != nil {
+		t.Fatalf("RestoreWorkspaceWithOptions failed: %v", err)
+	}
+
+	restoredPath := filepath.Join(deps.workspacesRoot, "PROJ-8", "sample-rename")
+	if branch := runGitOutput(t, restoredPath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "PROJ-8" {
+		t.Errorf("expected branch PROJ-8 after restoring as PROJ-8, got %s", branch)
+	}
+
+	if got := testutil.MustReadFile(t, filepath.Join(restoredPath, "LOCAL.md")); got != "local" {
+		t.Errorf("LOCAL.md = %q after restore, want %q", got, "local")
+	}
+
+	if branches := runGitOutput(t, canonicalPath, "branch", "--list", "PROJ-*", "--format=%(refname:short)"); branches != "PROJ-8" {
+		t.Errorf("expected the archived branch to be renamed to PROJ-8, got branches %q", branches)
+	}
+
+	restored, err := deps.wsEngine.Load(ctx, "PROJ-8")
+	if err != nil || restored.BranchName != "PROJ-8" {
+		t.Fatalf("expected PROJ-8 metadata with branch PROJ-8, got %+v, %v", restored, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(deps.workspacesRoot, "PROJ-7")); !os.IsNotExist(err) {
+		t.Errorf("expected no directory for the source ID, got %v", err)
+	}
+}
+
+func TestSnapshotRollbackCycle(t *testing.T) {
+	deps := newTestService(t)
+
+	sourceRepo := filepath.Join(deps.projectsRoot, "source-snapshot")
+	createRepoWithCommit(t, sourceRepo)
+
+	canonicalPath := filepath.Join(deps.projectsRoot, "sample-snapshot")
+	runGit(t, "", "clone", "--bare", sourceRepo, canonicalPath)
+
+	repoURL := "file://" + sourceRepo
+
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "PROJ-4", "", []domain.Repo{{Name: "sample-snapshot", URL: repoURL}}); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	worktreePath := filepath.Join(deps.workspacesRoot, "PROJ-4", "sample-snapshot")
+	commit := func(message string) {
+		runGit(t, worktreePath, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-am", message)
+	}
+
+	headBefore := runGitOutput(t, worktreePath, "rev-parse", "HEAD")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "README.md"), "in progress")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "STAGED.md"), "staged")
+	runGit(t, worktreePath, "add", "STAGED.md")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "notes.txt"), "untracked")
+
+	snapshot, err := deps.svc.SnapshotWorkspace(context.Background(), "PROJ-4", "before")
+	if err != nil {
+		t.Fatalf("SnapshotWorkspace failed: %v", err)
+	}
+
+	if len(snapshot.Repos) != 1 || snapshot.Repos[0].Head != headBefore || snapshot.Repos[0].Stash == "" {
+		t.Fatalf("expected snapshot of HEAD plus stash commit, got %+v", snapshot.Repos)
+	}
+
+	if ref := runGitOutput(t, canonicalPath, "rev-parse", snapshot.Repos[0].Ref); ref != snapshot.Repos[0].Stash {
+		t.Errorf("expected canonical ref to point at stash commit, got %s", ref)
+	}
+
+	wantStatus := "M README.md\nA  STAGED.md\n?? notes.txt"
+	if status := runGitOutput(t, worktreePath, "status", "--porcelain"); status != wantStatus {
+		t.Errorf("expected snapshot to leave the worktree untouched, got %q", status)
+	}
+
+	if _, err := deps.svc.SnapshotWorkspace(context.Background(), "PROJ-4", "before"); err == nil {
+		t.Error("expected duplicate snapshot name to be rejected")
+	}
+
+	commit("risky change")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "README.md"), "more changes")
+	testutil.MustWriteFile(t, filepath.Join(worktreePath, "scratch.txt"), "created after the snapshot")
+	testutil.MustRemoveAll(t, filepath.Join(worktreePath, "notes.txt"))
+
+	if _, err := deps.svc.RollbackWorkspace(context.Background(), "PROJ-4", "before", false); err == nil {
+		t.Fatal("expected rollback of dirty workspace to fail without force")
+	}
+
+	if _, err := deps.svc.RollbackWorkspace(context.Background(), "PROJ-4", "before", true); err != nil {
+		t.Fatalf("RollbackWorkspace failed: %v", err)
+	}
+
+	if head := runGitOutput(t, worktreePath, "rev-parse", "HEAD"); head != headBefore {
+		t.Errorf("expected HEAD %s after rollback, got %s", headBefore, head)
+	}
+
+	if got := testutil.MustReadFile(t, filepath.Join(worktreePath, "README.md")); got != "in progress" {
+		t.Errorf("expected uncommitted change restored, got %q", got)
+	}
+
+	if status := runGitOutput(t, worktreePath, "status", "--porcelain"); status != wantStatus {
+		t.Errorf("expected staged and untracked files restored as they were, got %q", status)
+	}
+
+	ws, err := deps.wsEngine.Load(context.Background(), "PROJ-4")
+	if err != nil {
+		t.Fatalf("failed to load workspace: %v", err)
+	}
+
+	if ws.FindSnapshot("before") == nil {
+		t.Error("expected snapshot to be listed in workspace metadata")
+	}
+
+	if _, err := deps.svc.RollbackWorkspace(context.Background(), "PROJ-4", "missing", true); err == nil {
+		t.Error("expected unknown snapshot to be rejected")
+	}
+
+	if err := deps.svc.DeleteSnapshot(context.Background(), "PROJ-4", "before"); err != nil {
+		t.Fatalf("DeleteSnapshot failed: %v", err)
+	}
+
+	if refs := runGitOutput(t, canonicalPath, "for-each-ref", "refs/canopy/snapshots/"); refs != "" {
+		t.Errorf("expected the snapshot ref to be deleted, got %q", refs)
+	}
+
+	if ws, err := deps.wsEngine.Load(context.Background(), "PROJ-4"); err != nil || ws.FindSnapshot("before") != nil {
+		t.Errorf("expected the snapshot to be removed from metadata (err=%v)", err)
+	}
+}
+
+func TestCloseWorkspaceDeletesSnapshotRefs(t *testing.T) {
+	deps := newTestService(t)
+
+	sourceRepo := filepath.Join(deps.projectsRoot, "source-snapshot-close")
+	createRepoWithCommit(t, sourceRepo)
+
+	canonicalPath := filepath.Join(deps.projectsRoot, "sample-snapshot-close")
+	runGit(t, "", "clone", "--bare", sourceRepo, canonicalPath)
+
+	repos := []domain.Repo{{Name: "sample-snapshot-close", URL: "file://" + sourceRepo}}
+
+	for _, id := range []string{"PROJ-5", "PROJ-6"} {
+		if _, err := deps.svc.CreateWorkspace(context.Background(), id, "", repos); err != nil {
+			t.Fatalf("failed to create workspace %s: %v", id, err)
+		}
+
+		if _, err := deps.svc.SnapshotWorkspace(context.Background(), id, "checkpoint"); err != nil {
+			t.Fatalf("SnapshotWorkspace failed: %v", err)
+		}
+	}
+
+	if err := deps.svc.RenameWorkspace(context.Background(), "PROJ-6", "PROJ-7", true, false); err != nil {
+		t.Fatalf("RenameWorkspace failed: %v", err)
+	}
+
+	if err := deps.svc.CloseWorkspace(context.Background(), "PROJ-5", true); err != nil {
+		t.Fatalf("CloseWorkspace failed: %v", err)
+	}
+
+	if refs := runGitOutput(t, canonicalPath, "for-each-ref", "--format=%(refname)", "refs/canopy/snapshots/"); refs != "refs/canopy/snapshots/PROJ-7/checkpoint" {
+		t.Errorf("expected only the renamed workspace's snapshot ref to remain, got %q", refs)
+	}
+
+	ws, err := deps.wsEngine.Load(context.Background(), "PROJ-7")
+	if err != nil {
+		t.Fatalf("failed to load workspace: %v", err)
+	}
+
+	if ref := ws.FindSnapshot("checkpoint").Repos[0].Ref; ref != "refs/canopy/snapshots/PROJ-7/checkpoint" {
+		t.Errorf("expected the recorded ref to follow the branch rename, got %s", ref)
+	}
+}
+
+func TestSnapshotRefsOfNestedBranches(t *testing.T) {
+	deps := newTestService(t)
+
+	sourceRepo := filepath.Join(deps.projectsRoot, "source-snapshot-nested")
+	createRepoWithCommit(t, sourceRepo)
+
+	canonicalPath := filepath.Join(deps.projectsRoot, "sample-snapshot-nested")
+	runGit(t, "", "clone", "--bare", sourceRepo, canonicalPath)
+
+	repos := []domain.Repo{{Name: "sample-snapshot-nested", URL: "file://" + sourceRepo}}
+
+	// The closed entry keeps the ref of snapshot x on branch feature, which would otherwise
+	// be refs/canopy/snapshots/feature/x and block the snapshot of branch feature/x
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "PROJ-10", "feature", repos); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	if _, err := deps.svc.SnapshotWorkspace(context.Background(), "PROJ-10", "x"); err != nil {
+		t.Fatalf("SnapshotWorkspace failed: %v", err)
+	}
+
+	if _, err := deps.svc.CloseWorkspaceKeepMetadata(context.Background(), "PROJ-10", false); err != nil {
+		t.Fatalf("CloseWorkspaceKeepMetadata failed: %v", err)
+	}
+
+	runGit(t, canonicalPath, "branch", "-D", "feature")
+
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "PROJ-11", "feature/x", repos); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	if _, err := deps.svc.SnapshotWorkspace(context.Background(), "PROJ-11", "y"); err != nil {
+		t.Fatalf("SnapshotWorkspace failed: %v", err)
+	}
+
+	want := "refs/canopy/snapshots/feature%2Fx/y\nrefs/canopy/snapshots/feature/x"
+	if refs := runGitOutput(t, canonicalPath, "for-each-ref", "--format=%(refname)", "refs/canopy/snapshots/"); refs != want {
+		t.Errorf("expected one snapshot ref per workspace, got %q", refs)
+	}
+}
+
 func TestCloseWorkspaceDirtyFailsWithoutForce(t *testing.T) {
 	deps := newTestService(t)
 
@@ -936,6 +1334,46 @@ func TestSyncWorkspace(t *testing.T) {
 	}
 }
 
+func TestSyncWorkspace_OntoDefault(t *testing.T) {
+	deps := newTestService(t)
+
+	sourceRepo := filepath.Join(deps.projectsRoot, "source-onto")
+	testutil.CreateRepoWithCommit(t, sourceRepo)
+
+	repoURL := "file://" + sourceRepo
+
+	if _, err := deps.svc.CreateWorkspace(context.Background(), "ONTO-WS", "", []domain.Repo{{Name: "onto-repo", URL: repoURL}}); err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	testutil.MustWriteFile(t, filepath.Join(sourceRepo, "UPSTREAM.txt"), "upstream")
+	testutil.RunGit(t, sourceRepo, "add", ".")
+	testutil.RunGit(t, sourceRepo, "commit", "-m", "upstream commit")
+
+	result, err := deps.svc.SyncWorkspace(context.Background(), "ONTO-WS", SyncOptions{OntoDefault: true})
+	if err != nil {
+		t.Fatalf("SyncWorkspace failed: %v", err)
+	}
+
+	repo := result.Repos[0]
+	if repo.Status != domain.SyncStatusUpdated || repo.Updated != 1 {
+		t.Fatalf("expected 1 commit integrated, got %+v", repo)
+	}
+
+	if repo.Onto != "origin/main" {
+		t.Errorf("expected onto origin/main, got %q", repo.Onto)
+	}
+
+	worktreePath := filepath.Join(deps.workspacesRoot, "ONTO-WS", "onto-repo")
+	if _, err := os.Stat(filepath.Join(worktreePath, "UPSTREAM.txt")); err != nil {
+		t.Fatalf("expected upstream file in worktree: %v", err)
+	}
+
+	if branch := testutil.RunGitOutput(t, worktreePath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "ONTO-WS" {
+		t.Errorf("expected worktree to stay on ONTO-WS, got %s", branch)
+	}
+}
+
 func TestSyncWorkspacesMatching(t *testing.T) {
 	deps := newTestService(t)
 
@@ -952,19 +1390,17 @@ func TestSyncWorkspacesMatching(t *testing.T) {
 		t.Fatalf("failed to create workspace: %v", err)
 	}
 
-	configureSyncRemote := func(workspaceID string) {
+	// Worktrees share the canonical repo's config, so the remote is configured once
+	// and each workspace branch only needs its upstream set.
+	canonicalPath := filepath.Join(deps.projectsRoot, "sync-repo")
+	testutil.RunGit(t, canonicalPath, "remote", "set-url", "origin", repoURL)
+	testutil.RunGit(t, canonicalPath, "fetch", "origin", "+refs/heads/*:refs/remotes/origin/*")
+
+	for _, workspaceID := range []string{"SYNC-ONE", "SYNC-TWO"} {
 		worktreePath := filepath.Join(deps.workspacesRoot, workspaceID, "sync-repo")
-		canonicalPath := filepath.Join(deps.projectsRoot, "sync-repo")
-		_ = testutil.RunGitOutput(t, canonicalPath, "remote", "set-url", "origin", repoURL)
-		_ = testutil.RunGitOutput(t, worktreePath, "remote", "remove", "origin")
-		testutil.RunGit(t, worktreePath, "remote", "add", "origin", repoURL)
-		testutil.RunGit(t, worktreePath, "fetch", "origin")
 		testutil.RunGit(t, worktreePath, "branch", "--set-upstream-to=origin/main", workspaceID)
 	}
 
-	configureSyncRemote("SYNC-ONE")
-	configureSyncRemote("SYNC-TWO")
-
 	result, err := deps.svc.SyncWorkspacesMatching(context.Background(), "^SYNC-", SyncOptions{})
 	if err != nil {
 		t.Fatalf("SyncWorkspacesMatching failed: %v", err)
@@ -987,6 +1423,27 @@ func TestSyncWorkspacesMatching(t *testing.T) {
 	if !seen["SYNC-ONE"] || !seen["SYNC-TWO"] {
 		t.Fatalf("expected results for SYNC-ONE and SYNC-TWO, got %v", seen)
 	}
+
+	// New upstream commits reach both worktrees from a single canonical fetch.
+	testutil.MustWriteFile(t, filepath.Join(sourceRepo, "NEW.txt"), "new content")
+	testutil.RunGit(t, sourceRepo, "add", ".")
+	testutil.RunGit(t, sourceRepo, "commit", "-m", "new commit")
+
+	result, err = deps.svc.SyncWorkspacesMatching(context.Background(), "^SYNC-", SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncWorkspacesMatching failed: %v", err)
+	}
+
+	for _, res := range result.Results {
+		if res.Err != nil || res.Result.TotalUpdated != 1 {
+			t.Fatalf("expected 1 updated commit for %s, got %+v (err=%v)", res.WorkspaceID, res.Result, res.Err)
+		}
+
+		worktreeFile := filepath.Join(deps.workspacesRoot, res.WorkspaceID, "sync-repo", "NEW.txt")
+		if _, err := os.Stat(worktreeFile); err != nil {
+			t.Errorf("expected NEW.txt in %s: %v", res.WorkspaceID, err)
+		}
+	}
 }
 
 func TestAggregateSyncResultsCountsOnlySuccessfulUpdates(t *testing.T) {
@@ -1097,6 +1554,66 @@ func TestGetAllCanonicalRepoStatuses(t *testing.T) {
 	}
 }
 
+func TestMaintainCanonicalRepos(t *testing.T) {
+	deps := newTestService(t)
+
+	source := filepath.Join(t.TempDir(), "source")
+	createRepoWithCommit(t, source)
+
+	for _, name := range []string{"repo-a", "repo-b"} {
+		runGit(t, deps.projectsRoot, "clone", "--bare", "--quiet", source, name)
+	}
+
+	results, err := deps.svc.MaintainCanonicalRepos(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("MaintainCanonicalRepos failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+
+	for _, res := range results {
+		if res.Error != "" {
+			t.Errorf("maintaining %s failed: %s", res.Name, res.Error)
+		}
+
+		if res.SizeBeforeBytes <= 0 || res.SizeAfterBytes <= 0 {
+			t.Errorf("expected sizes before and after for %s, got %d and %d", res.Name, res.SizeBeforeBytes, res.SizeAfterBytes)
+		}
+
+		if !reflect.DeepEqual(res.Tasks, domain.MaintenanceTasks) {
+			t.Errorf("tasks for %s = %v, want %v", res.Name, res.Tasks, domain.MaintenanceTasks)
+		}
+	}
+
+	status, err := deps.svc.GetCanonicalRepoStatus(context.Background(), "repo-a")
+	if err != nil {
+		t.Fatalf("GetCanonicalRepoStatus failed: %v", err)
+	}
+
+	if status.LastMaintenanceTime == nil {
+		t.Error("expected the maintenance time to be recorded")
+	}
+
+	results, err = deps.svc.MaintainCanonicalRepos(context.Background(), []string{"repo-b"}, []string{"pack-refs", "gc"})
+	if err != nil {
+		t.Fatalf("MaintainCanonicalRepos failed: %v", err)
+	}
+
+	if len(results) != 1 || !reflect.DeepEqual(results[0].Tasks, []string{"gc", "pack-refs"}) {
+		t.Errorf("expected only repo-b with tasks in run order, got %+v", results)
+	}
+
+	if _, err := deps.svc.MaintainCanonicalRepos(context.Background(), []string{"missing"}, nil); err == nil {
+		t.Error("expected an error for a missing repository")
+	}
+
+	if _, err := deps.svc.MaintainCanonicalRepos(context.Background(), nil, []string{"prefetch"}); err == nil {
+		t.Error("expected an error for an unknown task")
+	}
+}
+
 func TestImportWorkspaceWithBranchOverride(t *testing.T) {
 	deps := newTestService(t)
 
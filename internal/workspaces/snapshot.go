@@ -0,0 +1,323 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/validation"
+)
+
+// snapshotRefPrefix is where snapshot refs live in canonical repositories.
+const snapshotRefPrefix = "refs/canopy/snapshots/"
+
+// SnapshotWorkspace records the HEAD of each repo plus a stash-like commit of any uncommitted
+// changes, untracked files included, keeps them reachable through a ref in the canonical repo,
+// and lists the snapshot in the workspace metadata. An empty name defaults to the current UTC
+// timestamp.
+func (s *WorkspaceGitService) SnapshotWorkspace(ctx context.Context, workspaceID, name string) (*domain.Snapshot, error) {
+	targetWorkspace, dirName, err := s.workspaceFinder.FindWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := time.Now().UTC()
+	if name == "" {
+		name = createdAt.Format("20060102-150405")
+	}
+
+	if err := validation.ValidateSnapshotName(name); err != nil {
+		return nil, err
+	}
+
+	if targetWorkspace.FindSnapshot(name) != nil {
+		return nil, cerrors.NewInvalidArgument("snapshot", fmt.Sprintf("snapshot %q already exists for workspace %s", name, workspaceID))
+	}
+
+	heads, err := s.RunGitInWorkspace(ctx, workspaceID, []string{"rev-parse", "HEAD"}, GitRunOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := domain.Snapshot{Name: name, CreatedAt: createdAt}
+
+	for i, repo := range targetWorkspace.Repos {
+		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
+
+		stash, err := s.gitEngine.SnapshotWorktree(ctx, worktreePath, "canopy snapshot "+name)
+		if err != nil {
+			return nil, cerrors.WrapGitError(err, fmt.Sprintf("snapshot repo %s", repo.Name))
+		}
+
+		repoSnapshot := domain.RepoSnapshot{
+			Repo:  repo.Name,
+			Head:  strings.TrimSpace(heads[i].Stdout),
+			Stash: stash,
+			Ref:   snapshotRef(targetWorkspace, name),
+		}
+
+		if err := s.runGit(ctx, worktreePath, "update-ref", repoSnapshot.Ref, repoSnapshot.Target()); err != nil {
+			return nil, err
+		}
+
+		snapshot.Repos = append(snapshot.Repos, repoSnapshot)
+	}
+
+	targetWorkspace.Snapshots = append(targetWorkspace.Snapshots, snapshot)
+	if err := s.wsEngine.Save(ctx, *targetWorkspace); err != nil {
+		return nil, cerrors.NewWorkspaceMetadataError(workspaceID, "update", err)
+	}
+
+	if s.cache != nil {
+		s.cache.Invalidate(workspaceID)
+	}
+
+	return &snapshot, nil
+}
+
+// RollbackWorkspace resets each repo to the HEAD recorded in a snapshot and restores the
+// snapshot's worktree and index, including the files that were untracked. Dirty repos are
+// refused unless force is set, in which case their uncommitted changes and untracked files
+// are discarded.
+func (s *WorkspaceGitService) RollbackWorkspace(ctx context.Context, workspaceID, name string, force bool) (*domain.Snapshot, error) {
+	targetWorkspace, dirName, err := s.workspaceFinder.FindWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := targetWorkspace.FindSnapshot(name)
+	if snapshot == nil {
+		return nil, cerrors.NewInvalidArgument("snapshot", fmt.Sprintf("snapshot %q not found for workspace %s", name, workspaceID))
+	}
+
+	worktreePaths := make(map[string]string, len(snapshot.Repos))
+
+	for _, repoSnapshot := range snapshot.Repos {
+		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repoSnapshot.Repo)
+		if _, err := os.Stat(worktreePath); err != nil {
+			if s.logger != nil {
+				s.logger.Warn("Skipping snapshot repo missing from workspace", "repo", repoSnapshot.Repo, "snapshot", name)
+			}
+
+			continue
+		}
+
+		if !force {
+			isDirty, _, _, _, err := s.gitEngine.Status(ctx, worktreePath)
+			if err != nil {
+				return nil, cerrors.NewIOFailed("check repo status for "+repoSnapshot.Repo, err)
+			}
+
+			if isDirty {
+				return nil, cerrors.NewRepoNotClean(repoSnapshot.Repo, "rollback")
+			}
+		}
+
+		worktreePaths[repoSnapshot.Repo] = worktreePath
+	}
+
+	for _, repoSnapshot := range snapshot.Repos {
+		worktreePath, ok := worktreePaths[repoSnapshot.Repo]
+		if !ok {
+			continue
+		}
+
+		if err := s.restoreRepoSnapshot(ctx, worktreePath, repoSnapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cache != nil {
+		s.cache.Invalidate(workspaceID)
+	}
+
+	return snapshot, nil
+}
+
+// DeleteSnapshot removes a snapshot from the workspace metadata and deletes its refs from the
+// canonical repos.
+func (s *WorkspaceGitService) DeleteSnapshot(ctx context.Context, workspaceID, name string) error {
+	targetWorkspace, _, err := s.workspaceFinder.FindWorkspace(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	snapshot := targetWorkspace.FindSnapshot(name)
+	if snapshot == nil {
+		return cerrors.NewInvalidArgument("snapshot", fmt.Sprintf("snapshot %q not found for workspace %s", name, workspaceID))
+	}
+
+	deleted := *snapshot
+
+	targetWorkspace.Snapshots = slices.DeleteFunc(targetWorkspace.Snapshots, func(snap domain.Snapshot) bool {
+		return snap.Name == name
+	})
+
+	if err := s.wsEngine.Save(ctx, *targetWorkspace); err != nil {
+		return cerrors.NewWorkspaceMetadataError(workspaceID, "update", err)
+	}
+
+	if s.cache != nil {
+		s.cache.Invalidate(workspaceID)
+	}
+
+	s.deleteUnusedSnapshotRefs(ctx, []domain.Snapshot{deleted})
+
+	return nil
+}
+
+// deleteUnusedSnapshotRefs deletes the canonical refs of snapshots that no active workspace or
+// closed entry lists anymore. It is called once their workspace or closed entry is gone, and
+// failures are logged since the metadata is already updated.
+func (s *WorkspaceGitService) deleteUnusedSnapshotRefs(ctx context.Context, snapshots []domain.Snapshot) {
+	if len(snapshots) == 0 {
+		return
+	}
+
+	inUse, err := s.snapshotRefsInUse(ctx)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("Keeping snapshot refs, failed to list workspaces", "error", err)
+		}
+
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		for _, repoSnapshot := range snapshot.Repos {
+			if inUse[repoSnapshot.Repo+" "+repoSnapshot.Ref] {
+				continue
+			}
+
+			// Only delete the ref if it still points at this snapshot
+			canonicalPath := filepath.Join(s.config.GetProjectsRoot(), repoSnapshot.Repo)
+			if err := s.runGit(ctx, canonicalPath, "update-ref", "-d", repoSnapshot.Ref, repoSnapshot.Target()); err != nil && s.logger != nil {
+				s.logger.Warn("Failed to delete snapshot ref", "repo", repoSnapshot.Repo, "ref", repoSnapshot.Ref, "error", err)
+			}
+		}
+	}
+}
+
+// snapshotRefsInUse returns the repo and ref, joined by a space, of every snapshot listed by an
+// active workspace or a closed entry.
+func (s *WorkspaceGitService) snapshotRefsInUse(ctx context.Context) (map[string]bool, error) {
+	active, err := s.wsEngine.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := s.wsEngine.ListClosed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range closed {
+		active = append(active, entry.Metadata)
+	}
+
+	inUse := make(map[string]bool)
+
+	for _, ws := range active {
+		for _, snapshot := range ws.Snapshots {
+			for _, repoSnapshot := range snapshot.Repos {
+				inUse[repoSnapshot.Repo+" "+repoSnapshot.Ref] = true
+			}
+		}
+	}
+
+	return inUse, nil
+}
+
+// moveSnapshotRefs points new refs, under the renamed branch of ws, at its snapshots and
+// records them in ws. It returns the snapshots as they were, whose refs are deleted with
+// deleteUnusedSnapshotRefs once ws is saved. A ref that cannot be created is kept as is.
+func (s *WorkspaceGitService) moveSnapshotRefs(ctx context.Context, ws *domain.Workspace) []domain.Snapshot {
+	previous := make([]domain.Snapshot, len(ws.Snapshots))
+
+	for i, snapshot := range ws.Snapshots {
+		previous[i] = snapshot
+		newRef := snapshotRef(ws, snapshot.Name)
+		repos := slices.Clone(snapshot.Repos)
+
+		for j, repoSnapshot := range repos {
+			if repoSnapshot.Ref == newRef {
+				continue
+			}
+
+			canonicalPath := filepath.Join(s.config.GetProjectsRoot(), repoSnapshot.Repo)
+			if err := s.runGit(ctx, canonicalPath, "update-ref", newRef, repoSnapshot.Target()); err != nil {
+				if s.logger != nil {
+					s.logger.Warn("Failed to move snapshot ref", "repo", repoSnapshot.Repo, "ref", repoSnapshot.Ref, "error", err)
+				}
+
+				continue
+			}
+
+			repos[j].Ref = newRef
+		}
+
+		ws.Snapshots[i].Repos = repos
+	}
+
+	return previous
+}
+
+// restoreRepoSnapshot resets a worktree to a repo snapshot. The stash commit's tree is the
+// worktree at snapshot time and its second parent holds the index, so files that were untracked
+// come back untracked and files created since are removed.
+func (s *WorkspaceGitService) restoreRepoSnapshot(ctx context.Context, worktreePath string, repoSnapshot domain.RepoSnapshot) error {
+	if err := s.runGit(ctx, worktreePath, "reset", "--hard", repoSnapshot.Head); err != nil {
+		return err
+	}
+
+	if err := s.runGit(ctx, worktreePath, "clean", "-fd"); err != nil {
+		return err
+	}
+
+	if repoSnapshot.Stash == "" {
+		return nil
+	}
+
+	if err := s.runGit(ctx, worktreePath, "read-tree", "--reset", "-u", repoSnapshot.Stash); err != nil {
+		return err
+	}
+
+	return s.runGit(ctx, worktreePath, "read-tree", repoSnapshot.Stash+"^2")
+}
+
+// runGit runs a git command in a worktree and treats a non-zero exit code as an error.
+func (s *WorkspaceGitService) runGit(ctx context.Context, worktreePath string, args ...string) error {
+	result, err := s.gitEngine.RunCommand(ctx, worktreePath, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.ExitCode != 0 {
+		return cerrors.NewCommandFailed(
+			"git "+strings.Join(args, " "),
+			fmt.Errorf("exit code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr)),
+		)
+	}
+
+	return nil
+}
+
+// snapshotRef returns the canonical ref for a workspace snapshot. Worktrees cannot share a
+// checked-out branch, so the branch name keeps refs from different workspaces apart. It is
+// escaped into a single ref component so that branches such as feature and feature/x do not
+// nest their refs inside each other.
+func snapshotRef(workspace *domain.Workspace, name string) string {
+	branch := workspace.BranchName
+	if branch == "" {
+		branch = workspace.ID
+	}
+
+	return snapshotRefPrefix + url.PathEscape(branch) + "/" + name
+}
@@ -0,0 +1,117 @@
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+)
+
+// resolveSparsePaths picks the sparse checkout directories for a repo of a new workspace.
+// Precedence: paths already recorded on the repo (restore, import), registry entry, then
+// the workspace template.
+func (s *Service) resolveSparsePaths(templateName string, repo domain.Repo) ([]string, error) {
+	if len(repo.SparsePaths) > 0 {
+		return config.NormalizeSparsePaths(repo.SparsePaths)
+	}
+
+	if entry, ok := s.registryEntryForRepo(repo); ok && len(entry.SparsePaths) > 0 {
+		return config.NormalizeSparsePaths(entry.SparsePaths)
+	}
+
+	if templateName != "" {
+		if tmpl, err := s.config.ResolveTemplate(templateName); err == nil {
+			for key, paths := range tmpl.SparsePaths {
+				if key == repo.Name || key == repo.URL {
+					return config.NormalizeSparsePaths(paths)
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// withSparsePaths returns a copy of repos with their sparse checkout directories resolved.
+func (s *Service) withSparsePaths(templateName string, repos []domain.Repo) ([]domain.Repo, error) {
+	resolved := make([]domain.Repo, len(repos))
+
+	for i, repo := range repos {
+		paths, err := s.resolveSparsePaths(templateName, repo)
+		if err != nil {
+			return nil, cerrors.NewInvalidArgument("sparse_paths", fmt.Sprintf("repo %s: %v", repo.Name, err))
+		}
+
+		repo.SparsePaths = paths
+		resolved[i] = repo
+	}
+
+	return resolved, nil
+}
+
+// UpdateSparsePaths adds and removes sparse checkout directories of a repository in a
+// workspace, applies them to its worktree and records them in the workspace metadata.
+// Removing every directory checks out the full tree. It returns the resulting directories.
+func (s *Service) UpdateSparsePaths(ctx context.Context, workspaceID, repoName string, add, remove []string) ([]string, error) {
+	if err := validateAddRepoInputs(workspaceID, repoName); err != nil {
+		return nil, err
+	}
+
+	addPaths, err := config.NormalizeSparsePaths(add)
+	if err != nil {
+		return nil, err
+	}
+
+	removePaths, err := config.NormalizeSparsePaths(remove)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []string
+
+	err = s.withWorkspaceLock(ctx, workspaceID, false, func() error {
+		workspace, dirName, err := s.findWorkspace(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
+
+		ws := *workspace
+		ws.Repos = slices.Clone(workspace.Repos)
+
+		index := slices.IndexFunc(ws.Repos, func(r domain.Repo) bool { return r.Name == repoName })
+		if index == -1 {
+			return cerrors.NewRepoNotFound(repoName).WithContext("workspace_id", workspaceID)
+		}
+
+		paths := slices.DeleteFunc(append(slices.Clone(ws.Repos[index].SparsePaths), addPaths...), func(p string) bool {
+			return slices.Contains(removePaths, p)
+		})
+
+		paths, err = config.NormalizeSparsePaths(paths)
+		if err != nil {
+			return err
+		}
+
+		worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repoName)
+		if err := s.gitEngine.SetSparsePaths(ctx, worktreePath, paths); err != nil {
+			return cerrors.WrapGitError(err, fmt.Sprintf("set sparse paths for %s", repoName))
+		}
+
+		ws.Repos[index].SparsePaths = paths
+		if err := s.wsEngine.Save(ctx, ws); err != nil {
+			return cerrors.NewWorkspaceMetadataError(workspaceID, "update", err)
+		}
+
+		s.cache.Invalidate(workspaceID)
+
+		updated = paths
+
+		return nil
+	})
+
+	return updated, err
+}
@@ -0,0 +1,111 @@
+package workspaces
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/alexisbeaulieu97/canopy/internal/config"
+	"github.com/alexisbeaulieu97/canopy/internal/domain"
+)
+
+func TestCreateWorkspace_SparsePaths(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	deps.config.Registry = &config.RepoRegistry{Repos: map[string]config.RegistryEntry{
+		"monorepo": {URL: "https://example.com/monorepo.git", SparsePaths: []string{"services/api/"}},
+	}}
+	tmpl := config.Template{
+		Name:        "backend",
+		Repos:       []string{"monorepo", "tools"},
+		SparsePaths: map[string][]string{"monorepo": {"docs"}, "tools": {"scripts", "bin"}},
+	}
+	deps.config.Templates = map[string]config.Template{"backend": tmpl}
+
+	got := map[string][]string{}
+	deps.git.CreateWorktreeFunc = func(_ context.Context, repoName, _, _ string, sparsePaths []string) error {
+		got[repoName] = sparsePaths
+		return nil
+	}
+
+	repos := []domain.Repo{
+		{Name: "monorepo", URL: "https://example.com/monorepo.git"},
+		{Name: "tools", URL: "https://example.com/tools.git"},
+		{Name: "imported", URL: "https://example.com/imported.git", SparsePaths: []string{"web"}},
+	}
+
+	if _, err := deps.svc.CreateWorkspaceWithOptions(context.Background(), "ws-1", "", repos, CreateOptions{Template: &tmpl}); err != nil {
+		t.Fatalf("CreateWorkspaceWithOptions failed: %v", err)
+	}
+
+	want := map[string][]string{
+		"monorepo": {"services/api"},
+		"tools":    {"bin", "scripts"},
+		"imported": {"web"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("worktree sparse paths = %v, want %v", got, want)
+	}
+
+	for _, repo := range deps.storage.Workspaces["ws-1"].Repos {
+		if !reflect.DeepEqual(repo.SparsePaths, want[repo.Name]) {
+			t.Errorf("recorded sparse paths for %s = %v, want %v", repo.Name, repo.SparsePaths, want[repo.Name])
+		}
+	}
+
+	if repos[0].SparsePaths != nil {
+		t.Errorf("expected the caller's repos to be left alone, got %v", repos[0].SparsePaths)
+	}
+}
+
+func TestUpdateSparsePaths(t *testing.T) {
+	t.Parallel()
+
+	deps := newMockService(t)
+	addWorkspaceFixture(deps.storage, domain.Workspace{
+		ID:      "ws-1",
+		DirName: "ws-1",
+		Repos: []domain.Repo{
+			{Name: "monorepo", URL: "https://example.com/monorepo.git", SparsePaths: []string{"docs", "services/api"}},
+		},
+	})
+
+	var applied []string
+
+	deps.git.SetSparsePathsFunc = func(_ context.Context, _ string, paths []string) error {
+		applied = paths
+		return nil
+	}
+
+	paths, err := deps.svc.UpdateSparsePaths(context.Background(), "ws-1", "monorepo", []string{"services/web/", "docs"}, []string{"services/api"})
+	if err != nil {
+		t.Fatalf("UpdateSparsePaths failed: %v", err)
+	}
+
+	want := []string{"docs", "services/web"}
+	if !reflect.DeepEqual(paths, want) || !reflect.DeepEqual(applied, want) {
+		t.Errorf("paths = %v, applied = %v, want %v", paths, applied, want)
+	}
+
+	if got := deps.storage.Workspaces["ws-1"].Repos[0].SparsePaths; !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded sparse paths = %v, want %v", got, want)
+	}
+
+	paths, err = deps.svc.UpdateSparsePaths(context.Background(), "ws-1", "monorepo", nil, want)
+	if err != nil {
+		t.Fatalf("UpdateSparsePaths failed: %v", err)
+	}
+
+	if paths != nil || applied != nil {
+		t.Errorf("expected removing every path to disable sparse checkout, got %v (applied %v)", paths, applied)
+	}
+
+	if _, err := deps.svc.UpdateSparsePaths(context.Background(), "ws-1", "missing", []string{"docs"}, nil); err == nil {
+		t.Error("expected an error for a repo that is not in the workspace")
+	}
+
+	if _, err := deps.svc.UpdateSparsePaths(context.Background(), "ws-1", "monorepo", []string{"../escape"}, nil); err == nil {
+		t.Error("expected an error for an invalid sparse path")
+	}
+}
@@ -76,9 +76,28 @@ func (s *Service) WorkspaceLocked(workspaceID string) (bool, error) {
 	return s.lockManager.IsLocked(workspaceID)
 }
 
-// ListClosedWorkspaces returns closed workspace metadata.
+// ListClosedWorkspaces returns closed workspace metadata, newest first, with the disk usage
+// of each entry, including its archived patches and bundles.
 func (s *Service) ListClosedWorkspaces(ctx context.Context) ([]domain.ClosedWorkspace, error) {
-	return s.wsEngine.ListClosed(ctx)
+	closed, err := s.wsEngine.ListClosed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range closed {
+		usage, _, sizeErr := s.diskUsage.Calculate(closed[i].Path)
+		if sizeErr != nil {
+			if s.logger != nil {
+				s.logger.Debug("Failed to calculate closed entry size", "workspace", closed[i].Metadata.ID, "error", sizeErr)
+			}
+
+			continue
+		}
+
+		closed[i].Metadata.DiskUsageBytes = usage
+	}
+
+	return closed, nil
 }
 
 // GetStatus returns the aggregate status of a workspace
@@ -7,15 +7,47 @@ import (
 	"time"
 
 	"github.com/alexisbeaulieu97/canopy/internal/domain"
+	cerrors "github.com/alexisbeaulieu97/canopy/internal/errors"
+	"github.com/alexisbeaulieu97/canopy/internal/ports"
 )
 
+// defaultSyncTimeout bounds each repository sync when SyncOptions.Timeout is unset.
+const defaultSyncTimeout = 60 * time.Second
+
+// upstreamRef refers to the tracking branch of the checked-out branch.
+const upstreamRef = "@{upstream}"
+
 // SyncOptions configures workspace sync behavior.
 type SyncOptions struct {
 	Timeout time.Duration
+	// Strategy overrides the per-repo and per-template sync strategy when set.
+	Strategy domain.SyncStrategy
+	// OntoDefault integrates <sync remote>/<default branch> into each worktree branch
+	// instead of pulling the branch's own upstream.
+	OntoDefault bool
+}
+
+// syncTarget is what a repo integrates during sync, resolved before repos sync in parallel.
+type syncTarget struct {
+	strategy domain.SyncStrategy
+	// defaultBranch is only resolved for --onto-default.
+	defaultBranch string
+	defaultErr    error
 }
 
-// SyncWorkspace pulls updates for all repositories in the workspace.
+// canonicalFetchResults records the outcome of fetching each canonical repository once.
+// A nil error means the canonical refs are current and worktrees can be updated locally.
+type canonicalFetchResults map[string]error
+
+// SyncWorkspace pulls updates for all repositories in the workspace and then runs post_sync hooks.
+// If a post_sync hook fails, the sync result is returned along with the hook error.
 func (s *Service) SyncWorkspace(ctx context.Context, id string, opts SyncOptions) (*domain.SyncResult, error) {
+	return s.syncWorkspace(ctx, id, opts, nil)
+}
+
+// syncWorkspace syncs a workspace. When fetched is non-nil, canonical repositories present in it
+// are not fetched again and worktrees integrate the already-fetched refs without network access.
+func (s *Service) syncWorkspace(ctx context.Context, id string, opts SyncOptions, fetched canonicalFetchResults) (*domain.SyncResult, error) {
 	var result *domain.SyncResult
 
 	if err := s.withWorkspaceLock(ctx, id, false, func() error {
@@ -25,13 +57,26 @@ func (s *Service) SyncWorkspace(ctx context.Context, id string, opts SyncOptions
 		}
 
 		if opts.Timeout == 0 {
-			opts.Timeout = 60 * time.Second // Default timeout
+			opts.Timeout = defaultSyncTimeout
+		}
+
+		if opts.Strategy != "" && !opts.Strategy.IsValid() {
+			return cerrors.NewInvalidArgument("strategy", fmt.Sprintf("unsupported sync strategy %q", opts.Strategy))
+		}
+
+		// Resolve strategies and default branches once, before the repos sync in parallel
+		targets := make([]syncTarget, len(ws.Repos))
+
+		for i, repo := range ws.Repos {
+			targets[i].strategy = s.resolveSyncStrategy(ws, repo, opts.Strategy)
+			if opts.OntoDefault {
+				targets[i].defaultBranch, targets[i].defaultErr = s.resolveDefaultBranch(ws, repo)
+			}
 		}
 
 		executor := NewParallelExecutor(s.config.GetParallelWorkers())
 		results, err := ParallelMap(ctx, executor, len(ws.Repos), func(runCtx context.Context, index int) (domain.RepoSyncStatus, error) {
-			repo := ws.Repos[index]
-			return s.syncRepo(runCtx, dirName, repo, opts.Timeout), nil
+			return s.syncRepo(runCtx, dirName, ws.Repos[index], targets[index], opts, fetched), nil
 		}, ParallelOptions{ContinueOnError: true})
 		if err != nil {
 			return err
@@ -39,9 +84,9 @@ func (s *Service) SyncWorkspace(ctx context.Context, id string, opts SyncOptions
 
 		result = s.aggregateSyncResults(id, ExtractValues(results))
 
-		return nil
+		return s.runPhaseHooks(HookPhasePostSync, *ws, dirName, false)
 	}); err != nil {
-		return nil, err
+		return result, err
 	}
 
 	return result, nil
@@ -66,19 +111,112 @@ func (s *Service) aggregateSyncResults(workspaceID string, results []domain.Repo
 	return syncResult
 }
 
-func (s *Service) syncRepo(ctx context.Context, dirName string, repo domain.Repo, timeout time.Duration) domain.RepoSyncStatus {
+// resolveSyncStrategy picks the sync strategy for a repo.
+// Precedence: explicit option, registry entry, workspace template, then ff-only.
+func (s *Service) resolveSyncStrategy(ws *domain.Workspace, repo domain.Repo, requested domain.SyncStrategy) domain.SyncStrategy {
+	if requested != "" {
+		return requested
+	}
+
+	if entry, ok := s.registryEntryForRepo(repo); ok && entry.SyncStrategy != "" {
+		return domain.SyncStrategy(entry.SyncStrategy)
+	}
+
+	if ws != nil && ws.Template != "" {
+		if tmpl, err := s.config.ResolveTemplate(ws.Template); err == nil && tmpl.SyncStrategy != "" {
+			return domain.SyncStrategy(tmpl.SyncStrategy)
+		}
+	}
+
+	return domain.SyncStrategyFFOnly
+}
+
+// resolveDefaultBranch picks the integration branch for --onto-default.
+// Precedence: registry entry, workspace template, then the canonical remote HEAD.
+func (s *Service) resolveDefaultBranch(ws *domain.Workspace, repo domain.Repo) (string, error) {
+	if entry, ok := s.registryEntryForRepo(repo); ok && entry.DefaultBranch != "" {
+		return entry.DefaultBranch, nil
+	}
+
+	if ws != nil && ws.Template != "" {
+		if tmpl, err := s.config.ResolveTemplate(ws.Template); err == nil && tmpl.DefaultBranch != "" {
+			return tmpl.DefaultBranch, nil
+		}
+	}
+
+	return s.gitEngine.DefaultBranch(repo.Name, repo.SyncRemote())
+}
+
+// fetchCanonicalRepos fetches each canonical repository used by the workspaces exactly once.
+// Fetches run in parallel and go through the git engine's retry policy.
+func (s *Service) fetchCanonicalRepos(ctx context.Context, workspaces []domain.Workspace, timeout time.Duration) canonicalFetchResults {
+	seen := make(map[string]bool)
+
+	var names []string
+
+	for _, ws := range workspaces {
+		for _, repo := range ws.Repos {
+			if !seen[repo.Name] {
+				seen[repo.Name] = true
+				names = append(names, repo.Name)
+			}
+		}
+	}
+
+	errs := make([]error, len(names))
+	executor := NewParallelExecutor(s.config.GetParallelWorkers())
+
+	_ = executor.Run(ctx, len(names), func(runCtx context.Context, index int) error {
+		fetchCtx, cancel := context.WithTimeout(runCtx, timeout)
+		defer cancel()
+
+		errs[index] = s.gitEngine.Fetch(fetchCtx, names[index])
+
+		return errs[index]
+	}, ParallelOptions{ContinueOnError: true})
+
+	fetched := make(canonicalFetchResults, len(names))
+
+	for i, name := range names {
+		err := errs[i]
+		if err == nil && ctx.Err() != nil {
+			err = cerrors.NewContextError(ctx, "fetch", name)
+		}
+
+		fetched[name] = err
+	}
+
+	return fetched
+}
+
+func (s *Service) syncRepo(ctx context.Context, dirName string, repo domain.Repo, target syncTarget, opts SyncOptions, fetched canonicalFetchResults) domain.RepoSyncStatus {
+	strategy := target.strategy
+
 	result := domain.RepoSyncStatus{
-		Name:   repo.Name,
-		Status: domain.SyncStatusUpToDate,
+		Name:     repo.Name,
+		Status:   domain.SyncStatusUpToDate,
+		Strategy: strategy,
 	}
 
-	repoCtx, cancel := context.WithTimeout(ctx, timeout)
+	if !strategy.IsValid() {
+		result.Status = domain.SyncStatusError
+		result.Error = fmt.Sprintf("unsupported sync strategy %q", strategy)
+
+		return result
+	}
+
+	repoCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
-	// 1. Fetch canonical
-	if err := s.gitEngine.Fetch(repoCtx, repo.Name); err != nil {
+	// 1. Fetch canonical, unless it was already fetched for this sync run
+	fetchErr, prefetched := fetched[repo.Name]
+	if !prefetched {
+		fetchErr = s.gitEngine.Fetch(repoCtx, repo.Name)
+	}
+
+	if err := fetchErr; err != nil {
 		result.Updated = 0
-		if isDeadlineExceeded(err) {
+		if isDeadlineExceeded(err) || cerrors.IsOperationTimeout(err) {
 			result.Status = domain.SyncStatusTimeout
 			result.Error = "timeout during fetch"
 
@@ -93,8 +231,12 @@ func (s *Service) syncRepo(ctx context.Context, dirName string, repo domain.Repo
 
 	worktreePath := filepath.Join(s.config.GetWorkspacesRoot(), dirName, repo.Name)
 
+	if opts.OntoDefault {
+		return s.syncRepoOntoDefault(repoCtx, repo, target, worktreePath, result)
+	}
+
 	// 2. Get status before pull to see behind count
-	_, _, behind, _, err := s.gitEngine.Status(repoCtx, worktreePath)
+	isDirty, _, behind, _, err := s.gitEngine.Status(repoCtx, worktreePath)
 	if err != nil {
 		result.Status = domain.SyncStatusError
 		result.Error = fmt.Sprintf("status failed: %v", err)
@@ -105,28 +247,107 @@ func (s *Service) syncRepo(ctx context.Context, dirName string, repo domain.Repo
 
 	result.Updated = behind
 
-	// 3. Pull worktree only if behind remote
-	if result.Updated > 0 {
-		err = s.gitEngine.Pull(repoCtx, worktreePath)
-		if err != nil {
-			result.Updated = 0
-			if isDeadlineExceeded(err) {
-				result.Status = domain.SyncStatusTimeout
-				result.Error = "timeout during pull"
+	if result.Updated == 0 {
+		return result
+	}
 
-				return result
-			}
+	// Fetch-only leaves the worktree untouched and reports what is available
+	if strategy == domain.SyncStrategyFetchOnly {
+		result.Status = domain.SyncStatusFetched
+		return result
+	}
 
-			// Check for conflicts - Usually go-git returns error if pull cannot be done cleanly.
-			// For now we treat it as error, but we could improve detection.
-			result.Status = domain.SyncStatusError
-			result.Error = fmt.Sprintf("pull failed: %v", err)
+	// 3. Pull worktree only if behind remote, stashing local changes if needed.
+	// Prefetched canonicals share their refs with the worktree, so integrate locally.
+	pullOpts := ports.PullOptions{
+		Strategy:  strategy,
+		Autostash: isDirty,
+		Remote:    repo.SyncRemote(),
+	}
+	if prefetched {
+		pullOpts.Upstream = upstreamRef
+	}
 
-			return result
-		}
+	pullResult, err := s.gitEngine.Pull(repoCtx, worktreePath, pullOpts)
+
+	return applyPullOutcome(result, pullResult, err, "pull")
+}
+
+// syncRepoOntoDefault integrates <sync remote>/<default branch> into the worktree branch.
+// The canonical repository must already be fetched.
+func (s *Service) syncRepoOntoDefault(ctx context.Context, repo domain.Repo, target syncTarget, worktreePath string, result domain.RepoSyncStatus) domain.RepoSyncStatus {
+	if target.defaultErr != nil {
+		result.Status = domain.SyncStatusError
+		result.Error = fmt.Sprintf("resolve default branch failed: %v", target.defaultErr)
+
+		return result
+	}
+
+	result.Onto = repo.SyncRemote() + "/" + target.defaultBranch
+
+	behind, err := s.gitEngine.CommitsBehind(ctx, worktreePath, result.Onto)
+	if err != nil {
+		result.Status = domain.SyncStatusError
+		result.Error = fmt.Sprintf("compare with %s failed: %v", result.Onto, err)
+
+		return result
+	}
 
+	result.Updated = behind
+
+	if result.Updated == 0 {
+		return result
+	}
+
+	if result.Strategy == domain.SyncStrategyFetchOnly {
+		result.Status = domain.SyncStatusFetched
+		return result
+	}
+
+	isDirty, _, _, _, err := s.gitEngine.Status(ctx, worktreePath)
+	if err != nil {
+		result.Status = domain.SyncStatusError
+		result.Error = fmt.Sprintf("status failed: %v", err)
+		result.Updated = 0
+
+		return result
+	}
+
+	pullResult, err := s.gitEngine.Pull(ctx, worktreePath, ports.PullOptions{
+		Strategy:  result.Strategy,
+		Autostash: isDirty,
+		Upstream:  result.Onto,
+	})
+
+	return applyPullOutcome(result, pullResult, err, "integrate "+result.Onto)
+}
+
+// applyPullOutcome records the outcome of integrating upstream changes into a worktree.
+func applyPullOutcome(result domain.RepoSyncStatus, pullResult *ports.PullResult, err error, action string) domain.RepoSyncStatus {
+	if err == nil {
 		result.Status = domain.SyncStatusUpdated
+		return result
 	}
 
+	result.Updated = 0
+
+	if isDeadlineExceeded(err) || cerrors.IsOperationTimeout(err) {
+		result.Status = domain.SyncStatusTimeout
+		result.Error = "timeout during " + action
+
+		return result
+	}
+
+	if pullResult != nil && len(pullResult.Conflicts) > 0 {
+		result.Status = domain.SyncStatusConflict
+		result.Conflicts = pullResult.Conflicts
+		result.Error = fmt.Sprintf("%s stopped on conflicts in %d file(s)", result.Strategy, len(pullResult.Conflicts))
+
+		return result
+	}
+
+	result.Status = domain.SyncStatusError
+	result.Error = fmt.Sprintf("%s failed: %v", action, err)
+
 	return result
 }
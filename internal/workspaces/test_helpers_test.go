@@ -16,6 +16,7 @@ type mockServiceDeps struct {
 	storage *mocks.MockWorkspaceStorage
 	cache   *mocks.MockWorkspaceCache
 	disk    *mocks.MockDiskUsage
+	forge   *mocks.MockForge
 }
 
 func newMockService(t *testing.T) mockServiceDeps {
@@ -37,6 +38,7 @@ func newMockService(t *testing.T) mockServiceDeps {
 	}
 	cache := mocks.NewMockWorkspaceCache()
 	disk := mocks.NewMockDiskUsage()
+	forge := &mocks.MockForge{}
 
 	svc := NewService(
 		config,
@@ -45,6 +47,7 @@ func newMockService(t *testing.T) mockServiceDeps {
 		nil,
 		WithCache(cache),
 		WithDiskUsage(disk),
+		WithForgeResolver(&mocks.MockForgeResolver{Forge: forge}),
 	)
 
 	return mockServiceDeps{
@@ -54,6 +57,7 @@ func newMockService(t *testing.T) mockServiceDeps {
 		storage: storage,
 		cache:   cache,
 		disk:    disk,
+		forge:   forge,
 	}
 }
 
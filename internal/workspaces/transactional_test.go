@@ -28,10 +28,10 @@ func TestCreateWorkspaceRollbackOnCloneFailure(t *testing.T) {
 	mockConfig.ClosedRoot = closedRoot
 
 	mockGit := mocks.NewMockGitOperations()
-	mockGit.EnsureCanonicalFunc = func(_ context.Context, _, _ string) (*git.Repository, error) {
+	mockGit.EnsureCanonicalFunc = func(_ context.Context, _, _ string, _ domain.CloneOptions) (*git.Repository, error) {
 		return nil, nil
 	}
-	mockGit.CreateWorktreeFunc = func(_ context.Context, _, worktreePath, _ string) error {
+	mockGit.CreateWorktreeFunc = func(_ context.Context, _, worktreePath, _ string, _ []string) error {
 		if err := os.MkdirAll(worktreePath, 0o750); err != nil {
 			return err
 		}
@@ -74,10 +74,10 @@ func TestAddRepoRollbackOnSaveFailure(t *testing.T) {
 	}
 
 	mockGit := mocks.NewMockGitOperations()
-	mockGit.EnsureCanonicalFunc = func(_ context.Context, _, _ string) (*git.Repository, error) {
+	mockGit.EnsureCanonicalFunc = func(_ context.Context, _, _ string, _ domain.CloneOptions) (*git.Repository, error) {
 		return nil, nil
 	}
-	mockGit.CreateWorktreeFunc = func(_ context.Context, _, worktreePath, _ string) error {
+	mockGit.CreateWorktreeFunc = func(_ context.Context, _, worktreePath, _ string, _ []string) error {
 		return os.MkdirAll(worktreePath, 0o750)
 	}
 	mockGit.RemoveWorktreeFunc = func(_ context.Context, _, worktreePath string) error {
@@ -134,10 +134,10 @@ func TestRestoreWorkspaceRollbackOnRecreateFailure(t *testing.T) {
 	}
 
 	mockGit := mocks.NewMockGitOperations()
-	mockGit.EnsureCanonicalFunc = func(_ context.Context, _, _ string) (*git.Repository, error) {
+	mockGit.EnsureCanonicalFunc = func(_ context.Context, _, _ string, _ domain.CloneOptions) (*git.Repository, error) {
 		return nil, nil
 	}
-	mockGit.CreateWorktreeFunc = func(_ context.Context, _, worktreePath, _ string) error {
+	mockGit.CreateWorktreeFunc = func(_ context.Context, _, worktreePath, _ string, _ []string) error {
 		if err := os.MkdirAll(worktreePath, 0o750); err != nil {
 			return err
 		}